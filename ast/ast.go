@@ -59,11 +59,15 @@ type Expr interface {
 
 // LiteralExpr represents a literal value: number, string, bool, null.
 type LiteralExpr struct {
-	// Kind: "int", "float", "string", "bool", "null"
-	Kind       string
-	Int        int64
-	Float      float64
-	Str        string
+	// Kind: "int", "float", "string", "bool", "null", or "decimal" for a
+	// float literal the planner retyped next to a decimal (Str holds its text)
+	Kind  string
+	Int   int64
+	Float float64
+	Str   string
+	// Text is the source spelling of a float literal, so decimal contexts
+	// can read 0.20 exactly instead of through its float approximation.
+	Text       string
 	Bool       bool
 	SourceSpan PackedSpan
 }
@@ -123,8 +127,10 @@ func (e *UnaryExpr) Span() Span {
 
 // FuncCallExpr represents a function call: func(arg1, arg2, ...).
 type FuncCallExpr struct {
-	Name       string
-	Args       []Expr
+	Name string
+	Args []Expr
+	// Filter is the condition of "agg(x) where { cond }"; nil when absent.
+	Filter     Expr
 	SourceSpan PackedSpan
 }
 
@@ -186,6 +192,22 @@ func (e *IsNullExpr) Span() Span {
 	return e.SourceSpan.Unpack()
 }
 
+// LambdaExpr represents "x -> body" or "(acc, x) -> body". Lambdas are only
+// valid as arguments of higher-order list functions such as list_filter.
+type LambdaExpr struct {
+	Params     []string
+	Body       Expr
+	SourceSpan PackedSpan
+}
+
+func (e *LambdaExpr) exprNode() {}
+func (e *LambdaExpr) Span() Span {
+	if e == nil {
+		return Span{}
+	}
+	return e.SourceSpan.Unpack()
+}
+
 // Assignment represents "col = expr" in transform/reduce.
 type Assignment struct {
 	Column     string
@@ -203,14 +225,18 @@ func (a *Assignment) Span() Span {
 // LoadOptions configures how a source file is loaded.
 // Zero value keeps extension-based inference and CSV defaults (header row, comma delim).
 type LoadOptions struct {
-	Format              string // optional override: csv, json, jsonl, avro, parquet
-	Compression         string // optional file-level compression wrapper: gzip, zstd, deflate
-	Header              *bool  // csv only; nil = default (true)
-	Delim               string // csv only; "" = comma
-	AllowJaggedRows     *bool  // csv only; nil = default (false)
-	IgnoreUnknownValues *bool  // csv only; nil = default (false)
-	InferRows           *int   // csv/json/jsonl; nil = default (20480), -1 = all rows, 0 = csv all strings
-	MaxBadRecords       *int   // csv/json/jsonl; nil = default (0)
+	Format              string   // optional override: csv, json, jsonl, avro, parquet
+	Compression         string   // optional file-level compression wrapper: gzip, zstd, deflate
+	Header              *bool    // csv only; nil = default (true)
+	Delim               string   // csv only; "" = comma
+	AllowJaggedRows     *bool    // csv only; nil = default (false)
+	IgnoreUnknownValues *bool    // csv only; nil = default (false)
+	Decimals            *bool    // csv only; nil = default (false), true infers decimal(p,s) for plain decimals
+	Dates               *bool    // csv only; nil = default (false), true infers date and timestamp for ISO cells
+	Maps                []string // json/jsonl only; field paths whose objects load as map<string,T>
+	UnionNames          *bool    // avro only; nil = default (false), true keeps named union branches apart
+	InferRows           *int     // csv/json/jsonl; nil = default (20480), -1 = all rows, 0 = csv all strings
+	MaxBadRecords       *int     // csv/json/jsonl; nil = default (0)
 }
 
 // --- Operations (pipeline stages) ---
@@ -437,9 +463,12 @@ type JoinKey struct {
 
 // JoinOp joins the current table with another file.
 type JoinOp struct {
-	Kind       string // inner, left, right, full
+	Kind       string // inner, left, right, full, semi, anti, cross, asof
 	Filename   string
 	Keys       []JoinKey
+	Condition  Expr     // on terms that are not column keys; nil if none
+	Match      *JoinKey // asof only: right row must have Right <= Left
+	Tolerance  Expr     // asof only: max Left - Right gap, a number or duration("..."); nil = none
 	Load       LoadOptions
 	SourceSpan PackedSpan
 }
@@ -452,6 +481,90 @@ func (o *JoinOp) Span() Span {
 	return o.SourceSpan.Unpack()
 }
 
+// WindowOp computes per-row window functions over ordered partitions.
+type WindowOp struct {
+	PartitionBy [][]string
+	OrderBy     []SortKey
+	Assignments []Assignment
+	SourceSpan  PackedSpan
+}
+
+func (o *WindowOp) opNode() {}
+func (o *WindowOp) Span() Span {
+	if o == nil {
+		return Span{}
+	}
+	return o.SourceSpan.Unpack()
+}
+
+// ExplodeOp emits one row per element of a list column.
+type ExplodeOp struct {
+	Column     []string
+	As         string // "" = keep the column name
+	Position   string // "" = no position column
+	Outer      bool   // keep rows whose list is null or empty
+	SourceSpan PackedSpan
+}
+
+func (o *ExplodeOp) opNode() {}
+func (o *ExplodeOp) Span() Span {
+	if o == nil {
+		return Span{}
+	}
+	return o.SourceSpan.Unpack()
+}
+
+// PivotOp spreads the values of one column into one output column per listed
+// key value, aggregating the value column for each remaining column group.
+type PivotOp struct {
+	Key        []string
+	In         []*LiteralExpr
+	Values     []string // nil when the aggregate takes no argument
+	Agg        string   // "" = first
+	SourceSpan PackedSpan
+}
+
+func (o *PivotOp) opNode() {}
+func (o *PivotOp) Span() Span {
+	if o == nil {
+		return Span{}
+	}
+	return o.SourceSpan.Unpack()
+}
+
+// UnpivotOp turns columns into rows of (name, value) pairs.
+type UnpivotOp struct {
+	Columns    []string
+	NameCol    string // default "name"
+	ValueCol   string // default "value"
+	SourceSpan PackedSpan
+}
+
+func (o *UnpivotOp) opNode() {}
+func (o *UnpivotOp) Span() Span {
+	if o == nil {
+		return Span{}
+	}
+	return o.SourceSpan.Unpack()
+}
+
+// UnionOp appends the rows of another file to the current table.
+type UnionOp struct {
+	Filename   string
+	Load       LoadOptions
+	ByPosition bool   // match columns by position instead of by name
+	Tag        string // optional column holding each row's source filename
+	SourceSpan PackedSpan
+}
+
+func (o *UnionOp) opNode() {}
+func (o *UnionOp) Span() Span {
+	if o == nil {
+		return Span{}
+	}
+	return o.SourceSpan.Unpack()
+}
+
 // OutputOptions configures a terminal output format command.
 // Zero value keeps writer defaults.
 type OutputOptions struct {
@@ -12,6 +12,7 @@ func TestASTNodesImplementInterfaces(t *testing.T) {
 		&StructExpr{},
 		&ListExpr{},
 		&IsNullExpr{},
+		&LambdaExpr{},
 	}
 	for _, expr := range exprs {
 		expr.exprNode()
@@ -32,6 +33,11 @@ func TestASTNodesImplementInterfaces(t *testing.T) {
 		&RenameOp{},
 		&RemoveOp{},
 		&JoinOp{},
+		&WindowOp{},
+		&ExplodeOp{},
+		&PivotOp{},
+		&UnpivotOp{},
+		&UnionOp{},
 	}
 	for _, op := range ops {
 		op.opNode()
@@ -106,12 +106,18 @@ func validateLoadOptionValues(opts LoadOptions, prefix string) error {
 }
 
 func validateFormatSpecificOptions(opts LoadOptions, format, prefix string) error {
-	if opts.Header == nil && opts.Delim == "" && opts.AllowJaggedRows == nil && opts.IgnoreUnknownValues == nil && opts.InferRows == nil && opts.MaxBadRecords == nil {
+	if opts.Header == nil && opts.Delim == "" && opts.AllowJaggedRows == nil && opts.IgnoreUnknownValues == nil && opts.Decimals == nil && opts.Dates == nil && len(opts.Maps) == 0 && opts.UnionNames == nil && opts.InferRows == nil && opts.MaxBadRecords == nil {
 		return nil
 	}
 	if format == "" || !IsSupportedLoadFormat(format) {
 		return fmt.Errorf("%scannot determine file format: use with format=... in query (%s)", prefix, LoadFormatsList())
 	}
+	if len(opts.Maps) > 0 && format != "json" && format != "jsonl" {
+		return fmt.Errorf("%smaps applies only to json and jsonl formats", prefix)
+	}
+	if opts.UnionNames != nil && format != "avro" {
+		return fmt.Errorf("%sunion_names applies only to avro format", prefix)
+	}
 	if format == "csv" {
 		return nil
 	}
@@ -127,6 +133,12 @@ func validateFormatSpecificOptions(opts LoadOptions, format, prefix string) erro
 	if opts.IgnoreUnknownValues != nil {
 		return fmt.Errorf("%signore_unknown_values applies only to csv format", prefix)
 	}
+	if opts.Decimals != nil {
+		return fmt.Errorf("%sdecimals applies only to csv format", prefix)
+	}
+	if opts.Dates != nil {
+		return fmt.Errorf("%sdates applies only to csv format", prefix)
+	}
 	if format == "json" || format == "jsonl" {
 		if opts.InferRows != nil && *opts.InferRows == 0 {
 			return fmt.Errorf("%sinfer_rows=0 is invalid for %s format", prefix, format)
@@ -136,7 +148,10 @@ func validateFormatSpecificOptions(opts LoadOptions, format, prefix string) erro
 	if opts.InferRows != nil {
 		return fmt.Errorf("%sinfer_rows applies only to csv, json, and jsonl formats", prefix)
 	}
-	return fmt.Errorf("%smax_bad_records applies only to csv, json, and jsonl formats", prefix)
+	if opts.MaxBadRecords != nil {
+		return fmt.Errorf("%smax_bad_records applies only to csv, json, and jsonl formats", prefix)
+	}
+	return nil
 }
 
 func inferFormatFromFilename(filename string) string {
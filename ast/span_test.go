@@ -39,6 +39,7 @@ func TestExprSpanMethods(t *testing.T) {
 		{"struct", &StructExpr{SourceSpan: want.Pack()}},
 		{"list", &ListExpr{SourceSpan: want.Pack()}},
 		{"is_null", &IsNullExpr{SourceSpan: want.Pack()}},
+		{"lambda", &LambdaExpr{SourceSpan: want.Pack()}},
 	}
 
 	for _, tc := range exprs {
@@ -59,6 +60,7 @@ func TestExprSpanNilReceivers(t *testing.T) {
 	var strct *StructExpr
 	var list *ListExpr
 	var isNull *IsNullExpr
+	var lambda *LambdaExpr
 
 	cases := []struct {
 		name string
@@ -72,6 +74,7 @@ func TestExprSpanNilReceivers(t *testing.T) {
 		{"struct", strct.Span()},
 		{"list", list.Span()},
 		{"is_null", isNull.Span()},
+		{"lambda", lambda.Span()},
 	}
 
 	for _, tc := range cases {
@@ -104,6 +107,11 @@ func TestOpSpanMethods(t *testing.T) {
 		{"rename", &RenameOp{SourceSpan: want.Pack()}},
 		{"remove", &RemoveOp{SourceSpan: want.Pack()}},
 		{"join", &JoinOp{SourceSpan: want.Pack()}},
+		{"window", &WindowOp{SourceSpan: want.Pack()}},
+		{"explode", &ExplodeOp{SourceSpan: want.Pack()}},
+		{"pivot", &PivotOp{SourceSpan: want.Pack()}},
+		{"unpivot", &UnpivotOp{SourceSpan: want.Pack()}},
+		{"union", &UnionOp{SourceSpan: want.Pack()}},
 	}
 
 	for _, tc := range ops {
@@ -131,6 +139,11 @@ func TestOpSpanNilReceivers(t *testing.T) {
 	var rename *RenameOp
 	var remove *RemoveOp
 	var join *JoinOp
+	var window *WindowOp
+	var explode *ExplodeOp
+	var pivot *PivotOp
+	var unpivot *UnpivotOp
+	var union *UnionOp
 
 	cases := []struct {
 		name string
@@ -151,6 +164,11 @@ func TestOpSpanNilReceivers(t *testing.T) {
 		{"rename", rename.Span()},
 		{"remove", remove.Span()},
 		{"join", join.Span()},
+		{"window", window.Span()},
+		{"explode", explode.Span()},
+		{"pivot", pivot.Span()},
+		{"unpivot", unpivot.Span()},
+		{"union", union.Span()},
 	}
 
 	for _, tc := range cases {
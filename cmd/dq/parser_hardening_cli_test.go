@@ -42,8 +42,8 @@ func TestCLIParserHardeningRejectsSwallowedLexerErrorsAllInputFormats(t *testing
 
 	for _, tc := range cliFlatUserInputFiles() {
 		t.Run(tc.name, func(t *testing.T) {
-			out := runCLIQueryExpectError(t, bin, tc.path+` | transform out = age % 2 | json`)
-			assertCLIParseErrorContains(t, out, "lex error", "%")
+			out := runCLIQueryExpectError(t, bin, tc.path+` | transform out = age ^ 2 | json`)
+			assertCLIParseErrorContains(t, out, "lex error", "^")
 		})
 	}
 }
@@ -105,8 +105,8 @@ func TestCLIParserHardeningRejectsMalformedExpressionBeforeOutputDestination(t *
 	dir := t.TempDir()
 	outPath := filepath.Join(dir, "bad.csv")
 
-	out := runCLIQueryExpectError(t, bin, "../../testdata/users.csv | transform out = age % 2 | csv to "+outPath)
-	assertCLIParseErrorContains(t, out, "lex error", "%")
+	out := runCLIQueryExpectError(t, bin, "../../testdata/users.csv | transform out = age ^ 2 | csv to "+outPath)
+	assertCLIParseErrorContains(t, out, "lex error", "^")
 	if _, err := os.Stat(outPath); !os.IsNotExist(err) {
 		t.Fatalf("parse failure should not create %s, stat err=%v", outPath, err)
 	}
@@ -118,21 +118,21 @@ func TestCLIParserHardeningRejectsMalformedExpressionBeforeAllOutputFormats(t *t
 
 	for _, format := range formats {
 		t.Run(format, func(t *testing.T) {
-			out := runCLIQueryExpectError(t, bin, "../../testdata/users.csv | transform out = age % 2 | "+format)
-			assertCLIParseErrorContains(t, out, "lex error", "%")
+			out := runCLIQueryExpectError(t, bin, "../../testdata/users.csv | transform out = age ^ 2 | "+format)
+			assertCLIParseErrorContains(t, out, "lex error", "^")
 		})
 	}
 }
 
 func TestCLIParserHardeningRejectsMalformedExpressionFromStdin(t *testing.T) {
 	bin := buildCLI(t)
-	cmd := exec.Command(bin, `- with format=csv | transform out = age % 2 | json`)
+	cmd := exec.Command(bin, `- with format=csv | transform out = age ^ 2 | json`)
 	cmd.Stdin = strings.NewReader("name,age,city\nAlice,30,NY\nBob,25,LA\n")
 	out, err := cmd.CombinedOutput()
 	if err == nil {
 		t.Fatalf("expected parse failure, got output:\n%s", out)
 	}
-	assertCLIParseErrorContains(t, out, "lex error", "%")
+	assertCLIParseErrorContains(t, out, "lex error", "^")
 }
 
 func TestCLIParserHardeningDelimitedLexerErrors(t *testing.T) {
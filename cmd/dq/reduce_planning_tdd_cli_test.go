@@ -107,8 +107,8 @@ func TestCLIReducePlanningRejectsInvalidExpressionsBeforeRowsAllFlatFormats(t *t
 				{"sum_string_column", `sum(name)`, []string{"sum", "numeric"}},
 				{"avg_string_column", `avg(city)`, []string{"avg", "numeric"}},
 				{"missing_aggregate_column", `first(missing)`, []string{"missing", "not found"}},
-				{"aggregate_arg_must_be_column_function", `first(upper(name))`, []string{"first", "column reference"}},
-				{"aggregate_arg_must_be_column_expression", `sum(age + 1)`, []string{"sum", "column reference"}},
+				{"aggregate_arg_cannot_nest_aggregate", `first(sum(age))`, []string{"first", "sum", "nested"}},
+				{"aggregate_arg_expression_must_typecheck", `sum(upper(name))`, []string{"sum", "numeric"}},
 				{"aggregate_count_takes_no_args", `count(age)`, []string{"count", "no arguments"}},
 				{"not_requires_bool", `not sum(age)`, []string{"not", "boolean"}},
 				{"and_requires_bool_left", `sum(age) and true`, []string{"and", "boolean"}},
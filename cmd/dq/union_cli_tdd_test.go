@@ -438,32 +438,42 @@ func TestCLIAvroUnionTypesTDD(t *testing.T) {
 		}
 	})
 
-	t.Run("avro_output_rejects_union_schema_instead_of_stringifying", func(t *testing.T) {
+	t.Run("avro_output_writes_native_union", func(t *testing.T) {
 		path := writeCLIAvroUnionTDDScalarFile(t, []map[string]any{
 			{"u": goavro.Union("int", int32(7))},
 			{"u": goavro.Union("string", "seven")},
 		})
 		outPath := filepath.Join(t.TempDir(), "out.avro")
-		out := strings.ToLower(string(runCLIQueryExpectError(t, bin, path+" | avro to "+outPath)))
-		for _, part := range []string{"avro", "union", "u"} {
-			if !strings.Contains(out, part) {
-				t.Fatalf("avro union output error missing %q:\n%s", part, out)
-			}
+		runCLIQuery(t, bin, path+" | avro to "+outPath)
+		rows := readCLIDescribeRows(t, runCLIQuery(t, bin, outPath+" | describe | json"))
+		requireCLIDescribeSchema(t, rows, "u", "union", "union<int,string>", 2)
+		out := decodeCLIUnionJSONRows(t, runCLIQuery(t, bin, outPath+" | json"))
+		if len(out) != 2 {
+			t.Fatalf("avro union row count: got %#v", out)
 		}
+		requireCLIUnionJSONNumber(t, out[0]["u"], 7)
+		requireCLIUnionJSONString(t, out[1]["u"], "seven")
 	})
 
-	t.Run("parquet_output_rejects_union_schema_instead_of_stringifying", func(t *testing.T) {
-		path := writeCLIAvroUnionTDDScalarFile(t, []map[string]any{
+	t.Run("parquet_output_writes_union_member_group", func(t *testing.T) {
+		path := writeCLIAvroUnionTDDFile(t, cliAvroUnionTDDRowSchema(`{"name":"u","type":["null","int","string"],"default":null}`), []map[string]any{
 			{"u": goavro.Union("int", int32(7))},
+			{"u": nil},
 			{"u": goavro.Union("string", "seven")},
 		})
 		outPath := filepath.Join(t.TempDir(), "out.parquet")
-		out := strings.ToLower(string(runCLIQueryExpectError(t, bin, path+" | parquet to "+outPath)))
-		for _, part := range []string{"parquet", "union", "u"} {
-			if !strings.Contains(out, part) {
-				t.Fatalf("parquet union output error missing %q:\n%s", part, out)
-			}
+		runCLIQuery(t, bin, path+" | parquet to "+outPath)
+		rows := readCLIDescribeRows(t, runCLIQuery(t, bin, outPath+" | describe | json"))
+		requireCLIDescribeSchema(t, rows, "u", "union", "union<int,string>?", 3)
+		out := decodeCLIUnionJSONRows(t, runCLIQuery(t, bin, outPath+" | json"))
+		if len(out) != 3 {
+			t.Fatalf("parquet union row count: got %#v", out)
+		}
+		requireCLIUnionJSONNumber(t, out[0]["u"], 7)
+		if out[1]["u"] != nil {
+			t.Fatalf("parquet null union: got %#v", out[1]["u"])
 		}
+		requireCLIUnionJSONString(t, out[2]["u"], "seven")
 	})
 
 	t.Run("is_not_null_filter_works_on_nullable_union", func(t *testing.T) {
@@ -724,6 +734,63 @@ func TestCLIAvroUnionTypesTDD(t *testing.T) {
 		requireCLIUnionJSONString(t, rows[0]["label"], "numeric")
 		requireCLIUnionJSONString(t, rows[1]["label"], "text")
 	})
+
+	t.Run("union_names_keeps_identical_named_branches_apart", func(t *testing.T) {
+		path := writeCLIAvroUnionTDDFile(t, cliAvroUnionTDDRowSchema(
+			`{"name":"e","type":[{"type":"record","name":"Click","fields":[{"name":"x","type":"long"}]},{"type":"record","name":"View","fields":[{"name":"x","type":"long"}]}]}`,
+		), []map[string]any{
+			{"e": goavro.Union("Click", map[string]any{"x": int64(1)})},
+			{"e": goavro.Union("View", map[string]any{"x": int64(2)})},
+		})
+		rows := readCLIDescribeRows(t, runCLIQuery(t, bin, path+" with union_names=true | describe | json"))
+		requireCLIDescribeSchema(t, rows, "e", "union", "union<Click:record<x:int>,View:record<x:int>>", 2)
+		out := decodeCLIUnionJSONRows(t, runCLIQuery(t, bin, path+` with union_names=true | filter { union_is(e, "View") } | transform b = union_branch(e), v = as_record(e, "View") | transform x = v.x | select b, x | json`))
+		if len(out) != 1 {
+			t.Fatalf("filtered rows: got %#v", out)
+		}
+		requireCLIUnionJSONString(t, out[0]["b"], "View")
+		requireCLIUnionJSONNumber(t, out[0]["x"], 2)
+	})
+
+	t.Run("filtered_named_union_round_trips_through_avro", func(t *testing.T) {
+		path := writeCLIAvroUnionTDDFile(t, cliAvroUnionTDDRowSchema(
+			`{"name":"e","type":["null",{"type":"record","name":"Click","namespace":"ev","fields":[{"name":"x","type":"long"}]},{"type":"record","name":"View","namespace":"ev","fields":[{"name":"x","type":"long"}]}],"default":null}`,
+		), []map[string]any{
+			{"e": goavro.Union("ev.Click", map[string]any{"x": int64(1)})},
+			{"e": goavro.Union("ev.View", map[string]any{"x": int64(2)})},
+			{"e": goavro.Union("ev.View", map[string]any{"x": int64(3)})},
+		})
+		outPath := filepath.Join(t.TempDir(), "out.avro")
+		runCLIQuery(t, bin, path+` with union_names=true | filter { union_is(e, "View") } | avro to `+outPath)
+		rows := readCLIDescribeRows(t, runCLIQuery(t, bin, outPath+" with union_names=true | describe | json"))
+		requireCLIDescribeSchema(t, rows, "e", "union", "union<ev.Click:record<x:int>,ev.View:record<x:int>>?", 2)
+		out := decodeCLIUnionJSONRows(t, runCLIQuery(t, bin, outPath+` with union_names=true | transform b = union_branch(e) | select b | json`))
+		if len(out) != 2 {
+			t.Fatalf("round-tripped rows: got %#v", out)
+		}
+		requireCLIUnionJSONString(t, out[0]["b"], "ev.View")
+		requireCLIUnionJSONString(t, out[1]["b"], "ev.View")
+	})
+
+	t.Run("named_union_round_trips_through_parquet", func(t *testing.T) {
+		path := writeCLIAvroUnionTDDFile(t, cliAvroUnionTDDRowSchema(
+			`{"name":"id","type":"long"},{"name":"e","type":["null",{"type":"record","name":"Click","namespace":"ev","fields":[{"name":"x","type":"long"}]},{"type":"record","name":"View","namespace":"ev","fields":[{"name":"x","type":"long"}]},"long"],"default":null}`,
+		), []map[string]any{
+			{"id": int64(1), "e": goavro.Union("ev.View", map[string]any{"x": int64(2)})},
+			{"id": int64(2), "e": goavro.Union("long", int64(5))},
+			{"id": int64(3), "e": goavro.Union("ev.Click", map[string]any{"x": int64(1)})},
+		})
+		outPath := filepath.Join(t.TempDir(), "out.parquet")
+		runCLIQuery(t, bin, path+` with union_names=true | parquet to `+outPath)
+		rows := readCLIDescribeRows(t, runCLIQuery(t, bin, outPath+" | describe | json"))
+		requireCLIDescribeSchema(t, rows, "e", "union", "union<record<x:int>,int>?", 3)
+		out := decodeCLIUnionJSONRows(t, runCLIQuery(t, bin, outPath+` | filter { id > 1 } | transform b = union_branch(e) | select b | json`))
+		if len(out) != 2 {
+			t.Fatalf("round-tripped rows: got %#v", out)
+		}
+		requireCLIUnionJSONString(t, out[0]["b"], "int")
+		requireCLIUnionJSONString(t, out[1]["b"], "record<x:int>")
+	})
 }
 
 func cliAvroUnionTDDRowSchema(fields string) string {
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+const salesCSV = "region,name,price,qty,cost\n" +
+	"east,ann,2.5,4,6\n" +
+	"east,bob,10,1,4\n" +
+	"west,cat,3,3,\n" +
+	"west,,1,2,1\n"
+
+func TestAggregateExpressionArguments(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "sales.csv", salesCSV)
+	query := `reduce
+		revenue = sum(price * qty),
+		margin = sum(price * qty) - sum(cost),
+		top = max(upper(name)),
+		big = count_distinct(if(qty > 2, "big", "small")),
+		names = string_agg(upper(name), "-") where { price > 2 }
+	| remove grouped | sort region`
+	for _, prefix := range []string{"group region | ", "group region | filter { true } | "} {
+		result := loadAndQuery(t, path, prefix+query)
+		assertColumnStrings(t, result, "revenue", "20", "11")
+		assertColumnStrings(t, result, "margin", "10", "10")
+		assertColumnStrings(t, result, "top", "BOB", "CAT")
+		assertColumnStrings(t, result, "big", "2", "2")
+		assertColumnStrings(t, result, "names", "ANN-BOB", "CAT")
+		assertColumnSchema(t, result, "revenue", "float?")
+		assertColumnSchema(t, result, "top", "string?")
+	}
+}
+
+func TestAggregateExpressionArgumentErrors(t *testing.T) {
+	input := table.NewTable([]string{"city", "name", "age"})
+	input.AddRow([]table.Value{table.StrVal("NY"), table.StrVal("ann"), table.IntVal(30)})
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`group city | reduce n = sum(upper(name))`, "sum() requires a numeric column"},
+		{`group city | reduce n = sum(age + missing)`, `column "missing" not found`},
+		{`group city | reduce n = max(min(age))`, "max(): aggregate min() cannot be nested inside another aggregate"},
+		{`group city | reduce n = percentile(age, age / 100)`, "percentile() parameters after the first argument must be literals"},
+	}
+	for _, tc := range cases {
+		err := runQueryExpectErr(t, input, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// checkCollectSignature types collect_list() and collect_set(). Nulls are
+// skipped, so the elements are never null and a group without values gets an
+// empty list. The optional second argument, "asc" or "desc", sorts the list
+// and needs an orderable column.
+func checkCollectSignature(name string, args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, arityRangeError(name, 1, 2, len(args))
+	}
+	if len(args) == 2 {
+		if _, err := literalCollectOrder(name, args[1].raw); err != nil {
+			return nil, err
+		}
+		if !schemaOrderableOrNull(args[0].typ) {
+			return nil, fmt.Errorf("%s() can only sort an orderable column, got %s", name, schemaString(args[0].typ))
+		}
+	}
+	return &table.TypeDescriptor{Kind: table.TypeList, Elem: table.WithoutNull(args[0].typ)}, nil
+}
+
+func checkStringAggSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("string_agg() takes 2 arguments (column, separator), got %d", len(args))
+	}
+	if !schemaKindOrNull(args[0].typ, table.TypeString) {
+		return nil, fmt.Errorf("string_agg() requires a string column, got %s", schemaString(args[0].typ))
+	}
+	lit, ok := args[1].raw.(*ast.LiteralExpr)
+	if !ok {
+		return nil, aggregateParamError("string_agg")
+	}
+	if lit.Kind != "string" {
+		return nil, fmt.Errorf("string_agg() separator must be a string, got %s", evalLiteral(lit).AsString())
+	}
+	return &table.TypeDescriptor{Kind: table.TypeString, Nullable: true}, nil
+}
+
+func literalCollectOrder(name string, raw ast.Expr) (bool, error) {
+	lit, ok := raw.(*ast.LiteralExpr)
+	if !ok {
+		return false, aggregateParamError(name)
+	}
+	return collectOrder(name, evalLiteral(lit))
+}
+
+// collectOrder reads the "asc" / "desc" argument; desc reports descending.
+func collectOrder(name string, v table.Value) (bool, error) {
+	if v.Type == table.TypeString {
+		switch v.Str {
+		case "asc":
+			return false, nil
+		case "desc":
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf(`%s() order must be "asc" or "desc", got %s`, name, v.AsString())
+}
+
+func newCollectListAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	return newCollectAccumulator("collect_list", args, false)
+}
+
+func newCollectSetAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	return newCollectAccumulator("collect_set", args, true)
+}
+
+func newCollectAccumulator(name string, args []rowValueEvaluator, distinct bool) (aggregateAccumulator, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, arityRangeError(name, 1, 2, len(args))
+	}
+	acc := &collectAccumulator{name: name, arg: args[0]}
+	if distinct {
+		acc.seen = map[string]struct{}{}
+	}
+	if len(args) == 2 {
+		v, err := args[1](0)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := collectOrder(name, v)
+		if err != nil {
+			return nil, err
+		}
+		acc.sorted, acc.desc = true, desc
+	}
+	return acc, nil
+}
+
+func newStringAggAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("string_agg", args, 2); err != nil {
+		return nil, err
+	}
+	sep, err := args[1](0)
+	if err != nil {
+		return nil, err
+	}
+	if sep.Type != table.TypeString {
+		return nil, fmt.Errorf("string_agg() separator must be a string, got %s", sep.AsString())
+	}
+	return &stringAggAccumulator{arg: args[0], sep: sep.Str}, nil
+}
+
+// collectAccumulator gathers non-null values in input order. collect_set()
+// keeps the first of each canonical key, like distinct does.
+type collectAccumulator struct {
+	name   string
+	arg    rowValueEvaluator
+	seen   map[string]struct{}
+	sorted bool
+	desc   bool
+	values []table.Value
+}
+
+func (a *collectAccumulator) Update(row int) error {
+	v, err := a.arg(row)
+	if err != nil || v.IsNull() {
+		return err
+	}
+	if a.seen != nil {
+		key := table.CanonicalKey(v)
+		if _, ok := a.seen[key]; ok {
+			return nil
+		}
+		a.seen[key] = struct{}{}
+	}
+	a.values = append(a.values, v)
+	return nil
+}
+
+func (a *collectAccumulator) Finalize() (table.Value, error) {
+	if a.sorted {
+		var cmpErr error
+		sort.SliceStable(a.values, func(i, j int) bool {
+			cmp, _, err := expressionValuesCompare(a.values[i], a.values[j])
+			if err != nil && cmpErr == nil {
+				cmpErr = fmt.Errorf("%s: %s", a.name, err)
+			}
+			if a.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+		if cmpErr != nil {
+			return table.Null(), cmpErr
+		}
+	}
+	values := a.values
+	if values == nil {
+		values = []table.Value{}
+	}
+	return table.ListVal(values), nil
+}
+
+// stringAggAccumulator joins non-null strings in input order. A group with no
+// strings gives null rather than "".
+type stringAggAccumulator struct {
+	arg  rowValueEvaluator
+	sep  string
+	b    strings.Builder
+	seen bool
+}
+
+func (a *stringAggAccumulator) Update(row int) error {
+	v, err := a.arg(row)
+	if err != nil || v.IsNull() {
+		return err
+	}
+	if v.Type != table.TypeString {
+		return fmt.Errorf("string_agg: non-string value %v", v.AsString())
+	}
+	if a.seen {
+		a.b.WriteString(a.sep)
+	}
+	a.b.WriteString(v.Str)
+	a.seen = true
+	return nil
+}
+
+func (a *stringAggAccumulator) Finalize() (table.Value, error) {
+	if !a.seen {
+		return table.Null(), nil
+	}
+	return table.StrVal(a.b.String()), nil
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+const ordersCSV = "customer,city,amount\n" +
+	"ann,NY,3\n" +
+	"ann,LA,1\n" +
+	"ann,NY,2\n" +
+	"bob,,5\n"
+
+func TestCollectAggregatesInFusedGroupReduce(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.csv", ordersCSV)
+	result := loadAndQuery(t, path, `group customer | reduce
+		cities = collect_set(city),
+		sorted = collect_set(city, "asc"),
+		amounts = collect_list(amount),
+		largest = collect_list(amount, "desc"),
+		label = string_agg(city, ", ")
+	| remove grouped | sort customer`)
+	assertColumnStrings(t, result, "cities", "[NY, LA]", "[]")
+	assertColumnStrings(t, result, "sorted", "[LA, NY]", "[]")
+	assertColumnStrings(t, result, "amounts", "[3, 1, 2]", "[5]")
+	assertColumnStrings(t, result, "largest", "[3, 2, 1]", "[5]")
+	assertColumnStrings(t, result, "label", "NY, LA, NY", "null")
+	assertColumnSchema(t, result, "cities", "list<string>")
+	assertColumnSchema(t, result, "amounts", "list<int>")
+	assertColumnSchema(t, result, "label", "string?")
+}
+
+func TestCollectAggregateErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.csv", ordersCSV)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`group customer | reduce v = collect_list(amount, "up")`, `collect_list() order must be "asc" or "desc", got up`},
+		{`group customer | reduce v = collect_list(amount, "asc", 1)`, "collect_list() takes 1 to 2 arguments, got 3"},
+		{`group customer | reduce v = string_agg(amount, ",")`, "string_agg() requires a string column, got int"},
+		{`group customer | reduce v = string_agg(city, 1)`, "string_agg() separator must be a string, got 1"},
+		{`group customer | reduce v = string_agg(city)`, "string_agg() takes 2 arguments, got 1"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+
+	list := typedExpr{typ: &table.TypeDescriptor{Kind: table.TypeList, Elem: &table.TypeDescriptor{Kind: table.TypeInt}}}
+	order := typedExpr{raw: &ast.LiteralExpr{Kind: "string", Str: "asc"}, typ: &table.TypeDescriptor{Kind: table.TypeString}}
+	if _, err := checkCollectSignature("collect_set", []typedExpr{list, order}); err == nil || !strings.Contains(err.Error(), "collect_set() can only sort an orderable column, got list<int>") {
+		t.Fatalf("sorted list of lists error = %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+func TestAggregateWhereFiltersRowsPerAggregate(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "latency.csv", latencyCSV)
+	query := `reduce
+		n = count(),
+		slow = count() where { status == "slow" },
+		slow_ms = sum(ms) where { status == "slow" or ms >= 10 },
+		fast_share = count() where { ms < 3 } / count(),
+		late = first(status) where { ms > 2 }
+	| remove grouped | sort route`
+	for _, prefix := range []string{"group route | ", "group route | filter { true } | "} {
+		result := loadAndQuery(t, path, prefix+query)
+		assertColumnStrings(t, result, "n", "4", "2", "1")
+		assertColumnStrings(t, result, "slow", "2", "0", "0")
+		assertColumnStrings(t, result, "slow_ms", "15", "null", "null")
+		assertColumnStrings(t, result, "fast_share", "0.5", "0", "0")
+		assertColumnStrings(t, result, "late", "slow", "ok", "null")
+		assertColumnSchema(t, result, "slow", "int")
+	}
+}
+
+func TestAggregateWhereErrors(t *testing.T) {
+	input := table.NewTable([]string{"city", "age"})
+	input.AddRow([]table.Value{table.StrVal("NY"), table.IntVal(30)})
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`group city | reduce n = count() where { age }`, "count() where condition must be boolean, got int"},
+		{`group city | reduce n = count() where { missing > 1 }`, `count() where: column "missing" not found`},
+		{`transform n = upper(city) where { age > 1 }`, "upper(): where { ... } is only allowed on aggregate functions in 'reduce'"},
+		{`window n = row_number() where { age > 1 }`, "row_number(): where { ... } is only allowed on aggregate functions in 'reduce'"},
+	}
+	for _, tc := range cases {
+		err := runQueryExpectErr(t, input, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
@@ -17,14 +17,15 @@ type aggregateRuntimeSlot struct {
 	name      string
 	aggregate *aggregateSpec
 	args      []rowValueEvaluator
+	filter    rowPredicateEvaluator
 }
 
 func evalAggregateWithSpec(e *ast.FuncCallExpr, nested *table.Table, spec *aggregateSpec) (table.Value, error) {
 	if spec == nil || spec.NewAccumulator == nil {
 		return table.Null(), nonAggregateReduceFunctionError(e.Name)
 	}
-	if len(e.Args) != spec.Arity {
-		return table.Null(), aggregateArityError(e.Name, spec.Arity, len(e.Args))
+	if err := spec.checkArity(e.Name, len(e.Args)); err != nil {
+		return table.Null(), err
 	}
 	if nested == nil {
 		return table.Null(), fmt.Errorf("%s(): nil nested table", e.Name)
@@ -33,7 +34,41 @@ func evalAggregateWithSpec(e *ast.FuncCallExpr, nested *table.Table, spec *aggre
 	if err != nil {
 		return table.Null(), err
 	}
-	acc, err := newAggregateAccumulator(aggregateRuntimeSlot{name: e.Name, aggregate: spec, args: args})
+	return runAggregateSlot(aggregateRuntimeSlot{name: e.Name, aggregate: spec, args: args}, nested)
+}
+
+// evalTypedAggregateCall runs a planned aggregate call over one group's
+// nested rows. Its arguments and where filter were bound to the nested row
+// schema, so they compile directly against the nested table. A call without
+// typed arguments resolves its raw column arguments by name instead.
+func evalTypedAggregateCall(expr typedExpr, call *boundCall, nested *table.Table) (table.Value, error) {
+	if len(expr.args) != len(call.raw.Args) {
+		return evalAggregateCall(call.raw, nested)
+	}
+	spec, ok := builtinCatalog[call.raw.Name]
+	if !ok || spec.Category != builtinAggregate || spec.Aggregate == nil {
+		return table.Null(), nonAggregateReduceFunctionError(call.raw.Name)
+	}
+	if nested == nil {
+		return table.Null(), fmt.Errorf("%s(): nil nested table", call.raw.Name)
+	}
+	slot := aggregateRuntimeSlot{name: call.raw.Name, aggregate: spec.Aggregate, args: compileAggregateArgs(expr.args, nested)}
+	if expr.filter != nil {
+		slot.filter = compileFilterPredicate(*expr.filter, nested)
+	}
+	return runAggregateSlot(slot, nested)
+}
+
+func compileAggregateArgs(args []typedExpr, t *table.Table) []rowValueEvaluator {
+	out := make([]rowValueEvaluator, len(args))
+	for i := range args {
+		out[i] = compileTypedRowValue(args[i], t)
+	}
+	return out
+}
+
+func runAggregateSlot(slot aggregateRuntimeSlot, nested *table.Table) (table.Value, error) {
+	acc, err := newAggregateAccumulator(slot)
 	if err != nil {
 		return table.Null(), err
 	}
@@ -48,8 +83,15 @@ func evalAggregateWithSpec(e *ast.FuncCallExpr, nested *table.Table, spec *aggre
 func aggregateCallArgEvaluators(e *ast.FuncCallExpr, nested *table.Table) ([]rowValueEvaluator, error) {
 	args := make([]rowValueEvaluator, len(e.Args))
 	for i, arg := range e.Args {
+		if lit, ok := arg.(*ast.LiteralExpr); ok && i > 0 {
+			args[i] = constantRowValue(evalLiteral(lit))
+			continue
+		}
 		colExpr, ok := arg.(*ast.ColumnExpr)
 		if !ok {
+			if i > 0 {
+				return nil, aggregateParamError(e.Name)
+			}
 			return nil, fmt.Errorf("%s() argument must be a column reference", e.Name)
 		}
 		path := append([]string(nil), colExpr.Path...)
@@ -80,10 +122,48 @@ func newAggregateAccumulator(slot aggregateRuntimeSlot) (aggregateAccumulator, e
 	if slot.aggregate == nil || slot.aggregate.NewAccumulator == nil {
 		return nil, fmt.Errorf("unknown aggregate function %q", slot.name)
 	}
-	if len(slot.args) != slot.aggregate.Arity {
-		return nil, aggregateArityError(slot.name, slot.aggregate.Arity, len(slot.args))
+	if err := slot.aggregate.checkArity(slot.name, len(slot.args)); err != nil {
+		return nil, err
+	}
+	acc, err := slot.aggregate.NewAccumulator(slot.args)
+	if err != nil || slot.filter == nil {
+		return acc, err
+	}
+	return &filteredAccumulator{inner: acc, filter: slot.filter}, nil
+}
+
+// filteredAccumulator implements "agg(x) where { cond }": only rows where cond
+// is true reach the inner accumulator, so an aggregate that sees no rows
+// returns its empty result (0 for count, null for most others).
+type filteredAccumulator struct {
+	inner  aggregateAccumulator
+	filter rowPredicateEvaluator
+}
+
+func (a *filteredAccumulator) Update(row int) error {
+	ok, err := a.filter(row)
+	if err != nil || !ok {
+		return err
 	}
-	return slot.aggregate.NewAccumulator(slot.args)
+	return a.inner.Update(row)
+}
+
+func (a *filteredAccumulator) Finalize() (table.Value, error) {
+	return a.inner.Finalize()
+}
+
+func (s *aggregateSpec) checkArity(name string, got int) error {
+	if got < s.MinArity || got > s.MaxArity {
+		return arityRangeError(name, s.MinArity, s.MaxArity, got)
+	}
+	return nil
+}
+
+func arityRangeError(name string, minArity, maxArity, got int) error {
+	if minArity == maxArity {
+		return aggregateArityError(name, minArity, got)
+	}
+	return fmt.Errorf("%s() takes %d to %d arguments, got %d", name, minArity, maxArity, got)
 }
 
 func aggregateArityError(name string, want, got int) error {
@@ -96,6 +176,18 @@ func aggregateArityError(name string, want, got int) error {
 	return fmt.Errorf("%s() takes %d arguments, got %d", name, want, got)
 }
 
+// aggregateParamError reports a non-literal parameter such as the fraction of
+// percentile(); only the first aggregate argument reads rows.
+func aggregateParamError(name string) error {
+	return fmt.Errorf("%s() parameters after the first argument must be literals", name)
+}
+
+func constantRowValue(v table.Value) rowValueEvaluator {
+	return func(int) (table.Value, error) {
+		return v, nil
+	}
+}
+
 func requireAggregateArgCount(name string, args []rowValueEvaluator, want int) error {
 	if len(args) != want {
 		return aggregateArityError(name, want, len(args))
@@ -114,14 +206,14 @@ func newSumAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
 	if err := requireAggregateArgCount("sum", args, 1); err != nil {
 		return nil, err
 	}
-	return &sumAccumulator{arg: args[0], hasInt: true}, nil
+	return &sumAccumulator{name: "sum", arg: args[0], hasInt: true}, nil
 }
 
 func newAvgAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
 	if err := requireAggregateArgCount("avg", args, 1); err != nil {
 		return nil, err
 	}
-	return &avgAccumulator{arg: args[0]}, nil
+	return &avgAccumulator{arg: args[0], total: sumAccumulator{name: "avg", hasInt: true}}, nil
 }
 
 func newMinAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
@@ -165,13 +257,19 @@ func (a *countAccumulator) Finalize() (table.Value, error) {
 	return table.IntVal(a.n), nil
 }
 
+// sumAccumulator keeps a float sum alongside an exact one: an int sum while
+// every value is an int, and a decimal sum once a decimal arrives. A float
+// value makes the result float.
 type sumAccumulator struct {
+	name        string
 	arg         rowValueEvaluator
 	sum         float64
 	hasInt      bool
 	intSum      int64
 	intOverflow error
-	any         bool
+	decimal     *decimalSum
+	hasFloat    bool
+	count       int64
 }
 
 func (a *sumAccumulator) Update(row int) error {
@@ -179,34 +277,52 @@ func (a *sumAccumulator) Update(row int) error {
 	if err != nil {
 		return err
 	}
+	return a.add(v)
+}
+
+func (a *sumAccumulator) add(v table.Value) error {
 	if v.IsNull() {
 		return nil
 	}
 	f, ok := v.AsFloat()
 	if !ok {
-		return fmt.Errorf("sum: non-numeric value %v", v.AsString())
+		return fmt.Errorf("%s: non-numeric value %v", a.name, v.AsString())
 	}
 	a.sum += f
-	a.any = true
-	if v.Type == table.TypeInt {
+	a.count++
+	switch {
+	case v.Type == table.TypeDecimal && a.decimal == nil:
+		a.decimal = &decimalSum{}
+		if a.hasInt && a.intOverflow == nil {
+			a.decimal.add(table.IntVal(a.intSum))
+		}
+		a.hasInt = false
+		a.decimal.add(v)
+	case a.decimal != nil && (v.Type == table.TypeDecimal || v.Type == table.TypeInt):
+		a.decimal.add(v)
+	case v.Type == table.TypeInt:
 		if a.hasInt && a.intOverflow == nil {
 			next, err := evalIntArith("+", a.intSum, v.Int)
 			if err != nil {
-				a.intOverflow = fmt.Errorf("sum: %w", err)
+				a.intOverflow = fmt.Errorf("%s: %w", a.name, err)
 			} else {
 				a.intSum = next
 			}
 		}
-	} else {
+	default:
 		a.hasInt = false
+		a.hasFloat = true
 	}
 	return nil
 }
 
 func (a *sumAccumulator) Finalize() (table.Value, error) {
-	if !a.any {
+	if a.count == 0 {
 		return table.Null(), nil
 	}
+	if a.decimal != nil && !a.hasFloat {
+		return a.decimal.value()
+	}
 	if a.hasInt {
 		if a.intOverflow != nil {
 			return table.Null(), a.intOverflow
@@ -216,10 +332,11 @@ func (a *sumAccumulator) Finalize() (table.Value, error) {
 	return table.FloatVal(a.sum), nil
 }
 
+// avgAccumulator divides a running sum by the non-null count. Decimal inputs
+// average exactly; everything else averages in float.
 type avgAccumulator struct {
 	arg   rowValueEvaluator
-	sum   float64
-	count int
+	total sumAccumulator
 }
 
 func (a *avgAccumulator) Update(row int) error {
@@ -227,23 +344,17 @@ func (a *avgAccumulator) Update(row int) error {
 	if err != nil {
 		return err
 	}
-	if v.IsNull() {
-		return nil
-	}
-	f, ok := v.AsFloat()
-	if !ok {
-		return fmt.Errorf("avg: non-numeric value %v", v.AsString())
-	}
-	a.sum += f
-	a.count++
-	return nil
+	return a.total.add(v)
 }
 
 func (a *avgAccumulator) Finalize() (table.Value, error) {
-	if a.count == 0 {
+	if a.total.count == 0 {
 		return table.Null(), nil
 	}
-	return table.FloatVal(a.sum / float64(a.count)), nil
+	if a.total.decimal != nil && !a.total.hasFloat {
+		return a.total.decimal.avg(a.total.count)
+	}
+	return table.FloatVal(a.total.sum / float64(a.total.count)), nil
 }
 
 type extremumAccumulator struct {
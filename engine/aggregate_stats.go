@@ -0,0 +1,289 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// checkPercentileFraction requires the fraction of percentile() and
+// approx_percentile() to be a numeric literal between 0 and 1, so a bad
+// fraction fails before any rows are read.
+func checkPercentileFraction(name string, arg typedExpr) error {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok {
+		return aggregateParamError(name)
+	}
+	_, err := percentileFraction(name, evalLiteral(lit))
+	return err
+}
+
+func percentileFraction(name string, v table.Value) (float64, error) {
+	f, ok := v.AsFloat()
+	if v.IsNull() || !ok || f < 0 || f > 1 {
+		return 0, fmt.Errorf("%s() fraction must be a number between 0 and 1, got %s", name, v.AsString())
+	}
+	return f, nil
+}
+
+func newMedianAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("median", args, 1); err != nil {
+		return nil, err
+	}
+	return &percentileAccumulator{name: "median", arg: args[0], fraction: 0.5}, nil
+}
+
+func newPercentileAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("percentile", args, 2); err != nil {
+		return nil, err
+	}
+	fraction, err := constantPercentileFraction("percentile", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return &percentileAccumulator{name: "percentile", arg: args[0], fraction: fraction}, nil
+}
+
+func newApproxPercentileAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("approx_percentile", args, 2); err != nil {
+		return nil, err
+	}
+	fraction, err := constantPercentileFraction("approx_percentile", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return &approxPercentileAccumulator{arg: args[0], fraction: fraction, digest: newTDigest(defaultTDigestCompression)}, nil
+}
+
+func constantPercentileFraction(name string, arg rowValueEvaluator) (float64, error) {
+	v, err := arg(0)
+	if err != nil {
+		return 0, err
+	}
+	return percentileFraction(name, v)
+}
+
+func newVarAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("var", args, 1); err != nil {
+		return nil, err
+	}
+	return &varianceAccumulator{name: "var", arg: args[0]}, nil
+}
+
+func newStddevAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("stddev", args, 1); err != nil {
+		return nil, err
+	}
+	return &varianceAccumulator{name: "stddev", arg: args[0], sqrt: true}, nil
+}
+
+func newModeAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("mode", args, 1); err != nil {
+		return nil, err
+	}
+	return &modeAccumulator{arg: args[0], index: map[string]int{}}, nil
+}
+
+// numericAggregateInput reads a row value as float64. ok is false for nulls,
+// which every statistical aggregate skips.
+func numericAggregateInput(name string, arg rowValueEvaluator, row int) (float64, bool, error) {
+	v, err := arg(row)
+	if err != nil {
+		return 0, false, err
+	}
+	if v.IsNull() {
+		return 0, false, nil
+	}
+	f, ok := v.AsFloat()
+	if !ok {
+		return 0, false, fmt.Errorf("%s: non-numeric value %v", name, v.AsString())
+	}
+	return f, true, nil
+}
+
+// percentileAccumulator keeps every non-null value and interpolates linearly
+// between the two closest ranks, so median() of an even count is the mean of
+// the middle pair.
+type percentileAccumulator struct {
+	name     string
+	arg      rowValueEvaluator
+	fraction float64
+	values   []float64
+}
+
+func (a *percentileAccumulator) Update(row int) error {
+	f, ok, err := numericAggregateInput(a.name, a.arg, row)
+	if err != nil || !ok {
+		return err
+	}
+	a.values = append(a.values, f)
+	return nil
+}
+
+func (a *percentileAccumulator) Finalize() (table.Value, error) {
+	if len(a.values) == 0 {
+		return table.Null(), nil
+	}
+	sort.Float64s(a.values)
+	rank := a.fraction * float64(len(a.values)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return table.FloatVal(a.values[lo] + (a.values[hi]-a.values[lo])*(rank-float64(lo))), nil
+}
+
+// approxPercentileAccumulator summarizes values in a t-digest, trading exact
+// ranks for memory that stays bounded however large the group grows.
+type approxPercentileAccumulator struct {
+	arg      rowValueEvaluator
+	fraction float64
+	digest   *tdigest
+}
+
+func (a *approxPercentileAccumulator) Update(row int) error {
+	f, ok, err := numericAggregateInput("approx_percentile", a.arg, row)
+	if err != nil || !ok {
+		return err
+	}
+	a.digest.add(f)
+	return nil
+}
+
+func (a *approxPercentileAccumulator) Finalize() (table.Value, error) {
+	if a.digest.count() == 0 {
+		return table.Null(), nil
+	}
+	return table.FloatVal(a.digest.quantile(a.fraction)), nil
+}
+
+// varianceAccumulator computes the sample variance (n-1 denominator) with
+// Welford's online update, which stays accurate when values are large and
+// close together.
+type varianceAccumulator struct {
+	name string
+	arg  rowValueEvaluator
+	sqrt bool
+	n    int64
+	mean float64
+	m2   float64
+}
+
+func (a *varianceAccumulator) Update(row int) error {
+	f, ok, err := numericAggregateInput(a.name, a.arg, row)
+	if err != nil || !ok {
+		return err
+	}
+	a.n++
+	delta := f - a.mean
+	a.mean += delta / float64(a.n)
+	a.m2 += delta * (f - a.mean)
+	return nil
+}
+
+func (a *varianceAccumulator) Finalize() (table.Value, error) {
+	if a.n < 2 {
+		return table.Null(), nil
+	}
+	variance := a.m2 / float64(a.n-1)
+	if a.sqrt {
+		return table.FloatVal(math.Sqrt(variance)), nil
+	}
+	return table.FloatVal(variance), nil
+}
+
+// modeAccumulator counts values by canonical key. Ties go to the value seen
+// first, so the result does not depend on map order.
+type modeAccumulator struct {
+	arg    rowValueEvaluator
+	index  map[string]int
+	values []table.Value
+	counts []int64
+}
+
+func (a *modeAccumulator) Update(row int) error {
+	v, err := a.arg(row)
+	if err != nil {
+		return err
+	}
+	if v.IsNull() {
+		return nil
+	}
+	key := table.CanonicalKey(v)
+	if i, ok := a.index[key]; ok {
+		a.counts[i]++
+		return nil
+	}
+	a.index[key] = len(a.values)
+	a.values = append(a.values, v)
+	a.counts = append(a.counts, 1)
+	return nil
+}
+
+func (a *modeAccumulator) Finalize() (table.Value, error) {
+	best := -1
+	for i, n := range a.counts {
+		if best < 0 || n > a.counts[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return table.Null(), nil
+	}
+	return a.values[best], nil
+}
+
+func newCountDistinctAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("count_distinct", args, 1); err != nil {
+		return nil, err
+	}
+	return &countDistinctAccumulator{arg: args[0], seen: map[string]struct{}{}}, nil
+}
+
+func newApproxCountDistinctAccumulator(args []rowValueEvaluator) (aggregateAccumulator, error) {
+	if err := requireAggregateArgCount("approx_count_distinct", args, 1); err != nil {
+		return nil, err
+	}
+	return &approxCountDistinctAccumulator{arg: args[0], sketch: newHyperLogLog()}, nil
+}
+
+// countDistinctAccumulator counts non-null values by the same canonical key
+// that distinct and group use, so 1.50 and 1.5 or equal lists count once.
+type countDistinctAccumulator struct {
+	arg  rowValueEvaluator
+	seen map[string]struct{}
+}
+
+func (a *countDistinctAccumulator) Update(row int) error {
+	v, err := a.arg(row)
+	if err != nil || v.IsNull() {
+		return err
+	}
+	a.seen[table.CanonicalKey(v)] = struct{}{}
+	return nil
+}
+
+func (a *countDistinctAccumulator) Finalize() (table.Value, error) {
+	return table.IntVal(int64(len(a.seen))), nil
+}
+
+// approxCountDistinctAccumulator feeds the same canonical keys into a
+// HyperLogLog sketch, which uses fixed memory however many values it sees.
+type approxCountDistinctAccumulator struct {
+	arg    rowValueEvaluator
+	sketch *hyperLogLog
+}
+
+func (a *approxCountDistinctAccumulator) Update(row int) error {
+	v, err := a.arg(row)
+	if err != nil || v.IsNull() {
+		return err
+	}
+	a.sketch.add(table.CanonicalKey(v))
+	return nil
+}
+
+func (a *approxCountDistinctAccumulator) Finalize() (table.Value, error) {
+	return table.IntVal(a.sketch.estimate()), nil
+}
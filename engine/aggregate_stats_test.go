@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+const latencyCSV = "route,ms,status\n" +
+	"a,1,ok\n" +
+	"a,2,slow\n" +
+	"a,3,slow\n" +
+	"a,10,\n" +
+	"b,5,ok\n" +
+	"b,,ok\n" +
+	"c,,\n"
+
+func TestStatisticalAggregatesInFusedGroupReduce(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "latency.csv", latencyCSV)
+	result := loadAndQuery(t, path, `group route | reduce
+		med = median(ms),
+		p90 = percentile(ms, 0.9),
+		approx = approx_percentile(ms, 0.9),
+		sd = stddev(ms),
+		v = var(ms),
+		common = mode(status),
+		spread = percentile(ms, 1) - percentile(ms, 0)
+	| remove grouped | sort route`)
+	assertColumnStrings(t, result, "med", "2.5", "5", "null")
+	assertColumnStrings(t, result, "p90", "7.900000000000001", "5", "null")
+	assertColumnStrings(t, result, "approx", "7.900000000000001", "5", "null")
+	assertColumnStrings(t, result, "sd", "4.08248290463863", "null", "null")
+	assertColumnStrings(t, result, "v", "16.666666666666668", "null", "null")
+	assertColumnStrings(t, result, "common", "slow", "ok", "null")
+	assertColumnStrings(t, result, "spread", "9", "0", "null")
+	for _, name := range []string{"med", "p90", "approx", "sd", "v"} {
+		assertColumnSchema(t, result, name, "float?")
+	}
+	assertColumnSchema(t, result, "common", "string?")
+}
+
+func TestStatisticalAggregatesOnDecimals(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", decimalCSV)
+	result := loadAndQuery(t, path+" with decimals=true", "group item | reduce med = median(price), top = mode(price) | remove grouped | sort item")
+	assertColumnStrings(t, result, "med", "5.3125", "3.333", "null")
+	assertColumnStrings(t, result, "top", "10.500", "3.333", "null")
+	assertColumnSchema(t, result, "top", "decimal(5,3)?")
+}
+
+func TestStatisticalAggregateErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "latency.csv", latencyCSV)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`group route | reduce p = percentile(ms, 1.5)`, "percentile() fraction must be a number between 0 and 1, got 1.5"},
+		{`group route | reduce p = approx_percentile(ms, "x")`, "approx_percentile() fraction must be a number between 0 and 1"},
+		{`group route | reduce p = percentile(ms, route)`, "percentile() parameters after the first argument must be literals"},
+		{`group route | reduce p = percentile(ms)`, "percentile() takes 2 arguments, got 1"},
+		{`group route | reduce p = median(status)`, "median() requires a numeric column, got string?"},
+		{`group route | reduce p = stddev(median(ms))`, "stddev(): aggregate median() cannot be nested inside another aggregate"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
+
+func TestStatisticalAggregatesUnfusedEvaluation(t *testing.T) {
+	nested := table.NewTable([]string{"ms"})
+	for _, v := range []int64{4, 1, 3, 2} {
+		nested.AddRow([]table.Value{table.IntVal(v)})
+	}
+	col := &ast.ColumnExpr{Path: []string{"ms"}}
+	got, err := evalAggregateCall(&ast.FuncCallExpr{Name: "percentile", Args: []ast.Expr{col, &ast.LiteralExpr{Kind: "float", Float: 0.25}}}, nested)
+	if err != nil || got.AsString() != "1.75" {
+		t.Fatalf("percentile = %s, %v", got.AsString(), err)
+	}
+	got, err = evalAggregateCall(&ast.FuncCallExpr{Name: "var", Args: []ast.Expr{col}}, nested)
+	if err != nil || math.Abs(got.Float-5.0/3) > 1e-12 {
+		t.Fatalf("var = %s, %v", got.AsString(), err)
+	}
+}
+
+func TestTDigestQuantilesStayCloseToExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 100000
+	digest := newTDigest(defaultTDigestCompression)
+	for _, i := range rng.Perm(n) {
+		digest.add(float64(i))
+	}
+	for _, q := range []float64{0, 0.001, 0.01, 0.5, 0.95, 0.99, 1} {
+		exact := q * (n - 1)
+		if got := digest.quantile(q); math.Abs(got-exact) > n*0.002 {
+			t.Errorf("quantile(%v) = %v, want about %v", q, got, exact)
+		}
+	}
+	if len(digest.centroids) > 4*defaultTDigestCompression {
+		t.Fatalf("digest kept %d centroids", len(digest.centroids))
+	}
+}
+
+func TestCountDistinctUsesGroupKeySemantics(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", "day,user,amount\n1,ann,1.5\n1,bob,1.50\n1,ann,2\n1,,\n2,cat,3\n")
+	result := loadAndQuery(t, path+" with decimals=true", `group day | reduce
+		users = count_distinct(user),
+		approx_users = approx_count_distinct(user),
+		amounts = count_distinct(amount)
+	| remove grouped | sort day`)
+	assertColumnStrings(t, result, "users", "2", "1")
+	assertColumnStrings(t, result, "approx_users", "2", "1")
+	assertColumnStrings(t, result, "amounts", "2", "1")
+	assertColumnSchema(t, result, "users", "int")
+	assertColumnSchema(t, result, "approx_users", "int")
+}
+
+func TestHyperLogLogEstimateStaysClose(t *testing.T) {
+	sketch := newHyperLogLog()
+	const n = 200000
+	for i := 0; i < n; i++ {
+		key := table.CanonicalKey(table.IntVal(int64(i)))
+		sketch.add(key)
+		sketch.add(key)
+	}
+	if got := sketch.estimate(); math.Abs(float64(got-n)) > n*0.03 {
+		t.Fatalf("estimate = %d, want about %d", got, n)
+	}
+	if got := newHyperLogLog().estimate(); got != 0 {
+		t.Fatalf("empty estimate = %d", got)
+	}
+}
+
+func TestHyperLogLogSparseMatchesDense(t *testing.T) {
+	sparse := newHyperLogLog()
+	dense := newHyperLogLog()
+	dense.densify()
+	for i := 0; i < 1000; i++ {
+		key := table.CanonicalKey(table.IntVal(int64(i)))
+		sparse.add(key)
+		dense.add(key)
+	}
+	if sparse.registers != nil {
+		t.Fatalf("1000 keys should stay sparse, got %d sparse entries", len(sparse.sparse))
+	}
+	if got, want := sparse.estimate(), dense.estimate(); got != want {
+		t.Fatalf("sparse estimate = %d, dense estimate = %d", got, want)
+	}
+	for i := 1000; i < 5000; i++ {
+		sparse.add(table.CanonicalKey(table.IntVal(int64(i))))
+	}
+	if sparse.registers == nil || sparse.sparse != nil {
+		t.Fatalf("5000 keys should switch to dense registers")
+	}
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/razeghi71/dq/table"
+)
+
+// asofGroup holds the right rows of one key with a non-null match value,
+// sorted by that value. Rows with equal values keep their input order.
+type asofGroup struct {
+	rows   []int
+	values []table.Value
+}
+
+// asofJoinRows emits every left row once, paired with the last right row of
+// the same key whose match value is <= the left match value, or with no row
+// when there is none or it is further away than the tolerance.
+func asofJoinRows(p plannedJoin, left, right *table.Table, rightIndex map[string][]int, emit func(leftRow, rightRow int) error) error {
+	groups := make(map[string]*asofGroup, len(rightIndex))
+	for li := 0; li < left.NumRows; li++ {
+		key, ok, err := joinKeyAt(left, p.leftKeys, li)
+		if err != nil {
+			return fmt.Errorf("join: %w", err)
+		}
+		ri := -1
+		if ok {
+			group, err := asofGroupFor(p.asof, right, rightIndex, groups, key)
+			if err != nil {
+				return err
+			}
+			ri, err = asofMatch(p.asof, left, li, group)
+			if err != nil {
+				return err
+			}
+		}
+		if err := emit(li, ri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func asofGroupFor(asof *plannedJoinAsof, right *table.Table, rightIndex map[string][]int, groups map[string]*asofGroup, key string) (*asofGroup, error) {
+	if group, ok := groups[key]; ok {
+		return group, nil
+	}
+	group := &asofGroup{}
+	for _, ri := range rightIndex[key] {
+		v, err := resolveBoundColumn(asof.right, right, ri)
+		if err != nil {
+			return nil, fmt.Errorf("join: asof match: %w", err)
+		}
+		if v.IsNull() {
+			continue
+		}
+		group.rows = append(group.rows, ri)
+		group.values = append(group.values, v)
+	}
+	sort.Stable(group)
+	groups[key] = group
+	return group, nil
+}
+
+func (g *asofGroup) Len() int           { return len(g.rows) }
+func (g *asofGroup) Less(i, j int) bool { return compareValues(g.values[i], g.values[j]) < 0 }
+func (g *asofGroup) Swap(i, j int) {
+	g.rows[i], g.rows[j] = g.rows[j], g.rows[i]
+	g.values[i], g.values[j] = g.values[j], g.values[i]
+}
+
+// asofMatch returns the right row matched by left row li, or -1.
+func asofMatch(asof *plannedJoinAsof, left *table.Table, li int, group *asofGroup) (int, error) {
+	v, err := resolveBoundColumn(asof.left, left, li)
+	if err != nil {
+		return -1, fmt.Errorf("join: asof match: %w", err)
+	}
+	if v.IsNull() || group.Len() == 0 {
+		return -1, nil
+	}
+	idx := sort.Search(group.Len(), func(i int) bool {
+		return compareValues(group.values[i], v) > 0
+	}) - 1
+	if idx < 0 || !asofWithinTolerance(v, group.values[idx], asof.tolerance) {
+		return -1, nil
+	}
+	return group.rows[idx], nil
+}
+
+// asofWithinTolerance reports whether left - right is at most tolerance.
+// Temporal gaps compare as durations: dates count whole days, timestamps and
+// durations microseconds.
+func asofWithinTolerance(left, right, tolerance table.Value) bool {
+	if tolerance.IsNull() {
+		return true
+	}
+	if tolerance.Type == table.TypeDuration {
+		gap := left.Int - right.Int
+		if left.Type == table.TypeDate {
+			return gap <= tolerance.Int/microsPerDay
+		}
+		return gap <= tolerance.Int
+	}
+	if left.Type == table.TypeInt && right.Type == table.TypeInt && tolerance.Type == table.TypeInt {
+		return left.Int-right.Int <= tolerance.Int
+	}
+	l, _ := left.AsFloat()
+	r, _ := right.AsFloat()
+	t, _ := tolerance.AsFloat()
+	return l-r <= t
+}
@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/parser"
+	"github.com/razeghi71/dq/table"
+)
+
+func asofTradesTable() *table.Table {
+	t := table.NewTable([]string{"symbol", "ts", "qty"})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(10), table.IntVal(1)})
+	t.AddRow([]table.Value{table.StrVal("B"), table.IntVal(10), table.IntVal(2)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(3), table.IntVal(3)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(1), table.IntVal(4)})
+	t.AddRow([]table.Value{table.StrVal("C"), table.IntVal(5), table.IntVal(5)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.Null(), table.IntVal(6)})
+	return t
+}
+
+func asofQuotesTable() *table.Table {
+	t := table.NewTable([]string{"symbol", "ts", "price"})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(8), table.FloatVal(1.8)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(2), table.FloatVal(1.2)})
+	t.AddRow([]table.Value{table.StrVal("B"), table.IntVal(4), table.FloatVal(2.4)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.IntVal(8), table.FloatVal(1.9)})
+	t.AddRow([]table.Value{table.StrVal("A"), table.Null(), table.FloatVal(9.9)})
+	return t
+}
+
+func runAsofQuery(t *testing.T, query string) (*table.Table, error) {
+	t.Helper()
+	q, err := parser.Parse("trades.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return Execute(q, asofTradesTable(), func(string, ast.LoadOptions) (*table.Table, error) {
+		return asofQuotesTable(), nil
+	})
+}
+
+func TestAsofJoinTakesLatestPrecedingRowPerKey(t *testing.T) {
+	result, err := runAsofQuery(t, "join asof quotes.csv on symbol match ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(result.Columns, ",") != "symbol,ts,qty,quotes_ts,price" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "qty", "1", "2", "3", "4", "5", "6")
+	assertColumnStrings(t, result, "quotes_ts", "8", "4", "2", "null", "null", "null")
+	// Equal right timestamps resolve to the later right row.
+	assertColumnStrings(t, result, "price", "1.9", "2.4", "1.2", "null", "null", "null")
+	assertColumnSchema(t, result, "price", "float?")
+}
+
+func TestAsofJoinTolerance(t *testing.T) {
+	result, err := runAsofQuery(t, "join asof quotes.csv on symbol match ts tolerance 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertColumnStrings(t, result, "price", "1.9", "null", "1.2", "null", "null", "null")
+}
+
+// asofTemporalTable builds a one-column table of dates or timestamps.
+func asofTemporalTable(t *testing.T, col, kind string, cells ...string) *table.Table {
+	t.Helper()
+	schema, _ := table.ParseSchema(kind)
+	tbl := table.NewTableWithSchemas([]string{col}, []*table.TypeDescriptor{schema})
+	for _, cell := range cells {
+		v, ok := table.ParseTimestamp(cell)
+		if kind == "date" {
+			v, ok = table.ParseDate(cell)
+		}
+		if !ok {
+			t.Fatalf("bad %s %q", kind, cell)
+		}
+		if err := tbl.AddRowTyped([]table.Value{v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tbl
+}
+
+func runTemporalAsofQuery(t *testing.T, left, right *table.Table, query string) (*table.Table, error) {
+	t.Helper()
+	q, err := parser.Parse("trades.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return Execute(q, left, func(string, ast.LoadOptions) (*table.Table, error) {
+		return right, nil
+	})
+}
+
+func TestAsofJoinTemporalTolerance(t *testing.T) {
+	trades := asofTemporalTable(t, "at", "timestamp", "2024-03-01T10:00:00Z", "2024-03-01T10:10:00Z", "2024-03-01T09:00:00Z")
+	quotes := asofTemporalTable(t, "at", "timestamp", "2024-03-01T09:58:00Z", "2024-03-01T10:02:00Z")
+	result, err := runTemporalAsofQuery(t, trades, quotes, `join asof quotes.csv match at tolerance duration("5m")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertColumnStrings(t, result, "quotes_at", "2024-03-01T09:58:00Z", "null", "null")
+
+	days := asofTemporalTable(t, "day", "date", "2024-03-03", "2024-03-05")
+	events := asofTemporalTable(t, "day", "date", "2024-03-02")
+	result, err = runTemporalAsofQuery(t, days, events, `join asof events.csv match day tolerance duration("36h")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertColumnStrings(t, result, "events_day", "2024-03-02", "null")
+
+	for _, tc := range []struct {
+		query string
+		want  string
+	}{
+		{"join asof quotes.csv match at tolerance 5", `join: asof tolerance for timestamp match column at must be a duration such as duration("5m")`},
+		{`join asof quotes.csv match at tolerance duration("-1m")`, `join: asof tolerance must be a non-negative duration, got "-1m"`},
+	} {
+		if _, err := runTemporalAsofQuery(t, trades, quotes, tc.query); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want substring %q", tc.query, err, tc.want)
+		}
+	}
+}
+
+func TestAsofJoinWithoutKeys(t *testing.T) {
+	result, err := runAsofQuery(t, "join asof quotes.csv match ts >= ts | select qty, price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertColumnStrings(t, result, "price", "1.9", "1.9", "1.2", "null", "2.4", "null")
+}
+
+func TestAsofJoinErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"join asof quotes.csv on symbol match missing", `join: asof match: left join key column "missing" not found`},
+		{"join asof quotes.csv on symbol match ts >= price", "join: asof match type mismatch for ts and price: int? vs float"},
+		{"join asof quotes.csv on ts match symbol tolerance 1", "join: asof tolerance needs a numeric or temporal match column, got string for symbol"},
+		{`join asof quotes.csv on symbol match ts tolerance duration("1s")`, "join: asof tolerance for int? match column ts must be a number"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := runAsofQuery(t, tc.query)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
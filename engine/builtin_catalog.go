@@ -12,6 +12,7 @@ const (
 	builtinScalar builtinCategory = iota
 	builtinSpecialForm
 	builtinAggregate
+	builtinWindow
 )
 
 type builtinSpec struct {
@@ -20,37 +21,145 @@ type builtinSpec struct {
 	Check     func(args []typedExpr) (*table.TypeDescriptor, error)
 	TypedEval typedCallEvaluator
 	Aggregate *aggregateSpec
+	Window    *windowSpec
+	Lambda    *lambdaSpec
 }
 
 type aggregateSpec struct {
-	Arity          int
+	MinArity       int
+	MaxArity       int
 	NewAccumulator func(args []rowValueEvaluator) (aggregateAccumulator, error)
 }
 
+// lambdaSpec describes a higher-order builtin whose argument at Arg is a
+// lambda. Params types the lambda's parameters from the other arguments and
+// from the type the body returned on the previous binding pass, which is nil
+// on the first.
+type lambdaSpec struct {
+	Usage  string
+	Arity  int
+	Arg    int
+	Params func(args []typedExpr, body *table.TypeDescriptor) ([]*table.TypeDescriptor, error)
+}
+
+type windowSpec struct {
+	MinArity int
+	MaxArity int
+	Eval     func(partition windowPartition, args []rowValueEvaluator, out []table.Value) error
+}
+
 var builtinCatalog = map[string]builtinSpec{
-	"upper":         scalarBuiltin("upper", unaryStringSpec("upper"), typedStringUnaryEval("upper", strings.ToUpper)),
-	"lower":         scalarBuiltin("lower", unaryStringSpec("lower"), typedStringUnaryEval("lower", strings.ToLower)),
-	"trim":          scalarBuiltin("trim", unaryStringSpec("trim"), typedStringUnaryEval("trim", strings.TrimSpace)),
-	"str_len":       scalarBuiltin("str_len", unaryStringToIntSpec("str_len"), typedStringToIntEval("str_len", stringCodePointCount)),
-	"year":          scalarBuiltin("year", unaryStringToIntSpec("year"), typedDatePartEval("year")),
-	"month":         scalarBuiltin("month", unaryStringToIntSpec("month"), typedDatePartEval("month")),
-	"day":           scalarBuiltin("day", unaryStringToIntSpec("day"), typedDatePartEval("day")),
-	"substr":        scalarBuiltin("substr", checkSubstrSignature, typedSubstrEval),
-	"str_contains":  scalarBuiltin("str_contains", binaryStringToBoolSpec("str_contains", "substring"), typedStringPredicateEval("str_contains", "substring", strings.Contains)),
-	"starts_with":   scalarBuiltin("starts_with", binaryStringToBoolSpec("starts_with", "prefix"), typedStringPredicateEval("starts_with", "prefix", strings.HasPrefix)),
-	"ends_with":     scalarBuiltin("ends_with", binaryStringToBoolSpec("ends_with", "suffix"), typedStringPredicateEval("ends_with", "suffix", strings.HasSuffix)),
-	"matches":       scalarBuiltin("matches", binaryStringToBoolSpec("matches", "regex"), typedMatchesEval),
-	"list_len":      scalarBuiltin("list_len", checkListLenSignature, typedListLenEval),
-	"list_contains": scalarBuiltin("list_contains", checkListContainsSignature, typedListContainsEval),
-	"coalesce":      specialFormBuiltin("coalesce", checkCoalesceSignature, typedCoalesceEval),
-	"if":            specialFormBuiltin("if", checkIfSignature, typedIfEval),
-	"count":         aggregateBuiltin("count", 0, aggregateSignature("count"), newCountAccumulator),
-	"sum":           aggregateBuiltin("sum", 1, aggregateSignature("sum"), newSumAccumulator),
-	"avg":           aggregateBuiltin("avg", 1, aggregateSignature("avg"), newAvgAccumulator),
-	"min":           aggregateBuiltin("min", 1, aggregateSignature("min"), newMinAccumulator),
-	"max":           aggregateBuiltin("max", 1, aggregateSignature("max"), newMaxAccumulator),
-	"first":         aggregateBuiltin("first", 1, aggregateSignature("first"), newFirstAccumulator),
-	"last":          aggregateBuiltin("last", 1, aggregateSignature("last"), newLastAccumulator),
+	"upper":                 scalarBuiltin("upper", unaryStringSpec("upper"), typedStringUnaryEval("upper", strings.ToUpper)),
+	"lower":                 scalarBuiltin("lower", unaryStringSpec("lower"), typedStringUnaryEval("lower", strings.ToLower)),
+	"trim":                  scalarBuiltin("trim", unaryStringSpec("trim"), typedStringUnaryEval("trim", strings.TrimSpace)),
+	"str_len":               scalarBuiltin("str_len", unaryStringToIntSpec("str_len"), typedStringToIntEval("str_len", stringCodePointCount)),
+	"year":                  scalarBuiltin("year", datePartSpec("year"), typedDatePartEval("year")),
+	"month":                 scalarBuiltin("month", datePartSpec("month"), typedDatePartEval("month")),
+	"day":                   scalarBuiltin("day", datePartSpec("day"), typedDatePartEval("day")),
+	"date":                  scalarBuiltin("date", temporalConstructorSpec("date", table.TypeDate, table.TypeString, table.TypeDate, table.TypeTimestamp), typedDateEval),
+	"timestamp":             scalarBuiltin("timestamp", temporalConstructorSpec("timestamp", table.TypeTimestamp, table.TypeString, table.TypeDate, table.TypeTimestamp), typedTimestampEval),
+	"duration":              scalarBuiltin("duration", temporalConstructorSpec("duration", table.TypeDuration, table.TypeString, table.TypeDuration), typedDurationEval),
+	"substr":                scalarBuiltin("substr", checkSubstrSignature, typedSubstrEval),
+	"str_contains":          scalarBuiltin("str_contains", binaryStringToBoolSpec("str_contains", "substring"), typedStringPredicateEval("str_contains", "substring", strings.Contains)),
+	"starts_with":           scalarBuiltin("starts_with", binaryStringToBoolSpec("starts_with", "prefix"), typedStringPredicateEval("starts_with", "prefix", strings.HasPrefix)),
+	"ends_with":             scalarBuiltin("ends_with", binaryStringToBoolSpec("ends_with", "suffix"), typedStringPredicateEval("ends_with", "suffix", strings.HasSuffix)),
+	"matches":               scalarBuiltin("matches", checkMatchesSignature, typedMatchesEval),
+	"regex_extract":         scalarBuiltin("regex_extract", regexExtractSpec("regex_extract", false), typedRegexExtractEval),
+	"regex_extract_all":     scalarBuiltin("regex_extract_all", regexExtractSpec("regex_extract_all", true), typedRegexExtractAllEval),
+	"regex_replace":         scalarBuiltin("regex_replace", checkRegexReplaceSignature, typedRegexReplaceEval),
+	"regex_split":           scalarBuiltin("regex_split", checkRegexSplitSignature, typedRegexSplitEval),
+	"concat":                scalarBuiltin("concat", checkConcatSignature, typedConcatEval),
+	"split":                 scalarBuiltin("split", checkSplitSignature, typedSplitEval),
+	"replace":               scalarBuiltin("replace", stringFunctionSpec("replace", table.TypeString, 0, replaceParams), typedReplaceEval),
+	"lpad":                  scalarBuiltin("lpad", stringFunctionSpec("lpad", table.TypeString, 1, padParams), typedPadEval("lpad", true)),
+	"rpad":                  scalarBuiltin("rpad", stringFunctionSpec("rpad", table.TypeString, 1, padParams), typedPadEval("rpad", false)),
+	"repeat":                scalarBuiltin("repeat", stringFunctionSpec("repeat", table.TypeString, 0, repeatParams), typedRepeatEval),
+	"reverse":               scalarBuiltin("reverse", unaryStringSpec("reverse"), typedStringUnaryEval("reverse", reverseCodePoints)),
+	"index_of":              scalarBuiltin("index_of", stringFunctionSpec("index_of", table.TypeInt, 0, indexOfParams), typedIndexOfEval),
+	"left":                  scalarBuiltin("left", stringFunctionSpec("left", table.TypeString, 0, sliceParams), typedStringSliceEval("left", false)),
+	"right":                 scalarBuiltin("right", stringFunctionSpec("right", table.TypeString, 0, sliceParams), typedStringSliceEval("right", true)),
+	"format":                scalarBuiltin("format", checkFormatSignature, typedFormatEval),
+	"json_parse":            scalarBuiltin("json_parse", checkJSONParseSignature, typedJSONParseEval),
+	"json_extract":          scalarBuiltin("json_extract", checkJSONExtractSignature, typedJSONExtractEval),
+	"to_json":               scalarBuiltin("to_json", checkToJSONSignature, typedToJSONEval),
+	"list_len":              scalarBuiltin("list_len", checkListLenSignature, typedListLenEval),
+	"list_contains":         scalarBuiltin("list_contains", checkListContainsSignature, typedListContainsEval),
+	"list_get":              scalarBuiltin("list_get", checkListGetSignature, typedListGetEval),
+	"list_slice":            scalarBuiltin("list_slice", checkListSliceSignature, typedListSliceEval),
+	"list_sort":             scalarBuiltin("list_sort", checkListSortSignature, typedListSortEval),
+	"list_distinct":         scalarBuiltin("list_distinct", checkListDistinctSignature, typedListDistinctEval),
+	"list_concat":           scalarBuiltin("list_concat", checkListConcatSignature, typedListConcatEval),
+	"list_flatten":          scalarBuiltin("list_flatten", checkListFlattenSignature, typedListFlattenEval),
+	"list_intersect":        scalarBuiltin("list_intersect", checkListIntersectSignature, typedListIntersectEval),
+	"list_join":             scalarBuiltin("list_join", checkListJoinSignature, typedListJoinEval),
+	"map_keys":              scalarBuiltin("map_keys", checkMapKeysSignature, typedMapKeysEval),
+	"map_values":            scalarBuiltin("map_values", checkMapValuesSignature, typedMapValuesEval),
+	"map_get":               scalarBuiltin("map_get", checkMapGetSignature, typedMapGetEval),
+	"map_entries":           scalarBuiltin("map_entries", checkMapEntriesSignature, typedMapEntriesEval),
+	"record_merge":          scalarBuiltin("record_merge", checkRecordMergeSignature, typedRecordMergeEval),
+	"record_drop":           scalarBuiltin("record_drop", checkRecordDropSignature, typedRecordDropEval),
+	"record_get":            scalarBuiltin("record_get", checkRecordGetSignature, typedRecordGetEval),
+	"record_keys":           scalarBuiltin("record_keys", checkRecordKeysSignature, typedRecordKeysEval),
+	"union_branch":          scalarBuiltin("union_branch", checkUnionBranchSignature, typedUnionBranchEval),
+	"union_is":              scalarBuiltin("union_is", checkUnionIsSignature, typedUnionIsEval),
+	"as_int":                unionNarrowBuiltin("as_int", table.TypeInt),
+	"as_float":              unionNarrowBuiltin("as_float", table.TypeFloat),
+	"as_string":             unionNarrowBuiltin("as_string", table.TypeString),
+	"as_bool":               unionNarrowBuiltin("as_bool", table.TypeBool),
+	"as_decimal":            unionNarrowBuiltin("as_decimal", table.TypeDecimal),
+	"as_date":               unionNarrowBuiltin("as_date", table.TypeDate),
+	"as_timestamp":          unionNarrowBuiltin("as_timestamp", table.TypeTimestamp),
+	"as_duration":           unionNarrowBuiltin("as_duration", table.TypeDuration),
+	"as_list":               unionNarrowBuiltin("as_list", table.TypeList),
+	"as_map":                unionNarrowBuiltin("as_map", table.TypeMap),
+	"as_record":             unionNarrowBuiltin("as_record", table.TypeRecord),
+	"list_filter":           lambdaBuiltin("list_filter", listElemLambda("list_filter", listPredicateUsage), checkListFilterSignature, typedListFilterEval),
+	"list_map":              lambdaBuiltin("list_map", listElemLambda("list_map", listMapUsage), checkListMapSignature, typedListMapEval),
+	"list_any":              lambdaBuiltin("list_any", listElemLambda("list_any", listPredicateUsage), listPredicateSpec("list_any"), typedListQuantifierEval("list_any", true)),
+	"list_all":              lambdaBuiltin("list_all", listElemLambda("list_all", listPredicateUsage), listPredicateSpec("list_all"), typedListQuantifierEval("list_all", false)),
+	"list_reduce":           lambdaBuiltin("list_reduce", listReduceLambda, checkListReduceSignature, typedListReduceEval),
+	"abs":                   scalarBuiltin("abs", numericUnarySpec("abs"), typedNumericUnaryEval("abs", absValue)),
+	"sign":                  scalarBuiltin("sign", numericUnarySpec("sign"), typedNumericUnaryEval("sign", signValue)),
+	"floor":                 scalarBuiltin("floor", numericUnarySpec("floor"), typedNumericUnaryEval("floor", floorValue)),
+	"ceil":                  scalarBuiltin("ceil", numericUnarySpec("ceil"), typedNumericUnaryEval("ceil", ceilValue)),
+	"round":                 scalarBuiltin("round", checkRoundSignature, typedRoundEval),
+	"sqrt":                  scalarBuiltin("sqrt", floatMathSpec("sqrt", 1, true), typedFloatMathEval("sqrt", sqrtFloat)),
+	"log":                   scalarBuiltin("log", floatMathSpec("log", 1, true), typedFloatMathEval("log", logFloat)),
+	"exp":                   scalarBuiltin("exp", floatMathSpec("exp", 1, false), typedFloatMathEval("exp", expFloat)),
+	"pow":                   scalarBuiltin("pow", floatMathSpec("pow", 2, true), typedFloatMathEval("pow", powFloat)),
+	"mod":                   scalarBuiltin("mod", checkModSignature, typedModEval),
+	"greatest":              scalarBuiltin("greatest", extremumSpec("greatest"), typedExtremumEval("greatest", 1)),
+	"least":                 scalarBuiltin("least", extremumSpec("least"), typedExtremumEval("least", -1)),
+	"cast":                  scalarBuiltin("cast", castSpec("cast", "", false), typedCastEval("cast", "", false)),
+	"try_cast":              scalarBuiltin("try_cast", castSpec("try_cast", "", true), typedCastEval("try_cast", "", true)),
+	"to_int":                scalarBuiltin("to_int", castSpec("to_int", "int", false), typedCastEval("to_int", "int", false)),
+	"to_float":              scalarBuiltin("to_float", castSpec("to_float", "float", false), typedCastEval("to_float", "float", false)),
+	"to_string":             scalarBuiltin("to_string", castSpec("to_string", "string", false), typedCastEval("to_string", "string", false)),
+	"coalesce":              specialFormBuiltin("coalesce", checkCoalesceSignature, typedCoalesceEval),
+	"if":                    specialFormBuiltin("if", checkIfSignature, typedIfEval),
+	"count":                 aggregateBuiltin("count", 0, 0, aggregateSignature("count"), newCountAccumulator),
+	"sum":                   aggregateBuiltin("sum", 1, 1, aggregateSignature("sum"), newSumAccumulator),
+	"avg":                   aggregateBuiltin("avg", 1, 1, aggregateSignature("avg"), newAvgAccumulator),
+	"min":                   aggregateBuiltin("min", 1, 1, aggregateSignature("min"), newMinAccumulator),
+	"max":                   aggregateBuiltin("max", 1, 1, aggregateSignature("max"), newMaxAccumulator),
+	"first":                 aggregateBuiltin("first", 1, 1, aggregateSignature("first"), newFirstAccumulator),
+	"last":                  aggregateBuiltin("last", 1, 1, aggregateSignature("last"), newLastAccumulator),
+	"median":                aggregateBuiltin("median", 1, 1, aggregateSignature("median"), newMedianAccumulator),
+	"percentile":            aggregateBuiltin("percentile", 2, 2, aggregateSignature("percentile"), newPercentileAccumulator),
+	"approx_percentile":     aggregateBuiltin("approx_percentile", 2, 2, aggregateSignature("approx_percentile"), newApproxPercentileAccumulator),
+	"stddev":                aggregateBuiltin("stddev", 1, 1, aggregateSignature("stddev"), newStddevAccumulator),
+	"var":                   aggregateBuiltin("var", 1, 1, aggregateSignature("var"), newVarAccumulator),
+	"count_distinct":        aggregateBuiltin("count_distinct", 1, 1, aggregateSignature("count_distinct"), newCountDistinctAccumulator),
+	"approx_count_distinct": aggregateBuiltin("approx_count_distinct", 1, 1, aggregateSignature("approx_count_distinct"), newApproxCountDistinctAccumulator),
+	"collect_list":          aggregateBuiltin("collect_list", 1, 2, aggregateSignature("collect_list"), newCollectListAccumulator),
+	"collect_set":           aggregateBuiltin("collect_set", 1, 2, aggregateSignature("collect_set"), newCollectSetAccumulator),
+	"string_agg":            aggregateBuiltin("string_agg", 2, 2, aggregateSignature("string_agg"), newStringAggAccumulator),
+	"mode":                  aggregateBuiltin("mode", 1, 1, aggregateSignature("mode"), newModeAccumulator),
+	"row_number":            windowBuiltin("row_number", 0, 0, windowSignature("row_number"), evalWindowRowNumber),
+	"rank":                  windowBuiltin("rank", 0, 0, windowSignature("rank"), evalWindowRank),
+	"lag":                   windowBuiltin("lag", 1, 3, windowSignature("lag"), windowOffsetEval("lag", -1)),
+	"lead":                  windowBuiltin("lead", 1, 3, windowSignature("lead"), windowOffsetEval("lead", 1)),
+	"running_sum":           windowBuiltin("running_sum", 1, 1, windowSignature("running_sum"), evalWindowRunningSum),
 }
 
 func scalarBuiltin(name string, check func([]typedExpr) (*table.TypeDescriptor, error), typedEval typedCallEvaluator) builtinSpec {
@@ -61,12 +170,25 @@ func specialFormBuiltin(name string, check func([]typedExpr) (*table.TypeDescrip
 	return builtinSpec{Name: name, Category: builtinSpecialForm, Check: check, TypedEval: typedEval}
 }
 
-func aggregateBuiltin(name string, arity int, check func([]typedExpr) (*table.TypeDescriptor, error), newAccumulator func([]rowValueEvaluator) (aggregateAccumulator, error)) builtinSpec {
+func lambdaBuiltin(name string, lambda *lambdaSpec, check func([]typedExpr) (*table.TypeDescriptor, error), typedEval typedCallEvaluator) builtinSpec {
+	return builtinSpec{Name: name, Category: builtinScalar, Check: check, TypedEval: typedEval, Lambda: lambda}
+}
+
+func aggregateBuiltin(name string, minArity, maxArity int, check func([]typedExpr) (*table.TypeDescriptor, error), newAccumulator func([]rowValueEvaluator) (aggregateAccumulator, error)) builtinSpec {
 	return builtinSpec{
 		Name:      name,
 		Category:  builtinAggregate,
 		Check:     check,
-		Aggregate: &aggregateSpec{Arity: arity, NewAccumulator: newAccumulator},
+		Aggregate: &aggregateSpec{MinArity: minArity, MaxArity: maxArity, NewAccumulator: newAccumulator},
+	}
+}
+
+func windowBuiltin(name string, minArity, maxArity int, check func([]typedExpr) (*table.TypeDescriptor, error), eval func(windowPartition, []rowValueEvaluator, []table.Value) error) builtinSpec {
+	return builtinSpec{
+		Name:     name,
+		Category: builtinWindow,
+		Check:    check,
+		Window:   &windowSpec{MinArity: minArity, MaxArity: maxArity, Eval: eval},
 	}
 }
 
@@ -75,3 +197,9 @@ func aggregateSignature(name string) func([]typedExpr) (*table.TypeDescriptor, e
 		return checkAggregateSignature(name, args)
 	}
 }
+
+func windowSignature(name string) func([]typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		return checkWindowSignature(name, args)
+	}
+}
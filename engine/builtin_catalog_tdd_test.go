@@ -11,29 +11,117 @@ import (
 
 func TestBuiltinCatalogContainsEveryCurrentBuiltinWithExpectedCategory(t *testing.T) {
 	want := map[string]builtinCategory{
-		"upper":         builtinScalar,
-		"lower":         builtinScalar,
-		"trim":          builtinScalar,
-		"str_len":       builtinScalar,
-		"year":          builtinScalar,
-		"month":         builtinScalar,
-		"day":           builtinScalar,
-		"substr":        builtinScalar,
-		"str_contains":  builtinScalar,
-		"starts_with":   builtinScalar,
-		"ends_with":     builtinScalar,
-		"matches":       builtinScalar,
-		"list_len":      builtinScalar,
-		"list_contains": builtinScalar,
-		"coalesce":      builtinSpecialForm,
-		"if":            builtinSpecialForm,
-		"count":         builtinAggregate,
-		"sum":           builtinAggregate,
-		"avg":           builtinAggregate,
-		"min":           builtinAggregate,
-		"max":           builtinAggregate,
-		"first":         builtinAggregate,
-		"last":          builtinAggregate,
+		"upper":                 builtinScalar,
+		"lower":                 builtinScalar,
+		"trim":                  builtinScalar,
+		"str_len":               builtinScalar,
+		"year":                  builtinScalar,
+		"month":                 builtinScalar,
+		"day":                   builtinScalar,
+		"date":                  builtinScalar,
+		"timestamp":             builtinScalar,
+		"duration":              builtinScalar,
+		"substr":                builtinScalar,
+		"str_contains":          builtinScalar,
+		"starts_with":           builtinScalar,
+		"ends_with":             builtinScalar,
+		"matches":               builtinScalar,
+		"regex_extract":         builtinScalar,
+		"regex_extract_all":     builtinScalar,
+		"regex_replace":         builtinScalar,
+		"regex_split":           builtinScalar,
+		"concat":                builtinScalar,
+		"split":                 builtinScalar,
+		"replace":               builtinScalar,
+		"lpad":                  builtinScalar,
+		"rpad":                  builtinScalar,
+		"repeat":                builtinScalar,
+		"reverse":               builtinScalar,
+		"index_of":              builtinScalar,
+		"left":                  builtinScalar,
+		"right":                 builtinScalar,
+		"format":                builtinScalar,
+		"list_len":              builtinScalar,
+		"list_contains":         builtinScalar,
+		"list_get":              builtinScalar,
+		"list_slice":            builtinScalar,
+		"list_sort":             builtinScalar,
+		"list_distinct":         builtinScalar,
+		"list_concat":           builtinScalar,
+		"list_flatten":          builtinScalar,
+		"list_intersect":        builtinScalar,
+		"list_join":             builtinScalar,
+		"map_keys":              builtinScalar,
+		"map_values":            builtinScalar,
+		"map_get":               builtinScalar,
+		"map_entries":           builtinScalar,
+		"record_merge":          builtinScalar,
+		"record_drop":           builtinScalar,
+		"record_get":            builtinScalar,
+		"record_keys":           builtinScalar,
+		"union_branch":          builtinScalar,
+		"union_is":              builtinScalar,
+		"as_int":                builtinScalar,
+		"as_float":              builtinScalar,
+		"as_string":             builtinScalar,
+		"as_bool":               builtinScalar,
+		"as_decimal":            builtinScalar,
+		"as_date":               builtinScalar,
+		"as_timestamp":          builtinScalar,
+		"as_duration":           builtinScalar,
+		"as_list":               builtinScalar,
+		"as_map":                builtinScalar,
+		"as_record":             builtinScalar,
+		"list_filter":           builtinScalar,
+		"list_map":              builtinScalar,
+		"list_any":              builtinScalar,
+		"list_all":              builtinScalar,
+		"list_reduce":           builtinScalar,
+		"abs":                   builtinScalar,
+		"sign":                  builtinScalar,
+		"floor":                 builtinScalar,
+		"ceil":                  builtinScalar,
+		"round":                 builtinScalar,
+		"sqrt":                  builtinScalar,
+		"log":                   builtinScalar,
+		"exp":                   builtinScalar,
+		"pow":                   builtinScalar,
+		"mod":                   builtinScalar,
+		"greatest":              builtinScalar,
+		"least":                 builtinScalar,
+		"cast":                  builtinScalar,
+		"try_cast":              builtinScalar,
+		"to_int":                builtinScalar,
+		"to_float":              builtinScalar,
+		"to_string":             builtinScalar,
+		"json_parse":            builtinScalar,
+		"json_extract":          builtinScalar,
+		"to_json":               builtinScalar,
+		"coalesce":              builtinSpecialForm,
+		"if":                    builtinSpecialForm,
+		"count":                 builtinAggregate,
+		"sum":                   builtinAggregate,
+		"avg":                   builtinAggregate,
+		"min":                   builtinAggregate,
+		"max":                   builtinAggregate,
+		"first":                 builtinAggregate,
+		"last":                  builtinAggregate,
+		"median":                builtinAggregate,
+		"percentile":            builtinAggregate,
+		"approx_percentile":     builtinAggregate,
+		"stddev":                builtinAggregate,
+		"var":                   builtinAggregate,
+		"mode":                  builtinAggregate,
+		"collect_list":          builtinAggregate,
+		"collect_set":           builtinAggregate,
+		"string_agg":            builtinAggregate,
+		"count_distinct":        builtinAggregate,
+		"approx_count_distinct": builtinAggregate,
+		"row_number":            builtinWindow,
+		"rank":                  builtinWindow,
+		"lag":                   builtinWindow,
+		"lead":                  builtinWindow,
+		"running_sum":           builtinWindow,
 	}
 
 	if len(builtinCatalog) != len(want) {
@@ -88,6 +176,16 @@ func TestBuiltinCatalogCategoryHookInvariants(t *testing.T) {
 				if spec.TypedEval != nil {
 					t.Fatal("aggregate builtin must not have TypedEval")
 				}
+			case builtinWindow:
+				if spec.Window == nil || spec.Window.Eval == nil {
+					t.Fatal("window builtin must have Window evaluator")
+				}
+				if spec.Window.MinArity > spec.Window.MaxArity {
+					t.Fatal("window builtin arity range is inverted")
+				}
+				if spec.TypedEval != nil || spec.Aggregate != nil {
+					t.Fatal("window builtin must not have TypedEval or Aggregate metadata")
+				}
 			default:
 				t.Fatalf("unknown category %v", spec.Category)
 			}
@@ -110,13 +208,24 @@ func TestBuiltinCatalogAggregateAccumulatorsMatchFusedAndMaterializedReduce(t *t
 	mustAddFusedGroupReduceTDDRow(t, input, table.StrVal("B"), table.IntVal(8), table.FloatVal(4), table.StrVal("bee"))
 
 	assignments := map[string]string{
-		"count": "v = count()",
-		"sum":   "v = sum(age)",
-		"avg":   "v = avg(amount)",
-		"min":   "v = min(age)",
-		"max":   "v = max(age)",
-		"first": "v = first(name)",
-		"last":  "v = last(name)",
+		"count":                 "v = count()",
+		"sum":                   "v = sum(age)",
+		"avg":                   "v = avg(amount)",
+		"min":                   "v = min(age)",
+		"max":                   "v = max(age)",
+		"first":                 "v = first(name)",
+		"last":                  "v = last(name)",
+		"median":                "v = median(age)",
+		"percentile":            "v = percentile(amount, 0.9)",
+		"approx_percentile":     "v = approx_percentile(amount, 0.9)",
+		"stddev":                "v = stddev(age)",
+		"var":                   "v = var(amount)",
+		"mode":                  "v = mode(name)",
+		"collect_list":          "v = collect_list(age, \"desc\")",
+		"collect_set":           "v = collect_set(name)",
+		"string_agg":            "v = string_agg(name, \"|\")",
+		"count_distinct":        "v = count_distinct(name)",
+		"approx_count_distinct": "v = approx_count_distinct(age)",
 	}
 	for name, spec := range builtinCatalog {
 		if spec.Category != builtinAggregate {
@@ -169,9 +278,9 @@ func TestBuiltinCatalogRejectsDriftBetweenPlanningAndScalarRuntime(t *testing.T)
 		{"lower", []typedExpr{stringArg}, []table.Value{table.StrVal("ABC")}, []typedExpr{intArg}, "requires a string"},
 		{"trim", []typedExpr{stringArg}, []table.Value{table.StrVal(" abc ")}, []typedExpr{intArg}, "requires a string"},
 		{"str_len", []typedExpr{stringArg}, []table.Value{table.StrVal("abc")}, []typedExpr{intArg}, "requires a string"},
-		{"year", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a string"},
-		{"month", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a string"},
-		{"day", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a string"},
+		{"year", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a date, timestamp or string"},
+		{"month", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a date, timestamp or string"},
+		{"day", []typedExpr{stringArg}, []table.Value{table.StrVal("2024-01-02")}, []typedExpr{intArg}, "requires a date, timestamp or string"},
 		{"substr", []typedExpr{stringArg, intArg, intArg}, []table.Value{table.StrVal("abc"), table.IntVal(0), table.IntVal(1)}, []typedExpr{intArg, intArg, intArg}, "requires a string"},
 		{"str_contains", []typedExpr{stringArg, stringArg}, []table.Value{table.StrVal("abc"), table.StrVal("a")}, []typedExpr{intArg, stringArg}, "requires a string"},
 		{"starts_with", []typedExpr{stringArg, stringArg}, []table.Value{table.StrVal("abc"), table.StrVal("a")}, []typedExpr{intArg, stringArg}, "requires a string"},
@@ -399,7 +508,7 @@ func TestBuiltinCatalogTypedEvalDefensiveBranches(t *testing.T) {
 		{"upper_arity", "upper", nil, "upper() takes 1 argument"},
 		{"upper_bad_type", "upper", []typedExpr{typedIntLiteral(1)}, "upper() requires a string"},
 		{"str_len_bad_type", "str_len", []typedExpr{typedIntLiteral(1)}, "str_len() requires a string"},
-		{"year_bad_type", "year", []typedExpr{typedIntLiteral(1)}, "year() requires a string"},
+		{"year_bad_type", "year", []typedExpr{typedIntLiteral(1)}, "year() requires a date, timestamp or string"},
 		{"list_len_bad_type", "list_len", []typedExpr{typedStringLiteral("x")}, "list_len() requires a list"},
 		{"substr_arity", "substr", []typedExpr{typedStringLiteral("abc")}, "substr() takes 3 arguments"},
 		{"substr_bad_string", "substr", []typedExpr{typedIntLiteral(1), typedIntLiteral(0), typedIntLiteral(1)}, "substr() requires a string"},
@@ -0,0 +1,253 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+const castTypeNames = "int, float, string, bool, date, timestamp, duration, decimal(p,s)"
+
+// parseCastType reads the type written after "as" in cast(x as type). The
+// parser passes it on in canonical form, such as "int" or "decimal(10,2)".
+func parseCastType(name, typ string) (*table.TypeDescriptor, error) {
+	switch typ {
+	case "int":
+		return table.ScalarSchema(table.TypeInt), nil
+	case "float":
+		return table.ScalarSchema(table.TypeFloat), nil
+	case "string":
+		return table.ScalarSchema(table.TypeString), nil
+	case "bool":
+		return table.ScalarSchema(table.TypeBool), nil
+	case "date":
+		return table.ScalarSchema(table.TypeDate), nil
+	case "timestamp":
+		return table.ScalarSchema(table.TypeTimestamp), nil
+	case "duration":
+		return table.ScalarSchema(table.TypeDuration), nil
+	}
+	if params, ok := strings.CutPrefix(typ, "decimal("); ok && strings.HasSuffix(params, ")") {
+		p, s, _ := strings.Cut(strings.TrimSuffix(params, ")"), ",")
+		precision, perr := strconv.Atoi(p)
+		scale, serr := strconv.Atoi(s)
+		if s == "" {
+			scale, serr = 0, nil
+		}
+		if perr == nil && serr == nil {
+			schema := table.DecimalSchema(precision, scale)
+			if err := table.ValidateSchema(schema); err != nil {
+				return nil, fmt.Errorf("%s(): %s", name, strings.TrimPrefix(err.Error(), "<value> "))
+			}
+			return schema, nil
+		}
+	}
+	return nil, fmt.Errorf("%s(): unknown type %q (supported: %s)", name, typ, castTypeNames)
+}
+
+// castTarget returns the target type of a cast() or try_cast() call, whose
+// second argument the parser fills in from "as type".
+func castTarget(name string, arg typedExpr) (*table.TypeDescriptor, error) {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok || lit.Kind != "string" {
+		return nil, fmt.Errorf("%s() requires a target type, as in %s(x as int)", name, name)
+	}
+	return parseCastType(name, lit.Str)
+}
+
+// castSourceAllowed reports whether values of kind can ever convert to
+// target. Anything converts to a string; strings parse into every type.
+func castSourceAllowed(kind table.ValueType, target *table.TypeDescriptor) bool {
+	if kind == target.Kind || kind == table.TypeString || kind == table.TypeUnion || target.Kind == table.TypeString {
+		return true
+	}
+	switch target.Kind {
+	case table.TypeInt:
+		return kind == table.TypeFloat || kind == table.TypeDecimal || kind == table.TypeBool
+	case table.TypeFloat, table.TypeDecimal:
+		return kind == table.TypeInt || kind == table.TypeFloat || kind == table.TypeDecimal
+	case table.TypeBool:
+		return kind == table.TypeInt
+	case table.TypeDate, table.TypeTimestamp:
+		return kind == table.TypeDate || kind == table.TypeTimestamp
+	default:
+		return false
+	}
+}
+
+// castSpec types cast(), try_cast() and the to_int(), to_float() and
+// to_string() shorthands, which fix the target type. try_cast() returns null
+// where cast() fails, so its result is always nullable.
+func castSpec(name, fixed string, safe bool) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		var target *table.TypeDescriptor
+		var err error
+		if fixed != "" {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+			}
+			target, err = parseCastType(name, fixed)
+		} else {
+			if len(args) != 2 {
+				return nil, fmt.Errorf("%s() takes an expression and a type, as in %s(x as int)", name, name)
+			}
+			target, err = castTarget(name, args[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isNullOnly(args[0].typ) && !castSourceAllowed(finalizePlanningSchema(args[0].typ).Kind, target) {
+			return nil, fmt.Errorf("%s(): cannot cast %s to %s", name, schemaString(args[0].typ), target)
+		}
+		target.Nullable = safe || schemaMayBeNull(args[0].typ)
+		return target, nil
+	}
+}
+
+func typedCastEval(name, fixed string, safe bool) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		var target *table.TypeDescriptor
+		var err error
+		if fixed != "" {
+			target, err = parseCastType(name, fixed)
+		} else if len(args) == 2 {
+			target, err = castTarget(name, args[1])
+		} else {
+			err = fmt.Errorf("%s() takes an expression and a type, as in %s(x as int)", name, name)
+		}
+		if err != nil {
+			return table.Null(), err
+		}
+		v, err := evalTypedExpression(args[0], ctx)
+		if err != nil || v.IsNull() {
+			return table.Null(), err
+		}
+		out, ok := castValue(v, target)
+		if ok {
+			return out, nil
+		}
+		if safe {
+			return table.Null(), nil
+		}
+		if v.Type == table.TypeString {
+			return table.Null(), fmt.Errorf("%s(): cannot convert %q to %s", name, v.Str, target)
+		}
+		return table.Null(), fmt.Errorf("%s(): cannot convert %s %s to %s", name, valueTypeName(v), v.AsString(), target)
+	}
+}
+
+// castValue converts a non-null value to target; ok is false when the value
+// does not convert. Strings are trimmed before they are parsed, floats and
+// decimals truncate toward zero when cast to int, and timestamps become
+// dates in UTC.
+func castValue(v table.Value, target *table.TypeDescriptor) (table.Value, bool) {
+	if target.Kind == table.TypeString {
+		return table.StrVal(v.AsString()), true
+	}
+	if v.Type == table.TypeString {
+		v.Str = strings.TrimSpace(v.Str)
+	}
+	switch target.Kind {
+	case table.TypeInt:
+		return castToInt(v)
+	case table.TypeFloat:
+		if v.Type == table.TypeString {
+			f, err := strconv.ParseFloat(v.Str, 64)
+			return table.FloatVal(f), err == nil
+		}
+		f, ok := v.AsFloat()
+		return table.FloatVal(f), ok && v.Type != table.TypeBool
+	case table.TypeBool:
+		return castToBool(v)
+	case table.TypeDecimal:
+		return castToDecimal(v, target)
+	case table.TypeDate:
+		return dateFromValue(v)
+	case table.TypeTimestamp:
+		return timestampFromValue(v, time.UTC)
+	case table.TypeDuration:
+		if v.Type == table.TypeString {
+			return table.ParseDuration(v.Str)
+		}
+		return v, v.Type == table.TypeDuration
+	}
+	return table.Null(), false
+}
+
+func castToInt(v table.Value) (table.Value, bool) {
+	switch v.Type {
+	case table.TypeInt:
+		return v, true
+	case table.TypeBool:
+		if v.Bool {
+			return table.IntVal(1), true
+		}
+		return table.IntVal(0), true
+	case table.TypeFloat:
+		f := math.Trunc(v.Float)
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return table.Null(), false
+		}
+		return table.IntVal(int64(f)), true
+	case table.TypeDecimal:
+		unscaled, scale, ok := v.Decimal()
+		if !ok {
+			return table.Null(), false
+		}
+		n := new(big.Int).Quo(unscaled, table.Pow10(scale))
+		return table.IntVal(n.Int64()), n.IsInt64()
+	case table.TypeString:
+		n, err := strconv.ParseInt(v.Str, 10, 64)
+		return table.IntVal(n), err == nil
+	}
+	return table.Null(), false
+}
+
+// castToBool accepts true and false in any case, as CSV loading does, and
+// the ints 0 and 1.
+func castToBool(v table.Value) (table.Value, bool) {
+	switch v.Type {
+	case table.TypeBool:
+		return v, true
+	case table.TypeInt:
+		return table.BoolVal(v.Int == 1), v.Int == 0 || v.Int == 1
+	case table.TypeString:
+		if strings.EqualFold(v.Str, "true") {
+			return table.BoolVal(true), true
+		}
+		if strings.EqualFold(v.Str, "false") {
+			return table.BoolVal(false), true
+		}
+	}
+	return table.Null(), false
+}
+
+// castToDecimal rounds half away from zero to the target scale and fails
+// when the integer digits do not fit the target precision.
+func castToDecimal(v table.Value, target *table.TypeDescriptor) (table.Value, bool) {
+	switch v.Type {
+	case table.TypeFloat:
+		if math.IsNaN(v.Float) || math.IsInf(v.Float, 0) {
+			return table.Null(), false
+		}
+		v.Str = strconv.FormatFloat(v.Float, 'f', target.Scale, 64)
+		fallthrough
+	case table.TypeString:
+		d, ok := table.ParseDecimal(v.Str)
+		if !ok {
+			return table.Null(), false
+		}
+		v = d
+	case table.TypeInt, table.TypeDecimal:
+	default:
+		return table.Null(), false
+	}
+	out, err := table.CoerceValueToSchema(v, target)
+	return out, err == nil
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+const rawCSV = "id,amount,flag,day,wait\n" +
+	" 42 ,1.25,TRUE,2024-01-02,1h30m\n" +
+	"x,-3.5,false,2024-01-02T10:00:00Z,soon\n" +
+	",,,,\n"
+
+func TestCastConvertsStringColumns(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "raw.csv", rawCSV)
+	result := loadAndQuery(t, path+" with infer_rows=0", `transform
+		id = try_cast(id as int),
+		cents = cast(amount as decimal(6,1)),
+		amount = to_float(amount),
+		flag = cast(flag as bool),
+		day = cast(day as date),
+		at = cast(day as timestamp),
+		wait = try_cast(wait as duration)
+	| select id, cents, amount, flag, day, at, wait`)
+	assertColumnStrings(t, result, "id", "42", "null", "null")
+	assertColumnStrings(t, result, "cents", "1.3", "-3.5", "null")
+	assertColumnStrings(t, result, "amount", "1.25", "-3.5", "null")
+	assertColumnStrings(t, result, "flag", "true", "false", "null")
+	assertColumnStrings(t, result, "day", "2024-01-02", "2024-01-02", "null")
+	assertColumnStrings(t, result, "at", "2024-01-02T00:00:00Z", "2024-01-02T10:00:00Z", "null")
+	assertColumnStrings(t, result, "wait", "1h30m0s", "null", "null")
+	assertColumnSchema(t, result, "id", "int?")
+	assertColumnSchema(t, result, "cents", "decimal(6,1)?")
+	assertColumnSchema(t, result, "amount", "float?")
+	assertColumnSchema(t, result, "day", "date?")
+
+	sum := loadAndQuery(t, path+" with infer_rows=0", "filter { try_cast(id as int) > 10 } | transform doubled = to_int(id) * 2 | select doubled")
+	assertColumnStrings(t, sum, "doubled", "84")
+	assertColumnSchema(t, sum, "doubled", "int?")
+}
+
+func TestCastBetweenTypedValues(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		truncated = cast(x as int),
+		whole = to_int(cast("-7.9" as decimal(3,1))),
+		yes = cast(1 as bool),
+		text = to_string(x),
+		n_text = cast(n as string),
+		pct = cast(x * 100 as decimal(5,2)),
+		safe = try_cast(name as bool)
+	| select truncated, whole, yes, text, n_text, pct, safe`)
+	assertColumnStrings(t, result, "truncated", "1", "0", "null")
+	assertColumnStrings(t, result, "whole", "-7", "-7", "-7")
+	assertColumnStrings(t, result, "yes", "true", "true", "true")
+	assertColumnStrings(t, result, "text", "1.5", "-0.25", "null")
+	assertColumnStrings(t, result, "n_text", "7", "255", "null")
+	assertColumnStrings(t, result, "pct", "150.00", "-25.00", "null")
+	assertColumnStrings(t, result, "safe", "null", "null", "null")
+	assertColumnSchema(t, result, "whole", "int")
+	assertColumnSchema(t, result, "text", "string?")
+	assertColumnSchema(t, result, "safe", "bool?")
+}
+
+func TestCastErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "raw.csv", rawCSV)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform v = cast(id as int)`, `cast(): cannot convert "x" to int`},
+		{`transform v = to_float(flag)`, `to_float(): cannot convert "TRUE" to float`},
+		{`transform v = cast(amount as decimal(2,2))`, `cast(): cannot convert "1.25" to decimal(2,2)`},
+		{`transform v = cast(id as varchar)`, `cast(): unknown type "varchar"`},
+		{`transform v = cast(id as decimal(40,2))`, "cast(): invalid decimal(40,2)"},
+		{`transform v = cast(list(1) as int)`, "cast(): cannot cast list<int> to int"},
+		{`transform v = cast(1.5 as date)`, "cast(): cannot cast float to date"},
+		{`transform v = try_cast(true as float)`, "try_cast(): cannot cast bool to float"},
+		{`transform v = to_int(id, 2)`, "to_int() takes 1 argument, got 2"},
+		{`transform v = cast(9223372036854775807.0 as int)`, "cast(): cannot convert float 9.223372036854776e+18 to int"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path+" with infer_rows=0", tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
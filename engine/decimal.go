@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// minDecimalDivisionScale is the fewest fractional digits a decimal quotient
+// keeps, so 10.00 / 3 is 3.333333 rather than 3.33.
+const minDecimalDivisionScale = 6
+
+// decimalOperand returns the precision and scale of an int or decimal schema;
+// ints take part in decimal arithmetic as decimal(19,0).
+func decimalOperand(schema *table.TypeDescriptor) (int, int, bool) {
+	schema = finalizePlanningSchema(schema)
+	switch schema.Kind {
+	case table.TypeDecimal:
+		return schema.Precision, schema.Scale, true
+	case table.TypeInt:
+		return table.Int64DecimalPrecision, 0, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func schemaIsDecimal(schema *table.TypeDescriptor) bool {
+	return schemaKindOrNull(schema, table.TypeDecimal) && !isNullOnly(schema)
+}
+
+// decimalArithScale returns the scale of op's result for operands of scales
+// ls and rs. Runtime evaluation uses the same rule, so values always carry the
+// planned scale.
+func decimalArithScale(op string, ls, rs int) int {
+	switch op {
+	case "*":
+		return min(ls+rs, table.MaxDecimalPrecision)
+	case "/":
+		return max(minDecimalDivisionScale, ls, rs)
+	default:
+		return max(ls, rs)
+	}
+}
+
+// checkDecimalArithSignature types + - * / % over a decimal and a decimal or
+// int. Sums keep the wider scale and gain a carry digit, products add scales
+// and precisions, quotients use the full precision at decimalArithScale, and
+// remainders are no wider than either operand. A float operand makes the
+// result float; float literals next to a decimal were already retyped by
+// exactDecimalLiterals, so only float columns get here.
+func checkDecimalArithSignature(op string, left, right typedExpr) (*table.TypeDescriptor, error) {
+	switch {
+	case isNullOnly(left.typ):
+		return table.WithNullable(right.typ), nil
+	case isNullOnly(right.typ):
+		return table.WithNullable(left.typ), nil
+	}
+	if schemaKindOrNull(left.typ, table.TypeFloat) || schemaKindOrNull(right.typ, table.TypeFloat) {
+		out := nullableSchema(table.TypeFloat, left.typ, right.typ)
+		switch op {
+		case "/":
+			return plannedDivisionSchema(out, right.raw), nil
+		case "%":
+			return plannedRemainderSchema(out, right.raw), nil
+		}
+		return out, nil
+	}
+	lp, ls, lok := decimalOperand(left.typ)
+	rp, rs, rok := decimalOperand(right.typ)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s requires numeric operands, got %s and %s", op, schemaString(left.typ), schemaString(right.typ))
+	}
+	scale := decimalArithScale(op, ls, rs)
+	var precision int
+	switch op {
+	case "*":
+		precision = lp + rp + 1
+	case "/":
+		precision = table.MaxDecimalPrecision
+	case "%":
+		precision = min(lp-ls, rp-rs) + scale
+	default:
+		precision = max(lp-ls, rp-rs) + scale + 1
+	}
+	out := table.DecimalSchema(min(precision, table.MaxDecimalPrecision), scale)
+	out.Nullable = anySchemaMayBeNull(left.typ, right.typ)
+	switch op {
+	case "/":
+		return plannedDivisionSchema(out, right.raw), nil
+	case "%":
+		return plannedRemainderSchema(out, right.raw), nil
+	}
+	return out, nil
+}
+
+// exactDecimalLiterals retypes a float literal operand as an exact decimal
+// when the other operand is decimal, read from the literal's source text, so
+// amt == 0.2 matches 0.20 and amt + 0.1 stays decimal. Literals that need an
+// exponent or more than MaxDecimalPrecision digits stay float.
+func exactDecimalLiterals(left, right logicalTypedExpr) (logicalTypedExpr, logicalTypedExpr) {
+	if schemaIsDecimal(left.typ) {
+		right = decimalLiteralOperand(right)
+	}
+	if schemaIsDecimal(right.typ) {
+		left = decimalLiteralOperand(left)
+	}
+	return left, right
+}
+
+func decimalLiteralOperand(expr logicalTypedExpr) logicalTypedExpr {
+	lit, negated := floatLiteralOperand(expr.raw)
+	if lit == nil {
+		return expr
+	}
+	text, f := lit.Text, lit.Float
+	if negated {
+		text, f = "-"+text, -f
+	}
+	v, ok := table.ParseDecimal(text)
+	if !ok || table.DecimalPrecision(v) > table.MaxDecimalPrecision {
+		return expr
+	}
+	raw := &ast.LiteralExpr{Kind: "decimal", Float: f, Str: v.Str, Text: text, SourceSpan: expr.raw.Span().Pack()}
+	return logicalTypedExpr{bound: &logicalBoundLiteral{raw: raw}, raw: raw, typ: decimalLiteralSchema(v.Str)}
+}
+
+// floatLiteralOperand returns the float literal expr is, directly or under a
+// unary minus.
+func floatLiteralOperand(expr ast.Expr) (*ast.LiteralExpr, bool) {
+	switch e := expr.(type) {
+	case *ast.LiteralExpr:
+		if e.Kind == "float" && e.Text != "" {
+			return e, false
+		}
+	case *ast.UnaryExpr:
+		if lit, negated := floatLiteralOperand(e.Operand); e.Op == "-" && lit != nil {
+			return lit, !negated
+		}
+	}
+	return nil, false
+}
+
+func decimalLiteralSchema(text string) *table.TypeDescriptor {
+	v, ok := table.ParseDecimal(text)
+	if !ok {
+		return nil
+	}
+	_, scale, _ := v.Decimal()
+	return table.DecimalSchema(table.DecimalPrecision(v), scale)
+}
+
+// evalDecimalArith applies op to decimal or int operands exactly. Division
+// rounds half away from zero and, like float division, yields null for a zero
+// divisor; so does the remainder, which takes the sign of the dividend.
+func evalDecimalArith(op string, left, right table.Value) (table.Value, error) {
+	lu, ls, _ := left.Decimal()
+	ru, rs, _ := right.Decimal()
+	scale := decimalArithScale(op, ls, rs)
+	var out *big.Int
+	switch op {
+	case "+", "-":
+		l := table.RescaleDecimal(left, scale)
+		r := table.RescaleDecimal(right, scale)
+		lu, _, _ = l.Decimal()
+		ru, _, _ = r.Decimal()
+		if op == "+" {
+			out = new(big.Int).Add(lu, ru)
+		} else {
+			out = new(big.Int).Sub(lu, ru)
+		}
+	case "*":
+		out = new(big.Int).Mul(lu, ru)
+		if ls+rs > scale {
+			out = table.DivRoundHalfAway(out, table.Pow10(ls+rs-scale))
+		}
+	case "/":
+		if ru.Sign() == 0 {
+			return table.Null(), nil
+		}
+		num := new(big.Int).Mul(lu, table.Pow10(scale-ls+rs))
+		out = table.DivRoundHalfAway(num, ru)
+	case "%":
+		if ru.Sign() == 0 {
+			return table.Null(), nil
+		}
+		l, _, _ := table.RescaleDecimal(left, scale).Decimal()
+		r, _, _ := table.RescaleDecimal(right, scale).Decimal()
+		out = new(big.Int).Rem(l, r)
+	default:
+		return table.Null(), fmt.Errorf("unknown arithmetic operator %q", op)
+	}
+	v := table.DecimalVal(out, scale)
+	if table.DecimalPrecision(v) > table.MaxDecimalPrecision {
+		return table.Null(), fmt.Errorf("decimal overflow in %s: %s and %s", op, left.AsString(), right.AsString())
+	}
+	return v, nil
+}
+
+func negateDecimal(v table.Value) table.Value {
+	unscaled, scale, _ := v.Decimal()
+	return table.DecimalVal(unscaled.Neg(unscaled), scale)
+}
+
+// decimalSumSchema is the schema of sum() and running_sum() over decimal(p,s):
+// the full precision at the input scale, since many rows can carry into every
+// digit.
+func decimalSumSchema(schema *table.TypeDescriptor) *table.TypeDescriptor {
+	final := finalizePlanningSchema(schema)
+	out := table.DecimalSchema(table.MaxDecimalPrecision, final.Scale)
+	out.Nullable = true
+	return out
+}
+
+// decimalAvgSchema is the schema of avg() over decimal(p,s), which divides the
+// sum by the count with decimal division scale.
+func decimalAvgSchema(schema *table.TypeDescriptor) *table.TypeDescriptor {
+	final := finalizePlanningSchema(schema)
+	out := table.DecimalSchema(table.MaxDecimalPrecision, decimalArithScale("/", final.Scale, 0))
+	out.Nullable = true
+	return out
+}
+
+// decimalSum accumulates decimal and int values exactly at the widest scale
+// seen so far.
+type decimalSum struct {
+	unscaled *big.Int
+	scale    int
+}
+
+func (s *decimalSum) add(v table.Value) {
+	unscaled, scale, _ := v.Decimal()
+	if s.unscaled == nil {
+		s.unscaled = new(big.Int)
+	}
+	if scale > s.scale {
+		s.unscaled = table.RescaleDecimalUnscaled(s.unscaled, s.scale, scale)
+		s.scale = scale
+	}
+	s.unscaled.Add(s.unscaled, table.RescaleDecimalUnscaled(unscaled, scale, s.scale))
+}
+
+func (s *decimalSum) value() (table.Value, error) {
+	v := table.DecimalVal(s.unscaled, s.scale)
+	if table.DecimalPrecision(v) > table.MaxDecimalPrecision {
+		return table.Null(), fmt.Errorf("decimal overflow: sum %s exceeds %d digits", v.Str, table.MaxDecimalPrecision)
+	}
+	return v, nil
+}
+
+// avg divides the sum by count at decimal division scale.
+func (s *decimalSum) avg(count int64) (table.Value, error) {
+	sum, err := s.value()
+	if err != nil {
+		return table.Null(), err
+	}
+	return evalDecimalArith("/", sum, table.IntVal(count))
+}
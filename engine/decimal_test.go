@@ -0,0 +1,98 @@
+package engine
+
+import "testing"
+
+const decimalCSV = "item,price,qty,rate\n" +
+	"a,10.50,2,0.1\n" +
+	"b,3.333,1,0.2\n" +
+	"a,0.125,3,1e-1\n" +
+	"c,,4,0.5\n"
+
+func TestDecimalCSVInferenceAndDescribe(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", decimalCSV)
+	result := loadAndQuery(t, path+" with decimals=true", "describe")
+	assertDescribeSchemaRows(t, result, map[string]describeSchemaMeta{
+		"item":  {typ: "string", rows: 4, schema: "string"},
+		"price": {typ: "decimal", rows: 4, schema: "decimal(5,3)?"},
+		"qty":   {typ: "int", rows: 4, schema: "int"},
+		"rate":  {typ: "float", rows: 4, schema: "float"},
+	})
+
+	plain := loadAndQuery(t, path, "select price")
+	assertColumnSchema(t, plain, "price", "float?")
+}
+
+func TestDecimalArithmeticIsExact(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", "a,b\n0.1,0.2\n1.005,-3\n")
+	result := loadAndQuery(t, path+" with decimals=true", `transform
+		sum = a + b,
+		diff = a - b,
+		prod = a * b,
+		quot = a / b,
+		neg = -a,
+		mixed = a + 1,
+		approx = a * 1.5
+	| select sum, diff, prod, quot, neg, mixed, approx`)
+	assertColumnStrings(t, result, "sum", "0.300", "-1.995")
+	assertColumnStrings(t, result, "diff", "-0.100", "4.005")
+	assertColumnStrings(t, result, "prod", "0.0200", "-3.0150")
+	assertColumnStrings(t, result, "quot", "0.500000", "-0.335000")
+	assertColumnStrings(t, result, "neg", "-0.100", "-1.005")
+	assertColumnStrings(t, result, "mixed", "1.100", "2.005")
+	assertColumnSchema(t, result, "sum", "decimal(5,3)")
+	assertColumnSchema(t, result, "prod", "decimal(7,4)")
+	assertColumnSchema(t, result, "quot", "decimal(38,6)?")
+	assertColumnSchema(t, result, "mixed", "decimal(23,3)")
+	assertColumnStrings(t, result, "approx", "0.1500", "1.5075")
+	assertColumnSchema(t, result, "approx", "decimal(7,4)")
+}
+
+func TestDecimalFloatLiteralsAreExact(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", "amt,rate\n0.20,0.5\n0.10,1e0\n-0.30,2.5\n")
+	result := loadAndQuery(t, path+" with decimals=true", `transform
+		plus = amt + 0.1,
+		scaled = 1.5 * amt,
+		negated = amt - -0.25,
+		float_col = amt * rate
+	| select plus, scaled, negated, float_col`)
+	assertColumnStrings(t, result, "plus", "0.30", "0.20", "-0.20")
+	assertColumnStrings(t, result, "scaled", "0.300", "0.150", "-0.450")
+	assertColumnStrings(t, result, "negated", "0.45", "0.35", "-0.05")
+	assertColumnSchema(t, result, "plus", "decimal(3,2)")
+	assertColumnSchema(t, result, "scaled", "decimal(5,3)")
+	assertColumnSchema(t, result, "float_col", "float")
+
+	equal := loadAndQuery(t, path+" with decimals=true", "filter { amt == 0.2 or amt == -0.3 } | select amt")
+	assertColumnStrings(t, equal, "amt", "0.20", "-0.30")
+
+	sum := loadAndQuery(t, path+" with decimals=true", "filter { amt + 0.1 == 0.3 } | select amt")
+	assertColumnStrings(t, sum, "amt", "0.20")
+}
+
+func TestDecimalDivisionRoundsHalfAwayFromZero(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", "n,d\n2.000000,3\n-2.000000,3\n1.00,0\n")
+	result := loadAndQuery(t, path+" with decimals=true", "transform q = n / d | select q")
+	assertColumnStrings(t, result, "q", "0.666667", "-0.666667", "null")
+}
+
+func TestDecimalSumAndAvg(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", decimalCSV)
+	result := loadAndQuery(t, path+" with decimals=true", `group item | reduce total = sum(price), mean = avg(price), n = count() | sort item | select item, total, mean`)
+	assertColumnStrings(t, result, "total", "10.625", "3.333", "null")
+	assertColumnStrings(t, result, "mean", "5.312500", "3.333000", "null")
+	assertColumnSchema(t, result, "total", "decimal(38,3)?")
+	assertColumnSchema(t, result, "mean", "decimal(38,6)?")
+
+	running := loadAndQuery(t, path+" with decimals=true", "window running = running_sum(price) | select running")
+	assertColumnStrings(t, running, "running", "10.500", "13.833", "13.958", "13.958")
+	assertColumnSchema(t, running, "running", "decimal(38,3)?")
+}
+
+func TestDecimalComparisonAndSort(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", decimalCSV)
+	result := loadAndQuery(t, path+" with decimals=true", "filter { price > 0.2 and price != 10.5 or price == 0.125 } | sort -price | select price")
+	assertColumnStrings(t, result, "price", "3.333", "0.125")
+
+	distinct := loadAndQuery(t, writeCSVInferenceFile(t, t.TempDir(), "d.csv", "p\n1.5\n1.50\n2\n")+" with decimals=true", "distinct p | count")
+	assertColumnStrings(t, distinct, "count", "2")
+}
@@ -104,7 +104,7 @@ func demandPruningMayRewrite(plan *optimizedLogicalPipeline) bool {
 	}
 	for _, op := range plan.Ops {
 		switch op.(type) {
-		case logicalTransform, logicalSelect, logicalRename, logicalRemove, logicalGroupReduce, logicalJoin:
+		case logicalTransform, logicalSelect, logicalRename, logicalRemove, logicalGroupReduce, logicalJoin, logicalWindow, logicalUnion:
 			return true
 		}
 	}
@@ -170,6 +170,26 @@ func requiredInputDemandForLogicalOp(op logicalOp, input, output schemaEnv, out
 		return demandAllColumns()
 	case logicalJoin:
 		return demandForJoinInput(o, input, out)
+	case logicalWindow:
+		return demandForWindowInput(o, input, out)
+	case logicalExplode:
+		in := demandSameNames(input, out)
+		in.addPath(o.path)
+		return in
+	case logicalUnpivot:
+		in := demandSameNames(input, out)
+		for _, name := range o.columns {
+			in.add(name)
+		}
+		return in
+	case logicalUnion:
+		in := demandNoColumns()
+		for _, source := range o.outputSources {
+			if source.leftName != "" && (out.all || out.has(source.name)) {
+				in.add(source.leftName)
+			}
+		}
+		return in
 	default:
 		return demandAllColumns()
 	}
@@ -210,6 +230,37 @@ func demandForTransformInput(op logicalTransform, input schemaEnv, out columnDem
 	return in
 }
 
+func demandForWindowInput(op logicalWindow, input schemaEnv, out columnDemand) columnDemand {
+	if out.all {
+		return demandAllColumns()
+	}
+	assignments := logicalAssignmentsByName(op.assignments)
+	in := demandNoColumns()
+	computed := false
+	for _, col := range op.OutputEnv().columns {
+		if !out.has(col.name) {
+			continue
+		}
+		if assignment, ok := assignments[col.name]; ok {
+			in.addExpr(assignment.expr)
+			computed = true
+			continue
+		}
+		if _, ok := input.lookupColumn(col.name); ok {
+			in.add(col.name)
+		}
+	}
+	if computed {
+		for _, key := range op.partition {
+			in.addPath(key.path)
+		}
+		for _, key := range op.order {
+			in.addPath(key.path)
+		}
+	}
+	return in
+}
+
 func demandForGroupReduceInput(op logicalGroupReduce, out columnDemand) columnDemand {
 	if groupReducePayloadDemanded(op, out) {
 		return demandAllColumns()
@@ -264,6 +315,19 @@ func demandForJoinInput(op logicalJoin, input schemaEnv, out columnDemand) colum
 	for _, key := range op.leftKeys {
 		in.addPath(key.path)
 	}
+	for _, key := range op.leftExprKeys {
+		in.addExpr(key)
+	}
+	if op.asof != nil {
+		in.addPath(op.asof.left.path)
+	}
+	if op.condition != nil {
+		for _, source := range op.condition.sources {
+			if source.kind == logicalJoinOutputLeft {
+				in.add(source.leftName)
+			}
+		}
+	}
 	for _, source := range op.outputSources {
 		if !out.all && !out.has(source.name) {
 			continue
@@ -285,6 +349,19 @@ func demandForJoinRightSource(op logicalJoin, out columnDemand) columnDemand {
 	for _, key := range op.rightKeys {
 		in.addPath(key.path)
 	}
+	for _, key := range op.rightExprKeys {
+		in.addExpr(key)
+	}
+	if op.asof != nil {
+		in.addPath(op.asof.right.path)
+	}
+	if op.condition != nil {
+		for _, source := range op.condition.sources {
+			if source.kind == logicalJoinOutputRight {
+				in.add(source.rightName)
+			}
+		}
+	}
 	for _, source := range op.outputSources {
 		if !out.all && !out.has(source.name) {
 			continue
@@ -296,6 +373,16 @@ func demandForJoinRightSource(op logicalJoin, out columnDemand) columnDemand {
 	return in
 }
 
+// secondarySourceOutputColumnsForDemand is sourceOutputColumnsForDemand for
+// join and union sources. Their row count matters even when none of their
+// columns are used (cross joins, unions), so at least one column is loaded.
+func secondarySourceOutputColumnsForDemand(source logicalJoinSource, demand columnDemand) table.ColumnSelection {
+	if !demand.all && len(demand.columns) == 0 && len(source.env.columns) > 0 {
+		demand.add(source.env.columns[0].name)
+	}
+	return sourceOutputColumnsForDemand(source.env, source.outputColumns, demand)
+}
+
 func demandFromColumnList(cols []string) columnDemand {
 	demand := demandNoColumns()
 	for _, col := range cols {
@@ -358,6 +445,18 @@ func rewriteLogicalOpForDemand(op logicalOp, originalInput, currentInput schemaE
 		return logicalDescribe{logicalBase: logicalBaseFromEnv(env)}, env, true, nil
 	case logicalJoin:
 		return rewriteLogicalJoinForDemand(o, out)
+	case logicalWindow:
+		return rewriteLogicalWindowForDemand(o, currentInput, out)
+	case logicalExplode:
+		env := explodeOutputEnv(currentInput, o)
+		o.logicalBase = logicalBaseFromEnv(env)
+		return o, env, true, nil
+	case logicalUnpivot:
+		env := unpivotOutputEnv(currentInput, o)
+		o.logicalBase = logicalBaseFromEnv(env)
+		return o, env, true, nil
+	case logicalUnion:
+		return rewriteLogicalUnionForDemand(o, out)
 	default:
 		return op, op.OutputEnv(), true, nil
 	}
@@ -389,6 +488,32 @@ func rewriteLogicalTransformForDemand(op logicalTransform, input schemaEnv, out
 	}, env, true, nil
 }
 
+func rewriteLogicalWindowForDemand(op logicalWindow, input schemaEnv, out columnDemand) (logicalOp, schemaEnv, bool, error) {
+	assignments := make([]logicalAssignment, 0, len(op.assignments))
+	for _, assignment := range op.assignments {
+		if out.all || out.has(assignment.name) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	if len(assignments) == 0 {
+		return nil, input, false, nil
+	}
+
+	columns := input.cloneColumns()
+	for _, assignment := range assignments {
+		var target schemaEnvColumnRef
+		columns, target = upsertEnvColumn(columns, assignment.name)
+		columns[target.index].raw = finalizePlanningSchema(assignment.expr.typ)
+	}
+	env := schemaEnvFromKnownUniqueColumns(columns)
+	return logicalWindow{
+		logicalBase: logicalBaseFromEnv(env),
+		partition:   op.partition,
+		order:       op.order,
+		assignments: assignments,
+	}, env, true, nil
+}
+
 func rewriteLogicalGroupReduceForDemand(op logicalGroupReduce, out columnDemand) (logicalOp, schemaEnv, bool, error) {
 	materializeNested := groupReducePayloadDemanded(op, out)
 	assignments := make([]logicalAssignment, 0, len(op.assignments))
@@ -508,7 +633,7 @@ func rewriteLogicalJoinForDemand(op logicalJoin, out columnDemand) (logicalOp, s
 		}
 	}
 	env := schemaEnvFromKnownUniqueColumns(columns)
-	rightOutput := sourceOutputColumnsForDemand(op.right.env, op.right.outputColumns, demandForJoinRightSource(op, out))
+	rightOutput := secondarySourceOutputColumnsForDemand(op.right, demandForJoinRightSource(op, out))
 	right := op.right
 	right.outputColumns = rightOutput
 	return logicalJoin{
@@ -517,6 +642,38 @@ func rewriteLogicalJoinForDemand(op logicalJoin, out columnDemand) (logicalOp, s
 		right:         right,
 		leftKeys:      op.leftKeys,
 		rightKeys:     op.rightKeys,
+		leftExprKeys:  op.leftExprKeys,
+		rightExprKeys: op.rightExprKeys,
+		condition:     op.condition,
+		asof:          op.asof,
+		outputSources: sources,
+	}, env, true, nil
+}
+
+func rewriteLogicalUnionForDemand(op logicalUnion, out columnDemand) (logicalOp, schemaEnv, bool, error) {
+	original := op.OutputEnv()
+	if len(op.outputSources) != len(original.columns) {
+		return nil, schemaEnv{}, false, fmt.Errorf("demand pruning: union output source count mismatch")
+	}
+	columns := make([]schemaEnvColumn, 0, len(original.columns))
+	sources := make([]logicalUnionOutputSource, 0, len(original.columns))
+	rightDemand := demandNoColumns()
+	for i, col := range original.columns {
+		if out.all || out.has(col.name) {
+			columns = append(columns, col)
+			sources = append(sources, op.outputSources[i])
+			if name := op.outputSources[i].rightName; name != "" {
+				rightDemand.add(name)
+			}
+		}
+	}
+	env := schemaEnvFromKnownUniqueColumns(columns)
+	right := op.right
+	right.outputColumns = secondarySourceOutputColumnsForDemand(op.right, rightDemand)
+	return logicalUnion{
+		logicalBase:   logicalBaseFromEnv(env),
+		right:         right,
+		filename:      op.filename,
 		outputSources: sources,
 	}, env, true, nil
 }
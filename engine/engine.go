@@ -208,7 +208,7 @@ func unionBeforePathEndInSchema(schema *table.TypeDescriptor, path []string) *ta
 }
 
 func unionPathTraversalError(path []string, schema *table.TypeDescriptor) error {
-	return fmt.Errorf("%q: cannot access fields through union schema %s", strings.Join(path, "."), schema.String())
+	return fmt.Errorf("%q: cannot access fields through union schema %s; narrow it with as_record() first", strings.Join(path, "."), schema.String())
 }
 
 func validatePathDoesNotTraverseUnionInEnv(op string, env schemaEnv, path []string) error {
@@ -2169,7 +2169,7 @@ func TestDatePartNullPropagation(t *testing.T) {
 }
 
 func TestDatePartErrorOnInt(t *testing.T) {
-	expectQueryErrContains(t, salesTable(), "transform y = year(quantity)", "year() requires a string, got int")
+	expectQueryErrContains(t, salesTable(), "transform y = year(quantity)", "year() requires a date, timestamp or string, got int")
 }
 
 func TestDatePartErrorOnString(t *testing.T) {
@@ -2184,7 +2184,7 @@ func TestDatePartErrorOnString(t *testing.T) {
 
 func TestDatePartErrorOnIntResult(t *testing.T) {
 	// year(date) returns int; year(year(date)) should error
-	expectQueryErrContains(t, salesTable(), "transform y = year(date) | transform yy = year(y)", "year() requires a string, got int")
+	expectQueryErrContains(t, salesTable(), "transform y = year(date) | transform yy = year(y)", "year() requires a date, timestamp or string, got int")
 }
 
 func TestStringFuncsWrongTypeErrors(t *testing.T) {
@@ -2784,6 +2784,118 @@ func TestJoinFull(t *testing.T) {
 	}
 }
 
+func TestJoinSemiKeepsMatchingLeftRowsOnce(t *testing.T) {
+	result := runJoinQuery(t, usersTable(), `semi orders.csv on name == user_name`)
+	if strings.Join(result.Columns, ",") != "name,age,city" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "name", "Alice", "Bob", "Charlie")
+	assertColumnSchema(t, result, "age", "int")
+}
+
+func TestJoinAntiKeepsUnmatchedLeftRows(t *testing.T) {
+	left := usersTable()
+	left.AddRow([]table.Value{table.Null(), table.IntVal(50), table.StrVal("SF")})
+	result := runJoinQuery(t, left, `anti orders.csv on name == user_name`)
+	if strings.Join(result.Columns, ",") != "name,age,city" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "name", "Diana", "Eve", "Frank", "null")
+}
+
+func TestJoinCross(t *testing.T) {
+	left := table.NewTable([]string{"name", "amount"})
+	left.AddRow([]table.Value{table.StrVal("x"), table.IntVal(1)})
+	left.AddRow([]table.Value{table.StrVal("y"), table.IntVal(2)})
+	result := runJoinQuery(t, left, `cross orders.csv`)
+	if result.NumRows != 10 {
+		t.Fatalf("expected 10 rows, got %d", result.NumRows)
+	}
+	if strings.Join(result.Columns, ",") != "name,amount,order_id,user_name,product,orders_amount" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnSchema(t, result, "product", "string")
+
+	counted := runJoinQuery(t, left, `cross orders.csv | count`)
+	assertColumnStrings(t, counted, "count", "10")
+}
+
+func TestJoinResidualCondition(t *testing.T) {
+	result := runJoinQuery(t, usersTable(), `orders.csv on name == user_name and amount > age - 16`)
+	assertColumnStrings(t, result, "order_id", "2", "3", "4")
+
+	// Pairs that fail the condition do not count as matches for outer joins.
+	result = runJoinQuery(t, usersTable(), `left orders.csv on name == user_name and amount >= 20 | select name, order_id`)
+	assertColumnStrings(t, result, "name", "Alice", "Bob", "Charlie", "Diana", "Eve", "Frank")
+	assertColumnStrings(t, result, "order_id", "2", "null", "4", "null", "null", "null")
+
+	result = runJoinQuery(t, usersTable(), `anti orders.csv on name == user_name and product == "Widget"`)
+	assertColumnStrings(t, result, "name", "Diana", "Eve", "Frank")
+
+	result = runJoinQuery(t, usersTable(), `orders.csv on name == user_name and true | count`)
+	assertColumnStrings(t, result, "count", "4")
+}
+
+func TestJoinNonEquiConditionWithoutKeys(t *testing.T) {
+	left := table.NewTable([]string{"ts"})
+	for _, ts := range []int64{5, 15, 40} {
+		left.AddRow([]table.Value{table.IntVal(ts)})
+	}
+	result := runJoinQuery(t, left, `left orders.csv on ts >= amount and ts < amount + 10 | select ts, order_id`)
+	assertColumnStrings(t, result, "ts", "5", "15", "15", "40")
+	assertColumnStrings(t, result, "order_id", "null", "1", "3", "null")
+}
+
+func TestJoinExpressionKeys(t *testing.T) {
+	left := table.NewTable([]string{"email"})
+	left.AddRow([]table.Value{table.StrVal("ALICE")})
+	left.AddRow([]table.Value{table.StrVal("zed")})
+	result := runJoinQuery(t, left, `orders.csv on lower(email) == lower(user_name)`)
+	if strings.Join(result.Columns, ",") != "email,order_id,user_name,product,amount" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "order_id", "1", "2")
+}
+
+func TestJoinExpressionKeysUnifyIntAndFloat(t *testing.T) {
+	left := table.NewTable([]string{"price"})
+	left.AddRow([]table.Value{table.FloatVal(20)})
+	left.AddRow([]table.Value{table.FloatVal(30)})
+	left.AddRow([]table.Value{table.FloatVal(31.5)})
+	result := runJoinQuery(t, left, `orders.csv on price / 1 == amount * 2`)
+	assertColumnStrings(t, result, "order_id", "1", "3")
+}
+
+func TestJoinConditionUsesOutputColumnNames(t *testing.T) {
+	left := table.NewTable([]string{"user_name", "amount"})
+	left.AddRow([]table.Value{table.StrVal("Alice"), table.IntVal(20)})
+	result := runJoinQuery(t, left, `orders.csv on user_name and orders_amount > amount`)
+	assertColumnStrings(t, result, "order_id", "2")
+}
+
+func TestJoinConditionErrors(t *testing.T) {
+	cases := []struct {
+		on   string
+		want string
+	}{
+		{"name == user_name and age + 1", "join: on condition must return bool, got int"},
+		{"name == user_name and missing > 1", `join: on condition: column "missing" not found`},
+		{"age == lower(user_name)", "join: key type mismatch for age and lower(): int vs string"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.on, func(t *testing.T) {
+			q, err := parser.Parse("users.csv | join orders.csv on " + tc.on)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, err = Execute(q, usersTable(), func(string, ast.LoadOptions) (*table.Table, error) { return ordersTable(), nil })
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
+
 func TestJoinBasename(t *testing.T) {
 	cases := []struct{ in, want string }{
 		{"data/order-items.csv", "order_items"},
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/razeghi71/dq/table"
@@ -152,3 +153,30 @@ func assertNameSet(t *testing.T, result *table.Table, nameCol string, want ...st
 		}
 	}
 }
+
+// assertColumnStrings checks a column's rendered values in row order.
+func assertColumnStrings(t *testing.T, result *table.Table, name string, want ...string) {
+	t.Helper()
+	idx := result.ColIndex(name)
+	if idx < 0 {
+		t.Fatalf("column %q not found in %v", name, result.Columns)
+	}
+	got := make([]string, result.NumRows)
+	for i := range got {
+		got[i] = result.GetAt(i, idx).AsString()
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+}
+
+func assertColumnSchema(t *testing.T, result *table.Table, name, want string) {
+	t.Helper()
+	idx := result.ColIndex(name)
+	if idx < 0 {
+		t.Fatalf("column %q not found in %v", name, result.Columns)
+	}
+	if got := result.Col(idx).Schema().String(); got != want {
+		t.Fatalf("%s schema: got %s, want %s", name, got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/parser"
+	"github.com/razeghi71/dq/table"
+)
+
+func explodeOrdersTable() *table.Table {
+	order := func(status string, amount int64) table.Value {
+		return table.RecordVal([]table.RecordField{
+			{Name: "amount", Value: table.IntVal(amount)},
+			{Name: "status", Value: table.StrVal(status)},
+		})
+	}
+	t := table.NewTable([]string{"id", "orders"})
+	t.AddRow([]table.Value{table.IntVal(1), table.ListVal([]table.Value{order("paid", 3), order("open", 4)})})
+	t.AddRow([]table.Value{table.IntVal(2), table.ListVal(nil)})
+	t.AddRow([]table.Value{table.IntVal(3), table.Null()})
+	t.AddRow([]table.Value{table.IntVal(4), table.ListVal([]table.Value{order("paid", 5)})})
+	return t
+}
+
+func TestExplodeEmitsOneRowPerElement(t *testing.T) {
+	result := runQuery(t, explodeOrdersTable(), `explode orders | filter { orders.status == "paid" } | transform amount = orders.amount | select id, amount`)
+	assertColumnStrings(t, result, "id", "1", "4")
+	assertColumnStrings(t, result, "amount", "3", "5")
+}
+
+func TestExplodeReplacesListColumnWithElementSchema(t *testing.T) {
+	result := runQuery(t, explodeOrdersTable(), "explode orders")
+	if strings.Join(result.Columns, ",") != "id,orders" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "id", "1", "1", "4")
+	assertColumnSchema(t, result, "orders", "record<amount:int, status:string>")
+}
+
+func TestExplodeOuterKeepsNullAndEmptyLists(t *testing.T) {
+	result := runQuery(t, explodeOrdersTable(), "explode outer orders as order at pos | select id, order, pos")
+	assertColumnStrings(t, result, "id", "1", "1", "2", "3", "4")
+	assertColumnStrings(t, result, "pos", "0", "1", "null", "null", "0")
+	assertColumnSchema(t, result, "order", "record<amount:int, status:string>?")
+	assertColumnSchema(t, result, "pos", "int?")
+}
+
+func TestExplodeNestedPathAppendsFlattenedColumn(t *testing.T) {
+	input := table.NewTable([]string{"id", "meta"})
+	input.AddRow([]table.Value{table.IntVal(1), table.RecordVal([]table.RecordField{{Name: "xs", Value: table.ListVal([]table.Value{table.IntVal(7), table.IntVal(8)})}})})
+	result := runQuery(t, input, "explode meta.xs at i")
+	if strings.Join(result.Columns, ",") != "id,meta,meta_xs,i" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "meta_xs", "7", "8")
+	assertColumnStrings(t, result, "i", "0", "1")
+	assertColumnSchema(t, result, "i", "int")
+}
+
+func TestExplodePlanningErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"explode missing", `explode "missing"`},
+		{"explode id", `explode: column "id" must be a list, got int`},
+		{"explode orders as id", `explode: element column "id" already exists`},
+		{"explode orders at id", `explode: position column "id" already exists`},
+		{"explode orders as o at o", `explode: position column "o" already exists`},
+		{"explode orders | select orders.missing", `"missing"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := parser.Parse("test.csv | " + tc.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, err = Execute(q, explodeOrdersTable(), nil)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestExplodeRenamedSourceNameCanHoldPosition(t *testing.T) {
+	result := runQuery(t, explodeOrdersTable(), "explode orders as order at orders | select id, orders")
+	assertColumnStrings(t, result, "orders", "0", "1", "0")
+}
+
+func TestExplodeStreamingMatchesMaterialized(t *testing.T) {
+	query := "transform unused = id * 2 | explode outer orders at pos | select id, pos"
+	q, err := parser.Parse("test.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	streamed, err := ExecuteStreaming(q, explodeOrdersTable(), nil)
+	if err != nil {
+		t.Fatalf("streaming error: %v", err)
+	}
+	materialized := runQuery(t, explodeOrdersTable(), query)
+	if streamed.String() != materialized.String() {
+		t.Fatalf("streaming result differs:\n%s\nvs\n%s", streamed.String(), materialized.String())
+	}
+	assertColumnStrings(t, materialized, "pos", "0", "1", "null", "null", "0")
+}
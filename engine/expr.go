@@ -15,6 +15,9 @@ type EvalContext struct {
 	Table     *table.Table
 	RowIdx    int
 	RowValues []table.Value
+	// Lambda holds the parameter values of the lambdas being evaluated,
+	// indexed by the slot each parameter was bound to during planning.
+	Lambda []table.Value
 }
 
 func evalLiteral(e *ast.LiteralExpr) table.Value {
@@ -23,6 +26,8 @@ func evalLiteral(e *ast.LiteralExpr) table.Value {
 		return table.IntVal(e.Int)
 	case "float":
 		return table.FloatVal(e.Float)
+	case "decimal":
+		return table.Value{Type: table.TypeDecimal, Str: e.Str}
 	case "string":
 		return table.StrVal(e.Str)
 	case "bool":
@@ -39,6 +44,15 @@ func evalArith(op string, left, right table.Value) (table.Value, error) {
 	if op == "+" && left.Type == table.TypeString && right.Type == table.TypeString {
 		return table.StrVal(left.Str + right.Str), nil
 	}
+	if isTemporalKind(left.Type) || isTemporalKind(right.Type) {
+		return evalTemporalArith(op, left, right)
+	}
+	if isExactNumericValue(left) && isExactNumericValue(right) && (left.Type == table.TypeDecimal || right.Type == table.TypeDecimal) {
+		return evalDecimalArith(op, left, right)
+	}
+	if op == "%" {
+		return evalRemainder(left, right)
+	}
 
 	if op != "/" && left.Type == table.TypeInt && right.Type == table.TypeInt {
 		result, err := evalIntArith(op, left.Int, right.Int)
@@ -74,6 +88,37 @@ func evalArith(op string, left, right table.Value) (table.Value, error) {
 	return table.FloatVal(result), nil
 }
 
+// evalConcat joins two strings for ||; a null operand makes the result null.
+func evalConcat(left, right table.Value) (table.Value, error) {
+	if left.IsNull() || right.IsNull() {
+		return table.Null(), nil
+	}
+	if left.Type != table.TypeString || right.Type != table.TypeString {
+		return table.Null(), fmt.Errorf("cannot perform || on %v and %v", left.AsString(), right.AsString())
+	}
+	return table.StrVal(left.Str + right.Str), nil
+}
+
+// evalRemainder computes left % right for ints and floats. The result takes
+// the sign of the dividend, and a zero divisor yields null as in division.
+func evalRemainder(left, right table.Value) (table.Value, error) {
+	if left.Type == table.TypeInt && right.Type == table.TypeInt {
+		if right.Int == 0 {
+			return table.Null(), nil
+		}
+		return table.IntVal(left.Int % right.Int), nil
+	}
+	lf, lok := left.AsFloat()
+	rf, rok := right.AsFloat()
+	if !lok || !rok {
+		return table.Null(), fmt.Errorf("cannot perform %% on %v and %v", left.AsString(), right.AsString())
+	}
+	if rf == 0 {
+		return table.Null(), nil
+	}
+	return table.FloatVal(math.Mod(lf, rf)), nil
+}
+
 func evalIntArith(op string, left, right int64) (int64, error) {
 	switch op {
 	case "+":
@@ -218,6 +263,8 @@ func expressionValuesCompare(a, b table.Value) (int, bool, error) {
 		return 0, false, fmt.Errorf("type mismatch: %s vs %s", table.TypeName(a.Type), table.TypeName(b.Type))
 	}
 	switch a.Type {
+	case table.TypeDate, table.TypeTimestamp, table.TypeDuration:
+		return compareInt64(a.Int, b.Int), false, nil
 	case table.TypeString:
 		switch {
 		case a.Str < b.Str:
@@ -254,6 +301,8 @@ func expressionValuesEqual(a, b table.Value, topLevelMismatchIsError bool) (bool
 		return a.Str == b.Str, nil
 	case table.TypeBool:
 		return a.Bool == b.Bool, nil
+	case table.TypeDate, table.TypeTimestamp, table.TypeDuration:
+		return a.Int == b.Int, nil
 	case table.TypeList:
 		if len(a.List) != len(b.List) {
 			return false, nil
@@ -267,6 +316,8 @@ func expressionValuesEqual(a, b table.Value, topLevelMismatchIsError bool) (bool
 		return true, nil
 	case table.TypeRecord:
 		return expressionRecordsEqual(a.Fields, b.Fields)
+	case table.TypeMap:
+		return expressionMapsEqual(a.Fields, b.Fields)
 	default:
 		if topLevelMismatchIsError {
 			return false, fmt.Errorf("%s values are not comparable", table.TypeName(a.Type))
@@ -311,8 +362,35 @@ func expressionRecordsEqual(a, b []table.RecordField) (bool, error) {
 	return true, nil
 }
 
+// expressionMapsEqual compares maps by their key sets and values. Unlike a
+// record field, a missing key does not equal a null value.
+func expressionMapsEqual(a, b []table.RecordField) (bool, error) {
+	if len(a) != len(b) {
+		return false, nil
+	}
+	right := make(map[string]table.Value, len(b))
+	for _, entry := range b {
+		right[entry.Name] = entry.Value
+	}
+	for _, entry := range a {
+		other, ok := right[entry.Name]
+		if !ok {
+			return false, nil
+		}
+		eq, err := expressionValuesEqual(entry.Value, other, false)
+		if err != nil || !eq {
+			return eq, err
+		}
+	}
+	return true, nil
+}
+
 func isNumericValue(v table.Value) bool {
-	return v.Type == table.TypeInt || v.Type == table.TypeFloat
+	return v.Type == table.TypeInt || v.Type == table.TypeFloat || v.Type == table.TypeDecimal
+}
+
+func isExactNumericValue(v table.Value) bool {
+	return v.Type == table.TypeInt || v.Type == table.TypeDecimal
 }
 
 func compareNumericValuesExact(a, b table.Value) (int, bool, error) {
@@ -326,6 +404,13 @@ func compareNumericValuesExact(a, b table.Value) (int, bool, error) {
 	case a.Type == table.TypeFloat && b.Type == table.TypeInt:
 		cmp, unordered, err := compareIntFloatExact(b.Int, a.Float)
 		return -cmp, unordered, err
+	case isExactNumericValue(a) && isExactNumericValue(b):
+		return table.CompareDecimal(a, b), false, nil
+	case a.Type == table.TypeDecimal && b.Type == table.TypeFloat:
+		return compareDecimalFloatExact(a, b.Float)
+	case a.Type == table.TypeFloat && b.Type == table.TypeDecimal:
+		cmp, unordered, err := compareDecimalFloatExact(b, a.Float)
+		return -cmp, unordered, err
 	default:
 		return 0, false, fmt.Errorf("type mismatch: %s vs %s", table.TypeName(a.Type), table.TypeName(b.Type))
 	}
@@ -378,6 +463,20 @@ func compareIntFloatExact(i int64, f float64) (int, bool, error) {
 	return intRat.Cmp(floatRat), false, nil
 }
 
+func compareDecimalFloatExact(d table.Value, f float64) (int, bool, error) {
+	if math.IsNaN(f) {
+		return 0, true, nil
+	}
+	if math.IsInf(f, 0) {
+		return -int(math.Copysign(1, f)), false, nil
+	}
+	decRat, ok := new(big.Rat).SetString(d.Str)
+	if !ok {
+		return 0, false, fmt.Errorf("invalid decimal value %q", d.Str)
+	}
+	return decRat.Cmp(new(big.Rat).SetFloat64(f)), false, nil
+}
+
 func cmpResult(op string, cmp int) bool {
 	switch op {
 	case "==":
@@ -37,6 +37,10 @@ func evalTypedExpression(expr typedExpr, ctx *EvalContext) (table.Value, error)
 			return table.Null(), err
 		}
 		return coercePlannedExpressionValue(v, expr.coerceTo)
+	case *boundLambdaParam:
+		v, err = evalLambdaParam(e, ctx)
+	case *boundLambda:
+		err = lambdaOutsideCallError()
 	default:
 		err = fmt.Errorf("unknown bound expression type %T", expr.bound)
 	}
@@ -75,6 +79,13 @@ func evalBoundColumn(e *boundColumn, ctx *EvalContext) (table.Value, error) {
 	return resolveNestedValuePath(col.Get(ctx.RowIdx), e.nestedPath)
 }
 
+func evalLambdaParam(e *boundLambdaParam, ctx *EvalContext) (table.Value, error) {
+	if ctx == nil || e.slot >= len(ctx.Lambda) {
+		return table.Null(), fmt.Errorf("lambda parameter %q is not bound", e.rawPath[0])
+	}
+	return resolveNestedValuePath(ctx.Lambda[e.slot], e.nestedPath)
+}
+
 func evalTypedBinary(op string, leftExpr, rightExpr *typedExpr, ctx *EvalContext) (table.Value, error) {
 	left, err := evalTypedChild(leftExpr, ctx)
 	if err != nil {
@@ -85,11 +96,13 @@ func evalTypedBinary(op string, leftExpr, rightExpr *typedExpr, ctx *EvalContext
 		return table.Null(), err
 	}
 	switch op {
-	case "+", "-", "*", "/":
+	case "+", "-", "*", "/", "%":
 		if left.IsNull() || right.IsNull() {
 			return table.Null(), nil
 		}
 		return evalArith(op, left, right)
+	case "||":
+		return evalConcat(left, right)
 	case "==", "!=", "<", ">", "<=", ">=":
 		return evalComparison(op, left, right)
 	case "and":
@@ -120,8 +133,16 @@ func evalTypedUnary(op string, operandExpr *typedExpr, ctx *EvalContext) (table.
 				return table.Null(), err
 			}
 			return table.IntVal(v), nil
+		case table.TypeDuration:
+			v, err := negInt64(operand.Int)
+			if err != nil {
+				return table.Null(), err
+			}
+			return table.DurationVal(v), nil
 		case table.TypeFloat:
 			return table.FloatVal(-operand.Float), nil
+		case table.TypeDecimal:
+			return negateDecimal(operand), nil
 		default:
 			return table.Null(), fmt.Errorf("cannot negate %v", operand.AsString())
 		}
@@ -199,7 +220,7 @@ func evalTypedAggregateExpression(expr typedExpr, nested *table.Table) (table.Va
 	case *boundUnary:
 		return evalTypedAggregateUnary(e.raw.Op, expr.operand, nested)
 	case *boundCall:
-		return evalAggregateCall(e.raw, nested)
+		return evalTypedAggregateCall(expr, e, nested)
 	case *boundIsNull:
 		return evalTypedAggregateIsNull(e.raw.Negated, expr.operand, nested)
 	case *boundCoerce:
@@ -229,11 +250,13 @@ func evalTypedAggregateBinary(op string, leftExpr, rightExpr *typedExpr, nested
 		return table.Null(), err
 	}
 	switch op {
-	case "+", "-", "*", "/":
+	case "+", "-", "*", "/", "%":
 		if left.IsNull() || right.IsNull() {
 			return table.Null(), nil
 		}
 		return evalArith(op, left, right)
+	case "||":
+		return evalConcat(left, right)
 	case "==", "!=", "<", ">", "<=", ">=":
 		return evalComparison(op, left, right)
 	case "and":
@@ -264,8 +287,16 @@ func evalTypedAggregateUnary(op string, operandExpr *typedExpr, nested *table.Ta
 				return table.Null(), err
 			}
 			return table.IntVal(v), nil
+		case table.TypeDuration:
+			v, err := negInt64(operand.Int)
+			if err != nil {
+				return table.Null(), err
+			}
+			return table.DurationVal(v), nil
 		case table.TypeFloat:
 			return table.FloatVal(-operand.Float), nil
+		case table.TypeDecimal:
+			return negateDecimal(operand), nil
 		default:
 			return table.Null(), fmt.Errorf("cannot negate %v", operand.AsString())
 		}
@@ -166,7 +166,7 @@ func TestEvalTypedAggregateExpressionDefensiveErrors(t *testing.T) {
 		},
 		{
 			name: "binary_unknown_operator",
-			expr: typedExpr{bound: &boundBinary{raw: &ast.BinaryExpr{Op: "%"}}, left: typedExprPtr(lit(1)), right: typedExprPtr(lit(1))},
+			expr: typedExpr{bound: &boundBinary{raw: &ast.BinaryExpr{Op: "^"}}, left: typedExprPtr(lit(1)), right: typedExprPtr(lit(1))},
 			want: "unknown operator",
 		},
 		{
@@ -394,7 +394,7 @@ func TestEvalTypedExpressionDefensiveErrors(t *testing.T) {
 		},
 		{
 			name: "binary_unknown_operator",
-			expr: typedExpr{bound: &boundBinary{raw: &ast.BinaryExpr{Op: "%"}}, left: typedExprPtr(lit(1)), right: typedExprPtr(lit(1))},
+			expr: typedExpr{bound: &boundBinary{raw: &ast.BinaryExpr{Op: "^"}}, left: typedExprPtr(lit(1)), right: typedExprPtr(lit(1))},
 			want: "unknown operator",
 		},
 		{
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/razeghi71/dq/ast"
@@ -61,15 +62,33 @@ type boundIsNull struct {
 
 type boundCoerce struct{}
 
-func (*boundLiteral) boundExprNode() {}
-func (*boundColumn) boundExprNode()  {}
-func (*boundBinary) boundExprNode()  {}
-func (*boundUnary) boundExprNode()   {}
-func (*boundCall) boundExprNode()    {}
-func (*boundStruct) boundExprNode()  {}
-func (*boundList) boundExprNode()    {}
-func (*boundIsNull) boundExprNode()  {}
-func (*boundCoerce) boundExprNode()  {}
+// boundLambda is a lambda argument of a higher-order builtin. Its parameters
+// occupy the EvalContext lambda slots from slot up; the body is the typed
+// expression's operand.
+type boundLambda struct {
+	raw  *ast.LambdaExpr
+	slot int
+}
+
+// boundLambdaParam reads a lambda parameter, or a field nested inside it.
+type boundLambdaParam struct {
+	rawPath    []string
+	slot       int
+	nestedPath []string
+	typ        *table.TypeDescriptor
+}
+
+func (*boundLiteral) boundExprNode()     {}
+func (*boundColumn) boundExprNode()      {}
+func (*boundBinary) boundExprNode()      {}
+func (*boundUnary) boundExprNode()       {}
+func (*boundCall) boundExprNode()        {}
+func (*boundStruct) boundExprNode()      {}
+func (*boundList) boundExprNode()        {}
+func (*boundIsNull) boundExprNode()      {}
+func (*boundCoerce) boundExprNode()      {}
+func (*boundLambda) boundExprNode()      {}
+func (*boundLambdaParam) boundExprNode() {}
 
 type logicalBoundExpr interface {
 	logicalBoundExprNode()
@@ -95,8 +114,9 @@ type logicalBoundUnary struct {
 }
 
 type logicalBoundCall struct {
-	raw  *ast.FuncCallExpr
-	args []logicalBoundExpr
+	raw    *ast.FuncCallExpr
+	args   []logicalBoundExpr
+	filter logicalBoundExpr
 }
 
 type logicalBoundStructField struct {
@@ -122,15 +142,29 @@ type logicalBoundIsNull struct {
 
 type logicalBoundCoerce struct{}
 
-func (*logicalBoundLiteral) logicalBoundExprNode() {}
-func (*logicalBoundColumn) logicalBoundExprNode()  {}
-func (*logicalBoundBinary) logicalBoundExprNode()  {}
-func (*logicalBoundUnary) logicalBoundExprNode()   {}
-func (*logicalBoundCall) logicalBoundExprNode()    {}
-func (*logicalBoundStruct) logicalBoundExprNode()  {}
-func (*logicalBoundList) logicalBoundExprNode()    {}
-func (*logicalBoundIsNull) logicalBoundExprNode()  {}
-func (*logicalBoundCoerce) logicalBoundExprNode()  {}
+type logicalBoundLambda struct {
+	raw  *ast.LambdaExpr
+	slot int
+	body logicalBoundExpr
+}
+
+type logicalBoundLambdaParam struct {
+	rawPath []string
+	slot    int
+	typ     *table.TypeDescriptor
+}
+
+func (*logicalBoundLiteral) logicalBoundExprNode()     {}
+func (*logicalBoundColumn) logicalBoundExprNode()      {}
+func (*logicalBoundBinary) logicalBoundExprNode()      {}
+func (*logicalBoundUnary) logicalBoundExprNode()       {}
+func (*logicalBoundCall) logicalBoundExprNode()        {}
+func (*logicalBoundStruct) logicalBoundExprNode()      {}
+func (*logicalBoundList) logicalBoundExprNode()        {}
+func (*logicalBoundIsNull) logicalBoundExprNode()      {}
+func (*logicalBoundCoerce) logicalBoundExprNode()      {}
+func (*logicalBoundLambda) logicalBoundExprNode()      {}
+func (*logicalBoundLambdaParam) logicalBoundExprNode() {}
 
 type typedExpr struct {
 	bound    boundExpr
@@ -144,6 +178,8 @@ type typedExpr struct {
 	fields   []typedStructField
 	elements []typedExpr
 	coerceTo *table.TypeDescriptor
+	// filter is an aggregate's "where { cond }", bound to the nested rows.
+	filter *typedExpr
 }
 
 type typedStructField struct {
@@ -163,6 +199,7 @@ type logicalTypedExpr struct {
 	fields   []logicalTypedStructField
 	elements []logicalTypedExpr
 	coerceTo *table.TypeDescriptor
+	filter   *logicalTypedExpr
 }
 
 type logicalTypedStructField struct {
@@ -174,6 +211,14 @@ type logicalTypedStructField struct {
 type schemaEnv struct {
 	columns []schemaEnvColumn
 	index   map[string]int
+	// lambdaParams are the parameters of the lambdas enclosing the expression
+	// being bound, outermost first. A parameter's position is its slot.
+	lambdaParams []schemaEnvLambdaParam
+}
+
+type schemaEnvLambdaParam struct {
+	name string
+	typ  *table.TypeDescriptor
 }
 
 type schemaEnvColumn struct {
@@ -286,6 +331,27 @@ func (e schemaEnv) lookupColumn(name string) (schemaEnvColumnRef, bool) {
 	return schemaEnvColumnRef{}, false
 }
 
+// withLambdaParams returns the environment seen by a lambda body: the same
+// columns plus the lambda's parameters, which shadow columns of the same name.
+func (e schemaEnv) withLambdaParams(names []string, types []*table.TypeDescriptor) schemaEnv {
+	params := make([]schemaEnvLambdaParam, len(e.lambdaParams), len(e.lambdaParams)+len(names))
+	copy(params, e.lambdaParams)
+	for i, name := range names {
+		params = append(params, schemaEnvLambdaParam{name: name, typ: types[i]})
+	}
+	e.lambdaParams = params
+	return e
+}
+
+func (e schemaEnv) lookupLambdaParam(name string) (int, schemaEnvLambdaParam, bool) {
+	for i := len(e.lambdaParams) - 1; i >= 0; i-- {
+		if e.lambdaParams[i].name == name {
+			return i, e.lambdaParams[i], true
+		}
+	}
+	return -1, schemaEnvLambdaParam{}, false
+}
+
 func (e schemaEnv) columnAt(i int) (schemaEnvColumnRef, bool) {
 	if i < 0 || i >= len(e.columns) {
 		return schemaEnvColumnRef{}, false
@@ -378,7 +444,7 @@ func schemaIsNormalized(schema *table.TypeDescriptor) bool {
 				return false
 			}
 		}
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		return schemaIsNormalized(schema.Elem)
 	case table.TypeUnion:
 		return false
@@ -409,7 +475,7 @@ func schemaNeedsFinalization(schema *table.TypeDescriptor) bool {
 				return true
 			}
 		}
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		return schema.Elem == nil || schemaNeedsFinalization(schema.Elem)
 	case table.TypeUnion:
 		for _, branch := range schema.Branches {
@@ -426,6 +492,15 @@ func bindLogicalExpressionInEnv(expr ast.Expr, env schemaEnv) (logicalBoundExpr,
 	case *ast.LiteralExpr:
 		return &logicalBoundLiteral{raw: e}, nil
 	case *ast.ColumnExpr:
+		if len(e.Path) > 0 {
+			if slot, param, ok := env.lookupLambdaParam(e.Path[0]); ok {
+				typ, err := resolveNestedPathSchema(e.Path, param.typ)
+				if err != nil {
+					return nil, err
+				}
+				return &logicalBoundLambdaParam{rawPath: clonePath(e.Path), slot: slot, typ: typ}, nil
+			}
+		}
 		return bindColumnPathLogicalInEnv(env, e.Path)
 	case *ast.BinaryExpr:
 		left, err := bindLogicalExpressionInEnv(e.Left, env)
@@ -444,6 +519,12 @@ func bindLogicalExpressionInEnv(expr ast.Expr, env schemaEnv) (logicalBoundExpr,
 		}
 		return &logicalBoundUnary{raw: e, operand: operand}, nil
 	case *ast.FuncCallExpr:
+		if e.Filter != nil {
+			return nil, aggregateFilterOutsideReduceError(e.Name)
+		}
+		if spec, ok := builtinCatalog[e.Name]; ok && spec.Lambda != nil {
+			return bindLogicalLambdaCall(e, spec.Lambda, env)
+		}
 		args := make([]logicalBoundExpr, len(e.Args))
 		for i, arg := range e.Args {
 			bound, err := bindLogicalExpressionInEnv(arg, env)
@@ -479,11 +560,107 @@ func bindLogicalExpressionInEnv(expr ast.Expr, env schemaEnv) (logicalBoundExpr,
 			return nil, err
 		}
 		return &logicalBoundIsNull{raw: e, operand: operand}, nil
+	case *ast.LambdaExpr:
+		return nil, lambdaOutsideCallError()
 	default:
 		return nil, fmt.Errorf("unknown expression type %T", expr)
 	}
 }
 
+// maxLambdaTypingPasses bounds how often a lambda body is rebound while its
+// parameter types are still widening to what the body returns.
+const maxLambdaTypingPasses = 3
+
+// bindLogicalLambdaCall binds a higher-order builtin such as list_filter. The
+// other arguments are bound and type checked first so the lambda's parameters
+// can be typed from them, usually from the list's element schema. A builtin
+// whose parameter types depend on the body, like list_reduce's accumulator,
+// is rebound until those types settle.
+func bindLogicalLambdaCall(e *ast.FuncCallExpr, spec *lambdaSpec, env schemaEnv) (logicalBoundExpr, error) {
+	if len(e.Args) != spec.Arity {
+		return nil, fmt.Errorf("%s() takes %d arguments (%s), got %d", e.Name, spec.Arity, spec.Usage, len(e.Args))
+	}
+	lambda, ok := e.Args[spec.Arg].(*ast.LambdaExpr)
+	if !ok {
+		return nil, fmt.Errorf("%s() argument %d must be a lambda such as x -> x > 0 (%s)", e.Name, spec.Arg+1, spec.Usage)
+	}
+	args := make([]logicalBoundExpr, len(e.Args))
+	signature := make([]typedExpr, len(e.Args))
+	for i, arg := range e.Args {
+		if i == spec.Arg {
+			continue
+		}
+		bound, err := bindLogicalExpressionInEnv(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		typed, err := typeCheckLogicalExpression(bound)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = bound
+		signature[i] = logicalSignatureExpr(typed)
+	}
+
+	var prev []*table.TypeDescriptor
+	var body *table.TypeDescriptor
+	for pass := 0; ; pass++ {
+		params, err := spec.Params(signature, body)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil && sameLambdaParamSchemas(prev, params) {
+			break
+		}
+		if pass == maxLambdaTypingPasses {
+			return nil, fmt.Errorf("%s() lambda parameter types do not settle: %s", e.Name, lambdaParamSchemasString(lambda.Params, params))
+		}
+		if len(lambda.Params) != len(params) {
+			return nil, fmt.Errorf("%s() lambda takes %d parameter(s), got %d", e.Name, len(params), len(lambda.Params))
+		}
+		bound, err := bindLogicalExpressionInEnv(lambda.Body, env.withLambdaParams(lambda.Params, params))
+		if err != nil {
+			return nil, fmt.Errorf("%s() lambda: %w", e.Name, err)
+		}
+		typed, err := typeCheckLogicalExpression(bound)
+		if err != nil {
+			return nil, fmt.Errorf("%s() lambda: %w", e.Name, err)
+		}
+		args[spec.Arg] = &logicalBoundLambda{raw: lambda, slot: len(env.lambdaParams), body: bound}
+		body = typed.typ
+		prev = params
+	}
+	return &logicalBoundCall{raw: e, args: args}, nil
+}
+
+func sameLambdaParamSchemas(a, b []*table.TypeDescriptor) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !samePlanningSchema(normalizePlanningSchema(a[i]), normalizePlanningSchema(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func lambdaParamSchemasString(names []string, types []*table.TypeDescriptor) string {
+	parts := make([]string, len(types))
+	for i, typ := range types {
+		name := "_"
+		if i < len(names) {
+			name = names[i]
+		}
+		parts[i] = name + " " + schemaString(typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func lambdaOutsideCallError() error {
+	return fmt.Errorf("a lambda can only be passed to a higher-order function such as list_filter, list_map, list_any, list_all or list_reduce")
+}
+
 func bindColumnPathInEnv(env schemaEnv, path []string) (*boundColumn, error) {
 	idx, typ, err := resolveColumnPathInEnv(env, path)
 	if err != nil {
@@ -514,11 +691,20 @@ func resolveColumnPathInEnv(env schemaEnv, path []string) (int, *table.TypeDescr
 	if !ok {
 		return -1, nil, columnNotFoundError(path[0], env.columnNames())
 	}
-	typ := col.column.planningSchema()
+	typ, err := resolveNestedPathSchema(path, col.column.planningSchema())
+	if err != nil {
+		return -1, nil, err
+	}
+	return col.index, typ, nil
+}
+
+// resolveNestedPathSchema walks path[1:] through the record schema typ of
+// path[0]. A field under a nullable parent is itself nullable.
+func resolveNestedPathSchema(path []string, typ *table.TypeDescriptor) (*table.TypeDescriptor, error) {
 	parentNullable := false
 	for _, seg := range path[1:] {
 		if typ == nil {
-			return -1, nil, fmt.Errorf("field %q not found in column %q: parent schema is unknown", seg, strings.Join(path, "."))
+			return nil, fmt.Errorf("field %q not found in column %q: parent schema is unknown", seg, strings.Join(path, "."))
 		}
 		if typ.Nullable || typ.Kind == table.TypeNull {
 			parentNullable = true
@@ -526,9 +712,11 @@ func resolveColumnPathInEnv(env schemaEnv, path []string) (int, *table.TypeDescr
 		current := finalizePlanningSchema(typ)
 		switch current.Kind {
 		case table.TypeUnion:
-			return -1, nil, unionPathTraversalError(path, current)
+			return nil, unionPathTraversalError(path, current)
 		case table.TypeList:
-			return -1, nil, fmt.Errorf("cannot access field %q through list column %q of type %s", seg, strings.Join(path, "."), current.String())
+			return nil, fmt.Errorf("cannot access field %q through list column %q of type %s", seg, strings.Join(path, "."), current.String())
+		case table.TypeMap:
+			return nil, fmt.Errorf("cannot access field %q through map column %q of type %s; use map_get()", seg, strings.Join(path, "."), current.String())
 		case table.TypeRecord:
 			var next *table.TypeDescriptor
 			for _, field := range current.Fields {
@@ -538,18 +726,18 @@ func resolveColumnPathInEnv(env schemaEnv, path []string) (int, *table.TypeDescr
 				}
 			}
 			if next == nil {
-				return -1, nil, fmt.Errorf("field %q not found in column %q of type %s", seg, path[0], current.String())
+				return nil, fmt.Errorf("field %q not found in column %q of type %s", seg, path[0], current.String())
 			}
 			typ = next
 		default:
-			return -1, nil, fmt.Errorf("cannot access field %q in column %q of type %s", seg, path[0], current.String())
+			return nil, fmt.Errorf("cannot access field %q in column %q of type %s", seg, path[0], current.String())
 		}
 	}
 	typ = normalizePlanningSchema(typ)
 	if parentNullable {
 		typ = table.WithNullable(typ)
 	}
-	return col.index, typ, nil
+	return typ, nil
 }
 
 func bindLogicalReduceExpression(expr ast.Expr, nestedSchema *table.TypeDescriptor) (logicalBoundExpr, error) {
@@ -586,7 +774,11 @@ func bindLogicalReduceExpression(expr ast.Expr, nestedSchema *table.TypeDescript
 		if err != nil {
 			return nil, err
 		}
-		return &logicalBoundCall{raw: e, args: args}, nil
+		filter, err := bindLogicalAggregateFilter(e, nestedSchema)
+		if err != nil {
+			return nil, err
+		}
+		return &logicalBoundCall{raw: e, args: args, filter: filter}, nil
 	case *ast.StructExpr:
 		return nil, fmt.Errorf("struct constructor is not supported in reduce")
 	case *ast.ListExpr:
@@ -597,6 +789,8 @@ func bindLogicalReduceExpression(expr ast.Expr, nestedSchema *table.TypeDescript
 			return nil, err
 		}
 		return &logicalBoundIsNull{raw: e, operand: operand}, nil
+	case *ast.LambdaExpr:
+		return nil, lambdaOutsideCallError()
 	default:
 		return nil, fmt.Errorf("unknown expression type %T", expr)
 	}
@@ -606,29 +800,75 @@ func bindLogicalAggregateArgs(e *ast.FuncCallExpr, nestedSchema *table.TypeDescr
 	if err := validateAggregateFunctionArity(e); err != nil {
 		return nil, err
 	}
-	if e.Name == "count" {
+	if len(e.Args) == 0 {
 		return nil, nil
 	}
-	if len(e.Args) != 1 {
-		return nil, fmt.Errorf("%s() takes 1 argument, got %d", e.Name, len(e.Args))
+	env, err := envForRecordSchema(nestedSchema)
+	if err != nil {
+		return nil, err
 	}
-	col, ok := e.Args[0].(*ast.ColumnExpr)
-	if !ok {
-		return nil, fmt.Errorf("%s() argument must be a column reference", e.Name)
+	args := make([]logicalBoundExpr, len(e.Args))
+	for i, arg := range e.Args {
+		bound, err := bindLogicalExpressionInEnv(arg, env)
+		if err != nil {
+			if col, ok := arg.(*ast.ColumnExpr); ok {
+				return nil, fmt.Errorf("%s(%s): %w", e.Name, strings.Join(col.Path, "."), err)
+			}
+			return nil, fmt.Errorf("%s(): %w", e.Name, err)
+		}
+		args[i] = bound
 	}
-	bound, err := bindLogicalAggregateColumnPath(nestedSchema, col.Path)
-	if err != nil {
-		return nil, fmt.Errorf("%s(%s): %w", e.Name, strings.Join(col.Path, "."), err)
+	return args, nil
+}
+
+// logicalNestedAggregate returns the name of the first aggregate call inside
+// an aggregate argument.
+func logicalNestedAggregate(expr logicalBoundExpr) (string, bool) {
+	var children []logicalBoundExpr
+	switch e := expr.(type) {
+	case *logicalBoundCall:
+		if spec, ok := builtinCatalog[e.raw.Name]; ok && spec.Category == builtinAggregate {
+			return e.raw.Name, true
+		}
+		children = e.args
+	case *logicalBoundBinary:
+		children = []logicalBoundExpr{e.left, e.right}
+	case *logicalBoundUnary:
+		children = []logicalBoundExpr{e.operand}
+	case *logicalBoundIsNull:
+		children = []logicalBoundExpr{e.operand}
+	case *logicalBoundStruct:
+		for _, field := range e.fields {
+			children = append(children, field.expr)
+		}
+	case *logicalBoundList:
+		children = e.elements
+	case *logicalBoundLambda:
+		children = []logicalBoundExpr{e.body}
+	}
+	for _, child := range children {
+		if name, ok := logicalNestedAggregate(child); ok {
+			return name, true
+		}
 	}
-	return []logicalBoundExpr{bound}, nil
+	return "", false
 }
 
-func bindLogicalAggregateColumnPath(nestedSchema *table.TypeDescriptor, path []string) (*logicalBoundColumn, error) {
+// bindLogicalAggregateFilter binds the "where { cond }" of an aggregate as a
+// row-level expression over the nested rows.
+func bindLogicalAggregateFilter(e *ast.FuncCallExpr, nestedSchema *table.TypeDescriptor) (logicalBoundExpr, error) {
+	if e.Filter == nil {
+		return nil, nil
+	}
 	env, err := envForRecordSchema(nestedSchema)
 	if err != nil {
 		return nil, err
 	}
-	return bindColumnPathLogicalInEnv(env, path)
+	filter, err := bindLogicalExpressionInEnv(e.Filter, env)
+	if err != nil {
+		return nil, fmt.Errorf("%s() where: %w", e.Name, err)
+	}
+	return filter, nil
 }
 
 func envForRecordSchema(schema *table.TypeDescriptor) (schemaEnv, error) {
@@ -721,6 +961,7 @@ func typeCheckLogicalExpression(expr logicalBoundExpr) (logicalTypedExpr, error)
 		if err != nil {
 			return logicalTypedExpr{}, err
 		}
+		left, right = exactDecimalLiterals(left, right)
 		typ, err := checkBinarySignature(e.raw.Op, logicalSignatureExpr(left), logicalSignatureExpr(right))
 		if err != nil {
 			return logicalTypedExpr{}, err
@@ -752,6 +993,9 @@ func typeCheckLogicalExpression(expr logicalBoundExpr) (logicalTypedExpr, error)
 		if spec.Category == builtinAggregate {
 			return logicalTypedExpr{}, aggregateOutsideReduceError(e.raw.Name)
 		}
+		if spec.Category == builtinWindow {
+			return logicalTypedExpr{}, windowOutsideWindowError(e.raw.Name)
+		}
 		signatureArgs := logicalSignatureExprs(args)
 		typ, err := spec.Check(signatureArgs)
 		if err != nil {
@@ -759,7 +1003,7 @@ func typeCheckLogicalExpression(expr logicalBoundExpr) (logicalTypedExpr, error)
 		}
 		out := logicalTypedExpr{bound: expr, raw: e.raw, typ: typ, args: args}
 		switch e.raw.Name {
-		case "coalesce":
+		case "coalesce", "greatest", "least", "list_concat":
 			if typedArgsNeedCoercion(signatureArgs, typ) {
 				out = coerceLogicalTypedExpression(out, typ)
 			}
@@ -767,6 +1011,8 @@ func typeCheckLogicalExpression(expr logicalBoundExpr) (logicalTypedExpr, error)
 			if len(args) == 3 && (runtimeCoercionNeeded(args[1].typ, typ) || runtimeCoercionNeeded(args[2].typ, typ)) {
 				out = coerceLogicalTypedExpression(out, typ)
 			}
+		case "list_reduce":
+			coerceListReduceArgs(out.args, typ)
 		}
 		return out, nil
 	case *logicalBoundStruct:
@@ -809,6 +1055,14 @@ func typeCheckLogicalExpression(expr logicalBoundExpr) (logicalTypedExpr, error)
 			return logicalTypedExpr{}, err
 		}
 		return logicalTypedExpr{bound: expr, raw: e.raw, typ: &table.TypeDescriptor{Kind: table.TypeBool}, operand: &operand}, nil
+	case *logicalBoundLambda:
+		body, err := typeCheckLogicalExpression(e.body)
+		if err != nil {
+			return logicalTypedExpr{}, err
+		}
+		return logicalTypedExpr{bound: expr, raw: e.raw, typ: body.typ, operand: &body}, nil
+	case *logicalBoundLambdaParam:
+		return logicalTypedExpr{bound: expr, raw: &ast.ColumnExpr{Path: e.rawPath}, typ: normalizePlanningSchema(e.typ)}, nil
 	default:
 		return logicalTypedExpr{}, fmt.Errorf("unknown logical bound expression type %T", expr)
 	}
@@ -829,6 +1083,7 @@ func typeCheckLogicalReduceExpression(expr logicalBoundExpr) (logicalTypedExpr,
 		if err != nil {
 			return logicalTypedExpr{}, err
 		}
+		left, right = exactDecimalLiterals(left, right)
 		typ, err := checkBinarySignature(e.raw.Op, logicalSignatureExpr(left), logicalSignatureExpr(right))
 		if err != nil {
 			return logicalTypedExpr{}, err
@@ -845,11 +1100,15 @@ func typeCheckLogicalReduceExpression(expr logicalBoundExpr) (logicalTypedExpr,
 		}
 		return logicalTypedExpr{bound: expr, raw: e.raw, typ: typ, operand: &operand}, nil
 	case *logicalBoundCall:
+		// Aggregate arguments are row-level expressions over the nested rows.
 		args := make([]logicalTypedExpr, len(e.args))
 		for i, arg := range e.args {
-			typed, err := typeCheckLogicalReduceExpression(arg)
+			if inner, ok := logicalNestedAggregate(arg); ok {
+				return logicalTypedExpr{}, fmt.Errorf("%s(): aggregate %s() cannot be nested inside another aggregate", e.raw.Name, inner)
+			}
+			typed, err := typeCheckLogicalExpression(arg)
 			if err != nil {
-				return logicalTypedExpr{}, err
+				return logicalTypedExpr{}, fmt.Errorf("%s(): %w", e.raw.Name, err)
 			}
 			args[i] = typed
 		}
@@ -864,7 +1123,18 @@ func typeCheckLogicalReduceExpression(expr logicalBoundExpr) (logicalTypedExpr,
 		if err != nil {
 			return logicalTypedExpr{}, err
 		}
-		return logicalTypedExpr{bound: expr, raw: e.raw, typ: typ, args: args}, nil
+		out := logicalTypedExpr{bound: expr, raw: e.raw, typ: typ, args: args}
+		if e.filter != nil {
+			filter, err := typeCheckLogicalExpression(e.filter)
+			if err != nil {
+				return logicalTypedExpr{}, fmt.Errorf("%s() where: %w", e.raw.Name, err)
+			}
+			if !schemaKindOrNull(filter.typ, table.TypeBool) {
+				return logicalTypedExpr{}, fmt.Errorf("%s() where condition must be boolean, got %s", e.raw.Name, schemaString(filter.typ))
+			}
+			out.filter = &filter
+		}
+		return out, nil
 	case *logicalBoundStruct:
 		return logicalTypedExpr{}, fmt.Errorf("struct constructor is not supported in reduce")
 	case *logicalBoundList:
@@ -910,6 +1180,8 @@ func literalType(e *ast.LiteralExpr) *table.TypeDescriptor {
 		return &table.TypeDescriptor{Kind: table.TypeInt}
 	case "float":
 		return &table.TypeDescriptor{Kind: table.TypeFloat}
+	case "decimal":
+		return decimalLiteralSchema(e.Str)
 	case "string":
 		return &table.TypeDescriptor{Kind: table.TypeString}
 	case "bool":
@@ -923,7 +1195,7 @@ func literalType(e *ast.LiteralExpr) *table.TypeDescriptor {
 
 func checkBinarySignature(op string, left, right typedExpr) (*table.TypeDescriptor, error) {
 	switch op {
-	case "+", "-", "*", "/":
+	case "+", "-", "*", "/", "%":
 		if op == "+" && schemaKindOrNull(left.typ, table.TypeString) && schemaKindOrNull(right.typ, table.TypeString) {
 			out, err := unifyExpressionStrict(left.typ, right.typ)
 			if err != nil {
@@ -931,14 +1203,28 @@ func checkBinarySignature(op string, left, right typedExpr) (*table.TypeDescript
 			}
 			return out, nil
 		}
+		if schemaIsTemporal(left.typ) || schemaIsTemporal(right.typ) {
+			return checkTemporalArithSignature(op, left, right)
+		}
+		if schemaIsDecimal(left.typ) || schemaIsDecimal(right.typ) {
+			return checkDecimalArithSignature(op, left, right)
+		}
 		out, err := table.NumericResult(left.typ, right.typ)
 		if err != nil {
 			return nil, fmt.Errorf("operator %s requires numeric operands, got %s and %s", op, schemaString(left.typ), schemaString(right.typ))
 		}
-		if op == "/" {
+		switch op {
+		case "/":
 			return plannedDivisionSchema(out, right.raw), nil
+		case "%":
+			return plannedRemainderSchema(out, right.raw), nil
 		}
 		return out, nil
+	case "||":
+		if !schemaKindOrNull(left.typ, table.TypeString) || !schemaKindOrNull(right.typ, table.TypeString) {
+			return nil, fmt.Errorf("operator || requires string operands, got %s and %s", schemaString(left.typ), schemaString(right.typ))
+		}
+		return nullableSchema(table.TypeString, left.typ, right.typ), nil
 	case "==", "!=":
 		if isNullOnly(left.typ) || isNullOnly(right.typ) {
 			return nil, fmt.Errorf("%s null is not comparison syntax; use is null or is not null", op)
@@ -985,7 +1271,7 @@ func checkUnarySignature(op string, operand typedExpr) (*table.TypeDescriptor, e
 		if isNullOnly(operand.typ) {
 			return &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}, nil
 		}
-		if !table.IsNumeric(operand.typ) {
+		if !schemaNumericOrNull(operand.typ) && !schemaKindOrNull(operand.typ, table.TypeDuration) {
 			return nil, fmt.Errorf("operator - requires numeric operand, got %s", schemaString(operand.typ))
 		}
 		return normalizePlanningSchema(operand.typ), nil
@@ -1018,6 +1304,55 @@ func unaryStringToIntSpec(name string) func(args []typedExpr) (*table.TypeDescri
 	}
 }
 
+func datePartSpec(name string) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		if !schemaKindOrNull(args[0].typ, table.TypeDate) && !schemaKindOrNull(args[0].typ, table.TypeTimestamp) && !schemaKindOrNull(args[0].typ, table.TypeString) {
+			return nil, fmt.Errorf("%s() requires a date, timestamp or string, got %s", name, schemaString(args[0].typ))
+		}
+		return nullableSchema(table.TypeInt, args[0].typ), nil
+	}
+}
+
+// temporalConstructorSpec types date(), timestamp() and duration(), which
+// convert strings (and, where listed, other temporal kinds) to kind out.
+// timestamp() also takes an optional zone name for zoneless strings.
+func temporalConstructorSpec(name string, out table.ValueType, accepts ...table.ValueType) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		maxArgs := 1
+		if out == table.TypeTimestamp {
+			maxArgs = 2
+		}
+		if len(args) < 1 || len(args) > maxArgs {
+			if maxArgs == 1 {
+				return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+			}
+			return nil, fmt.Errorf("%s() takes 1 or 2 arguments, got %d", name, len(args))
+		}
+		ok := false
+		for _, kind := range accepts {
+			ok = ok || schemaKindOrNull(args[0].typ, kind)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s() cannot convert %s", name, schemaString(args[0].typ))
+		}
+		if len(args) == 2 && !schemaKindOrNull(args[1].typ, table.TypeString) {
+			return nil, fmt.Errorf("%s() zone must be a string, got %s", name, schemaString(args[1].typ))
+		}
+		return nullableSchema(out, typedExprSchemas(args)...), nil
+	}
+}
+
+func typedExprSchemas(args []typedExpr) []*table.TypeDescriptor {
+	types := make([]*table.TypeDescriptor, len(args))
+	for i, arg := range args {
+		types[i] = arg.typ
+	}
+	return types
+}
+
 func binaryStringToBoolSpec(name, secondArgLabel string) func(args []typedExpr) (*table.TypeDescriptor, error) {
 	return func(args []typedExpr) (*table.TypeDescriptor, error) {
 		if len(args) != 2 {
@@ -1091,6 +1426,9 @@ func checkAggregateSignature(name string, args []typedExpr) (*table.TypeDescript
 		if !schemaNumericOrNull(args[0].typ) {
 			return nil, fmt.Errorf("%s() requires a numeric column, got %s", name, schemaString(args[0].typ))
 		}
+		if schemaIsDecimal(args[0].typ) {
+			return decimalSumSchema(args[0].typ), nil
+		}
 		return table.WithNullable(args[0].typ), nil
 	case "min", "max":
 		if len(args) != 1 {
@@ -1107,17 +1445,318 @@ func checkAggregateSignature(name string, args []typedExpr) (*table.TypeDescript
 		if !schemaNumericOrNull(args[0].typ) {
 			return nil, fmt.Errorf("avg() requires a numeric column, got %s", schemaString(args[0].typ))
 		}
+		if schemaIsDecimal(args[0].typ) {
+			return decimalAvgSchema(args[0].typ), nil
+		}
 		return &table.TypeDescriptor{Kind: table.TypeFloat, Nullable: true}, nil
-	case "first", "last":
+	case "first", "last", "mode":
 		if len(args) != 1 {
 			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
 		}
 		return table.WithNullable(args[0].typ), nil
+	case "count_distinct", "approx_count_distinct":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		return &table.TypeDescriptor{Kind: table.TypeInt}, nil
+	case "collect_list", "collect_set":
+		return checkCollectSignature(name, args)
+	case "string_agg":
+		return checkStringAggSignature(args)
+	case "median", "stddev", "var":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		if !schemaNumericOrNull(args[0].typ) {
+			return nil, fmt.Errorf("%s() requires a numeric column, got %s", name, schemaString(args[0].typ))
+		}
+		return &table.TypeDescriptor{Kind: table.TypeFloat, Nullable: true}, nil
+	case "percentile", "approx_percentile":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s() takes 2 arguments (column, fraction), got %d", name, len(args))
+		}
+		if !schemaNumericOrNull(args[0].typ) {
+			return nil, fmt.Errorf("%s() requires a numeric column, got %s", name, schemaString(args[0].typ))
+		}
+		if err := checkPercentileFraction(name, args[1]); err != nil {
+			return nil, err
+		}
+		return &table.TypeDescriptor{Kind: table.TypeFloat, Nullable: true}, nil
 	default:
 		return nil, fmt.Errorf("unknown aggregate function %q", name)
 	}
 }
 
+func checkWindowSignature(name string, args []typedExpr) (*table.TypeDescriptor, error) {
+	switch name {
+	case "row_number", "rank":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("%s() takes no arguments, got %d", name, len(args))
+		}
+		return &table.TypeDescriptor{Kind: table.TypeInt}, nil
+	case "lag", "lead":
+		if len(args) < 1 || len(args) > 3 {
+			return nil, fmt.Errorf("%s() takes 1 to 3 arguments, got %d", name, len(args))
+		}
+		if len(args) >= 2 && (!schemaKindOrNull(args[1].typ, table.TypeInt) || schemaMayBeNull(args[1].typ)) {
+			return nil, fmt.Errorf("%s() offset must be a non-null int, got %s", name, schemaString(args[1].typ))
+		}
+		if len(args) < 3 {
+			return table.WithNullable(args[0].typ), nil
+		}
+		out, err := unifyExpressionStrict(args[0].typ, args[2].typ)
+		if err != nil {
+			return nil, fmt.Errorf("%s() default must match the value type: %s vs %s", name, schemaString(args[0].typ), schemaString(args[2].typ))
+		}
+		return out, nil
+	case "running_sum":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		if !schemaNumericOrNull(args[0].typ) {
+			return nil, fmt.Errorf("%s() requires a numeric column, got %s", name, schemaString(args[0].typ))
+		}
+		// Every running total includes its own row, so it is null only while
+		// the partition has seen nothing but nulls.
+		if schemaIsDecimal(args[0].typ) {
+			out := decimalSumSchema(args[0].typ)
+			out.Nullable = schemaMayBeNull(args[0].typ)
+			return out, nil
+		}
+		return args[0].typ, nil
+	default:
+		return nil, fmt.Errorf("unknown window function %q", name)
+	}
+}
+
+// checkConstantRegex compiles a string literal pattern during planning, so a
+// bad regex fails before any rows are read. Patterns computed per row return
+// nil and are checked as they are evaluated.
+func checkConstantRegex(name string, arg typedExpr) (*regexp.Regexp, error) {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok || lit.Kind != "string" {
+		return nil, nil
+	}
+	re, err := compileRegex(lit.Str)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): invalid regex %q: %v", name, lit.Str, err)
+	}
+	return re, nil
+}
+
+func checkMatchesSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	out, err := binaryStringToBoolSpec("matches", "regex")(args)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkConstantRegex("matches", args[1]); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// checkRegexArgs checks the string and regex arguments that every regex
+// function starts with.
+func checkRegexArgs(name string, args []typedExpr) (*regexp.Regexp, error) {
+	if !schemaKindOrNull(args[0].typ, table.TypeString) {
+		return nil, fmt.Errorf("%s() requires a string, got %s", name, schemaString(args[0].typ))
+	}
+	if !schemaKindOrNull(args[1].typ, table.TypeString) {
+		return nil, fmt.Errorf("%s() requires a string regex, got %s", name, schemaString(args[1].typ))
+	}
+	return checkConstantRegex(name, args[1])
+}
+
+// regexExtractSpec types regex_extract() and regex_extract_all(). A literal
+// group is checked against a literal regex during planning.
+func regexExtractSpec(name string, all bool) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) < 2 || len(args) > 3 {
+			return nil, fmt.Errorf("%s() takes 2 or 3 arguments (string, regex[, group]), got %d", name, len(args))
+		}
+		re, err := checkRegexArgs(name, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 3 {
+			if !schemaKindOrNull(args[2].typ, table.TypeInt) {
+				return nil, fmt.Errorf("%s() group must be an int, got %s", name, schemaString(args[2].typ))
+			}
+			if lit, ok := args[2].raw.(*ast.LiteralExpr); ok && lit.Kind == "int" && re != nil {
+				if err := regexGroupInRange(name, re, lit.Int); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !all {
+			return &table.TypeDescriptor{Kind: table.TypeString, Nullable: true}, nil
+		}
+		return &table.TypeDescriptor{
+			Kind:     table.TypeList,
+			Nullable: anySchemaMayBeNull(typedExprSchemas(args)...),
+			Elem:     &table.TypeDescriptor{Kind: table.TypeString},
+		}, nil
+	}
+}
+
+func checkRegexReplaceSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("regex_replace() takes 3 arguments (string, regex, replacement), got %d", len(args))
+	}
+	if _, err := checkRegexArgs("regex_replace", args); err != nil {
+		return nil, err
+	}
+	if !schemaKindOrNull(args[2].typ, table.TypeString) {
+		return nil, fmt.Errorf("regex_replace() requires a string replacement, got %s", schemaString(args[2].typ))
+	}
+	return nullableSchema(table.TypeString, typedExprSchemas(args)...), nil
+}
+
+func checkRegexSplitSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("regex_split() takes 2 arguments (string, regex), got %d", len(args))
+	}
+	if _, err := checkRegexArgs("regex_split", args); err != nil {
+		return nil, err
+	}
+	return &table.TypeDescriptor{
+		Kind:     table.TypeList,
+		Nullable: anySchemaMayBeNull(typedExprSchemas(args)...),
+		Elem:     &table.TypeDescriptor{Kind: table.TypeString},
+	}, nil
+}
+
+// numericUnarySpec types abs(), sign(), floor() and ceil(). Ints and floats
+// keep their type; decimals keep theirs for abs(), while sign(), floor() and
+// ceil() drop the fraction and keep room for a carry digit.
+func numericUnarySpec(name string) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		if err := requireNumericArg(name, args[0]); err != nil {
+			return nil, err
+		}
+		if !schemaIsDecimal(args[0].typ) || name == "abs" {
+			return normalizePlanningSchema(args[0].typ), nil
+		}
+		precision, scale, _ := decimalOperand(args[0].typ)
+		out := table.DecimalSchema(min(precision-scale+1, table.MaxDecimalPrecision), 0)
+		if name == "sign" {
+			out.Precision = 1
+		}
+		out.Nullable = schemaMayBeNull(args[0].typ)
+		return out, nil
+	}
+}
+
+// checkRoundSignature types round(x[, digits]). Rounding a decimal needs the
+// digits as an int literal, since they fix the scale of the result.
+func checkRoundSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("round() takes 1 or 2 arguments, got %d", len(args))
+	}
+	if err := requireNumericArg("round", args[0]); err != nil {
+		return nil, err
+	}
+	if len(args) == 2 && !schemaKindOrNull(args[1].typ, table.TypeInt) {
+		return nil, fmt.Errorf("round() digits must be an int, got %s", schemaString(args[1].typ))
+	}
+	if !schemaIsDecimal(args[0].typ) {
+		out := normalizePlanningSchema(args[0].typ)
+		if len(args) == 2 && schemaMayBeNull(args[1].typ) {
+			out = table.WithNullable(out)
+		}
+		return out, nil
+	}
+	digits := int64(0)
+	if len(args) == 2 {
+		lit, ok := args[1].raw.(*ast.LiteralExpr)
+		if !ok || lit.Kind != "int" {
+			return nil, fmt.Errorf("round() digits must be an int literal when rounding a decimal")
+		}
+		digits = lit.Int
+	}
+	precision, scale, _ := decimalOperand(args[0].typ)
+	outScale := int(max(0, min(digits, int64(scale))))
+	out := table.DecimalSchema(min(precision-scale+outScale+1, table.MaxDecimalPrecision), outScale)
+	out.Nullable = schemaMayBeNull(args[0].typ)
+	return out, nil
+}
+
+// floatMathSpec types functions that always compute in float64. Functions
+// that are undefined for part of their domain, like sqrt() of a negative
+// number, return null there, so their result is always nullable.
+func floatMathSpec(name string, arity int, partial bool) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) != arity {
+			if arity == 1 {
+				return nil, fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+			}
+			return nil, fmt.Errorf("%s() takes %d arguments, got %d", name, arity, len(args))
+		}
+		for _, arg := range args {
+			if err := requireNumericArg(name, arg); err != nil {
+				return nil, err
+			}
+		}
+		out := nullableSchema(table.TypeFloat, typedExprSchemas(args)...)
+		if partial {
+			out.Nullable = true
+		}
+		return out, nil
+	}
+}
+
+func checkModSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("mod() takes 2 arguments, got %d", len(args))
+	}
+	for _, arg := range args {
+		if err := requireNumericArg("mod", arg); err != nil {
+			return nil, err
+		}
+	}
+	return checkBinarySignature("%", args[0], args[1])
+}
+
+// extremumSpec types greatest() and least(), which unify their arguments like
+// coalesce() and skip nulls, so the result is null only when every argument
+// may be null.
+func extremumSpec(name string) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s() requires at least 2 arguments, got %d", name, len(args))
+		}
+		for _, arg := range args {
+			if table.SchemaContainsUnion(arg.typ) || !schemaOrderableOrNull(arg.typ) {
+				return nil, fmt.Errorf("%s() requires orderable arguments, got %s", name, schemaString(arg.typ))
+			}
+		}
+		out := args[0].typ
+		nullable := schemaMayBeNull(args[0].typ)
+		for i := 1; i < len(args); i++ {
+			merged, err := unifyExpressionStrict(out, args[i].typ)
+			if err != nil {
+				return nil, fmt.Errorf("%s() arguments do not have one common type: argument 1 has type %s, argument %d has type %s", name, schemaString(out), i+1, schemaString(args[i].typ))
+			}
+			out = merged
+			nullable = nullable && schemaMayBeNull(args[i].typ)
+		}
+		if nullable {
+			return table.WithNullable(out), nil
+		}
+		return table.WithoutNull(out), nil
+	}
+}
+
+func requireNumericArg(name string, arg typedExpr) error {
+	if !schemaNumericOrNull(arg.typ) {
+		return fmt.Errorf("%s() requires a number, got %s", name, schemaString(arg.typ))
+	}
+	return nil
+}
+
 func checkCoalesceSignature(args []typedExpr) (*table.TypeDescriptor, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("coalesce() requires at least 1 argument")
@@ -1193,7 +1832,7 @@ func sameRuntimeSchema(a, b *table.TypeDescriptor) bool {
 		return false
 	}
 	switch a.Kind {
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		return sameRuntimeSchema(a.Elem, b.Elem)
 	case table.TypeRecord:
 		if len(a.Fields) != len(b.Fields) {
@@ -1236,7 +1875,7 @@ func schemaContainsMixed(schema *table.TypeDescriptor) bool {
 				return true
 			}
 		}
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		return schemaContainsMixed(schema.Elem)
 	case table.TypeUnion:
 		for _, branch := range schema.Branches {
@@ -1260,7 +1899,7 @@ func schemaBoolOrNull(schema *table.TypeDescriptor) bool {
 }
 
 func schemaNumericOrNull(schema *table.TypeDescriptor) bool {
-	return schemaKindOrNull(schema, table.TypeInt) || schemaKindOrNull(schema, table.TypeFloat)
+	return schemaKindOrNull(schema, table.TypeInt) || schemaKindOrNull(schema, table.TypeFloat) || schemaKindOrNull(schema, table.TypeDecimal)
 }
 
 func schemaOrderableOrNull(schema *table.TypeDescriptor) bool {
@@ -775,10 +775,10 @@ func TestCheckBinarySignaturePlannerEdges(t *testing.T) {
 		},
 		{
 			name:    "unknown_operator_rejects",
-			op:      "%",
+			op:      "^",
 			left:    typedExpr{typ: intType},
 			right:   typedExpr{typ: intType},
-			wantErr: `unknown operator "%"`,
+			wantErr: `unknown operator "^"`,
 		},
 	}
 
@@ -847,8 +847,8 @@ func TestTypeCheckLogicalReduceExpressionFailsClosedForUnsupportedBoundNodes(t *
 		},
 		{
 			name: "unknown_call",
-			expr: &logicalBoundCall{raw: &ast.FuncCallExpr{Name: "median"}},
-			want: `unknown function "median"`,
+			expr: &logicalBoundCall{raw: &ast.FuncCallExpr{Name: "geomean"}},
+			want: `unknown function "geomean"`,
 		},
 	}
 
@@ -911,7 +911,7 @@ func TestCheckAggregateSignaturePlannerEdges(t *testing.T) {
 		{name: "min_rejects_list", aggregate: "min", args: []typedExpr{listArg}, wantErr: "min() requires an orderable column"},
 		{name: "avg_rejects_no_args", aggregate: "avg", wantErr: "avg() takes 1 argument"},
 		{name: "first_rejects_no_args", aggregate: "first", wantErr: "first() takes 1 argument"},
-		{name: "unknown_aggregate", aggregate: "median", args: []typedExpr{intArg}, wantErr: `unknown aggregate function "median"`},
+		{name: "unknown_aggregate", aggregate: "geomean", args: []typedExpr{intArg}, wantErr: `unknown aggregate function "geomean"`},
 	}
 
 	for _, tc := range cases {
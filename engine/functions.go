@@ -2,6 +2,8 @@ package engine
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"regexp"
 	"strings"
 	"sync"
@@ -28,6 +30,10 @@ func valueTypeName(v table.Value) string {
 		return "list"
 	case table.TypeRecord:
 		return "record"
+	case table.TypeMap:
+		return "map"
+	case table.TypeDate, table.TypeTimestamp, table.TypeDuration, table.TypeDecimal:
+		return table.TypeName(v.Type)
 	default:
 		return "unknown"
 	}
@@ -288,6 +294,126 @@ func typedMatchesEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
 	return table.BoolVal(re.MatchString(s)), nil
 }
 
+// typedRegexArgs evaluates the string and pattern arguments shared by the
+// regex functions and compiles the pattern through the regex cache. ok is
+// false when either argument is null.
+func typedRegexArgs(name string, args []typedExpr, ctx *EvalContext) (string, *regexp.Regexp, bool, error) {
+	s, pattern, ok, err := typedStringPredicateArgs(name, "regex", args[:2], ctx)
+	if err != nil || !ok {
+		return "", nil, false, err
+	}
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%s(): invalid regex %q: %v", name, pattern, err)
+	}
+	return s, re, true, nil
+}
+
+// typedRegexGroupArg evaluates the optional capture group argument at index i,
+// which defaults to the whole match.
+func typedRegexGroupArg(name string, args []typedExpr, i int, re *regexp.Regexp, ctx *EvalContext) (int, bool, error) {
+	if len(args) <= i {
+		return 0, true, nil
+	}
+	v, err := evalTypedExpression(args[i], ctx)
+	if err != nil || v.IsNull() {
+		return 0, false, err
+	}
+	if v.Type != table.TypeInt {
+		return 0, false, fmt.Errorf("%s() group must be an int, got %s", name, valueTypeName(v))
+	}
+	if err := regexGroupInRange(name, re, v.Int); err != nil {
+		return 0, false, err
+	}
+	return int(v.Int), true, nil
+}
+
+func regexGroupInRange(name string, re *regexp.Regexp, group int64) error {
+	if group < 0 || group > int64(re.NumSubexp()) {
+		return fmt.Errorf("%s(): group %d out of range, regex %q has %d groups", name, group, re.String(), re.NumSubexp())
+	}
+	return nil
+}
+
+// typedRegexExtractEval returns the text of one capture group from the first
+// match, or null when the regex or the group does not match.
+func typedRegexExtractEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return table.Null(), fmt.Errorf("regex_extract() takes 2 or 3 arguments (string, regex[, group]), got %d", len(args))
+	}
+	s, re, ok, err := typedRegexArgs("regex_extract", args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	group, ok, err := typedRegexGroupArg("regex_extract", args, 2, re, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil || loc[2*group] < 0 {
+		return table.Null(), nil
+	}
+	return table.StrVal(s[loc[2*group]:loc[2*group+1]]), nil
+}
+
+// typedRegexExtractAllEval returns one capture group from every match; a group
+// that does not take part in a match contributes an empty string.
+func typedRegexExtractAllEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return table.Null(), fmt.Errorf("regex_extract_all() takes 2 or 3 arguments (string, regex[, group]), got %d", len(args))
+	}
+	s, re, ok, err := typedRegexArgs("regex_extract_all", args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	group, ok, err := typedRegexGroupArg("regex_extract_all", args, 2, re, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	matches := re.FindAllStringSubmatch(s, -1)
+	out := make([]table.Value, len(matches))
+	for i, m := range matches {
+		out[i] = table.StrVal(m[group])
+	}
+	return table.ListVal(out), nil
+}
+
+// typedRegexReplaceEval replaces every match; the replacement may refer to
+// capture groups as $1 or ${name}.
+func typedRegexReplaceEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) != 3 {
+		return table.Null(), fmt.Errorf("regex_replace() takes 3 arguments (string, regex, replacement), got %d", len(args))
+	}
+	s, re, ok, err := typedRegexArgs("regex_replace", args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	repl, err := evalTypedExpression(args[2], ctx)
+	if err != nil || repl.IsNull() {
+		return table.Null(), err
+	}
+	if repl.Type != table.TypeString {
+		return table.Null(), fmt.Errorf("regex_replace() requires a string replacement, got %s", valueTypeName(repl))
+	}
+	return table.StrVal(re.ReplaceAllString(s, repl.Str)), nil
+}
+
+func typedRegexSplitEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) != 2 {
+		return table.Null(), fmt.Errorf("regex_split() takes 2 arguments (string, regex), got %d", len(args))
+	}
+	s, re, ok, err := typedRegexArgs("regex_split", args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	parts := re.Split(s, -1)
+	out := make([]table.Value, len(parts))
+	for i, part := range parts {
+		out[i] = table.StrVal(part)
+	}
+	return table.ListVal(out), nil
+}
+
 func typedCoalesceEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
 	if len(args) == 0 {
 		return table.Null(), fmt.Errorf("coalesce() requires at least 1 argument")
@@ -343,27 +469,22 @@ func typedDatePartEval(part string) typedCallEvaluator {
 		if v.IsNull() {
 			return table.Null(), nil
 		}
-		if v.Type != table.TypeString {
-			return table.Null(), fmt.Errorf("%s() requires a string, got %s", part, valueTypeName(v))
-		}
 		return datePartValue(part, v)
 	}
 }
 
 func datePartValue(part string, v table.Value) (table.Value, error) {
-	var (
-		t   time.Time
-		err error
-	)
-	parsed := false
-	for _, fmt := range dateFormats {
-		if t, err = time.Parse(fmt, v.Str); err == nil {
-			parsed = true
-			break
+	var t time.Time
+	switch v.Type {
+	case table.TypeDate, table.TypeTimestamp:
+		t, _ = v.Time()
+	case table.TypeString:
+		var ok bool
+		if t, ok = parseDateString(v.Str); !ok {
+			return table.Null(), fmt.Errorf("%s(): cannot parse %q as a date", part, v.Str)
 		}
-	}
-	if !parsed {
-		return table.Null(), fmt.Errorf("%s(): cannot parse %q as a date", part, v.Str)
+	default:
+		return table.Null(), fmt.Errorf("%s() requires a date, timestamp or string, got %s", part, valueTypeName(v))
 	}
 	switch part {
 	case "year":
@@ -376,8 +497,366 @@ func datePartValue(part string, v table.Value) (table.Value, error) {
 	return table.Null(), nil
 }
 
+func parseDateString(s string) (time.Time, bool) {
+	for _, layout := range dateFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func typedDateEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	v, err := temporalConstructorArg("date", args, ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), err
+	}
+	if d, ok := dateFromValue(v); ok {
+		return d, nil
+	}
+	if v.Type == table.TypeString {
+		return table.Null(), fmt.Errorf("date(): cannot parse %q as a date", v.Str)
+	}
+	return table.Null(), fmt.Errorf("date() cannot convert %s", valueTypeName(v))
+}
+
+// dateFromValue converts a date, a timestamp (in UTC) or a date string; ok is
+// false when v is none of these.
+func dateFromValue(v table.Value) (table.Value, bool) {
+	switch v.Type {
+	case table.TypeDate:
+		return v, true
+	case table.TypeTimestamp:
+		t, _ := v.Time()
+		return table.DateFromTime(t), true
+	case table.TypeString:
+		if d, ok := table.ParseDate(v.Str); ok {
+			return d, true
+		}
+		if ts, ok := table.ParseTimestamp(v.Str); ok {
+			t, _ := ts.Time()
+			return table.DateFromTime(t), true
+		}
+		if t, ok := parseDateString(v.Str); ok {
+			return table.DateFromTime(t), true
+		}
+	}
+	return table.Null(), false
+}
+
+func typedTimestampEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	v, err := temporalConstructorArg("timestamp", args, ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), err
+	}
+	loc := time.UTC
+	if len(args) == 2 {
+		zone, err := evalTypedExpression(args[1], ctx)
+		if err != nil || zone.IsNull() {
+			return table.Null(), err
+		}
+		if zone.Type != table.TypeString {
+			return table.Null(), fmt.Errorf("timestamp() zone must be a string, got %s", valueTypeName(zone))
+		}
+		if loc, err = time.LoadLocation(zone.Str); err != nil {
+			return table.Null(), fmt.Errorf("timestamp(): unknown time zone %q", zone.Str)
+		}
+	}
+	if ts, ok := timestampFromValue(v, loc); ok {
+		return ts, nil
+	}
+	if v.Type == table.TypeString {
+		return table.Null(), fmt.Errorf("timestamp(): cannot parse %q as a timestamp", v.Str)
+	}
+	return table.Null(), fmt.Errorf("timestamp() cannot convert %s", valueTypeName(v))
+}
+
+// timestampFromValue converts a timestamp, a date (midnight in loc) or a
+// timestamp or date string read in loc; ok is false when v is none of these.
+func timestampFromValue(v table.Value, loc *time.Location) (table.Value, bool) {
+	switch v.Type {
+	case table.TypeTimestamp:
+		return v, true
+	case table.TypeDate:
+		t, _ := v.Time()
+		return table.TimestampFromTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)), true
+	case table.TypeString:
+		if ts, ok := table.ParseTimestampIn(v.Str, loc); ok {
+			return ts, true
+		}
+		if d, ok := table.ParseDate(v.Str); ok {
+			t, _ := d.Time()
+			return table.TimestampFromTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)), true
+		}
+	}
+	return table.Null(), false
+}
+
+func typedDurationEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	v, err := temporalConstructorArg("duration", args, ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), err
+	}
+	switch v.Type {
+	case table.TypeDuration:
+		return v, nil
+	case table.TypeString:
+		if d, ok := table.ParseDuration(v.Str); ok {
+			return d, nil
+		}
+		return table.Null(), fmt.Errorf("duration(): cannot parse %q as a duration", v.Str)
+	default:
+		return table.Null(), fmt.Errorf("duration() cannot convert %s", valueTypeName(v))
+	}
+}
+
+func temporalConstructorArg(name string, args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	maxArgs := 1
+	if name == "timestamp" {
+		maxArgs = 2
+	}
+	if len(args) < 1 || len(args) > maxArgs {
+		return table.Null(), fmt.Errorf("%s() takes %d argument(s), got %d", name, maxArgs, len(args))
+	}
+	return evalTypedExpression(args[0], ctx)
+}
+
 // --- Aggregate evaluation (used by reduce) ---
 
+// typedNumericArg evaluates a math function argument. ok is false for null,
+// which every math function passes through.
+func typedNumericArg(name string, arg typedExpr, ctx *EvalContext) (table.Value, bool, error) {
+	v, err := evalTypedExpression(arg, ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), false, err
+	}
+	if !isNumericValue(v) {
+		return table.Null(), false, fmt.Errorf("%s() requires a number, got %s", name, valueTypeName(v))
+	}
+	return v, true, nil
+}
+
+func typedNumericUnaryEval(name string, fn func(table.Value) (table.Value, error)) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		if len(args) != 1 {
+			return table.Null(), fmt.Errorf("%s() takes 1 argument, got %d", name, len(args))
+		}
+		v, ok, err := typedNumericArg(name, args[0], ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		return fn(v)
+	}
+}
+
+func absValue(v table.Value) (table.Value, error) {
+	switch v.Type {
+	case table.TypeInt:
+		if v.Int == math.MinInt64 {
+			return table.Null(), fmt.Errorf("integer overflow in abs(): %d", v.Int)
+		}
+		if v.Int < 0 {
+			return table.IntVal(-v.Int), nil
+		}
+		return v, nil
+	case table.TypeDecimal:
+		if unscaled, _, _ := v.Decimal(); unscaled.Sign() < 0 {
+			return negateDecimal(v), nil
+		}
+		return v, nil
+	default:
+		return table.FloatVal(math.Abs(v.Float)), nil
+	}
+}
+
+func signValue(v table.Value) (table.Value, error) {
+	switch v.Type {
+	case table.TypeInt:
+		return table.IntVal(int64(compareInt64(v.Int, 0))), nil
+	case table.TypeDecimal:
+		unscaled, _, _ := v.Decimal()
+		return table.DecimalVal(big.NewInt(int64(unscaled.Sign())), 0), nil
+	default:
+		if math.IsNaN(v.Float) {
+			return v, nil
+		}
+		cmp, _, _ := compareFloat64(v.Float, 0)
+		return table.FloatVal(float64(cmp)), nil
+	}
+}
+
+func floorValue(v table.Value) (table.Value, error) {
+	switch v.Type {
+	case table.TypeInt:
+		return v, nil
+	case table.TypeDecimal:
+		unscaled, scale, _ := v.Decimal()
+		// big.Int.Div rounds toward negative infinity for a positive divisor.
+		return table.DecimalVal(new(big.Int).Div(unscaled, table.Pow10(scale)), 0), nil
+	default:
+		return table.FloatVal(math.Floor(v.Float)), nil
+	}
+}
+
+func ceilValue(v table.Value) (table.Value, error) {
+	if v.Type != table.TypeDecimal {
+		if v.Type == table.TypeFloat {
+			return table.FloatVal(math.Ceil(v.Float)), nil
+		}
+		return v, nil
+	}
+	floor, err := floorValue(negateDecimal(v))
+	if err != nil {
+		return table.Null(), err
+	}
+	return negateDecimal(floor), nil
+}
+
+// typedRoundEval rounds half away from zero to digits places after the point;
+// negative digits round to tens, hundreds and so on.
+func typedRoundEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return table.Null(), fmt.Errorf("round() takes 1 or 2 arguments, got %d", len(args))
+	}
+	v, ok, err := typedNumericArg("round", args[0], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	digits := int64(0)
+	if len(args) == 2 {
+		d, err := evalTypedExpression(args[1], ctx)
+		if err != nil || d.IsNull() {
+			return table.Null(), err
+		}
+		if d.Type != table.TypeInt {
+			return table.Null(), fmt.Errorf("round() digits must be an int, got %s", valueTypeName(d))
+		}
+		digits = d.Int
+	}
+	switch v.Type {
+	case table.TypeFloat:
+		scale := math.Pow(10, float64(digits))
+		if scaled := v.Float * scale; !math.IsInf(scale, 0) && !math.IsInf(scaled, 0) && scale != 0 {
+			return table.FloatVal(math.Round(scaled) / scale), nil
+		}
+		if digits < 0 {
+			return table.FloatVal(0), nil
+		}
+		return v, nil
+	case table.TypeInt:
+		if digits >= 0 {
+			return v, nil
+		}
+		rounded := roundUnscaled(big.NewInt(v.Int), 0, digits)
+		if !rounded.IsInt64() {
+			return table.Null(), fmt.Errorf("integer overflow in round(): %d", v.Int)
+		}
+		return table.IntVal(rounded.Int64()), nil
+	default:
+		unscaled, scale, _ := v.Decimal()
+		if digits >= 0 {
+			return table.RescaleDecimal(v, int(min(digits, int64(scale)))), nil
+		}
+		return table.DecimalVal(roundUnscaled(unscaled, scale, digits), 0), nil
+	}
+}
+
+// roundUnscaled rounds the decimal n / 10^scale half away from zero to a
+// multiple of 10^-digits for negative digits, and returns it as an integer.
+func roundUnscaled(n *big.Int, scale int, digits int64) *big.Int {
+	if -digits > table.MaxDecimalPrecision+1 {
+		return new(big.Int)
+	}
+	q := table.DivRoundHalfAway(n, table.Pow10(scale+int(-digits)))
+	return q.Mul(q, table.Pow10(int(-digits)))
+}
+
+// typedFloatMathEval applies fn to float64 arguments. fn reports false where
+// the function is undefined, which yields null.
+func typedFloatMathEval(name string, fn func(args []float64) (float64, bool)) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		in := make([]float64, len(args))
+		for i, arg := range args {
+			v, ok, err := typedNumericArg(name, arg, ctx)
+			if err != nil || !ok {
+				return table.Null(), err
+			}
+			in[i], _ = v.AsFloat()
+		}
+		out, ok := fn(in)
+		if !ok {
+			return table.Null(), nil
+		}
+		return table.FloatVal(out), nil
+	}
+}
+
+func sqrtFloat(args []float64) (float64, bool) {
+	return math.Sqrt(args[0]), args[0] >= 0
+}
+
+func logFloat(args []float64) (float64, bool) {
+	return math.Log(args[0]), args[0] > 0
+}
+
+func expFloat(args []float64) (float64, bool) {
+	return math.Exp(args[0]), true
+}
+
+// powFloat is undefined for a zero base with a negative exponent, like
+// division by zero, and for a negative base with a fractional exponent.
+func powFloat(args []float64) (float64, bool) {
+	if args[0] == 0 && args[1] < 0 {
+		return 0, false
+	}
+	out := math.Pow(args[0], args[1])
+	return out, !math.IsNaN(out)
+}
+
+func typedModEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) != 2 {
+		return table.Null(), fmt.Errorf("mod() takes 2 arguments, got %d", len(args))
+	}
+	left, ok, err := typedNumericArg("mod", args[0], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	right, ok, err := typedNumericArg("mod", args[1], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	return evalArith("%", left, right)
+}
+
+// typedExtremumEval returns the largest (want > 0) or smallest (want < 0)
+// non-null argument.
+func typedExtremumEval(name string, want int) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		best := table.Null()
+		for _, arg := range args {
+			v, err := evalTypedExpression(arg, ctx)
+			if err != nil {
+				return table.Null(), err
+			}
+			if v.IsNull() {
+				continue
+			}
+			if best.IsNull() {
+				best = v
+				continue
+			}
+			cmp, unordered, err := expressionValuesCompare(v, best)
+			if err != nil {
+				return table.Null(), fmt.Errorf("%s(): %w", name, err)
+			}
+			if !unordered && cmp*want > 0 {
+				best = v
+			}
+		}
+		return best, nil
+	}
+}
+
 func evalAggregateCall(e *ast.FuncCallExpr, nested *table.Table) (table.Value, error) {
 	if err := validateAggregateFunctionArity(e); err != nil {
 		return table.Null(), err
@@ -394,10 +873,7 @@ func validateAggregateFunctionArity(e *ast.FuncCallExpr) error {
 	if !ok || spec.Category != builtinAggregate || spec.Aggregate == nil {
 		return nonAggregateReduceFunctionError(e.Name)
 	}
-	if len(e.Args) != spec.Aggregate.Arity {
-		return aggregateArityError(e.Name, spec.Aggregate.Arity, len(e.Args))
-	}
-	return nil
+	return spec.Aggregate.checkArity(e.Name, len(e.Args))
 }
 
 func nonAggregateReduceFunctionError(name string) error {
@@ -407,3 +883,11 @@ func nonAggregateReduceFunctionError(name string) error {
 func aggregateOutsideReduceError(name string) error {
 	return fmt.Errorf("aggregate function %q can only be used inside 'reduce'", name)
 }
+
+func aggregateFilterOutsideReduceError(name string) error {
+	return fmt.Errorf("%s(): where { ... } is only allowed on aggregate functions in 'reduce'", name)
+}
+
+func windowOutsideWindowError(name string) error {
+	return fmt.Errorf("window function %q can only be used inside 'window'", name)
+}
@@ -57,19 +57,11 @@ func compileAggregateFinalExpr(expr typedExpr, slots *[]plannedAggregateSlot) (a
 		if spec.Category != builtinAggregate || spec.Aggregate == nil {
 			return aggregateFinalExpr{}, nonAggregateReduceFunctionError(e.raw.Name)
 		}
-		if len(expr.args) != spec.Aggregate.Arity {
-			return aggregateFinalExpr{}, aggregateArityError(e.raw.Name, spec.Aggregate.Arity, len(expr.args))
-		}
-		args := make([]boundColumn, len(expr.args))
-		for i, exprArg := range expr.args {
-			col, ok := exprArg.bound.(*boundColumn)
-			if !ok {
-				return aggregateFinalExpr{}, fmt.Errorf("%s() argument must be a column reference", e.raw.Name)
-			}
-			args[i] = *col
+		if err := spec.Aggregate.checkArity(e.raw.Name, len(expr.args)); err != nil {
+			return aggregateFinalExpr{}, err
 		}
 		slot := len(*slots)
-		*slots = append(*slots, plannedAggregateSlot{name: e.raw.Name, aggregate: spec.Aggregate, args: args})
+		*slots = append(*slots, plannedAggregateSlot{name: e.raw.Name, aggregate: spec.Aggregate, args: expr.args, filter: expr.filter})
 		return aggregateFinalExpr{kind: aggregateFinalSlot, slot: slot}, nil
 	case *boundIsNull:
 		operand, err := compileAggregateFinalExprPtr(expr.operand, slots)
@@ -114,16 +106,9 @@ func runtimeAggregateSlots(slots []plannedAggregateSlot, input *table.Table) []a
 	for i, slot := range slots {
 		out[i].name = slot.name
 		out[i].aggregate = slot.aggregate
-		out[i].args = make([]rowValueEvaluator, len(slot.args))
-		for argIdx := range slot.args {
-			arg := slot.args[argIdx]
-			if eval, ok := compileBoundColumnValue(&arg, input); ok {
-				out[i].args[argIdx] = eval
-				continue
-			}
-			out[i].args[argIdx] = func(row int) (table.Value, error) {
-				return resolveBoundColumn(arg, input, row)
-			}
+		out[i].args = compileAggregateArgs(slot.args, input)
+		if slot.filter != nil {
+			out[i].filter = compileFilterPredicate(*slot.filter, input)
 		}
 	}
 	return out
@@ -143,11 +128,13 @@ func evalAggregateFinalExpr(expr aggregateFinalExpr, states []aggregateAccumulat
 			return table.Null(), err
 		}
 		switch expr.op {
-		case "+", "-", "*", "/":
+		case "+", "-", "*", "/", "%":
 			if left.IsNull() || right.IsNull() {
 				return table.Null(), nil
 			}
 			return evalArith(expr.op, left, right)
+		case "||":
+			return evalConcat(left, right)
 		case "==", "!=", "<", ">", "<=", ">=":
 			return evalComparison(expr.op, left, right)
 		case "and":
@@ -178,6 +165,8 @@ func evalAggregateFinalExpr(expr aggregateFinalExpr, states []aggregateAccumulat
 				return table.IntVal(v), nil
 			case table.TypeFloat:
 				return table.FloatVal(-operand.Float), nil
+			case table.TypeDecimal:
+				return negateDecimal(operand), nil
 			default:
 				return table.Null(), fmt.Errorf("cannot negate %v", operand.AsString())
 			}
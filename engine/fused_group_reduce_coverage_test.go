@@ -120,7 +120,7 @@ func TestFusedGroupReduceCoverageRuntimeSlotFallbackAndEmptyInput(t *testing.T)
 	)
 
 	slots := runtimeAggregateSlots([]plannedAggregateSlot{
-		{name: "sum", aggregate: builtinCatalog["sum"].Aggregate, args: []boundColumn{{rawPath: []string{"amount"}, topIndex: -1}}},
+		{name: "sum", aggregate: builtinCatalog["sum"].Aggregate, args: []typedExpr{{bound: &boundColumn{rawPath: []string{"amount"}, topIndex: -1}}}},
 	}, input)
 	if len(slots) != 1 || len(slots[0].args) != 1 || slots[0].args[0] == nil {
 		t.Fatalf("runtime slot fallback did not bind aggregate arg")
@@ -220,14 +220,6 @@ func TestFusedGroupReduceCoverageCompileAggregateFinalExprErrors(t *testing.T) {
 			expr: typedExpr{bound: &boundCall{raw: &ast.FuncCallExpr{Name: "sum"}}, args: nil},
 			want: "takes 1 argument",
 		},
-		{
-			name: "aggregate_arg_not_column",
-			expr: typedExpr{
-				bound: &boundCall{raw: &ast.FuncCallExpr{Name: "sum"}},
-				args:  []typedExpr{{bound: &boundLiteral{raw: &ast.LiteralExpr{Kind: "int", Int: 1}}}},
-			},
-			want: "column reference",
-		},
 		{
 			name: "direct_column",
 			expr: typedExpr{bound: &boundColumn{rawPath: []string{"x"}}},
@@ -40,6 +40,16 @@ func TestFusedGroupReduceTDDNoPayloadDemandPrunesSourceReadSet(t *testing.T) {
 			query: `wide.csv | group status | reduce n = count(), total = sum(amount), ratio = sum(amount) / count() | remove grouped | select status, ratio | json`,
 			read:  []string{"status", "amount"},
 		},
+		{
+			name:  "aggregate_where_demands_condition_columns",
+			query: `wide.csv | group status | reduce n = count() where { amount > 10 } | remove grouped | select status, n | json`,
+			read:  []string{"status", "amount"},
+		},
+		{
+			name:  "aggregate_expression_args_demand_referenced_columns",
+			query: `wide.csv | group status | reduce revenue = sum(amount * quantity), top = max(upper(name)) | remove grouped | select status, revenue, top | json`,
+			read:  []string{"status", "amount", "quantity", "name"},
+		},
 		{
 			name:  "nested_aggregate_paths_demand_top_level_roots",
 			query: `nested.json | group address.city as rows | reduce rows avg_zip = avg(address.zip), first_address = first(address) | remove rows | select address_city, avg_zip, first_address | json`,
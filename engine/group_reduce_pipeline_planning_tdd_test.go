@@ -228,7 +228,7 @@ func TestGroupReducePipelinePlanningTDDRejectsInvalidSchemasBeforeRows(t *testin
 		{name: "reduce_without_grouped_column", pipeline: `filter { false } | reduce total = sum(age) | count`, wants: []string{"reduce", "grouped", "not found"}},
 		{name: "reduce_scalar_nested_source", pipeline: `filter { false } | reduce age n = count() | count`, wants: []string{"reduce", "age", "list", "record"}},
 		{name: "sum_rejects_string_column", pipeline: `filter { false } | group city | reduce bad = sum(name) | count`, wants: []string{"reduce", "bad", "sum", "numeric"}},
-		{name: "aggregate_arg_must_be_column_path", pipeline: `filter { false } | group city | reduce bad = sum(age + 1) | count`, wants: []string{"sum", "column reference"}},
+		{name: "aggregate_arg_cannot_nest_aggregate", pipeline: `filter { false } | group city | reduce bad = sum(age + count()) | count`, wants: []string{"sum", "count", "nested"}},
 		{name: "scalar_function_rejected_in_reduce", pipeline: `filter { false } | group city | reduce bad = upper(name) | count`, wants: []string{"upper", "reduce"}},
 		{name: "duplicate_reduce_target", pipeline: `filter { false } | group city | reduce x = count(), x = sum(age) | count`, wants: []string{"reduce target", "x", "more than once"}},
 		{name: "sibling_reduce_assignment_not_visible", pipeline: `filter { false } | group city | reduce total = sum(age), doubled = total * 2 | count`, wants: []string{"total", "reduce"}},
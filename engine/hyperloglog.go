@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// hyperLogLogPrecision uses 2^14 registers: 16 KiB per dense sketch and a
+// standard error of about 0.8%.
+const hyperLogLogPrecision = 14
+
+// hyperLogLogSparseLimit is how many registers a sketch keeps in its sparse
+// list before switching to the dense array. A sparse entry takes 4 bytes, so
+// a sparse sketch never uses more than half the dense size.
+const hyperLogLogSparseLimit = 1 << hyperLogLogPrecision / 8
+
+// hyperLogLog estimates the number of distinct keys in bounded memory. Keys
+// are hashed deterministically, so the same input always gives the same
+// estimate. Small sketches (most groups in a grouped query) only record the
+// registers they have set; the sparse and dense forms give identical
+// estimates.
+type hyperLogLog struct {
+	sparse    []uint32 // index<<8 | rank, sorted by index; used while registers is nil
+	registers []uint8
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{}
+}
+
+func (h *hyperLogLog) add(key string) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(key))
+	x := mixHash64(hasher.Sum64())
+	idx := uint32(x >> (64 - hyperLogLogPrecision))
+	rank := uint8(bits.LeadingZeros64(x<<hyperLogLogPrecision|1<<(hyperLogLogPrecision-1)) + 1)
+	if h.registers == nil {
+		h.addSparse(idx, rank)
+		return
+	}
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) addSparse(idx uint32, rank uint8) {
+	i := sort.Search(len(h.sparse), func(i int) bool { return h.sparse[i]>>8 >= idx })
+	if i < len(h.sparse) && h.sparse[i]>>8 == idx {
+		if rank > uint8(h.sparse[i]) {
+			h.sparse[i] = idx<<8 | uint32(rank)
+		}
+		return
+	}
+	if len(h.sparse) >= hyperLogLogSparseLimit {
+		h.densify()
+		h.registers[idx] = rank
+		return
+	}
+	h.sparse = append(h.sparse, 0)
+	copy(h.sparse[i+1:], h.sparse[i:])
+	h.sparse[i] = idx<<8 | uint32(rank)
+}
+
+func (h *hyperLogLog) densify() {
+	h.registers = make([]uint8, 1<<hyperLogLogPrecision)
+	for _, entry := range h.sparse {
+		h.registers[entry>>8] = uint8(entry)
+	}
+	h.sparse = nil
+}
+
+// estimate applies the raw HyperLogLog estimator, switching to linear counting
+// while many registers are still empty, which keeps small counts near exact.
+func (h *hyperLogLog) estimate() int64 {
+	m := float64(int(1) << hyperLogLogPrecision)
+	sum := 0.0
+	zeros := 0
+	if h.registers == nil {
+		zeros = 1<<hyperLogLogPrecision - len(h.sparse)
+		sum = float64(zeros)
+		for _, entry := range h.sparse {
+			sum += math.Ldexp(1, -int(uint8(entry)))
+		}
+	} else {
+		for _, r := range h.registers {
+			sum += math.Ldexp(1, -int(r))
+			if r == 0 {
+				zeros++
+			}
+		}
+	}
+	est := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return int64(math.Round(est))
+}
+
+// mixHash64 is the MurmurHash3 finalizer. FNV alone leaves the high bits of
+// short, similar keys correlated, and HyperLogLog reads those bits directly.
+func mixHash64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -95,14 +95,13 @@ func execPlannedJoin(p plannedJoin, left *table.Table) (*table.Table, error) {
 	if err := validateJoinRightInputSchema(p.right.env, right); err != nil {
 		return nil, err
 	}
-	leftKeys := p.leftKeys
-	rightKeys := p.rightKeys
 	outputs := p.outputs
 
 	outCols, outSchemas := outputEnvColumns(p.OutputEnv())
 	result := table.NewTableWithSchemas(outCols, outSchemas)
 
-	rightIndex, err := buildJoinIndex(right, rightKeys)
+	leftExprKeys := compileJoinExprKeys(p.leftExprKeys, left)
+	rightIndex, err := buildJoinIndex(right, p.rightKeys, compileJoinExprKeys(p.rightExprKeys, right)...)
 	if err != nil {
 		return nil, fmt.Errorf("join: %w", err)
 	}
@@ -113,39 +112,11 @@ func execPlannedJoin(p plannedJoin, left *table.Table) (*table.Table, error) {
 	emit := func(leftRow, rightRow int) error {
 		vals := make([]table.Value, len(outCols))
 		for outIdx, output := range outputs {
-			switch output.kind {
-			case plannedJoinOutputLeft:
-				if leftRow >= 0 {
-					vals[outIdx] = left.Col(output.leftIndex).Get(leftRow)
-				} else {
-					vals[outIdx] = table.Null()
-				}
-			case plannedJoinOutputKey:
-				switch {
-				case leftRow >= 0:
-					v, err := resolveBoundColumn(leftKeys[output.keyIndex].column, left, leftRow)
-					if err != nil {
-						return err
-					}
-					vals[outIdx] = v
-				case rightRow >= 0:
-					v, err := resolveBoundColumn(rightKeys[output.keyIndex].column, right, rightRow)
-					if err != nil {
-						return err
-					}
-					vals[outIdx] = v
-				default:
-					vals[outIdx] = table.Null()
-				}
-			case plannedJoinOutputRight:
-				if rightRow >= 0 {
-					vals[outIdx] = right.Col(output.rightIndex).Get(rightRow)
-				} else {
-					vals[outIdx] = table.Null()
-				}
-			default:
-				return fmt.Errorf("join: output column %d has unknown source kind", outIdx)
+			v, err := joinOutputValue(p, output, left, right, leftRow, rightRow)
+			if err != nil {
+				return err
 			}
+			vals[outIdx] = v
 		}
 		if err := result.AddRowTyped(vals); err != nil {
 			return err
@@ -153,31 +124,66 @@ func execPlannedJoin(p plannedJoin, left *table.Table) (*table.Table, error) {
 		return nil
 	}
 
+	if p.asof != nil {
+		if err := asofJoinRows(p, left, right, rightIndex, emit); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
 	keepLeftUnmatched := p.kind == "left" || p.kind == "full"
 	keepRightUnmatched := p.kind == "right" || p.kind == "full"
 
-	for li := 0; li < left.NumRows; li++ {
-		key, ok, err := joinKeyAt(left, leftKeys, li)
-		if err != nil {
-			return nil, fmt.Errorf("join: %w", err)
+	for start := 0; start < left.NumRows; {
+		// Candidates are gathered for a batch of left rows so that a residual
+		// condition can be checked for the whole batch at once.
+		var rows []int
+		var candidates [][]int
+		pairs := 0
+		for ; start < left.NumRows && (pairs < joinConditionBatchPairs || len(rows) == 0); start++ {
+			key, ok, err := joinKeyAt(left, p.leftKeys, start, leftExprKeys...)
+			if err != nil {
+				return nil, fmt.Errorf("join: %w", err)
+			}
+			var matches []int
+			if ok {
+				matches = rightIndex[key]
+			}
+			rows = append(rows, start)
+			candidates = append(candidates, matches)
+			pairs += len(matches)
 		}
-		var matches []int
-		if ok {
-			matches = rightIndex[key]
+		if p.condition != nil && pairs > 0 {
+			candidates, err = filterJoinCandidates(p, left, right, rows, candidates, pairs)
+			if err != nil {
+				return nil, err
+			}
 		}
-		if len(matches) == 0 {
-			if keepLeftUnmatched {
-				if err := emit(li, -1); err != nil {
-					return nil, err
+
+		for i, li := range rows {
+			matches := candidates[i]
+			if joinKeepsLeftOnly(p.kind) {
+				if (len(matches) > 0) == (p.kind == "semi") {
+					if err := emit(li, -1); err != nil {
+						return nil, err
+					}
 				}
+				continue
 			}
-			continue
-		}
-		for _, ri := range matches {
-			if err := emit(li, ri); err != nil {
-				return nil, err
+			if len(matches) == 0 {
+				if keepLeftUnmatched {
+					if err := emit(li, -1); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+			for _, ri := range matches {
+				if err := emit(li, ri); err != nil {
+					return nil, err
+				}
+				matchedRight[ri] = true
 			}
-			matchedRight[ri] = true
 		}
 	}
 
@@ -194,6 +200,91 @@ func execPlannedJoin(p plannedJoin, left *table.Table) (*table.Table, error) {
 	return result, nil
 }
 
+// joinConditionBatchPairs bounds how many candidate pairs are materialized at
+// once to check a residual on condition.
+const joinConditionBatchPairs = 4096
+
+// joinOutputValue resolves one output column for a pair of rows. A negative
+// row index means that side has no row.
+func joinOutputValue(p plannedJoin, output plannedJoinOutput, left, right *table.Table, leftRow, rightRow int) (table.Value, error) {
+	switch output.kind {
+	case plannedJoinOutputLeft:
+		if leftRow >= 0 {
+			return left.Col(output.leftIndex).Get(leftRow), nil
+		}
+		return table.Null(), nil
+	case plannedJoinOutputKey:
+		switch {
+		case leftRow >= 0:
+			return resolveBoundColumn(p.leftKeys[output.keyIndex].column, left, leftRow)
+		case rightRow >= 0:
+			return resolveBoundColumn(p.rightKeys[output.keyIndex].column, right, rightRow)
+		default:
+			return table.Null(), nil
+		}
+	case plannedJoinOutputRight:
+		if rightRow >= 0 {
+			return right.Col(output.rightIndex).Get(rightRow), nil
+		}
+		return table.Null(), nil
+	default:
+		return table.Null(), fmt.Errorf("join: output column has unknown source kind %d", output.kind)
+	}
+}
+
+// filterJoinCandidates keeps the candidate pairs for which the residual on
+// condition is true. The pairs are laid out as rows of a table holding just
+// the columns the condition reads, so it runs through the compiled
+// expression path like a filter.
+func filterJoinCandidates(p plannedJoin, left, right *table.Table, rows []int, candidates [][]int, pairs int) ([][]int, error) {
+	cond := p.condition
+	cols, schemas := outputEnvColumns(cond.env)
+	pairTable := table.NewTableWithSchemas(cols, schemas)
+	vals := make([]table.Value, len(cols))
+	for i, li := range rows {
+		for _, ri := range candidates[i] {
+			for c, source := range cond.sources {
+				v, err := joinOutputValue(p, source, left, right, li, ri)
+				if err != nil {
+					return nil, fmt.Errorf("join: on condition: %w", err)
+				}
+				vals[c] = v
+			}
+			if err := pairTable.AddRowTyped(vals); err != nil {
+				return nil, fmt.Errorf("join: on condition: %w", err)
+			}
+		}
+	}
+	if pairTable.NumRows != pairs {
+		return nil, fmt.Errorf("join: on condition: built %d candidate rows for %d pairs", pairTable.NumRows, pairs)
+	}
+
+	pred := compileFilterPredicate(cond.expr, pairTable)
+	filtered := make([][]int, len(candidates))
+	pair := 0
+	for i := range candidates {
+		for _, ri := range candidates[i] {
+			ok, err := pred(pair)
+			if err != nil {
+				return nil, fmt.Errorf("join: on condition: %w", err)
+			}
+			if ok {
+				filtered[i] = append(filtered[i], ri)
+			}
+			pair++
+		}
+	}
+	return filtered, nil
+}
+
+func compileJoinExprKeys(keys []typedExpr, t *table.Table) []rowValueEvaluator {
+	evals := make([]rowValueEvaluator, len(keys))
+	for i, key := range keys {
+		evals[i] = compileTypedRowValue(key, t)
+	}
+	return evals
+}
+
 func validateJoinRightInputSchema(want schemaEnv, got *table.Table) error {
 	if got == nil {
 		return fmt.Errorf("join: right input is nil")
@@ -231,7 +322,7 @@ func joinKeyComparableSchema(schema *table.TypeDescriptor) *table.TypeDescriptor
 	out := *schema
 	out.Nullable = false
 	switch out.Kind {
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		out.Elem = joinKeyComparableSchema(out.Elem)
 	case table.TypeRecord:
 		out.Fields = make([]table.FieldDescriptor, len(schema.Fields))
@@ -302,11 +393,12 @@ func stripGlobMeta(s string) string {
 	return strings.Trim(b.String(), "_")
 }
 
-// joinKeyAt builds the composite key for a row. ok is false when any key part
-// is null (null keys never match). Resolution failures (e.g. dot path through
+// joinKeyAt builds the composite key for a row from its key columns and any
+// expression keys. ok is false when any key part is null (null keys never
+// match). Resolution failures (e.g. dot path through
 // a non-record value) are returned as errors, matching the rest of the engine.
-func joinKeyAt(t *table.Table, keys []resolvedJoinKey, row int) (string, bool, error) {
-	parts := make([]string, len(keys))
+func joinKeyAt(t *table.Table, keys []resolvedJoinKey, row int, exprs ...rowValueEvaluator) (string, bool, error) {
+	parts := make([]string, len(keys), len(keys)+len(exprs))
 	for i, k := range keys {
 		v, err := resolveBoundColumn(k.column, t, row)
 		if err != nil {
@@ -317,13 +409,23 @@ func joinKeyAt(t *table.Table, keys []resolvedJoinKey, row int) (string, bool, e
 		}
 		parts[i] = table.CanonicalKey(v)
 	}
+	for _, eval := range exprs {
+		v, err := eval(row)
+		if err != nil {
+			return "", false, err
+		}
+		if v.IsNull() {
+			return "", false, nil
+		}
+		parts = append(parts, table.CanonicalKey(v))
+	}
 	return strings.Join(parts, "\x00"), true, nil
 }
 
-func buildJoinIndex(t *table.Table, keys []resolvedJoinKey) (map[string][]int, error) {
+func buildJoinIndex(t *table.Table, keys []resolvedJoinKey, exprs ...rowValueEvaluator) (map[string][]int, error) {
 	index := make(map[string][]int)
 	for i := 0; i < t.NumRows; i++ {
-		key, ok, err := joinKeyAt(t, keys, i)
+		key, ok, err := joinKeyAt(t, keys, i, exprs...)
 		if err != nil {
 			return nil, err
 		}
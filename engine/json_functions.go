@@ -0,0 +1,381 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+var (
+	jsonSchemaCacheMu sync.Mutex
+	// jsonSchemaCache holds parsed schema literals; like regexCache it lives
+	// for the process, which is fine because schemas must be literals.
+	jsonSchemaCache = map[string]*table.TypeDescriptor{}
+)
+
+// parseJSONSchemaArg reads the schema argument of json_parse() and
+// json_extract(), which must be a string literal in describe's notation.
+func parseJSONSchemaArg(name string, arg typedExpr) (*table.TypeDescriptor, error) {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok || lit.Kind != "string" {
+		return nil, fmt.Errorf("%s() schema must be a string literal, as in \"record<id:int>\"", name)
+	}
+	jsonSchemaCacheMu.Lock()
+	defer jsonSchemaCacheMu.Unlock()
+	if schema, ok := jsonSchemaCache[lit.Str]; ok {
+		return schema, nil
+	}
+	schema, err := table.ParseSchema(lit.Str)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", name, err)
+	}
+	jsonSchemaCache[lit.Str] = schema
+	return schema, nil
+}
+
+// decodeJSONString decodes one JSON document, keeping numbers as
+// json.Number so ints stay exact.
+func decodeJSONString(name, s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s(): invalid JSON %q: %v", name, s, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%s(): invalid JSON %q: unexpected data after the value", name, s)
+	}
+	return v, nil
+}
+
+// jsonToSchema converts a decoded JSON value to schema. JSON null is always
+// allowed because json_parse() and json_extract() results are nullable.
+func jsonToSchema(name string, v interface{}, schema *table.TypeDescriptor) (table.Value, error) {
+	out, err := jsonValueForSchema(v, schema, "")
+	if err != nil {
+		return table.Null(), fmt.Errorf("%s(): %w", name, err)
+	}
+	if out.IsNull() {
+		return out, nil
+	}
+	out, err = table.CoerceValueToSchema(out, schema)
+	if err != nil {
+		return table.Null(), fmt.Errorf("%s(): %w", name, err)
+	}
+	return out, nil
+}
+
+// jsonValueForSchema is table.ValueFromJSON, except that numbers at decimal
+// positions of schema are read as decimals, so 12.50 stays exact instead of
+// passing through float64.
+func jsonValueForSchema(v interface{}, schema *table.TypeDescriptor, path string) (table.Value, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if schema.Kind == table.TypeDecimal {
+			if d, ok := table.ParseDecimal(val.String()); ok {
+				return d, nil
+			}
+		}
+	case []interface{}:
+		if schema.Kind == table.TypeList && schema.Elem != nil {
+			elems := make([]table.Value, len(val))
+			for i, elem := range val {
+				out, err := jsonValueForSchema(elem, schema.Elem, path+"[]")
+				if err != nil {
+					return table.Null(), err
+				}
+				elems[i] = out
+			}
+			return table.ListVal(elems), nil
+		}
+	case map[string]interface{}:
+		if schema.Kind == table.TypeRecord {
+			fieldSchemas := make(map[string]*table.TypeDescriptor, len(schema.Fields))
+			for _, field := range schema.Fields {
+				fieldSchemas[field.Name] = field.Type
+			}
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			fields := make([]table.RecordField, len(keys))
+			for i, k := range keys {
+				fieldPath := k
+				if path != "" {
+					fieldPath = path + "." + k
+				}
+				var err error
+				if fieldSchema, ok := fieldSchemas[k]; ok {
+					fields[i].Value, err = jsonValueForSchema(val[k], fieldSchema, fieldPath)
+				} else {
+					fields[i].Value, err = table.ValueFromJSON(val[k], fieldPath)
+				}
+				if err != nil {
+					return table.Null(), err
+				}
+				fields[i].Name = k
+			}
+			return table.RecordVal(fields), nil
+		}
+		if schema.Kind == table.TypeMap && schema.Elem != nil {
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			entries := make([]table.RecordField, len(keys))
+			for i, k := range keys {
+				out, err := jsonValueForSchema(val[k], schema.Elem, path+"{}")
+				if err != nil {
+					return table.Null(), err
+				}
+				entries[i] = table.RecordField{Name: k, Value: out}
+			}
+			return table.MapVal(entries), nil
+		}
+	}
+	return table.ValueFromJSON(v, path)
+}
+
+// inferredJSONSchema types a literal JSON document by its own value.
+func inferredJSONSchema(name, s string) (*table.TypeDescriptor, error) {
+	v, err := decodeJSONString(name, s)
+	if err != nil {
+		return nil, err
+	}
+	value, err := table.ValueFromJSON(v, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", name, err)
+	}
+	return table.FinalizeSchema(table.InferValueSchema(value)), nil
+}
+
+// jsonParseSchema returns the schema json_parse() converts to: the schema
+// argument, or the type of a literal input when there is none.
+func jsonParseSchema(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) == 2 {
+		return parseJSONSchemaArg("json_parse", args[1])
+	}
+	if lit, ok := args[0].raw.(*ast.LiteralExpr); ok && lit.Kind == "string" {
+		return inferredJSONSchema("json_parse", lit.Str)
+	}
+	return nil, fmt.Errorf("json_parse() needs a schema unless its input is a literal, as in json_parse(payload, \"record<id:int>\")")
+}
+
+func checkJSONParseSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("json_parse() takes 1 or 2 arguments (string[, schema]), got %d", len(args))
+	}
+	if !schemaKindOrNull(args[0].typ, table.TypeString) {
+		return nil, fmt.Errorf("json_parse() requires a string, got %s", schemaString(args[0].typ))
+	}
+	schema, err := jsonParseSchema(args)
+	if err != nil {
+		return nil, err
+	}
+	return table.WithNullable(schema), nil
+}
+
+func typedJSONParseEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return table.Null(), fmt.Errorf("json_parse() takes 1 or 2 arguments (string[, schema]), got %d", len(args))
+	}
+	schema, err := jsonParseSchema(args)
+	if err != nil {
+		return table.Null(), err
+	}
+	s, err := evalTypedExpression(args[0], ctx)
+	if err != nil || s.IsNull() {
+		return table.Null(), err
+	}
+	if s.Type != table.TypeString {
+		return table.Null(), fmt.Errorf("json_parse() requires a string, got %s", valueTypeName(s))
+	}
+	v, err := decodeJSONString("json_parse", s.Str)
+	if err != nil {
+		return table.Null(), err
+	}
+	return jsonToSchema("json_parse", v, schema)
+}
+
+// jsonPathStep is one step of a json_extract() path: an object key, or an
+// array index when key is empty.
+type jsonPathStep struct {
+	key   string
+	index int
+}
+
+// parseJSONPath parses paths such as $.user.tags[0] or $["odd key"]. Keys
+// after a dot run to the next dot or bracket.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json_extract(): path %q must start with $", path)
+	}
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("json_extract(): path %q has an empty key", path)
+			}
+			steps = append(steps, jsonPathStep{key: rest[1 : end+1]})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("json_extract(): path %q has an unclosed [", path)
+			}
+			inner := rest[1:end]
+			if key, err := strconv.Unquote(inner); err == nil && strings.HasPrefix(inner, `"`) {
+				steps = append(steps, jsonPathStep{key: key})
+			} else if i, err := strconv.Atoi(inner); err == nil && i >= 0 {
+				steps = append(steps, jsonPathStep{index: i})
+			} else {
+				return nil, fmt.Errorf("json_extract(): path %q: expected an index or a quoted key in [%s]", path, inner)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("json_extract(): path %q: expected . or [ at %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// walkJSONPath follows steps through a decoded document; ok is false when a
+// key or index is missing.
+func walkJSONPath(v interface{}, steps []jsonPathStep) (interface{}, bool) {
+	for _, step := range steps {
+		if step.key != "" {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[step.key]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		arr, ok := v.([]interface{})
+		if !ok || step.index >= len(arr) {
+			return nil, false
+		}
+		v = arr[step.index]
+	}
+	return v, true
+}
+
+func checkJSONExtractSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("json_extract() takes 2 or 3 arguments (string, path[, schema]), got %d", len(args))
+	}
+	if !schemaKindOrNull(args[0].typ, table.TypeString) {
+		return nil, fmt.Errorf("json_extract() requires a string, got %s", schemaString(args[0].typ))
+	}
+	if !schemaKindOrNull(args[1].typ, table.TypeString) {
+		return nil, fmt.Errorf("json_extract() requires a string path, got %s", schemaString(args[1].typ))
+	}
+	if lit, ok := args[1].raw.(*ast.LiteralExpr); ok && lit.Kind == "string" {
+		if _, err := parseJSONPath(lit.Str); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) == 3 {
+		schema, err := parseJSONSchemaArg("json_extract", args[2])
+		if err != nil {
+			return nil, err
+		}
+		return table.WithNullable(schema), nil
+	}
+	return &table.TypeDescriptor{Kind: table.TypeString, Nullable: true}, nil
+}
+
+// typedJSONExtractEval returns the value at path, or null when the path is
+// missing. Without a schema the value comes back as text: strings unquoted,
+// and numbers, booleans, objects and arrays as compact JSON.
+func typedJSONExtractEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return table.Null(), fmt.Errorf("json_extract() takes 2 or 3 arguments (string, path[, schema]), got %d", len(args))
+	}
+	var schema *table.TypeDescriptor
+	if len(args) == 3 {
+		var err error
+		if schema, err = parseJSONSchemaArg("json_extract", args[2]); err != nil {
+			return table.Null(), err
+		}
+	}
+	s, path, ok, err := typedStringPredicateArgs("json_extract", "path", args[:2], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return table.Null(), err
+	}
+	doc, err := decodeJSONString("json_extract", s)
+	if err != nil {
+		return table.Null(), err
+	}
+	v, found := walkJSONPath(doc, steps)
+	if !found || v == nil {
+		return table.Null(), nil
+	}
+	if schema != nil {
+		return jsonToSchema("json_extract", v, schema)
+	}
+	if str, ok := v.(string); ok {
+		return table.StrVal(str), nil
+	}
+	text, err := marshalJSON(v)
+	if err != nil {
+		return table.Null(), fmt.Errorf("json_extract(): %w", err)
+	}
+	return table.StrVal(text), nil
+}
+
+// marshalJSON encodes compactly with the same escaping as the json and jsonl
+// writers.
+func marshalJSON(v interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func checkToJSONSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("to_json() takes 1 argument, got %d", len(args))
+	}
+	return nullableSchema(table.TypeString, args[0].typ), nil
+}
+
+// typedToJSONEval serializes a value the way the json output format writes
+// it, with record fields in name order.
+func typedToJSONEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) != 1 {
+		return table.Null(), fmt.Errorf("to_json() takes 1 argument, got %d", len(args))
+	}
+	v, err := evalTypedExpression(args[0], ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), err
+	}
+	text, err := marshalJSON(table.ValueToJSON(v))
+	if err != nil {
+		return table.Null(), fmt.Errorf("to_json(): %w", err)
+	}
+	return table.StrVal(text), nil
+}
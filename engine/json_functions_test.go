@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+const payloadCSV = "id,payload\n" +
+	`1,"{""user"":{""name"":""ann"",""tags"":[""a"",""b""]},""n"":3,""price"":12.50}"` + "\n" +
+	`2,"{""user"":{""name"":""bob""},""n"":null}"` + "\n" +
+	"3,\n"
+
+const payloadSchema = `"record<user:record<name:string, tags:list<string>?>, n:int?, price:decimal(5,2)?>"`
+
+func TestJSONParseWithSchema(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "payload.csv", payloadCSV)
+	result := loadAndQuery(t, path, `transform p = json_parse(payload, `+payloadSchema+`)
+		| transform name = p.user.name, tags = p.user.tags, total = p.price * p.n
+		| select name, tags, total`)
+	assertColumnStrings(t, result, "name", "ann", "bob", "null")
+	assertColumnStrings(t, result, "tags", "[a, b]", "null", "null")
+	assertColumnStrings(t, result, "total", "37.50", "null", "null")
+	assertColumnSchema(t, result, "name", "string?")
+	assertColumnSchema(t, result, "tags", "list<string>?")
+
+	described := loadAndQuery(t, path, `transform p = json_parse(payload, "record<n:int?>") | select p`)
+	assertColumnSchema(t, described, "p", "record<n:int?>?")
+	assertColumnStrings(t, described, "p", "{n:3}", "{n:null}", "null")
+}
+
+func TestJSONParseInfersLiteralSchema(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		xs = json_parse("[1, 2, 3]"),
+		rec = json_parse("{\"b\": [true], \"a\": \"x\"}")
+	| select xs, rec`)
+	assertColumnStrings(t, result, "xs", "[1, 2, 3]", "[1, 2, 3]", "[1, 2, 3]")
+	assertColumnSchema(t, result, "xs", "list<int>?")
+	assertColumnSchema(t, result, "rec", "record<a:string, b:list<bool>>?")
+}
+
+func TestJSONExtract(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "payload.csv", payloadCSV)
+	result := loadAndQuery(t, path, `transform
+		name = json_extract(payload, "$.user.name"),
+		tag = json_extract(payload, "$.user.tags[1]"),
+		user = json_extract(payload, "$[\"user\"]"),
+		price = json_extract(payload, "$.price"),
+		n = json_extract(payload, "$.n", "int"),
+		tags = json_extract(payload, "$.user.tags", "list<string>"),
+		missing = json_extract(payload, "$.user.tags[5]")
+	| select name, tag, user, price, n, tags, missing`)
+	assertColumnStrings(t, result, "name", "ann", "bob", "null")
+	assertColumnStrings(t, result, "tag", "b", "null", "null")
+	assertColumnStrings(t, result, "user", `{"name":"ann","tags":["a","b"]}`, `{"name":"bob"}`, "null")
+	assertColumnStrings(t, result, "price", "12.50", "null", "null")
+	assertColumnStrings(t, result, "n", "3", "null", "null")
+	assertColumnStrings(t, result, "tags", "[a, b]", "null", "null")
+	assertColumnStrings(t, result, "missing", "null", "null", "null")
+	assertColumnSchema(t, result, "name", "string?")
+	assertColumnSchema(t, result, "n", "int?")
+	assertColumnSchema(t, result, "tags", "list<string>?")
+}
+
+func TestToJSONRoundTrips(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "payload.csv", payloadCSV)
+	result := loadAndQuery(t, path, `transform p = json_parse(payload, `+payloadSchema+`)
+		| transform text = to_json(p), again = to_json(json_parse(to_json(p), `+payloadSchema+`)), rec = to_json(struct(id = id, tag = "<b>", on = date("2024-01-02")))
+		| select text, again, rec`)
+	assertColumnStrings(t, result, "text",
+		`{"n":3,"price":12.50,"user":{"name":"ann","tags":["a","b"]}}`,
+		`{"n":null,"price":null,"user":{"name":"bob","tags":null}}`,
+		"null")
+	for i := 0; i < result.NumRows; i++ {
+		if got, want := result.Get(i, "again").AsString(), result.Get(i, "text").AsString(); got != want {
+			t.Errorf("row %d: round trip = %s, want %s", i, got, want)
+		}
+	}
+	assertColumnStrings(t, result, "rec",
+		`{"id":1,"on":"2024-01-02","tag":"\u003cb\u003e"}`,
+		`{"id":2,"on":"2024-01-02","tag":"\u003cb\u003e"}`,
+		`{"id":3,"on":"2024-01-02","tag":"\u003cb\u003e"}`)
+	assertColumnSchema(t, result, "rec", "string")
+}
+
+func TestJSONFunctionErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "payload.csv", payloadCSV)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform v = json_parse(payload)`, "json_parse() needs a schema unless its input is a literal"},
+		{`transform v = json_parse(payload, payload)`, "json_parse() schema must be a string literal"},
+		{`transform v = json_parse(payload, "record<n:varchar>")`, `json_parse(): invalid schema "record<n:varchar>" at offset 9: unknown type "varchar"`},
+		{`transform v = json_parse(id, "int")`, "json_parse() requires a string, got int"},
+		{`transform v = json_parse(payload, "record<n:string?>")`, "json_parse(): n expected string?, got int"},
+		{`transform v = json_parse("{bad")`, `json_parse(): invalid JSON "{bad"`},
+		{`transform v = json_parse(payload || "x", "record<n:int?>")`, "unexpected data after the value"},
+		{`transform v = json_extract(payload, "user")`, `json_extract(): path "user" must start with $`},
+		{`transform v = json_extract(payload, "$.a[x]")`, "expected an index or a quoted key in [x]"},
+		{`transform v = json_extract(payload, "$.a..b")`, "has an empty key"},
+		{`transform v = json_extract(payload)`, "json_extract() takes 2 or 3 arguments (string, path[, schema]), got 1"},
+		{`transform v = to_json()`, "to_json() takes 1 argument, got 0"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
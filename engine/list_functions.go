@@ -0,0 +1,679 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/razeghi71/dq/table"
+)
+
+const (
+	listPredicateUsage = "list, x -> bool"
+	listMapUsage       = "list, x -> value"
+	listReduceUsage    = "list, initial, (acc, x) -> value"
+)
+
+// listElemLambda types the single lambda parameter of list_filter, list_map,
+// list_any and list_all from the element schema of the list argument.
+func listElemLambda(name, usage string) *lambdaSpec {
+	return &lambdaSpec{
+		Usage: usage,
+		Arity: 2,
+		Arg:   1,
+		Params: func(args []typedExpr, _ *table.TypeDescriptor) ([]*table.TypeDescriptor, error) {
+			elem, err := listElemSchema(name, args[0].typ)
+			if err != nil {
+				return nil, err
+			}
+			return []*table.TypeDescriptor{elem}, nil
+		},
+	}
+}
+
+var listReduceLambda = &lambdaSpec{Usage: listReduceUsage, Arity: 3, Arg: 2, Params: listReduceParams}
+
+// listReduceParams types list_reduce's lambda as (accumulator, element). The
+// accumulator starts as the initial value's schema and widens to what the
+// body returns, so list_reduce(xs, 0, (acc, x) -> acc + x.price) accumulates
+// floats when prices are floats.
+func listReduceParams(args []typedExpr, body *table.TypeDescriptor) ([]*table.TypeDescriptor, error) {
+	elem, err := listElemSchema("list_reduce", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := listReduceAccumulatorSchema(args[1].typ, body)
+	if err != nil {
+		return nil, err
+	}
+	return []*table.TypeDescriptor{acc, elem}, nil
+}
+
+// listReduceAccumulatorSchema unifies the initial value with the lambda's
+// result. A decimal accumulator widens to the maximum precision, as sum()
+// does, so a running total is not limited to the digits of its inputs.
+func listReduceAccumulatorSchema(init, body *table.TypeDescriptor) (*table.TypeDescriptor, error) {
+	acc := init
+	if body != nil {
+		unified, err := unifyExpressionStrict(init, body)
+		if err != nil {
+			return nil, fmt.Errorf("list_reduce() lambda returns %s, which does not match the initial value's %s", schemaString(body), schemaString(init))
+		}
+		acc = unified
+	}
+	if schemaIsDecimal(acc) {
+		out := table.DecimalSchema(table.MaxDecimalPrecision, finalizePlanningSchema(acc).Scale)
+		out.Nullable = acc.Nullable
+		return out, nil
+	}
+	return normalizePlanningSchema(acc), nil
+}
+
+// listElemSchema returns the element schema of a list argument. A null list
+// or an empty list literal has null elements.
+func listElemSchema(name string, typ *table.TypeDescriptor) (*table.TypeDescriptor, error) {
+	if !schemaKindOrNull(typ, table.TypeList) {
+		return nil, fmt.Errorf("%s() requires a list, got %s", name, schemaString(typ))
+	}
+	list := normalizePlanningSchema(typ)
+	if list.Kind != table.TypeList || list.Elem == nil {
+		return &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}, nil
+	}
+	return list.Elem, nil
+}
+
+func checkLambdaCallArgs(name, usage string, args []typedExpr, arity int) error {
+	if len(args) != arity {
+		return fmt.Errorf("%s() takes %d arguments (%s), got %d", name, arity, usage, len(args))
+	}
+	_, err := listElemSchema(name, args[0].typ)
+	return err
+}
+
+func checkLambdaPredicate(name string, pred typedExpr) error {
+	if !schemaBoolOrNull(pred.typ) {
+		return fmt.Errorf("%s() lambda must return bool, got %s", name, schemaString(pred.typ))
+	}
+	return nil
+}
+
+func checkListFilterSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if err := checkLambdaCallArgs("list_filter", listPredicateUsage, args, 2); err != nil {
+		return nil, err
+	}
+	if err := checkLambdaPredicate("list_filter", args[1]); err != nil {
+		return nil, err
+	}
+	return normalizePlanningSchema(args[0].typ), nil
+}
+
+func checkListMapSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if err := checkLambdaCallArgs("list_map", listMapUsage, args, 2); err != nil {
+		return nil, err
+	}
+	elem := args[1].typ
+	if elem == nil {
+		elem = &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}
+	}
+	return &table.TypeDescriptor{Kind: table.TypeList, Elem: elem, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+// listPredicateSpec checks list_any and list_all, which follow three-valued
+// logic: a null predicate result makes the answer null unless another element
+// already decides it.
+func listPredicateSpec(name string) func([]typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if err := checkLambdaCallArgs(name, listPredicateUsage, args, 2); err != nil {
+			return nil, err
+		}
+		if err := checkLambdaPredicate(name, args[1]); err != nil {
+			return nil, err
+		}
+		return nullableSchema(table.TypeBool, args[0].typ, args[1].typ), nil
+	}
+}
+
+func checkListReduceSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if err := checkLambdaCallArgs("list_reduce", listReduceUsage, args, 3); err != nil {
+		return nil, err
+	}
+	acc, err := listReduceAccumulatorSchema(args[1].typ, args[2].typ)
+	if err != nil {
+		return nil, err
+	}
+	if anySchemaMayBeNull(args[0].typ) {
+		acc = table.WithNullable(acc)
+	}
+	return acc, nil
+}
+
+// coerceListReduceArgs converts the initial value and the lambda's result to
+// the accumulator schema, so the accumulator keeps one runtime type.
+func coerceListReduceArgs(args []logicalTypedExpr, typ *table.TypeDescriptor) {
+	if len(args) != 3 || args[2].operand == nil {
+		return
+	}
+	if runtimeCoercionNeeded(args[1].typ, typ) {
+		args[1] = coerceLogicalTypedExpression(args[1], typ)
+	}
+	if body := *args[2].operand; runtimeCoercionNeeded(body.typ, typ) {
+		coerced := coerceLogicalTypedExpression(body, typ)
+		args[2].operand = &coerced
+	}
+}
+
+// callLambda evaluates a lambda argument with its parameters bound to values.
+// Each lambda owns the slots from its first parameter up, so a nested lambda
+// never overwrites the parameters of the lambdas around it.
+func callLambda(fn typedExpr, ctx *EvalContext, values ...table.Value) (table.Value, error) {
+	lambda, ok := fn.bound.(*boundLambda)
+	if !ok || fn.operand == nil {
+		return table.Null(), fmt.Errorf("expected a lambda argument, got %T", fn.bound)
+	}
+	for len(ctx.Lambda) < lambda.slot+len(values) {
+		ctx.Lambda = append(ctx.Lambda, table.Null())
+	}
+	copy(ctx.Lambda[lambda.slot:], values)
+	return evalTypedExpression(*fn.operand, ctx)
+}
+
+// evalLambdaListArg evaluates the list argument of a higher-order builtin.
+// ok is false when the list is null, which makes the whole call null.
+func evalLambdaListArg(name string, args []typedExpr, arity int, ctx *EvalContext) ([]table.Value, bool, error) {
+	if len(args) != arity {
+		return nil, false, fmt.Errorf("%s() takes %d arguments, got %d", name, arity, len(args))
+	}
+	if ctx == nil {
+		return nil, false, fmt.Errorf("%s() needs an evaluation context", name)
+	}
+	v, err := evalTypedExpression(args[0], ctx)
+	if err != nil || v.IsNull() {
+		return nil, false, err
+	}
+	if v.Type != table.TypeList {
+		return nil, false, fmt.Errorf("%s() requires a list, got %s", name, valueTypeName(v))
+	}
+	return v.List, true, nil
+}
+
+// callLambdaPredicate calls a bool lambda and returns its result as a truth
+// value, which is true, false or null.
+func callLambdaPredicate(name string, fn typedExpr, ctx *EvalContext, elem table.Value) (table.Value, error) {
+	v, err := callLambda(fn, ctx, elem)
+	if err != nil {
+		return table.Null(), err
+	}
+	if !v.IsBoolOrNull() {
+		return table.Null(), fmt.Errorf("%s() lambda must return bool, got %s", name, valueTypeName(v))
+	}
+	return v, nil
+}
+
+func typedListFilterEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	list, ok, err := evalLambdaListArg("list_filter", args, 2, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := make([]table.Value, 0, len(list))
+	for _, elem := range list {
+		keep, err := callLambdaPredicate("list_filter", args[1], ctx, elem)
+		if err != nil {
+			return table.Null(), err
+		}
+		if keep.IsExplicitTrue() {
+			out = append(out, elem)
+		}
+	}
+	return table.ListVal(out), nil
+}
+
+func typedListMapEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	list, ok, err := evalLambdaListArg("list_map", args, 2, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := make([]table.Value, len(list))
+	for i, elem := range list {
+		if out[i], err = callLambda(args[1], ctx, elem); err != nil {
+			return table.Null(), err
+		}
+	}
+	return table.ListVal(out), nil
+}
+
+// typedListQuantifierEval evaluates list_any (decisive = true) and list_all
+// (decisive = false). The first element whose predicate returns the decisive
+// value ends the scan; otherwise a null predicate result makes the answer
+// null, and an empty list gives the opposite of the decisive value.
+func typedListQuantifierEval(name string, decisive bool) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		list, ok, err := evalLambdaListArg(name, args, 2, ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		sawNull := false
+		for _, elem := range list {
+			v, err := callLambdaPredicate(name, args[1], ctx, elem)
+			if err != nil {
+				return table.Null(), err
+			}
+			if v.IsNull() {
+				sawNull = true
+				continue
+			}
+			if v.Bool == decisive {
+				return table.BoolVal(decisive), nil
+			}
+		}
+		if sawNull {
+			return table.Null(), nil
+		}
+		return table.BoolVal(!decisive), nil
+	}
+}
+
+func typedListReduceEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	list, ok, err := evalLambdaListArg("list_reduce", args, 3, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	acc, err := evalTypedExpression(args[1], ctx)
+	if err != nil {
+		return table.Null(), err
+	}
+	for _, elem := range list {
+		if acc, err = callLambda(args[2], ctx, acc, elem); err != nil {
+			return table.Null(), err
+		}
+	}
+	return acc, nil
+}
+
+// listSchemaOf returns the schema of a list argument for builtins that return
+// elements of that list, made nullable when another argument may be null.
+func listSchemaOf(list *table.TypeDescriptor, args []typedExpr) *table.TypeDescriptor {
+	out := normalizePlanningSchema(list)
+	for _, arg := range args {
+		if schemaMayBeNull(arg.typ) {
+			return table.WithNullable(out)
+		}
+	}
+	return out
+}
+
+func checkListGetSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("list_get() takes 2 arguments (list, index), got %d", len(args))
+	}
+	elem, err := listElemSchema("list_get", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaKindOrNull(args[1].typ, table.TypeInt) {
+		return nil, fmt.Errorf("list_get() index must be an int, got %s", schemaString(args[1].typ))
+	}
+	return table.WithNullable(elem), nil
+}
+
+func checkListSliceSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, arityRangeError("list_slice", 2, 3, len(args))
+	}
+	if _, err := listElemSchema("list_slice", args[0].typ); err != nil {
+		return nil, err
+	}
+	for _, arg := range args[1:] {
+		if !schemaKindOrNull(arg.typ, table.TypeInt) {
+			return nil, fmt.Errorf("list_slice() bounds must be ints, got %s", schemaString(arg.typ))
+		}
+	}
+	return listSchemaOf(args[0].typ, args[1:]), nil
+}
+
+// checkListSortSignature types list_sort(). The optional order is an "asc" or
+// "desc" literal, as for collect_list(), and the elements must be orderable.
+func checkListSortSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, arityRangeError("list_sort", 1, 2, len(args))
+	}
+	elem, err := listElemSchema("list_sort", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaOrderableOrNull(elem) {
+		return nil, fmt.Errorf("list_sort() requires orderable elements, got %s", schemaString(args[0].typ))
+	}
+	if len(args) == 2 {
+		if _, err := literalCollectOrder("list_sort", args[1].raw); err != nil {
+			return nil, err
+		}
+	}
+	return listSchemaOf(args[0].typ, nil), nil
+}
+
+func checkListDistinctSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("list_distinct() takes 1 argument, got %d", len(args))
+	}
+	if _, err := listElemSchema("list_distinct", args[0].typ); err != nil {
+		return nil, err
+	}
+	return listSchemaOf(args[0].typ, nil), nil
+}
+
+// checkListConcatSignature unifies the element schemas of every list, so
+// list_concat(ints, floats) is a list<float>. The result is null when any
+// list is null.
+func checkListConcatSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("list_concat() requires at least 2 arguments, got %d", len(args))
+	}
+	var out *table.TypeDescriptor
+	for _, arg := range args {
+		if _, err := listElemSchema("list_concat", arg.typ); err != nil {
+			return nil, err
+		}
+		unified, err := unifyExpressionStrict(out, arg.typ)
+		if err != nil {
+			return nil, fmt.Errorf("list_concat() element type mismatch: %s vs %s", schemaString(out), schemaString(arg.typ))
+		}
+		out = unified
+	}
+	return normalizePlanningSchema(out), nil
+}
+
+// checkListFlattenSignature turns a list<list<T>> into a list<T>. Null inner
+// lists are skipped.
+func checkListFlattenSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("list_flatten() takes 1 argument, got %d", len(args))
+	}
+	inner, err := listElemSchema("list_flatten", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaKindOrNull(inner, table.TypeList) {
+		return nil, fmt.Errorf("list_flatten() requires a list of lists, got %s", schemaString(args[0].typ))
+	}
+	out := &table.TypeDescriptor{Kind: table.TypeList, Nullable: anySchemaMayBeNull(args[0].typ)}
+	if inner = normalizePlanningSchema(inner); inner.Kind == table.TypeList {
+		out.Elem = inner.Elem
+	}
+	return out, nil
+}
+
+// checkListIntersectSignature types list_intersect(), whose result holds
+// elements of the first list, so it keeps that list's schema.
+func checkListIntersectSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("list_intersect() takes 2 arguments (list, list), got %d", len(args))
+	}
+	left, err := listElemSchema("list_intersect", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	right, err := listElemSchema("list_intersect", args[1].typ)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := unifyExpressionStrict(left, right); err != nil {
+		return nil, fmt.Errorf("list_intersect() element type mismatch: %s vs %s", schemaString(args[0].typ), schemaString(args[1].typ))
+	}
+	return listSchemaOf(args[0].typ, args[1:]), nil
+}
+
+func checkListJoinSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("list_join() takes 2 arguments (list, separator), got %d", len(args))
+	}
+	elem, err := listElemSchema("list_join", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaKindOrNull(elem, table.TypeString) {
+		return nil, fmt.Errorf("list_join() requires a list of strings, got %s", schemaString(args[0].typ))
+	}
+	if !schemaKindOrNull(args[1].typ, table.TypeString) {
+		return nil, fmt.Errorf("list_join() separator must be a string, got %s", schemaString(args[1].typ))
+	}
+	return nullableSchema(table.TypeString, args[0].typ, args[1].typ), nil
+}
+
+// evalListCallArgs evaluates the arguments of a list builtin and checks that
+// the first minLists of them are lists. ok is false when any argument is null,
+// which makes the call null.
+func evalListCallArgs(name string, args []typedExpr, minArity, maxArity, minLists int, ctx *EvalContext) ([]table.Value, bool, error) {
+	if maxArity < 0 && len(args) < minArity {
+		return nil, false, fmt.Errorf("%s() requires at least %d arguments, got %d", name, minArity, len(args))
+	}
+	if maxArity >= 0 && (len(args) < minArity || len(args) > maxArity) {
+		return nil, false, arityRangeError(name, minArity, maxArity, len(args))
+	}
+	values := make([]table.Value, len(args))
+	for i, arg := range args {
+		v, err := evalTypedExpression(arg, ctx)
+		if err != nil || v.IsNull() {
+			return nil, false, err
+		}
+		if i < minLists && v.Type != table.TypeList {
+			return nil, false, fmt.Errorf("%s() requires a list, got %s", name, valueTypeName(v))
+		}
+		values[i] = v
+	}
+	return values, true, nil
+}
+
+// listIndex converts a 0-based index, counting from the end when negative, to
+// a position in a list of n elements. ok is false when it is out of range.
+func listIndex(i int64, n int) (int, bool) {
+	if i < 0 {
+		i += int64(n)
+	}
+	if i < 0 || i >= int64(n) {
+		return 0, false
+	}
+	return int(i), true
+}
+
+// listBound clamps a Python-style slice bound to [0, n].
+func listBound(i int64, n int) int {
+	if i < 0 {
+		i += int64(n)
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > int64(n) {
+		return n
+	}
+	return int(i)
+}
+
+func typedListGetEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_get", args, 2, 2, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	if values[1].Type != table.TypeInt {
+		return table.Null(), fmt.Errorf("list_get() index must be an int, got %s", valueTypeName(values[1]))
+	}
+	i, ok := listIndex(values[1].Int, len(values[0].List))
+	if !ok {
+		return table.Null(), nil
+	}
+	return values[0].List[i], nil
+}
+
+func typedListSliceEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_slice", args, 2, 3, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	list := values[0].List
+	bounds := []int{0, len(list)}
+	for i, v := range values[1:] {
+		if v.Type != table.TypeInt {
+			return table.Null(), fmt.Errorf("list_slice() bounds must be ints, got %s", valueTypeName(v))
+		}
+		bounds[i] = listBound(v.Int, len(list))
+	}
+	if bounds[1] <= bounds[0] {
+		return table.ListVal([]table.Value{}), nil
+	}
+	return table.ListVal(append([]table.Value(nil), list[bounds[0]:bounds[1]]...)), nil
+}
+
+// typedListSortEval sorts a copy of the list. The sort is stable and nulls go
+// last in either order.
+func typedListSortEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_sort", args, 1, 2, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	desc := false
+	if len(values) == 2 {
+		if desc, err = collectOrder("list_sort", values[1]); err != nil {
+			return table.Null(), err
+		}
+	}
+	out := append([]table.Value(nil), values[0].List...)
+	var cmpErr error
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].IsNull() || out[j].IsNull() {
+			return !out[i].IsNull()
+		}
+		cmp, _, err := expressionValuesCompare(out[i], out[j])
+		if err != nil && cmpErr == nil {
+			cmpErr = fmt.Errorf("list_sort: %s", err)
+		}
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	if cmpErr != nil {
+		return table.Null(), cmpErr
+	}
+	if out == nil {
+		out = []table.Value{}
+	}
+	return table.ListVal(out), nil
+}
+
+// typedListDistinctEval keeps the first of each structurally equal element.
+func typedListDistinctEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_distinct", args, 1, 1, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := make([]table.Value, 0, len(values[0].List))
+	seen := make(map[string]struct{}, len(values[0].List))
+	for _, v := range values[0].List {
+		key := table.CanonicalKey(v)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			out = append(out, v)
+		}
+	}
+	return table.ListVal(out), nil
+}
+
+func typedListConcatEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_concat", args, 2, -1, len(args), ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := []table.Value{}
+	for _, v := range values {
+		out = append(out, v.List...)
+	}
+	return table.ListVal(out), nil
+}
+
+func typedListFlattenEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_flatten", args, 1, 1, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := []table.Value{}
+	for _, inner := range values[0].List {
+		if inner.IsNull() {
+			continue
+		}
+		if inner.Type != table.TypeList {
+			return table.Null(), fmt.Errorf("list_flatten() requires a list of lists, got element %s", valueTypeName(inner))
+		}
+		out = append(out, inner.List...)
+	}
+	return table.ListVal(out), nil
+}
+
+// typedListIntersectEval returns the distinct elements of the first list that
+// also appear in the second, in first-list order.
+func typedListIntersectEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_intersect", args, 2, 2, 2, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	// A list<int> may meet a list<float>, so when the element schemas differ
+	// both sides are keyed at their unified schema, where 2 and 2.0 agree.
+	var elem *table.TypeDescriptor
+	left, _ := listElemSchema("list_intersect", args[0].typ)
+	right, _ := listElemSchema("list_intersect", args[1].typ)
+	if !samePlanningSchema(left, right) {
+		if unified, err := unifyExpressionStrict(left, right); err == nil {
+			elem = finalizePlanningSchema(table.WithNullable(unified))
+		}
+	}
+	keep := make(map[string]struct{}, len(values[1].List))
+	for _, v := range values[1].List {
+		keep[listElemKey(v, elem)] = struct{}{}
+	}
+	out := []table.Value{}
+	seen := make(map[string]struct{}, len(values[0].List))
+	for _, v := range values[0].List {
+		key := listElemKey(v, elem)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := keep[key]; ok {
+			out = append(out, v)
+		}
+	}
+	return table.ListVal(out), nil
+}
+
+// listElemKey returns the CanonicalKey of v, converted to elem first when elem
+// is not nil.
+func listElemKey(v table.Value, elem *table.TypeDescriptor) string {
+	if elem != nil {
+		if coerced, err := table.CoerceValueToFinalSchemaMode(v, elem, table.CoerceCoerciveMode); err == nil {
+			v = coerced
+		}
+	}
+	return table.CanonicalKey(v)
+}
+
+// typedListJoinEval joins the strings of a list, skipping null elements like
+// string_agg() does.
+func typedListJoinEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := evalListCallArgs("list_join", args, 2, 2, 1, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	if values[1].Type != table.TypeString {
+		return table.Null(), fmt.Errorf("list_join() separator must be a string, got %s", valueTypeName(values[1]))
+	}
+	parts := make([]string, 0, len(values[0].List))
+	for _, v := range values[0].List {
+		if v.IsNull() {
+			continue
+		}
+		if v.Type != table.TypeString {
+			return table.Null(), fmt.Errorf("list_join() requires a list of strings, got element %s", valueTypeName(v))
+		}
+		parts = append(parts, v.Str)
+	}
+	return table.StrVal(strings.Join(parts, values[1].Str)), nil
+}
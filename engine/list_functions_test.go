@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+const ordersJSON = `[
+	{"id": 1, "orders": [{"amount": 50, "sku": "a"}, {"amount": 150.5, "sku": "b"}], "tags": ["x", "yy"]},
+	{"id": 2, "orders": [], "tags": null},
+	{"id": 3, "orders": [{"amount": 200, "sku": "c"}], "tags": ["zzz", null]}
+]`
+
+func TestHigherOrderListFunctions(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	result := loadAndQuery(t, path, `transform
+		big = list_filter(orders, o -> o.amount > 100),
+		skus = list_map(orders, o -> upper(o.sku)),
+		any_big = list_any(orders, o -> o.amount > 100),
+		all_big = list_all(orders, o -> o.amount > 100),
+		total = list_reduce(orders, 0, (acc, o) -> acc + o.amount),
+		joined = list_reduce(tags, "", (acc, t) -> acc || coalesce(t, "?"))
+	| select big, skus, any_big, all_big, total, joined`)
+	assertColumnStrings(t, result, "big", "[{amount:150.5, sku:b}]", "[]", "[{amount:200, sku:c}]")
+	assertColumnStrings(t, result, "skus", "[A, B]", "[]", "[C]")
+	assertColumnStrings(t, result, "any_big", "true", "false", "true")
+	assertColumnStrings(t, result, "all_big", "false", "true", "true")
+	assertColumnStrings(t, result, "total", "200.5", "0", "200")
+	assertColumnStrings(t, result, "joined", "xyy", "null", "zzz?")
+	assertColumnSchema(t, result, "big", "list<record<amount:float, sku:string>>")
+	assertColumnSchema(t, result, "skus", "list<string>")
+	assertColumnSchema(t, result, "any_big", "bool")
+	assertColumnSchema(t, result, "total", "float")
+	assertColumnSchema(t, result, "joined", "string?")
+}
+
+func TestLambdaSeesOuterColumnsAndNestedLambdas(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	result := loadAndQuery(t, path, `transform
+		offset = list_map(orders, o -> o.amount + id),
+		nested = list_map(orders, o -> list_len(list_filter(tags, t -> str_len(t) > 1)) + o.amount),
+		id = list_map(orders, id -> id.sku)
+	| select offset, nested, id`)
+	assertColumnStrings(t, result, "offset", "[51, 151.5]", "[]", "[203]")
+	assertColumnStrings(t, result, "nested", "[51, 151.5]", "[]", "[201]")
+	assertColumnStrings(t, result, "id", "[a, b]", "[]", "[c]")
+}
+
+func TestListQuantifiersFollowThreeValuedLogic(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		any_null = list_any(list(1, null), x -> x > 1),
+		any_true = list_any(list(1, null, 2), x -> x > 1),
+		all_null = list_all(list(2, null), x -> x > 1),
+		all_false = list_all(list(2, null, 0), x -> x > 1),
+		all_empty = list_all(list(), x -> x),
+		filtered = list_filter(list(1, null, 3), x -> x > 1)
+	| select any_null, any_true, all_null, all_false, all_empty, filtered`)
+	assertColumnStrings(t, result, "any_null", "null", "null", "null")
+	assertColumnStrings(t, result, "any_true", "true", "true", "true")
+	assertColumnStrings(t, result, "all_null", "null", "null", "null")
+	assertColumnStrings(t, result, "all_false", "false", "false", "false")
+	assertColumnStrings(t, result, "all_empty", "true", "true", "true")
+	assertColumnStrings(t, result, "filtered", "[3]", "[3]", "[3]")
+	assertColumnSchema(t, result, "any_null", "bool?")
+}
+
+func TestListReduceWidensDecimalAccumulator(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "prices.csv", decimalCSV)
+	result := loadAndQuery(t, path+" with decimals=true", `group item
+		| transform total = list_reduce(grouped, 0, (acc, r) -> acc + coalesce(r.price, 0))
+		| sort item | select item, total`)
+	assertColumnStrings(t, result, "total", "10.625", "3.333", "0.000")
+	assertColumnSchema(t, result, "total", "decimal(38,3)")
+}
+
+func TestLambdaInsideAggregateArgument(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	result := loadAndQuery(t, path, `filter { list_any(orders, o -> o.amount > 100) }
+		| group id | reduce n = sum(list_len(list_filter(orders, o -> o.amount > 100)))
+		| select id, n`)
+	assertColumnStrings(t, result, "id", "1", "3")
+	assertColumnStrings(t, result, "n", "1", "1")
+}
+
+func TestLambdaErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform a = upper(o -> o)`, "a lambda can only be passed to a higher-order function"},
+		{`transform a = list_filter(orders, (a, b) -> true)`, "list_filter() lambda takes 1 parameter(s), got 2"},
+		{`transform a = list_filter(orders, o -> o.amount)`, "list_filter() lambda must return bool, got float"},
+		{`transform a = list_all(id, o -> true)`, "list_all() requires a list, got int"},
+		{`transform a = list_map(orders, 3)`, "list_map() argument 2 must be a lambda"},
+		{`transform a = list_map(orders)`, "list_map() takes 2 arguments (list, x -> value), got 1"},
+		{`transform a = list_map(orders, o -> o.nope)`, `list_map() lambda: field "nope" not found`},
+		{`transform a = list_map(tags, t -> o)`, `list_map() lambda: column "o" not found`},
+		{`transform a = list_reduce(tags, 0, (acc, t) -> t)`, "list_reduce() lambda returns string?, which does not match the initial value's int"},
+		{`transform a = list_reduce(tags, list(), (acc, t) -> list(acc))`, "list_reduce() lambda parameter types do not settle"},
+		{`group id | reduce n = sum(o -> o)`, "a lambda can only be passed to a higher-order function"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
+
+func TestListIndexingAndSlicing(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	result := loadAndQuery(t, path, `transform
+		first = tags[0],
+		last = tags[-1],
+		missing = list_get(tags, 5),
+		sku = list_map(orders, o -> o.sku)[0],
+		amount = orders[0].amount,
+		nested = list(list(1, 2), list(3))[0][1],
+		head = list_slice(orders, 0, 1),
+		tail = list_slice(list(1, 2, 3, 4), -2),
+		empty = list_slice(list(1, 2, 3), 2, 1)
+	| select first, last, missing, sku, amount, nested, head, tail, empty`)
+	assertColumnStrings(t, result, "first", "x", "null", "zzz")
+	assertColumnStrings(t, result, "last", "yy", "null", "null")
+	assertColumnStrings(t, result, "missing", "null", "null", "null")
+	assertColumnStrings(t, result, "sku", "a", "null", "c")
+	assertColumnStrings(t, result, "amount", "50", "null", "200")
+	assertColumnStrings(t, result, "nested", "2", "2", "2")
+	assertColumnStrings(t, result, "head", "[{amount:50, sku:a}]", "[]", "[{amount:200, sku:c}]")
+	assertColumnStrings(t, result, "tail", "[3, 4]", "[3, 4]", "[3, 4]")
+	assertColumnStrings(t, result, "empty", "[]", "[]", "[]")
+	assertColumnSchema(t, result, "first", "string?")
+	assertColumnSchema(t, result, "sku", "string?")
+	assertColumnSchema(t, result, "amount", "float?")
+	assertColumnSchema(t, result, "head", "list<record<amount:float, sku:string>>")
+	assertColumnSchema(t, result, "tail", "list<int>")
+}
+
+func TestListSortDistinctAndSetFunctions(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		asc = list_sort(list(3, null, 1.5, 2)),
+		desc = list_sort(list("b", null, "c", "a"), "desc"),
+		distinct = list_distinct(list(1, 1.0, 2, null, null, 2)),
+		records = list_distinct(list(struct(a = 1, b = list(1)), struct(b = list(1.0), a = 1))),
+		both = list_concat(list(1, 2), list(), list(2.5)),
+		flat = list_flatten(list(list(1), null, list(2, 3))),
+		common = list_intersect(list(1, 2, 2, 3), list(3.0, 2)),
+		joined = list_join(list("a", null, "b"), "-")
+	| select asc, desc, distinct, records, both, flat, common, joined`)
+	assertColumnStrings(t, result, "asc", "[1.5, 2, 3, null]", "[1.5, 2, 3, null]", "[1.5, 2, 3, null]")
+	assertColumnStrings(t, result, "desc", "[c, b, a, null]", "[c, b, a, null]", "[c, b, a, null]")
+	assertColumnStrings(t, result, "distinct", "[1, 2, null]", "[1, 2, null]", "[1, 2, null]")
+	assertColumnStrings(t, result, "records", "[{a:1, b:[1]}]", "[{a:1, b:[1]}]", "[{a:1, b:[1]}]")
+	assertColumnStrings(t, result, "both", "[1, 2, 2.5]", "[1, 2, 2.5]", "[1, 2, 2.5]")
+	assertColumnStrings(t, result, "flat", "[1, 2, 3]", "[1, 2, 3]", "[1, 2, 3]")
+	assertColumnStrings(t, result, "common", "[2, 3]", "[2, 3]", "[2, 3]")
+	assertColumnStrings(t, result, "joined", "a-b", "a-b", "a-b")
+	assertColumnSchema(t, result, "asc", "list<float?>")
+	assertColumnSchema(t, result, "both", "list<float>")
+	assertColumnSchema(t, result, "flat", "list<int>")
+	assertColumnSchema(t, result, "common", "list<int>")
+	assertColumnSchema(t, result, "joined", "string")
+}
+
+func TestListFunctionsPropagateNullLists(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	result := loadAndQuery(t, path, `transform
+		sorted = list_sort(tags),
+		both = list_concat(tags, list("w")),
+		joined = list_join(tags, ",")
+	| select sorted, both, joined`)
+	assertColumnStrings(t, result, "sorted", "[x, yy]", "null", "[zzz, null]")
+	assertColumnStrings(t, result, "both", "[x, yy, w]", "null", "[zzz, null, w]")
+	assertColumnStrings(t, result, "joined", "x,yy", "null", "zzz")
+	assertColumnSchema(t, result, "sorted", "list<string?>?")
+	assertColumnSchema(t, result, "both", "list<string?>?")
+	assertColumnSchema(t, result, "joined", "string?")
+}
+
+func TestListFunctionErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "orders.json", ordersJSON)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform a = id[0]`, "list_get() requires a list, got int"},
+		{`transform a = tags["x"]`, "list_get() index must be an int, got string"},
+		{`transform a = list_slice(tags)`, "list_slice() takes 2 to 3 arguments, got 1"},
+		{`transform a = list_slice(tags, 0, 1.5)`, "list_slice() bounds must be ints, got float"},
+		{`transform a = list_sort(orders)`, "list_sort() requires orderable elements"},
+		{`transform a = list_sort(tags, "up")`, `list_sort() order must be "asc" or "desc", got up`},
+		{`transform a = list_concat(tags)`, "list_concat() requires at least 2 arguments, got 1"},
+		{`transform a = list_concat(tags, list(1))`, "list_concat() element type mismatch"},
+		{`transform a = list_flatten(tags)`, "list_flatten() requires a list of lists"},
+		{`transform a = list_intersect(tags, list(1))`, "list_intersect() element type mismatch"},
+		{`transform a = orders[0].price`, `record_get() field "price" not found`},
+		{`transform a = tags[0].sku`, "record_get() requires a record, got string?"},
+		{`transform a = list_join(list(1), ",")`, "list_join() requires a list of strings, got list<int>"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
@@ -25,4 +25,7 @@ func collectLogicalTypedExprColumns(expr logicalTypedExpr, out map[string]bool)
 	for i := range expr.elements {
 		collectLogicalTypedExprColumns(expr.elements[i], out)
 	}
+	if expr.filter != nil {
+		collectLogicalTypedExprColumns(*expr.filter, out)
+	}
 }
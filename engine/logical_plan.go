@@ -109,6 +109,61 @@ type logicalSortKey struct {
 	desc bool
 }
 
+type logicalWindow struct {
+	logicalBase
+	partition   []logicalPathBinding
+	order       []logicalSortKey
+	assignments []logicalAssignment
+}
+
+type logicalExplode struct {
+	logicalBase
+	path       []string
+	elemName   string
+	elemSchema *table.TypeDescriptor
+	position   string
+	outer      bool
+}
+
+type logicalPivot struct {
+	logicalBase
+	groups  []logicalPathBinding
+	key     []string
+	keyType *table.TypeDescriptor // type the key column and listed values are compared in
+	columns []logicalPivotColumn
+	agg     logicalTypedExpr
+}
+
+type logicalPivotColumn struct {
+	name string
+	key  table.Value
+}
+
+type logicalUnpivot struct {
+	logicalBase
+	columns     []string
+	nameCol     string
+	valueCol    string
+	valueSchema *table.TypeDescriptor
+}
+
+type logicalUnion struct {
+	logicalBase
+	right         logicalJoinSource
+	filename      string
+	outputSources []logicalUnionOutputSource
+}
+
+// logicalUnionOutputSource names where one union output column comes from.
+// leftName or rightName is empty when that side has no matching column; tag
+// marks the column that receives the union filename for appended rows.
+type logicalUnionOutputSource struct {
+	name      string
+	leftName  string
+	rightName string
+	tag       bool
+}
+
 type logicalSelect struct {
 	logicalBase
 	projections  []logicalPathBinding
@@ -145,9 +200,31 @@ type logicalJoin struct {
 	right         logicalJoinSource
 	leftKeys      []logicalPathBinding
 	rightKeys     []logicalPathBinding
+	leftExprKeys  []logicalTypedExpr
+	rightExprKeys []logicalTypedExpr
+	condition     *logicalJoinCondition
+	asof          *logicalJoinAsof
 	outputSources []logicalJoinOutputSource
 }
 
+// logicalJoinAsof is the match of an asof join: each left row takes the last
+// right row (in right order) with right <= left, within tolerance when it is
+// not null.
+type logicalJoinAsof struct {
+	left      logicalPathBinding
+	right     logicalPathBinding
+	tolerance table.Value
+}
+
+// logicalJoinCondition is the residual part of an on condition, checked for
+// every pair of rows whose keys match. expr is planned against env, which
+// holds the columns it references under their inner join output names.
+type logicalJoinCondition struct {
+	expr    logicalTypedExpr
+	env     schemaEnv
+	sources []logicalJoinOutputSource
+}
+
 type logicalJoinSource struct {
 	source        PreparedJoinSource
 	env           schemaEnv
@@ -282,6 +359,16 @@ func planLogicalOp(input schemaEnv, op ast.Op, joinSources JoinSourceProvider) (
 		return logicalDescribe{logicalBase: logicalBaseFromEnv(describeOutputEnv())}, nil
 	case *ast.JoinOp:
 		return planLogicalJoin(o, input, joinSources)
+	case *ast.WindowOp:
+		return planLogicalWindow(o, input)
+	case *ast.ExplodeOp:
+		return planLogicalExplode(o, input)
+	case *ast.PivotOp:
+		return planLogicalPivot(o, input)
+	case *ast.UnpivotOp:
+		return planLogicalUnpivot(o, input)
+	case *ast.UnionOp:
+		return planLogicalUnion(o, input, joinSources)
 	default:
 		return nil, fmt.Errorf("unknown logical operation type %T", op)
 	}
@@ -396,27 +483,437 @@ func planLogicalReduce(o *ast.ReduceOp, input schemaEnv) (logicalReduce, error)
 }
 
 func planLogicalSortKeys(o *ast.SortOp, input schemaEnv) ([]logicalSortKey, error) {
-	keys := make([]logicalSortKey, len(o.Keys))
-	for i, k := range o.Keys {
+	return planLogicalOrderKeys("sort", o.Keys, input)
+}
+
+func planLogicalOrderKeys(opName string, sortKeys []ast.SortKey, input schemaEnv) ([]logicalSortKey, error) {
+	keys := make([]logicalSortKey, len(sortKeys))
+	for i, k := range sortKeys {
 		bound, err := bindColumnPathLogicalInEnv(input, k.Path)
 		if err != nil {
-			return nil, fmt.Errorf("sort %q: %w", strings.Join(k.Path, "."), err)
+			return nil, fmt.Errorf("%s %q: %w", opName, strings.Join(k.Path, "."), err)
 		}
-		if err := validatePathDoesNotTraverseUnionInEnv("sort", input, k.Path); err != nil {
+		if err := validatePathDoesNotTraverseUnionInEnv(opName, input, k.Path); err != nil {
 			return nil, err
 		}
 		schema := finalizePlanningSchema(bound.typ)
 		if table.SchemaContainsUnion(schema) {
-			return nil, fmt.Errorf("sort %q: union values are not orderable", strings.Join(k.Path, "."))
+			return nil, fmt.Errorf("%s %q: union values are not orderable", opName, strings.Join(k.Path, "."))
 		}
 		if !table.IsOrderable(schema) {
-			return nil, fmt.Errorf("sort %q: %s values are not orderable", strings.Join(k.Path, "."), table.TypeName(schema.Kind))
+			return nil, fmt.Errorf("%s %q: %s values are not orderable", opName, strings.Join(k.Path, "."), table.TypeName(schema.Kind))
 		}
 		keys[i] = logicalSortKey{path: clonePath(k.Path), desc: k.Desc}
 	}
 	return keys, nil
 }
 
+func planLogicalWindow(o *ast.WindowOp, input schemaEnv) (logicalWindow, error) {
+	partition := make([]logicalPathBinding, len(o.PartitionBy))
+	for i, path := range o.PartitionBy {
+		bound, err := bindColumnPathLogicalInEnv(input, path)
+		if err != nil {
+			return logicalWindow{}, fmt.Errorf("window by %q: %w", strings.Join(path, "."), err)
+		}
+		if err := validatePathDoesNotTraverseUnionInEnv("window by", input, path); err != nil {
+			return logicalWindow{}, err
+		}
+		partition[i] = logicalPathBinding{name: pathToColumnName(path), path: clonePath(path), schema: bound.typ}
+	}
+	order, err := planLogicalOrderKeys("window order", o.OrderBy, input)
+	if err != nil {
+		return logicalWindow{}, err
+	}
+
+	columns := input.cloneColumns()
+	assignments := make([]logicalAssignment, len(o.Assignments))
+	seenTargets := make(map[string]bool, len(o.Assignments))
+	for _, a := range o.Assignments {
+		if seenTargets[a.Column] {
+			return logicalWindow{}, fmt.Errorf("window target %q assigned more than once", a.Column)
+		}
+		seenTargets[a.Column] = true
+	}
+
+	for i, a := range o.Assignments {
+		var target schemaEnvColumnRef
+		columns, target = upsertEnvColumn(columns, a.Column)
+		planned, err := planLogicalWindowExpr(a.Expr, input)
+		if err != nil {
+			return logicalWindow{}, fmt.Errorf("window %q: %w", a.Column, err)
+		}
+		assignments[i] = logicalAssignment{name: a.Column, expr: planned}
+		columns[target.index].raw = finalizePlanningSchema(planned.typ)
+	}
+
+	return logicalWindow{
+		logicalBase: logicalBaseFromEnv(schemaEnvFromKnownUniqueColumns(columns)),
+		partition:   partition,
+		order:       order,
+		assignments: assignments,
+	}, nil
+}
+
+func planLogicalExplode(o *ast.ExplodeOp, input schemaEnv) (logicalExplode, error) {
+	name := strings.Join(o.Column, ".")
+	bound, err := bindColumnPathLogicalInEnv(input, o.Column)
+	if err != nil {
+		return logicalExplode{}, fmt.Errorf("explode %q: %w", name, err)
+	}
+	if err := validatePathDoesNotTraverseUnionInEnv("explode", input, o.Column); err != nil {
+		return logicalExplode{}, err
+	}
+	elem, err := explodeElementSchema(name, bound.typ)
+	if err != nil {
+		return logicalExplode{}, err
+	}
+	if o.Outer {
+		elem = table.WithNullable(elem)
+	}
+
+	replacesSource := len(o.Column) == 1
+	elemName := o.As
+	switch {
+	case elemName == "" && replacesSource:
+		elemName = o.Column[0]
+	case elemName == "":
+		elemName = uniqueColumnName(pathToColumnName(o.Column), input.columnNames())
+	default:
+		if _, exists := input.lookupColumn(elemName); exists && !(replacesSource && elemName == o.Column[0]) {
+			return logicalExplode{}, fmt.Errorf("explode: element column %q already exists", elemName)
+		}
+	}
+	if o.Position != "" {
+		_, exists := input.lookupColumn(o.Position)
+		if o.Position == elemName || (exists && !(replacesSource && o.Position == o.Column[0])) {
+			return logicalExplode{}, fmt.Errorf("explode: position column %q already exists", o.Position)
+		}
+	}
+
+	op := logicalExplode{
+		path:       clonePath(o.Column),
+		elemName:   elemName,
+		elemSchema: elem,
+		position:   o.Position,
+		outer:      o.Outer,
+	}
+	op.logicalBase = logicalBaseFromEnv(explodeOutputEnv(input, op))
+	return op, nil
+}
+
+// explodeOutputEnv replaces a top-level list column with its element column in
+// place, or appends the element column for nested paths. The position column,
+// when requested, is appended last.
+func explodeOutputEnv(input schemaEnv, op logicalExplode) schemaEnv {
+	columns := input.cloneColumns()
+	if ref, ok := findEnvColumn(columns, op.path[0]); ok && len(op.path) == 1 {
+		columns[ref.index] = schemaEnvColumn{name: op.elemName, raw: op.elemSchema}
+	} else {
+		columns = append(columns, schemaEnvColumn{name: op.elemName, raw: op.elemSchema})
+	}
+	if op.position != "" {
+		columns = append(columns, schemaEnvColumn{name: op.position, raw: &table.TypeDescriptor{Kind: table.TypeInt, Nullable: op.outer}})
+	}
+	return schemaEnvFromKnownUniqueColumns(columns)
+}
+
+func planLogicalPivot(o *ast.PivotOp, input schemaEnv) (logicalPivot, error) {
+	keyName := strings.Join(o.Key, ".")
+	bound, err := bindColumnPathLogicalInEnv(input, o.Key)
+	if err != nil {
+		return logicalPivot{}, fmt.Errorf("pivot %q: %w", keyName, err)
+	}
+	if err := validatePathDoesNotTraverseUnionInEnv("pivot", input, o.Key); err != nil {
+		return logicalPivot{}, err
+	}
+	keySchema := finalizePlanningSchema(bound.typ)
+
+	aggName := o.Agg
+	if aggName == "" {
+		aggName = "first"
+	}
+	spec, ok := builtinCatalog[aggName]
+	if !ok {
+		return logicalPivot{}, fmt.Errorf("pivot: unknown aggregate function %q", aggName)
+	}
+	if spec.Category != builtinAggregate || spec.Aggregate == nil {
+		return logicalPivot{}, fmt.Errorf("pivot: %q is not an aggregate function", aggName)
+	}
+	var args []ast.Expr
+	if o.Values != nil {
+		args = []ast.Expr{&ast.ColumnExpr{Path: clonePath(o.Values)}}
+	}
+	if err := spec.Aggregate.checkArity(aggName, len(args)); err != nil {
+		switch {
+		case spec.Aggregate.MaxArity == 0:
+			return logicalPivot{}, fmt.Errorf("pivot: %s() takes no values column", aggName)
+		case len(args) == 0:
+			return logicalPivot{}, fmt.Errorf("pivot: %s() needs a values column; add values <column>", aggName)
+		default:
+			return logicalPivot{}, fmt.Errorf("pivot: %w", err)
+		}
+	}
+	agg, err := planLogicalReduceExpr(&ast.FuncCallExpr{Name: aggName, Args: args}, recordSchemaForEnv(input))
+	if err != nil {
+		return logicalPivot{}, fmt.Errorf("pivot: %w", err)
+	}
+
+	var groups []logicalPathBinding
+	names := make([]string, 0, len(input.columns)+len(o.In))
+	for _, col := range input.columns {
+		if col.name == o.Key[0] || (o.Values != nil && col.name == o.Values[0]) {
+			continue
+		}
+		groups = append(groups, logicalPathBinding{name: col.name, path: []string{col.name}, schema: col.planningSchema()})
+		names = append(names, col.name)
+	}
+
+	// Listed values may be any literal that unifies with the key column, so
+	// an int list works against a float column; both sides are compared in
+	// the unified type.
+	keyType := table.WithoutNull(keySchema)
+	for _, lit := range o.In {
+		unified, err := unifyExpressionStrict(keyType, literalType(lit))
+		if lit.Kind == "null" || err != nil || unified.Kind == table.TypeUnion {
+			return logicalPivot{}, fmt.Errorf("pivot: %s value %q does not match key column %q of type %s", lit.Kind, evalLiteral(lit).AsString(), keyName, table.Render(keySchema))
+		}
+		keyType = unified
+	}
+	keyType = finalizePlanningSchema(keyType)
+
+	columns := make([]logicalPivotColumn, len(o.In))
+	seen := make(map[string]string, len(o.In))
+	for i, lit := range o.In {
+		name := evalLiteral(lit).AsString()
+		if lit.Kind == "float" && lit.Text != "" {
+			name = lit.Text // keep the spelling, so 2.0 names column "2.0"
+		}
+		value, err := table.CoerceValueToFinalSchemaMode(evalLiteral(lit), keyType, table.CoerceCoerciveMode)
+		if err != nil {
+			return logicalPivot{}, fmt.Errorf("pivot: value %q: %w", name, err)
+		}
+		if prev, ok := seen[table.CanonicalKey(value)]; ok {
+			if prev == name {
+				return logicalPivot{}, fmt.Errorf("pivot: value %q listed more than once", name)
+			}
+			return logicalPivot{}, fmt.Errorf("pivot: values %q and %q are the same key", prev, name)
+		}
+		seen[table.CanonicalKey(value)] = name
+		if containsColumnName(names, name) {
+			return logicalPivot{}, fmt.Errorf("pivot: output column %q already exists", name)
+		}
+		names = append(names, name)
+		columns[i] = logicalPivotColumn{name: name, key: value}
+	}
+
+	envColumns := make([]schemaEnvColumn, 0, len(names))
+	for _, group := range groups {
+		envColumns = append(envColumns, schemaEnvColumn{name: group.name, raw: group.schema})
+	}
+	for _, col := range columns {
+		envColumns = append(envColumns, schemaEnvColumn{name: col.name, raw: finalizePlanningSchema(agg.typ)})
+	}
+	return logicalPivot{
+		logicalBase: logicalBaseFromEnv(schemaEnvFromKnownUniqueColumns(envColumns)),
+		groups:      groups,
+		key:         clonePath(o.Key),
+		keyType:     keyType,
+		columns:     columns,
+		agg:         agg,
+	}, nil
+}
+
+func planLogicalUnpivot(o *ast.UnpivotOp, input schemaEnv) (logicalUnpivot, error) {
+	var valueSchema *table.TypeDescriptor
+	for i, name := range o.Columns {
+		if containsColumnName(o.Columns[:i], name) {
+			return logicalUnpivot{}, fmt.Errorf("unpivot: column %q listed more than once", name)
+		}
+		col, ok := input.lookupColumn(name)
+		if !ok {
+			return logicalUnpivot{}, fmt.Errorf("unpivot %q: %w", name, columnNotFoundError(name, input.columnNames()))
+		}
+		schema := col.column.planningSchema()
+		if valueSchema == nil {
+			valueSchema = schema
+			continue
+		}
+		unified, err := unifyExpressionStrict(valueSchema, schema)
+		if err != nil {
+			return logicalUnpivot{}, fmt.Errorf("unpivot: column %q has type %s, which does not match %s", name, schemaString(schema), schemaString(valueSchema))
+		}
+		valueSchema = unified
+	}
+	if o.NameCol == o.ValueCol {
+		return logicalUnpivot{}, fmt.Errorf("unpivot: name and value columns must differ, got %q for both", o.NameCol)
+	}
+	for _, col := range input.columns {
+		if containsColumnName(o.Columns, col.name) {
+			continue
+		}
+		if col.name == o.NameCol || col.name == o.ValueCol {
+			return logicalUnpivot{}, fmt.Errorf("unpivot: output column %q already exists", col.name)
+		}
+	}
+
+	op := logicalUnpivot{
+		columns:     append([]string(nil), o.Columns...),
+		nameCol:     o.NameCol,
+		valueCol:    o.ValueCol,
+		valueSchema: finalizePlanningSchema(valueSchema),
+	}
+	op.logicalBase = logicalBaseFromEnv(unpivotOutputEnv(input, op))
+	return op, nil
+}
+
+// unpivotOutputEnv keeps the columns that are not unpivoted, in input order,
+// followed by the name and value columns.
+func unpivotOutputEnv(input schemaEnv, op logicalUnpivot) schemaEnv {
+	columns := make([]schemaEnvColumn, 0, len(input.columns)+2)
+	for _, col := range input.columns {
+		if !containsColumnName(op.columns, col.name) {
+			columns = append(columns, col)
+		}
+	}
+	columns = append(columns,
+		schemaEnvColumn{name: op.nameCol, raw: &table.TypeDescriptor{Kind: table.TypeString}},
+		schemaEnvColumn{name: op.valueCol, raw: op.valueSchema},
+	)
+	return schemaEnvFromKnownUniqueColumns(columns)
+}
+
+func planLogicalUnion(o *ast.UnionOp, left schemaEnv, joinSources JoinSourceProvider) (logicalUnion, error) {
+	if joinSources == nil {
+		return logicalUnion{}, fmt.Errorf("union: source preparer not configured")
+	}
+	if o.Filename == "-" {
+		return logicalUnion{}, fmt.Errorf("union: stdin is not supported as union source")
+	}
+
+	rightSource, err := joinSources.PrepareJoinSource(o.Filename, o.Load)
+	if err != nil {
+		return logicalUnion{}, fmt.Errorf("union: load %q: %w", o.Filename, err)
+	}
+	rightEnv, err := schemaEnvFromSchema(rightSource.planningSchema())
+	if err != nil {
+		return logicalUnion{}, fmt.Errorf("union: schema of %q: %w", o.Filename, err)
+	}
+	if o.Tag != "" {
+		if _, ok := rightEnv.lookupColumn(o.Tag); ok {
+			return logicalUnion{}, fmt.Errorf("union: tag column %q already exists in %q", o.Tag, o.Filename)
+		}
+	}
+
+	var sources []logicalUnionOutputSource
+	if o.ByPosition {
+		sources, err = unionOutputSourcesByPosition(left, rightEnv, o)
+	} else {
+		sources = unionOutputSourcesByName(left, rightEnv, o.Tag)
+	}
+	if err != nil {
+		return logicalUnion{}, err
+	}
+
+	columns := make([]schemaEnvColumn, len(sources))
+	for i, source := range sources {
+		columns[i] = schemaEnvColumn{name: source.name, raw: unionOutputSchema(left, rightEnv, source)}
+	}
+	return logicalUnion{
+		logicalBase:   logicalBaseFromEnv(schemaEnvFromKnownUniqueColumns(columns)),
+		right:         logicalJoinSource{source: rightSource, env: rightEnv},
+		filename:      o.Filename,
+		outputSources: sources,
+	}, nil
+}
+
+// unionOutputSourcesByName keeps the pipeline columns in order and appends
+// columns that only the other source has, in that source's order.
+func unionOutputSourcesByName(left, right schemaEnv, tag string) []logicalUnionOutputSource {
+	sources := make([]logicalUnionOutputSource, 0, len(left.columns)+len(right.columns)+1)
+	for _, col := range left.columns {
+		source := logicalUnionOutputSource{name: col.name, leftName: col.name}
+		if col.name == tag {
+			source.tag = true
+		} else if _, ok := right.lookupColumn(col.name); ok {
+			source.rightName = col.name
+		}
+		sources = append(sources, source)
+	}
+	for _, col := range right.columns {
+		if _, ok := left.lookupColumn(col.name); !ok {
+			sources = append(sources, logicalUnionOutputSource{name: col.name, rightName: col.name})
+		}
+	}
+	if tag != "" {
+		if _, ok := left.lookupColumn(tag); !ok {
+			sources = append(sources, logicalUnionOutputSource{name: tag, tag: true})
+		}
+	}
+	return sources
+}
+
+// unionOutputSourcesByPosition pairs columns by position and keeps the
+// pipeline's column names. An existing tag column does not take part in the
+// pairing.
+func unionOutputSourcesByPosition(left, right schemaEnv, o *ast.UnionOp) ([]logicalUnionOutputSource, error) {
+	paired := 0
+	for _, col := range left.columns {
+		if col.name != o.Tag {
+			paired++
+		}
+	}
+	if paired != len(right.columns) {
+		return nil, fmt.Errorf("union by position: pipeline has %d columns but %q has %d", paired, o.Filename, len(right.columns))
+	}
+	sources := make([]logicalUnionOutputSource, 0, len(left.columns)+1)
+	next := 0
+	for _, col := range left.columns {
+		source := logicalUnionOutputSource{name: col.name, leftName: col.name}
+		if col.name == o.Tag {
+			source.tag = true
+		} else {
+			source.rightName = right.columns[next].name
+			next++
+		}
+		sources = append(sources, source)
+	}
+	if o.Tag != "" {
+		if _, ok := left.lookupColumn(o.Tag); !ok {
+			sources = append(sources, logicalUnionOutputSource{name: o.Tag, tag: true})
+		}
+	}
+	return sources, nil
+}
+
+// unionOutputSchema unifies the schemas of both sides of one output column.
+// Types that strict unification rejects become a union of both; a column that
+// one side lacks becomes nullable.
+func unionOutputSchema(left, right schemaEnv, source logicalUnionOutputSource) *table.TypeDescriptor {
+	var leftSchema, rightSchema *table.TypeDescriptor
+	if source.leftName != "" {
+		col, _ := left.lookupColumn(source.leftName)
+		leftSchema = col.column.planningSchema()
+	}
+	switch {
+	case source.tag:
+		rightSchema = &table.TypeDescriptor{Kind: table.TypeString}
+	case source.rightName != "":
+		col, _ := right.lookupColumn(source.rightName)
+		rightSchema = col.column.planningSchema()
+	}
+	if leftSchema == nil {
+		return finalizePlanningSchema(table.WithNullable(rightSchema))
+	}
+	if rightSchema == nil {
+		return finalizePlanningSchema(table.WithNullable(leftSchema))
+	}
+	unified, err := unifyExpressionStrict(leftSchema, rightSchema)
+	if err != nil {
+		unified = table.UnionOf([]*table.TypeDescriptor{leftSchema, rightSchema}, false)
+	}
+	return finalizePlanningSchema(unified)
+}
+
 func planLogicalProjections(opName string, paths [][]string, env schemaEnv) ([]logicalPathBinding, bool, error) {
 	projections := make([]logicalPathBinding, len(paths))
 	cols := make([]string, 0, len(paths))
@@ -484,8 +981,32 @@ func planLogicalJoin(o *ast.JoinOp, left schemaEnv, joinSources JoinSourceProvid
 	if err != nil {
 		return logicalJoin{}, err
 	}
+	leftExprKeys, rightExprKeys, residual, err := splitLogicalJoinCondition(o.Condition, left, rightEnv)
+	if err != nil {
+		return logicalJoin{}, err
+	}
+	condition, err := planLogicalJoinCondition(residual, left, rightEnv, leftKeys, rightKeys, o.Filename)
+	if err != nil {
+		return logicalJoin{}, err
+	}
+	var asof *logicalJoinAsof
+	if o.Kind == "asof" {
+		if condition != nil || len(leftExprKeys) > 0 {
+			return logicalJoin{}, fmt.Errorf("join: asof join keys must be column equalities")
+		}
+		asof, err = planLogicalJoinAsof(o, left, rightEnv)
+		if err != nil {
+			return logicalJoin{}, err
+		}
+	}
 
-	outCols, outputSources := buildLogicalJoinLayout(left.columnNames(), rightEnv.columnNames(), leftKeys, rightKeys, o.Filename)
+	var outCols []string
+	var outputSources []logicalJoinOutputSource
+	if joinKeepsLeftOnly(o.Kind) {
+		outCols, outputSources = buildLogicalLeftOnlyJoinLayout(left.columnNames())
+	} else {
+		outCols, outputSources = buildLogicalJoinLayout(left.columnNames(), rightEnv.columnNames(), leftKeys, rightKeys, o.Filename)
+	}
 	outSchemas, err := buildLogicalJoinOutputSchemas(left, rightEnv, leftKeys, rightKeys, outputSources, o.Kind)
 	if err != nil {
 		return logicalJoin{}, err
@@ -502,10 +1023,198 @@ func planLogicalJoin(o *ast.JoinOp, left schemaEnv, joinSources JoinSourceProvid
 		right:         logicalJoinSource{source: rightSource, env: rightEnv},
 		leftKeys:      leftKeys,
 		rightKeys:     rightKeys,
+		leftExprKeys:  leftExprKeys,
+		rightExprKeys: rightExprKeys,
+		condition:     condition,
+		asof:          asof,
 		outputSources: outputSources,
 	}, nil
 }
 
+func planLogicalJoinAsof(o *ast.JoinOp, left, right schemaEnv) (*logicalJoinAsof, error) {
+	if o.Match == nil {
+		return nil, fmt.Errorf("join: asof join needs a match column")
+	}
+	lm, err := resolveLogicalJoinKeySide(o.Match.Left, left, "left")
+	if err != nil {
+		return nil, fmt.Errorf("join: asof match: %w", err)
+	}
+	rm, err := resolveLogicalJoinKeySide(o.Match.Right, right, "right")
+	if err != nil {
+		return nil, fmt.Errorf("join: asof match: %w", err)
+	}
+	leftLabel := strings.Join(lm.path, ".")
+	rightLabel := strings.Join(rm.path, ".")
+	if !schemaOrderableOrNull(lm.schema) || schemaContainsMixed(lm.schema) {
+		return nil, fmt.Errorf("join: asof match column %s must be orderable, got %s", leftLabel, schemaString(lm.schema))
+	}
+	if !table.EquivalentSchema(joinKeyComparableSchema(lm.schema), joinKeyComparableSchema(rm.schema)) {
+		return nil, fmt.Errorf("join: asof match type mismatch for %s and %s: %s vs %s", leftLabel, rightLabel, schemaString(lm.schema), schemaString(rm.schema))
+	}
+
+	tolerance := table.Null()
+	if o.Tolerance != nil {
+		tolerance, err = asofToleranceValue(o.Tolerance, lm.schema, leftLabel)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logicalJoinAsof{left: lm, right: rm, tolerance: tolerance}, nil
+}
+
+// asofToleranceValue evaluates an asof tolerance against the match column's
+// type: a non-negative number for numeric columns, and a non-negative
+// duration("...") for date, timestamp and duration columns.
+func asofToleranceValue(expr ast.Expr, match *table.TypeDescriptor, label string) (table.Value, error) {
+	if schemaKindOrNull(match, table.TypeDate) || schemaKindOrNull(match, table.TypeTimestamp) || schemaKindOrNull(match, table.TypeDuration) {
+		call, ok := expr.(*ast.FuncCallExpr)
+		if !ok || call.Name != "duration" {
+			return table.Null(), fmt.Errorf("join: asof tolerance for %s match column %s must be a duration such as duration(\"5m\")", schemaString(match), label)
+		}
+		var lit *ast.LiteralExpr
+		if len(call.Args) == 1 {
+			lit, _ = call.Args[0].(*ast.LiteralExpr)
+		}
+		if lit == nil || lit.Kind != "string" {
+			return table.Null(), fmt.Errorf("join: asof tolerance duration() takes one string literal")
+		}
+		d, ok := table.ParseDuration(lit.Str)
+		if !ok || d.Int < 0 {
+			return table.Null(), fmt.Errorf("join: asof tolerance must be a non-negative duration, got %q", lit.Str)
+		}
+		return d, nil
+	}
+	if !schemaNumericOrNull(match) {
+		return table.Null(), fmt.Errorf("join: asof tolerance needs a numeric or temporal match column, got %s for %s", schemaString(match), label)
+	}
+	lit, ok := expr.(*ast.LiteralExpr)
+	if !ok {
+		return table.Null(), fmt.Errorf("join: asof tolerance for %s match column %s must be a number", schemaString(match), label)
+	}
+	tolerance := evalLiteral(lit)
+	if f, ok := tolerance.AsFloat(); !ok || f < 0 {
+		return table.Null(), fmt.Errorf("join: asof tolerance must be a non-negative number")
+	}
+	return tolerance, nil
+}
+
+// splitLogicalJoinCondition finds the hashable terms among the non-key parts
+// of an on condition: an equality whose left side plans against the left
+// table and whose right side plans against the right table, such as
+// lower(email) == lower(mail). The remaining terms are returned as residual.
+func splitLogicalJoinCondition(cond ast.Expr, left, right schemaEnv) ([]logicalTypedExpr, []logicalTypedExpr, []ast.Expr, error) {
+	if cond == nil {
+		return nil, nil, nil, nil
+	}
+	var leftKeys, rightKeys []logicalTypedExpr
+	var residual []ast.Expr
+	for _, term := range joinConditionTerms(cond) {
+		b, ok := term.(*ast.BinaryExpr)
+		if !ok || b.Op != "==" {
+			residual = append(residual, term)
+			continue
+		}
+		lk, lerr := planLogicalTransformExprInEnv(b.Left, left)
+		rk, rerr := planLogicalTransformExprInEnv(b.Right, right)
+		if lerr != nil || rerr != nil {
+			residual = append(residual, term)
+			continue
+		}
+		lk, rk, err := unifyJoinExprKeys(lk, rk)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		leftKeys = append(leftKeys, lk)
+		rightKeys = append(rightKeys, rk)
+	}
+	return leftKeys, rightKeys, residual, nil
+}
+
+func joinConditionTerms(expr ast.Expr) []ast.Expr {
+	if b, ok := expr.(*ast.BinaryExpr); ok && b.Op == "and" {
+		return append(joinConditionTerms(b.Left), joinConditionTerms(b.Right)...)
+	}
+	return []ast.Expr{expr}
+}
+
+// unifyJoinExprKeys makes both sides of an expression equi-key produce values
+// of one type, so that hashing them by CanonicalKey matches equal values:
+// an int expression against a float one is coerced to float on both sides.
+// Keys whose types do not unify are rejected.
+func unifyJoinExprKeys(left, right logicalTypedExpr) (logicalTypedExpr, logicalTypedExpr, error) {
+	leftLabel := logicalExpressionLabel(left.bound)
+	rightLabel := logicalExpressionLabel(right.bound)
+	if schemaContainsMixed(left.typ) || schemaContainsMixed(right.typ) {
+		return left, right, fmt.Errorf("join: key type mismatch for %s and %s: mixed key schemas are not supported", leftLabel, rightLabel)
+	}
+	leftKey := joinKeyComparableSchema(left.typ)
+	rightKey := joinKeyComparableSchema(right.typ)
+	if table.EquivalentSchema(leftKey, rightKey) {
+		return left, right, nil
+	}
+	unified, err := unifyExpressionStrict(leftKey, rightKey)
+	if err != nil || unified.Kind == table.TypeUnion {
+		return left, right, fmt.Errorf("join: key type mismatch for %s and %s: %s vs %s", leftLabel, rightLabel, schemaString(left.typ), schemaString(right.typ))
+	}
+	return coerceJoinExprKey(left, unified), coerceJoinExprKey(right, unified), nil
+}
+
+func coerceJoinExprKey(key logicalTypedExpr, target *table.TypeDescriptor) logicalTypedExpr {
+	if key.typ != nil && key.typ.Nullable {
+		target = table.WithNullable(target)
+	}
+	if !runtimeCoercionNeeded(key.typ, target) {
+		return key
+	}
+	return coerceLogicalTypedExpression(key, target)
+}
+
+// planLogicalJoinCondition plans the residual terms against the inner join
+// output columns, so they name columns the way the joined table does
+// (a right column that clashes with a left one gets the file prefix).
+func planLogicalJoinCondition(terms []ast.Expr, left, right schemaEnv, leftKeys, rightKeys []logicalPathBinding, filename string) (*logicalJoinCondition, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	cond := terms[0]
+	for _, term := range terms[1:] {
+		cond = &ast.BinaryExpr{Op: "and", Left: cond, Right: term}
+	}
+
+	names, sources := buildLogicalJoinLayout(left.columnNames(), right.columnNames(), leftKeys, rightKeys, filename)
+	schemas, err := buildLogicalJoinOutputSchemas(left, right, leftKeys, rightKeys, sources, "inner")
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]schemaEnvColumn, len(names))
+	for i := range names {
+		columns[i] = schemaEnvColumn{name: names[i], raw: schemas[i]}
+	}
+	expr, err := planLogicalTransformExprInEnv(cond, schemaEnvFromKnownUniqueColumns(columns))
+	if err != nil {
+		return nil, fmt.Errorf("join: on condition: %w", err)
+	}
+	if !schemaBoolOrNull(expr.typ) {
+		return nil, fmt.Errorf("join: on condition must return bool, got %s from %s", schemaString(expr.typ), logicalExpressionLabel(expr.bound))
+	}
+
+	referenced := make(map[string]bool)
+	collectLogicalTypedExprColumns(expr, referenced)
+	used := make([]schemaEnvColumn, 0, len(referenced))
+	usedSources := make([]logicalJoinOutputSource, 0, len(referenced))
+	for i, col := range columns {
+		if referenced[col.name] {
+			used = append(used, col)
+			usedSources = append(usedSources, sources[i])
+		}
+	}
+	return &logicalJoinCondition{
+		expr:    expr,
+		env:     schemaEnvFromKnownUniqueColumns(used),
+		sources: usedSources,
+	}, nil
+}
+
 func resolveLogicalJoinKeys(keys []ast.JoinKey, left, right schemaEnv) ([]logicalPathBinding, []logicalPathBinding, error) {
 	leftKeys := make([]logicalPathBinding, len(keys))
 	rightKeys := make([]logicalPathBinding, len(keys))
@@ -609,6 +1318,26 @@ func buildLogicalJoinLayout(leftCols, rightCols []string, leftKeys, rightKeys []
 	return outCols, outputSources
 }
 
+// joinKeepsLeftOnly reports whether a join kind filters left rows instead of
+// combining them with right rows.
+func joinKeepsLeftOnly(kind string) bool {
+	return kind == "semi" || kind == "anti"
+}
+
+// buildLogicalLeftOnlyJoinLayout is the semi/anti join layout: the left
+// columns unchanged, with nothing from the right side.
+func buildLogicalLeftOnlyJoinLayout(leftCols []string) ([]string, []logicalJoinOutputSource) {
+	outputSources := make([]logicalJoinOutputSource, len(leftCols))
+	for i, col := range leftCols {
+		outputSources[i] = logicalJoinOutputSource{
+			kind:     logicalJoinOutputLeft,
+			name:     col,
+			leftName: col,
+		}
+	}
+	return append([]string(nil), leftCols...), outputSources
+}
+
 func buildLogicalJoinOutputSchemas(left, right schemaEnv, leftKeys, rightKeys []logicalPathBinding, sources []logicalJoinOutputSource, joinKind string) ([]*table.TypeDescriptor, error) {
 	schemas := make([]*table.TypeDescriptor, len(sources))
 	keySchemas, err := logicalJoinKeyOutputSchemas(leftKeys, rightKeys)
@@ -639,7 +1368,7 @@ func buildLogicalJoinOutputSchemas(left, right schemaEnv, leftKeys, rightKeys []
 				return nil, fmt.Errorf("join: right output column %q not found", source.rightName)
 			}
 			schema = rightCol.column.raw
-			if joinKind == "left" || joinKind == "full" {
+			if joinKind == "left" || joinKind == "full" || joinKind == "asof" {
 				schema = table.WithNullable(schema)
 			}
 		default:
@@ -907,6 +1636,16 @@ func planPhysicalOp(input schemaEnv, op logicalOp) (plannedOp, error) {
 		return plannedDescribe{plannedBase: plannedBaseFromEnv(o.OutputEnv())}, nil
 	case logicalJoin:
 		return planPhysicalJoin(input, o)
+	case logicalWindow:
+		return planPhysicalWindow(input, o)
+	case logicalExplode:
+		return planPhysicalExplode(input, o)
+	case logicalPivot:
+		return planPhysicalPivot(input, o)
+	case logicalUnpivot:
+		return planPhysicalUnpivot(input, o)
+	case logicalUnion:
+		return planPhysicalUnion(input, o)
 	default:
 		return nil, fmt.Errorf("unknown optimized logical operation type %T", op)
 	}
@@ -1039,6 +1778,191 @@ func planPhysicalSort(input schemaEnv, o logicalSort) (plannedSort, error) {
 	return plannedSort{plannedBase: plannedBaseFromEnv(o.OutputEnv()), keys: keys}, nil
 }
 
+func planPhysicalWindow(input schemaEnv, o logicalWindow) (plannedWindow, error) {
+	output := o.OutputEnv()
+	partition := make([]boundColumn, len(o.partition))
+	for i, key := range o.partition {
+		bound, err := bindColumnPathInEnv(input, key.path)
+		if err != nil {
+			return plannedWindow{}, fmt.Errorf("window by %q: %w", strings.Join(key.path, "."), err)
+		}
+		partition[i] = *bound
+	}
+	order := make([]plannedSortKey, len(o.order))
+	for i, key := range o.order {
+		bound, err := bindColumnPathInEnv(input, key.path)
+		if err != nil {
+			return plannedWindow{}, fmt.Errorf("window order %q: %w", strings.Join(key.path, "."), err)
+		}
+		order[i] = plannedSortKey{column: *bound, desc: key.desc}
+	}
+	assignments := make([]plannedWindowAssignment, len(o.assignments))
+	for i, assignment := range o.assignments {
+		target, ok := output.lookupColumn(assignment.name)
+		if !ok {
+			return plannedWindow{}, fmt.Errorf("window: target %q missing from output schema", assignment.name)
+		}
+		expr, err := physicalizeTypedExpr(assignment.expr, input)
+		if err != nil {
+			return plannedWindow{}, fmt.Errorf("window %q: %w", assignment.name, err)
+		}
+		call, ok := expr.bound.(*boundCall)
+		if !ok {
+			return plannedWindow{}, fmt.Errorf("window %q: expected a window function call", assignment.name)
+		}
+		spec := builtinCatalog[call.raw.Name]
+		if spec.Window == nil {
+			return plannedWindow{}, fmt.Errorf("window %q: %q is not a window function", assignment.name, call.raw.Name)
+		}
+		assignments[i] = plannedWindowAssignment{name: assignment.name, target: target.index, window: spec.Window, args: expr.args}
+	}
+	return plannedWindow{
+		plannedBase: plannedBaseFromEnv(output),
+		partition:   partition,
+		order:       order,
+		assignments: assignments,
+	}, nil
+}
+
+func planPhysicalExplode(input schemaEnv, o logicalExplode) (plannedExplode, error) {
+	output := o.OutputEnv()
+	bound, err := bindColumnPathInEnv(input, o.path)
+	if err != nil {
+		return plannedExplode{}, fmt.Errorf("explode %q: %w", strings.Join(o.path, "."), err)
+	}
+	elem, ok := output.lookupColumn(o.elemName)
+	if !ok {
+		return plannedExplode{}, fmt.Errorf("explode: element column %q missing from output schema", o.elemName)
+	}
+	position := -1
+	if o.position != "" {
+		ref, ok := output.lookupColumn(o.position)
+		if !ok {
+			return plannedExplode{}, fmt.Errorf("explode: position column %q missing from output schema", o.position)
+		}
+		position = ref.index
+	}
+	return plannedExplode{
+		plannedBase: plannedBaseFromEnv(output),
+		list:        *bound,
+		elemTarget:  elem.index,
+		posTarget:   position,
+		outer:       o.outer,
+	}, nil
+}
+
+func planPhysicalPivot(input schemaEnv, o logicalPivot) (plannedPivot, error) {
+	groups := make([]boundColumn, len(o.groups))
+	for i, group := range o.groups {
+		bound, err := bindColumnPathInEnv(input, group.path)
+		if err != nil {
+			return plannedPivot{}, fmt.Errorf("pivot: group column %q: %w", group.name, err)
+		}
+		groups[i] = *bound
+	}
+	key, err := bindColumnPathInEnv(input, o.key)
+	if err != nil {
+		return plannedPivot{}, fmt.Errorf("pivot %q: %w", strings.Join(o.key, "."), err)
+	}
+	columns := make(map[string]int, len(o.columns))
+	for i, col := range o.columns {
+		columns[table.CanonicalKey(col.key)] = i
+	}
+	var keyType *table.TypeDescriptor
+	if runtimeCoercionNeeded(key.typ, o.keyType) {
+		keyType = o.keyType
+	}
+	expr, err := physicalizeTypedExpr(o.agg, input)
+	if err != nil {
+		return plannedPivot{}, fmt.Errorf("pivot: %w", err)
+	}
+	var slots []plannedAggregateSlot
+	agg, err := compileAggregateFinalExpr(expr, &slots)
+	if err != nil {
+		return plannedPivot{}, fmt.Errorf("pivot: %w", err)
+	}
+	return plannedPivot{
+		plannedBase: plannedBaseFromEnv(o.OutputEnv()),
+		groups:      groups,
+		key:         *key,
+		keyType:     keyType,
+		columns:     columns,
+		width:       len(o.columns),
+		agg:         agg,
+		slots:       slots,
+	}, nil
+}
+
+func planPhysicalUnpivot(input schemaEnv, o logicalUnpivot) (plannedUnpivot, error) {
+	output := o.OutputEnv()
+	kept := make([]int, 0, len(input.columns))
+	for i, col := range input.columns {
+		if !containsColumnName(o.columns, col.name) {
+			kept = append(kept, i)
+		}
+	}
+	sources := make([]int, len(o.columns))
+	for i, name := range o.columns {
+		col, ok := input.lookupColumn(name)
+		if !ok {
+			return plannedUnpivot{}, fmt.Errorf("unpivot: column %q not found", name)
+		}
+		sources[i] = col.index
+	}
+	return plannedUnpivot{
+		plannedBase: plannedBaseFromEnv(output),
+		kept:        kept,
+		sources:     sources,
+		names:       append([]string(nil), o.columns...),
+	}, nil
+}
+
+func planPhysicalUnion(input schemaEnv, o logicalUnion) (plannedUnion, error) {
+	rightEnv, ok := logicalJoinRightOutputEnv(o.right)
+	if !ok {
+		return plannedUnion{}, fmt.Errorf("union: cannot derive schema of %q", o.filename)
+	}
+	output := o.OutputEnv()
+	if len(o.outputSources) != len(output.columns) {
+		return plannedUnion{}, fmt.Errorf("union: output source count %d does not match schema column count %d", len(o.outputSources), len(output.columns))
+	}
+	left := make([]int, len(o.outputSources))
+	right := make([]int, len(o.outputSources))
+	tag := -1
+	for i, source := range o.outputSources {
+		left[i], right[i] = -1, -1
+		if source.leftName != "" {
+			col, ok := input.lookupColumn(source.leftName)
+			if !ok {
+				return plannedUnion{}, fmt.Errorf("union: column %q not found after optimization", source.leftName)
+			}
+			left[i] = col.index
+		}
+		if source.rightName != "" {
+			col, ok := rightEnv.lookupColumn(source.rightName)
+			if !ok {
+				return plannedUnion{}, fmt.Errorf("union: column %q of %q not found after optimization", source.rightName, o.filename)
+			}
+			right[i] = col.index
+		}
+		if source.tag {
+			tag = i
+		}
+	}
+	return plannedUnion{
+		plannedBase: plannedBaseFromEnv(output),
+		right: plannedJoinRightSource{
+			source: o.right.source,
+			spec:   JoinSourceLoadSpec{Columns: o.right.outputColumns},
+			env:    rightEnv,
+		},
+		filename: o.filename,
+		left:     left,
+		rightCol: right,
+		tag:      tag,
+	}, nil
+}
+
 func physicalProjectionPlan(input schemaEnv, projections []logicalPathBinding, topLevelOnly bool) (*projectionPlan, error) {
 	plan := &projectionPlan{
 		cols:        make([]string, len(projections)),
@@ -1084,6 +2008,18 @@ func planPhysicalJoin(input schemaEnv, o logicalJoin) (plannedJoin, error) {
 	if err != nil {
 		return plannedJoin{}, err
 	}
+	leftExprKeys, rightExprKeys, err := physicalJoinExprKeys(input, rightEnv, o.leftExprKeys, o.rightExprKeys)
+	if err != nil {
+		return plannedJoin{}, err
+	}
+	condition, err := physicalJoinCondition(input, rightEnv, o)
+	if err != nil {
+		return plannedJoin{}, err
+	}
+	asof, err := physicalJoinAsof(input, rightEnv, o.asof)
+	if err != nil {
+		return plannedJoin{}, err
+	}
 	outputs, err := physicalJoinOutputs(input, rightEnv, o)
 	if err != nil {
 		return plannedJoin{}, err
@@ -1096,9 +2032,13 @@ func planPhysicalJoin(input schemaEnv, o logicalJoin) (plannedJoin, error) {
 			spec:   JoinSourceLoadSpec{Columns: o.right.outputColumns},
 			env:    rightEnv,
 		},
-		leftKeys:  leftKeys,
-		rightKeys: rightKeys,
-		outputs:   outputs,
+		leftKeys:      leftKeys,
+		rightKeys:     rightKeys,
+		leftExprKeys:  leftExprKeys,
+		rightExprKeys: rightExprKeys,
+		condition:     condition,
+		asof:          asof,
+		outputs:       outputs,
 	}, nil
 }
 
@@ -1132,31 +2072,87 @@ func physicalJoinOutputs(left, right schemaEnv, o logicalJoin) ([]plannedJoinOut
 		if output.columns[i].name != source.name {
 			return nil, fmt.Errorf("join: output source %d changed from %q to %q", i, output.columns[i].name, source.name)
 		}
-		switch source.kind {
-		case logicalJoinOutputLeft:
-			leftCol, ok := left.lookupColumn(source.leftName)
-			if !ok {
-				return nil, fmt.Errorf("join: left output column %q not found after optimization", source.leftName)
-			}
-			outputs[i] = plannedJoinOutput{kind: plannedJoinOutputLeft, leftIndex: leftCol.index}
-		case logicalJoinOutputKey:
-			if source.keyIndex < 0 || source.keyIndex >= len(o.leftKeys) {
-				return nil, fmt.Errorf("join: key output %q has invalid key index %d", source.name, source.keyIndex)
-			}
-			outputs[i] = plannedJoinOutput{kind: plannedJoinOutputKey, keyIndex: source.keyIndex}
-		case logicalJoinOutputRight:
-			rightCol, ok := right.lookupColumn(source.rightName)
-			if !ok {
-				return nil, fmt.Errorf("join: right output column %q not found", source.rightName)
-			}
-			outputs[i] = plannedJoinOutput{kind: plannedJoinOutputRight, rightIndex: rightCol.index}
-		default:
-			return nil, fmt.Errorf("join: output %q has unknown source kind", source.name)
+		planned, err := physicalJoinOutput(left, right, source, len(o.leftKeys))
+		if err != nil {
+			return nil, err
 		}
+		outputs[i] = planned
 	}
 	return outputs, nil
 }
 
+func physicalJoinOutput(left, right schemaEnv, source logicalJoinOutputSource, keyCount int) (plannedJoinOutput, error) {
+	switch source.kind {
+	case logicalJoinOutputLeft:
+		leftCol, ok := left.lookupColumn(source.leftName)
+		if !ok {
+			return plannedJoinOutput{}, fmt.Errorf("join: left output column %q not found after optimization", source.leftName)
+		}
+		return plannedJoinOutput{kind: plannedJoinOutputLeft, leftIndex: leftCol.index}, nil
+	case logicalJoinOutputKey:
+		if source.keyIndex < 0 || source.keyIndex >= keyCount {
+			return plannedJoinOutput{}, fmt.Errorf("join: key output %q has invalid key index %d", source.name, source.keyIndex)
+		}
+		return plannedJoinOutput{kind: plannedJoinOutputKey, keyIndex: source.keyIndex}, nil
+	case logicalJoinOutputRight:
+		rightCol, ok := right.lookupColumn(source.rightName)
+		if !ok {
+			return plannedJoinOutput{}, fmt.Errorf("join: right output column %q not found", source.rightName)
+		}
+		return plannedJoinOutput{kind: plannedJoinOutputRight, rightIndex: rightCol.index}, nil
+	default:
+		return plannedJoinOutput{}, fmt.Errorf("join: output %q has unknown source kind", source.name)
+	}
+}
+
+func physicalJoinExprKeys(left, right schemaEnv, leftKeys, rightKeys []logicalTypedExpr) ([]typedExpr, []typedExpr, error) {
+	physicalLeft := make([]typedExpr, len(leftKeys))
+	physicalRight := make([]typedExpr, len(rightKeys))
+	for i := range leftKeys {
+		lk, err := physicalizeTypedExpr(leftKeys[i], left)
+		if err != nil {
+			return nil, nil, fmt.Errorf("join: left key %s: %w", logicalExpressionLabel(leftKeys[i].bound), err)
+		}
+		rk, err := physicalizeTypedExpr(rightKeys[i], right)
+		if err != nil {
+			return nil, nil, fmt.Errorf("join: right key %s: %w", logicalExpressionLabel(rightKeys[i].bound), err)
+		}
+		physicalLeft[i] = lk
+		physicalRight[i] = rk
+	}
+	return physicalLeft, physicalRight, nil
+}
+
+func physicalJoinAsof(left, right schemaEnv, asof *logicalJoinAsof) (*plannedJoinAsof, error) {
+	if asof == nil {
+		return nil, nil
+	}
+	leftKeys, rightKeys, err := physicalizeJoinKeys(left, right, []logicalPathBinding{asof.left}, []logicalPathBinding{asof.right})
+	if err != nil {
+		return nil, err
+	}
+	return &plannedJoinAsof{left: leftKeys[0].column, right: rightKeys[0].column, tolerance: asof.tolerance}, nil
+}
+
+func physicalJoinCondition(left, right schemaEnv, o logicalJoin) (*plannedJoinCondition, error) {
+	if o.condition == nil {
+		return nil, nil
+	}
+	expr, err := physicalizeTypedExpr(o.condition.expr, o.condition.env)
+	if err != nil {
+		return nil, fmt.Errorf("join: on condition: %w", err)
+	}
+	sources := make([]plannedJoinOutput, len(o.condition.sources))
+	for i, source := range o.condition.sources {
+		planned, err := physicalJoinOutput(left, right, source, len(o.leftKeys))
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = planned
+	}
+	return &plannedJoinCondition{expr: expr, env: o.condition.env, sources: sources}, nil
+}
+
 func physicalizeJoinKeys(left, right schemaEnv, leftKeys, rightKeys []logicalPathBinding) ([]resolvedJoinKey, []resolvedJoinKey, error) {
 	physicalLeft := make([]resolvedJoinKey, len(leftKeys))
 	physicalRight := make([]resolvedJoinKey, len(rightKeys))
@@ -1236,13 +2232,21 @@ func physicalizeTypedExpr(expr logicalTypedExpr, env schemaEnv) (typedExpr, erro
 		if !ok {
 			return typedExpr{}, fmt.Errorf("unknown function %q during physical planning", b.raw.Name)
 		}
-		return typedExpr{
+		out := typedExpr{
 			bound:    &boundCall{raw: b.raw, args: boundArgs},
 			raw:      expr.raw,
 			typ:      expr.typ,
 			callEval: spec.TypedEval,
 			args:     args,
-		}, nil
+		}
+		if expr.filter != nil {
+			filter, err := physicalizeTypedExprPtr(expr.filter, env)
+			if err != nil {
+				return typedExpr{}, fmt.Errorf("%s() where: %w", b.raw.Name, err)
+			}
+			out.filter = filter
+		}
+		return out, nil
 	case *logicalBoundStruct:
 		fields := make([]typedStructField, len(expr.fields))
 		boundFields := make([]boundStructField, len(expr.fields))
@@ -1300,6 +2304,28 @@ func physicalizeTypedExpr(expr logicalTypedExpr, env schemaEnv) (typedExpr, erro
 			operand:  operand,
 			coerceTo: expr.coerceTo,
 		}, nil
+	case *logicalBoundLambda:
+		body, err := physicalizeTypedExprPtr(expr.operand, env)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return typedExpr{
+			bound:   &boundLambda{raw: b.raw, slot: b.slot},
+			raw:     expr.raw,
+			typ:     expr.typ,
+			operand: body,
+		}, nil
+	case *logicalBoundLambdaParam:
+		return typedExpr{
+			bound: &boundLambdaParam{
+				rawPath:    clonePath(b.rawPath),
+				slot:       b.slot,
+				nestedPath: clonePath(b.rawPath[1:]),
+				typ:        b.typ,
+			},
+			raw: expr.raw,
+			typ: expr.typ,
+		}, nil
 	default:
 		return typedExpr{}, fmt.Errorf("unknown logical typed expression binding %T", expr.bound)
 	}
@@ -1344,7 +2370,7 @@ func sameNormalizedPlanningSchema(a, b *table.TypeDescriptor) bool {
 				return false
 			}
 		}
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		return sameNormalizedPlanningSchema(a.Elem, b.Elem)
 	case table.TypeUnion:
 		if len(a.Branches) != len(b.Branches) {
@@ -0,0 +1,357 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// mapValueSchema returns the value schema of a map argument. A null map has
+// null values.
+func mapValueSchema(name string, typ *table.TypeDescriptor) (*table.TypeDescriptor, error) {
+	if !schemaKindOrNull(typ, table.TypeMap) {
+		return nil, fmt.Errorf("%s() requires a map, got %s", name, schemaString(typ))
+	}
+	m := normalizePlanningSchema(typ)
+	if m.Kind != table.TypeMap || m.Elem == nil {
+		return &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}, nil
+	}
+	return m.Elem, nil
+}
+
+// recordFieldsSchema returns the field schemas of a record argument, or none
+// for a null literal.
+func recordFieldsSchema(name string, typ *table.TypeDescriptor) ([]table.FieldDescriptor, error) {
+	if !schemaKindOrNull(typ, table.TypeRecord) {
+		return nil, fmt.Errorf("%s() requires a record, got %s", name, schemaString(typ))
+	}
+	rec := finalizePlanningSchema(normalizePlanningSchema(typ))
+	if rec.Kind != table.TypeRecord {
+		return nil, nil
+	}
+	return rec.Fields, nil
+}
+
+func stringListSchema(nullable bool) *table.TypeDescriptor {
+	return &table.TypeDescriptor{Kind: table.TypeList, Elem: &table.TypeDescriptor{Kind: table.TypeString}, Nullable: nullable}
+}
+
+func checkMapKeysSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("map_keys() takes 1 argument, got %d", len(args))
+	}
+	if _, err := mapValueSchema("map_keys", args[0].typ); err != nil {
+		return nil, err
+	}
+	return stringListSchema(anySchemaMayBeNull(args[0].typ)), nil
+}
+
+func checkMapValuesSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("map_values() takes 1 argument, got %d", len(args))
+	}
+	elem, err := mapValueSchema("map_values", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	return &table.TypeDescriptor{Kind: table.TypeList, Elem: elem, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+func checkMapGetSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("map_get() takes 2 arguments (map, key), got %d", len(args))
+	}
+	elem, err := mapValueSchema("map_get", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaKindOrNull(args[1].typ, table.TypeString) {
+		return nil, fmt.Errorf("map_get() key must be a string, got %s", schemaString(args[1].typ))
+	}
+	return table.WithNullable(elem), nil
+}
+
+// checkMapEntriesSignature types map_entries(), which lists a map as
+// record<key:string, value:T> entries in key order.
+func checkMapEntriesSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("map_entries() takes 1 argument, got %d", len(args))
+	}
+	elem, err := mapValueSchema("map_entries", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	entry := &table.TypeDescriptor{Kind: table.TypeRecord, Fields: []table.FieldDescriptor{
+		{Name: "key", Type: &table.TypeDescriptor{Kind: table.TypeString}},
+		{Name: "value", Type: elem},
+	}}
+	return &table.TypeDescriptor{Kind: table.TypeList, Elem: entry, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+// checkRecordMergeSignature types record_merge(). A field in a later record
+// replaces the field of the same name, type included, in an earlier one.
+func checkRecordMergeSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("record_merge() requires at least 2 arguments, got %d", len(args))
+	}
+	index := map[string]int{}
+	var fields []table.FieldDescriptor
+	for _, arg := range args {
+		argFields, err := recordFieldsSchema("record_merge", arg.typ)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range argFields {
+			if i, ok := index[field.Name]; ok {
+				fields[i].Type = field.Type
+				continue
+			}
+			index[field.Name] = len(fields)
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	typs := make([]*table.TypeDescriptor, len(args))
+	for i, arg := range args {
+		typs[i] = arg.typ
+	}
+	return &table.TypeDescriptor{Kind: table.TypeRecord, Fields: fields, Nullable: anySchemaMayBeNull(typs...)}, nil
+}
+
+// checkRecordDropSignature types record_drop(). The fields to drop are string
+// literals naming fields of the record, so a typo fails before any rows are
+// read.
+func checkRecordDropSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("record_drop() requires at least 2 arguments, got %d", len(args))
+	}
+	fields, err := recordFieldsSchema("record_drop", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	drop, err := recordDropNames(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]table.FieldDescriptor, 0, len(fields))
+	for _, field := range fields {
+		if !drop[field.Name] {
+			kept = append(kept, field)
+		}
+	}
+	if args[0].typ.Kind == table.TypeRecord {
+		for name := range drop {
+			if _, ok := schemaField(fields, name); !ok {
+				return nil, fmt.Errorf("record_drop() field %q not found in %s", name, schemaString(args[0].typ))
+			}
+		}
+	}
+	return &table.TypeDescriptor{Kind: table.TypeRecord, Fields: kept, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+func recordDropNames(args []typedExpr) (map[string]bool, error) {
+	names := make(map[string]bool, len(args))
+	for _, arg := range args {
+		lit, ok := arg.raw.(*ast.LiteralExpr)
+		if !ok || lit.Kind != "string" {
+			return nil, fmt.Errorf("record_drop() field names must be string literals")
+		}
+		names[lit.Str] = true
+	}
+	return names, nil
+}
+
+func schemaField(fields []table.FieldDescriptor, name string) (table.FieldDescriptor, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return table.FieldDescriptor{}, false
+}
+
+// checkRecordGetSignature types record_get(), which reads one field named by
+// a string literal; r.field after an index or call is sugar for it.
+func checkRecordGetSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("record_get() takes 2 arguments (record, field), got %d", len(args))
+	}
+	fields, err := recordFieldsSchema("record_get", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	name, err := recordGetName(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if normalizePlanningSchema(args[0].typ).Kind != table.TypeRecord {
+		return &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}, nil
+	}
+	field, ok := schemaField(fields, name)
+	if !ok {
+		return nil, fmt.Errorf("record_get() field %q not found in %s", name, schemaString(args[0].typ))
+	}
+	if anySchemaMayBeNull(args[0].typ) {
+		return table.WithNullable(field.Type), nil
+	}
+	return field.Type, nil
+}
+
+func recordGetName(arg typedExpr) (string, error) {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok || lit.Kind != "string" {
+		return "", fmt.Errorf("record_get() field name must be a string literal")
+	}
+	return lit.Str, nil
+}
+
+func checkRecordKeysSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("record_keys() takes 1 argument, got %d", len(args))
+	}
+	if _, err := recordFieldsSchema("record_keys", args[0].typ); err != nil {
+		return nil, err
+	}
+	return stringListSchema(anySchemaMayBeNull(args[0].typ)), nil
+}
+
+// evalNestedCallArg evaluates the map or record argument of a builtin. ok is
+// false when it is null, which makes the call null.
+func evalNestedCallArg(name string, arg typedExpr, kind table.ValueType, ctx *EvalContext) (table.Value, bool, error) {
+	v, err := evalTypedExpression(arg, ctx)
+	if err != nil || v.IsNull() {
+		return table.Null(), false, err
+	}
+	if v.Type != kind {
+		return table.Null(), false, fmt.Errorf("%s() requires a %s, got %s", name, table.TypeName(kind), valueTypeName(v))
+	}
+	return v, true, nil
+}
+
+func typedMapKeysEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	m, ok, err := evalNestedCallArg("map_keys", args[0], table.TypeMap, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	return fieldNameList(m.Fields), nil
+}
+
+func typedMapValuesEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	m, ok, err := evalNestedCallArg("map_values", args[0], table.TypeMap, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := make([]table.Value, len(m.Fields))
+	for i, entry := range m.Fields {
+		out[i] = entry.Value
+	}
+	return table.ListVal(out), nil
+}
+
+func typedMapGetEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	m, ok, err := evalNestedCallArg("map_get", args[0], table.TypeMap, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	key, err := evalTypedExpression(args[1], ctx)
+	if err != nil || key.IsNull() {
+		return table.Null(), err
+	}
+	if key.Type != table.TypeString {
+		return table.Null(), fmt.Errorf("map_get() key must be a string, got %s", valueTypeName(key))
+	}
+	i := sort.Search(len(m.Fields), func(i int) bool { return m.Fields[i].Name >= key.Str })
+	if i < len(m.Fields) && m.Fields[i].Name == key.Str {
+		return m.Fields[i].Value, nil
+	}
+	return table.Null(), nil
+}
+
+func typedMapEntriesEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	m, ok, err := evalNestedCallArg("map_entries", args[0], table.TypeMap, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	out := make([]table.Value, len(m.Fields))
+	for i, entry := range m.Fields {
+		out[i] = table.RecordVal([]table.RecordField{
+			{Name: "key", Value: table.StrVal(entry.Name)},
+			{Name: "value", Value: entry.Value},
+		})
+	}
+	return table.ListVal(out), nil
+}
+
+func typedRecordMergeEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	index := map[string]int{}
+	var fields []table.RecordField
+	for _, arg := range args {
+		rec, ok, err := evalNestedCallArg("record_merge", arg, table.TypeRecord, ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		for _, field := range rec.Fields {
+			if i, ok := index[field.Name]; ok {
+				fields[i].Value = field.Value
+				continue
+			}
+			index[field.Name] = len(fields)
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return table.RecordVal(fields), nil
+}
+
+func typedRecordDropEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	rec, ok, err := evalNestedCallArg("record_drop", args[0], table.TypeRecord, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	drop, err := recordDropNames(args[1:])
+	if err != nil {
+		return table.Null(), err
+	}
+	kept := make([]table.RecordField, 0, len(rec.Fields))
+	for _, field := range rec.Fields {
+		if !drop[field.Name] {
+			kept = append(kept, field)
+		}
+	}
+	return table.RecordVal(kept), nil
+}
+
+func typedRecordGetEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	rec, ok, err := evalNestedCallArg("record_get", args[0], table.TypeRecord, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	name, err := recordGetName(args[1])
+	if err != nil {
+		return table.Null(), err
+	}
+	for _, field := range rec.Fields {
+		if field.Name == name {
+			return field.Value, nil
+		}
+	}
+	return table.Null(), nil
+}
+
+func typedRecordKeysEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	rec, ok, err := evalNestedCallArg("record_keys", args[0], table.TypeRecord, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	return fieldNameList(rec.Fields), nil
+}
+
+func fieldNameList(fields []table.RecordField) table.Value {
+	out := make([]table.Value, len(fields))
+	for i, field := range fields {
+		out[i] = table.StrVal(field.Name)
+	}
+	return table.ListVal(out)
+}
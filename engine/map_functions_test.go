@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+const countsJSONL = `{"id": 1, "counts": {"2024-02": 5, "2024-01": 3}, "meta": {"a": 1, "b": "x"}}
+{"id": 2, "counts": {}, "meta": {"a": 2, "b": null}}
+{"id": 3, "counts": null, "meta": null}
+`
+
+func TestMapFunctions(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "counts.jsonl", countsJSONL)
+	result := loadAndQuery(t, path+` with maps="counts"`, `transform
+		keys = map_keys(counts),
+		vals = map_values(counts),
+		jan = map_get(counts, "2024-01"),
+		entries = map_entries(counts)
+	| select counts, keys, vals, jan, entries`)
+	assertColumnStrings(t, result, "counts", "{2024-01:3, 2024-02:5}", "{}", "null")
+	assertColumnStrings(t, result, "keys", "[2024-01, 2024-02]", "[]", "null")
+	assertColumnStrings(t, result, "vals", "[3, 5]", "[]", "null")
+	assertColumnStrings(t, result, "jan", "3", "null", "null")
+	assertColumnStrings(t, result, "entries", "[{key:2024-01, value:3}, {key:2024-02, value:5}]", "[]", "null")
+	assertColumnSchema(t, result, "counts", "map<string,int>?")
+	assertColumnSchema(t, result, "keys", "list<string>?")
+	assertColumnSchema(t, result, "vals", "list<int>?")
+	assertColumnSchema(t, result, "jan", "int?")
+	assertColumnSchema(t, result, "entries", "list<record<key:string, value:int>>?")
+}
+
+func TestRecordHelpers(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "counts.jsonl", countsJSONL)
+	result := loadAndQuery(t, path, `transform
+		merged = record_merge(meta, struct(b = id, c = true)),
+		dropped = record_drop(meta, "b"),
+		keys = record_keys(meta)
+	| select merged, dropped, keys`)
+	assertColumnStrings(t, result, "merged", "{a:1, b:1, c:true}", "{a:2, b:2, c:true}", "null")
+	assertColumnStrings(t, result, "dropped", "{a:1}", "{a:2}", "null")
+	assertColumnStrings(t, result, "keys", "[a, b]", "[a, b]", "null")
+	assertColumnSchema(t, result, "merged", "record<a:int, b:int, c:bool>?")
+	assertColumnSchema(t, result, "dropped", "record<a:int>?")
+	assertColumnSchema(t, result, "keys", "list<string>?")
+}
+
+func TestMapValuesGroupAndCompareByContent(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "counts.jsonl", `{"m": {"a": 1, "b": 2}}
+{"m": {"b": 2, "a": 1.0}}
+{"m": {"a": 1}}
+`)
+	result := loadAndQuery(t, path+` with maps="m"`, `group m | reduce n = count() | remove grouped | sort n`)
+	assertColumnStrings(t, result, "n", "1", "2")
+}
+
+func TestMapAndRecordFunctionErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "counts.jsonl", countsJSONL)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform a = map_keys(meta)`, "map_keys() requires a map, got record<a:int, b:string?>?"},
+		{`transform a = map_get(counts, 1)`, "map_get() key must be a string, got int"},
+		{`transform a = map_values(counts, counts)`, "map_values() takes 1 argument, got 2"},
+		{`transform a = counts.x`, "map_get()"},
+		{`sort counts`, "map values are not orderable"},
+		{`transform a = record_keys(counts)`, "record_keys() requires a record, got map<string,int>?"},
+		{`transform a = record_merge(meta)`, "record_merge() requires at least 2 arguments, got 1"},
+		{`transform a = record_drop(meta, "c")`, `record_drop() field "c" not found in record<a:int, b:string?>?`},
+		{`transform a = record_drop(meta, id)`, "record_drop() field names must be string literals"},
+	}
+	for _, tc := range cases {
+		err := loadAndQueryExpectErr(t, path+` with maps="counts"`, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
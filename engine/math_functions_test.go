@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+const mathCSV = "a,b,x\n" +
+	"7,3,10.25\n" +
+	"-7,2,-2.5\n" +
+	"5,0,\n"
+
+func TestModuloOperatorAndMod(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "math.csv", mathCSV)
+	result := loadAndQuery(t, path, "transform r = a % b, m = mod(a, b), f = x % 3, lit = a % 4 | select r, m, f, lit")
+	assertColumnStrings(t, result, "r", "1", "-1", "null")
+	assertColumnStrings(t, result, "m", "1", "-1", "null")
+	assertColumnStrings(t, result, "f", "1.25", "-2.5", "null")
+	assertColumnStrings(t, result, "lit", "3", "-3", "1")
+	assertColumnSchema(t, result, "r", "int?")
+	assertColumnSchema(t, result, "f", "float?")
+	assertColumnSchema(t, result, "lit", "int")
+
+	decimals := loadAndQuery(t, path+" with decimals=true", "transform r = x % 3 | select r")
+	assertColumnStrings(t, decimals, "r", "1.25", "-2.50", "null")
+	assertColumnSchema(t, decimals, "r", "decimal(4,2)?")
+
+	grouped := loadAndQuery(t, path, "group b | reduce odd = count() where { a % 2 != 0 } % 2 | remove grouped | sort b")
+	assertColumnStrings(t, grouped, "odd", "1", "1", "1")
+}
+
+func TestMathFunctions(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "math.csv", mathCSV)
+	result := loadAndQuery(t, path, `transform
+		abs_a = abs(a),
+		sign_a = sign(a),
+		floor_x = floor(x),
+		ceil_x = ceil(x),
+		round_x = round(x),
+		round_1 = round(x, 1),
+		round_tens = round(a * 13, -1),
+		root = sqrt(a),
+		ln = log(b),
+		e = exp(0),
+		sq = pow(a, 2),
+		hi = greatest(a, b, 4.5),
+		lo = least(a, b, x)
+	| select abs_a, sign_a, floor_x, ceil_x, round_x, round_1, round_tens, root, ln, e, sq, hi, lo`)
+	assertColumnStrings(t, result, "abs_a", "7", "7", "5")
+	assertColumnStrings(t, result, "sign_a", "1", "-1", "1")
+	assertColumnStrings(t, result, "floor_x", "10", "-3", "null")
+	assertColumnStrings(t, result, "ceil_x", "11", "-2", "null")
+	assertColumnStrings(t, result, "round_x", "10", "-3", "null")
+	assertColumnStrings(t, result, "round_1", "10.3", "-2.5", "null")
+	assertColumnStrings(t, result, "round_tens", "90", "-90", "70")
+	assertColumnStrings(t, result, "root", "2.6457513110645907", "null", "2.23606797749979")
+	assertColumnStrings(t, result, "ln", "1.0986122886681096", "0.6931471805599453", "null")
+	assertColumnStrings(t, result, "e", "1", "1", "1")
+	assertColumnStrings(t, result, "sq", "49", "49", "25")
+	assertColumnStrings(t, result, "hi", "7", "4.5", "5")
+	assertColumnStrings(t, result, "lo", "3", "-7", "0")
+	for name, want := range map[string]string{
+		"abs_a": "int", "round_tens": "int", "floor_x": "float?", "root": "float?",
+		"e": "float", "sq": "float?", "hi": "float", "lo": "float",
+	} {
+		assertColumnSchema(t, result, name, want)
+	}
+}
+
+func TestMathFunctionsOnDecimals(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "math.csv", mathCSV)
+	result := loadAndQuery(t, path+" with decimals=true", `transform
+		abs_x = abs(x),
+		sign_x = sign(x),
+		floor_x = floor(x),
+		ceil_x = ceil(x),
+		round_1 = round(x, 1),
+		round_tens = round(x, -1),
+		hi = greatest(x, 0)
+	| select abs_x, sign_x, floor_x, ceil_x, round_1, round_tens, hi`)
+	assertColumnStrings(t, result, "abs_x", "10.25", "2.50", "null")
+	assertColumnStrings(t, result, "sign_x", "1", "-1", "null")
+	assertColumnStrings(t, result, "floor_x", "10", "-3", "null")
+	assertColumnStrings(t, result, "ceil_x", "11", "-2", "null")
+	assertColumnStrings(t, result, "round_1", "10.3", "-2.5", "null")
+	assertColumnStrings(t, result, "round_tens", "10", "0", "null")
+	assertColumnStrings(t, result, "hi", "10.25", "0.00", "0.00")
+	assertColumnSchema(t, result, "abs_x", "decimal(4,2)?")
+	assertColumnSchema(t, result, "sign_x", "decimal(1,0)?")
+	assertColumnSchema(t, result, "floor_x", "decimal(3,0)?")
+	assertColumnSchema(t, result, "round_1", "decimal(4,1)?")
+}
+
+func TestRoundHalfAwayFromZeroWithoutDoubleRounding(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "r.csv", "x\n4.5\n-15.5\n0.05\n")
+	result := loadAndQuery(t, path+" with decimals=true", "transform tens = round(x, -1), ones = round(x) | select tens, ones")
+	assertColumnStrings(t, result, "tens", "0", "-20", "0")
+	assertColumnStrings(t, result, "ones", "5", "-16", "0")
+}
+
+func TestMathFunctionErrors(t *testing.T) {
+	input := table.NewTable([]string{"n", "s", "big"})
+	input.AddRow([]table.Value{table.IntVal(-9223372036854775807 - 1), table.StrVal("x"), table.IntVal(9223372036854775807)})
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"transform v = abs(n)", "integer overflow in abs(): -9223372036854775808"},
+		{"transform v = round(big, -1)", "integer overflow in round(): 9223372036854775807"},
+		{"transform v = sqrt(s)", "sqrt() requires a number, got string"},
+		{"transform v = round(n, 1.5)", "round() digits must be an int, got float"},
+		{"transform v = pow(n)", "pow() takes 2 arguments, got 1"},
+		{"transform v = greatest(n)", "greatest() requires at least 2 arguments, got 1"},
+		{"transform v = least(n, s)", "least() arguments do not have one common type"},
+		{"transform v = s % 2", "operator % requires numeric operands, got string and int"},
+		{"transform v = mod(n, s)", "mod() requires a number, got string"},
+	}
+	for _, tc := range cases {
+		err := runQueryExpectErr(t, input, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
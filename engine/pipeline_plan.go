@@ -104,6 +104,21 @@ func (plannedDescribe) executionTraits() plannedExecutionTraits {
 func (plannedJoin) executionTraits() plannedExecutionTraits {
 	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
 }
+func (plannedWindow) executionTraits() plannedExecutionTraits {
+	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
+}
+func (plannedExplode) executionTraits() plannedExecutionTraits {
+	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
+}
+func (plannedPivot) executionTraits() plannedExecutionTraits {
+	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
+}
+func (plannedUnpivot) executionTraits() plannedExecutionTraits {
+	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
+}
+func (plannedUnion) executionTraits() plannedExecutionTraits {
+	return plannedExecutionTraits{class: plannedExecutionMaterializedBoundary}
+}
 
 type plannedHead struct {
 	plannedBase
@@ -184,7 +199,12 @@ type plannedGroupReduceAssignment struct {
 type plannedAggregateSlot struct {
 	name      string
 	aggregate *aggregateSpec
-	args      []boundColumn
+	// args are row-level expressions over the grouped input, compiled
+	// once per execution.
+	args []typedExpr
+	// filter is the aggregate's "where { cond }"; rows where it is not true
+	// are skipped.
+	filter *typedExpr
 }
 
 type plannedSort struct {
@@ -197,6 +217,58 @@ type plannedSortKey struct {
 	desc   bool
 }
 
+type plannedWindow struct {
+	plannedBase
+	partition   []boundColumn
+	order       []plannedSortKey
+	assignments []plannedWindowAssignment
+}
+
+type plannedWindowAssignment struct {
+	name   string
+	target int
+	window *windowSpec
+	args   []typedExpr
+}
+
+type plannedExplode struct {
+	plannedBase
+	list       boundColumn
+	elemTarget int
+	posTarget  int // -1 = no position column
+	outer      bool
+}
+
+type plannedPivot struct {
+	plannedBase
+	groups  []boundColumn
+	key     boundColumn
+	keyType *table.TypeDescriptor // set when key values must be coerced before lookup
+	columns map[string]int        // canonical key value -> pivot column slot
+	width   int
+	agg     aggregateFinalExpr
+	slots   []plannedAggregateSlot
+}
+
+type plannedUnpivot struct {
+	plannedBase
+	kept    []int
+	sources []int
+	names   []string
+}
+
+// plannedUnion maps each output column to its input column (left), its column
+// in the appended source (rightCol), or -1 when that side has none. tag is the
+// output column that receives filename for appended rows, or -1.
+type plannedUnion struct {
+	plannedBase
+	right    plannedJoinRightSource
+	filename string
+	left     []int
+	rightCol []int
+	tag      int
+}
+
 type plannedSelect struct {
 	plannedBase
 	projections *projectionPlan
@@ -226,11 +298,29 @@ type plannedDescribe struct {
 
 type plannedJoin struct {
 	plannedBase
-	kind      string
-	right     plannedJoinRightSource
-	leftKeys  []resolvedJoinKey
-	rightKeys []resolvedJoinKey
-	outputs   []plannedJoinOutput
+	kind          string
+	right         plannedJoinRightSource
+	leftKeys      []resolvedJoinKey
+	rightKeys     []resolvedJoinKey
+	leftExprKeys  []typedExpr
+	rightExprKeys []typedExpr
+	condition     *plannedJoinCondition
+	asof          *plannedJoinAsof
+	outputs       []plannedJoinOutput
+}
+
+type plannedJoinAsof struct {
+	left      boundColumn
+	right     boundColumn
+	tolerance table.Value
+}
+
+// plannedJoinCondition is a residual on condition. sources say where each
+// env column comes from in a candidate pair of rows.
+type plannedJoinCondition struct {
+	expr    typedExpr
+	env     schemaEnv
+	sources []plannedJoinOutput
 }
 
 type plannedJoinRightSource struct {
@@ -259,7 +349,8 @@ func isSchemaPlannedOp(op ast.Op) bool {
 	case *ast.HeadOp, *ast.TailOp, *ast.FilterOp, *ast.SelectOp, *ast.SortOp,
 		*ast.TransformOp, *ast.GroupOp, *ast.ReduceOp, *ast.RenameOp,
 		*ast.RemoveOp, *ast.DistinctOp, *ast.CountOp, *ast.DescribeOp,
-		*ast.JoinOp:
+		*ast.JoinOp, *ast.WindowOp, *ast.ExplodeOp, *ast.PivotOp, *ast.UnpivotOp,
+		*ast.UnionOp:
 		return true
 	default:
 		return false
@@ -380,6 +471,16 @@ func execPlannedOp(op plannedOp, input *table.Table) (*table.Table, error) {
 		return execPlannedDescribe(p, input)
 	case plannedJoin:
 		return execPlannedJoin(p, input)
+	case plannedWindow:
+		return execPlannedWindow(p, input)
+	case plannedExplode:
+		return execPlannedExplode(p, input)
+	case plannedPivot:
+		return execPlannedPivot(p, input)
+	case plannedUnpivot:
+		return execPlannedUnpivot(p, input)
+	case plannedUnion:
+		return execPlannedUnion(p, input)
 	default:
 		return nil, fmt.Errorf("unknown planned operation type %T", op)
 	}
@@ -584,6 +685,53 @@ func execPlannedGroup(p plannedGroup, input *table.Table) (*table.Table, error)
 	return result, nil
 }
 
+func execPlannedExplode(p plannedExplode, input *table.Table) (*table.Table, error) {
+	cols, schemas := outputEnvColumns(p.OutputEnv())
+	targets := []int{p.elemTarget}
+	if p.posTarget >= 0 {
+		targets = append(targets, p.posTarget)
+	}
+	result := table.NewTableWithSchemas(cols, schemas)
+	for row := 0; row < input.NumRows; row++ {
+		list, err := resolveBoundColumn(p.list, input, row)
+		if err != nil {
+			return nil, fmt.Errorf("explode %q: %w", strings.Join(p.list.rawPath, "."), err)
+		}
+		if !list.IsNull() && list.Type != table.TypeList {
+			return nil, fmt.Errorf("explode %q: expected list, got %s", strings.Join(p.list.rawPath, "."), table.TypeName(list.Type))
+		}
+		if len(list.List) == 0 && !p.outer {
+			continue
+		}
+
+		base := make([]table.Value, len(cols))
+		for col := 0; col < len(input.Columns); col++ {
+			base[col] = input.Col(col).Get(row)
+		}
+		for col := len(input.Columns); col < len(cols); col++ {
+			base[col] = table.Null()
+		}
+		if len(list.List) == 0 {
+			base[p.elemTarget] = table.Null()
+			if err := result.AddRowTypedColumns(base, targets); err != nil {
+				return nil, fmt.Errorf("explode: %w", err)
+			}
+			continue
+		}
+		for i, elem := range list.List {
+			vals := append([]table.Value(nil), base...)
+			vals[p.elemTarget] = elem
+			if p.posTarget >= 0 {
+				vals[p.posTarget] = table.IntVal(int64(i))
+			}
+			if err := result.AddRowTypedColumns(vals, targets); err != nil {
+				return nil, fmt.Errorf("explode: %w", err)
+			}
+		}
+	}
+	return result, nil
+}
+
 func execPlannedReduce(p plannedReduce, input *table.Table) (*table.Table, error) {
 	if p.nestedIndex < 0 || p.nestedIndex >= len(input.Columns) {
 		return nil, fmt.Errorf("reduce: nested column %q not found (did you forget to group first?)", p.nestedName)
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/razeghi71/dq/table"
+)
+
+type plannedPivotEntry struct {
+	key    []table.Value
+	states [][]aggregateAccumulator
+}
+
+// execPlannedPivot groups rows by the remaining columns in first-seen order
+// and keeps one set of aggregate states per listed key value. Rows whose key
+// value is not listed still form their group but feed no pivot column.
+func execPlannedPivot(p plannedPivot, input *table.Table) (*table.Table, error) {
+	runtimeSlots := runtimeAggregateSlots(p.slots, input)
+	groups := make([]plannedPivotEntry, 0)
+	keyMap := make(map[string]int)
+
+	for row := 0; row < input.NumRows; row++ {
+		keyVals := make([]table.Value, len(p.groups))
+		keyParts := make([]string, len(p.groups))
+		for i, col := range p.groups {
+			v, err := resolveBoundColumn(col, input, row)
+			if err != nil {
+				return nil, fmt.Errorf("pivot: group column %q: %w", strings.Join(col.rawPath, "."), err)
+			}
+			keyVals[i] = v
+			keyParts[i] = table.CanonicalKey(v)
+		}
+		key := canonicalTupleKey(keyParts)
+		groupIdx, exists := keyMap[key]
+		if !exists {
+			states := make([][]aggregateAccumulator, p.width)
+			for i := range states {
+				var err error
+				states[i], err = newAggregateAccumulators(runtimeSlots)
+				if err != nil {
+					return nil, fmt.Errorf("pivot: %w", err)
+				}
+			}
+			groupIdx = len(groups)
+			keyMap[key] = groupIdx
+			groups = append(groups, plannedPivotEntry{key: keyVals, states: states})
+		}
+
+		pivotKey, err := resolveBoundColumn(p.key, input, row)
+		if err != nil {
+			return nil, fmt.Errorf("pivot %q: %w", strings.Join(p.key.rawPath, "."), err)
+		}
+		if p.keyType != nil && !pivotKey.IsNull() {
+			pivotKey, err = table.CoerceValueToFinalSchemaMode(pivotKey, p.keyType, table.CoerceCoerciveMode)
+			if err != nil {
+				return nil, fmt.Errorf("pivot %q: %w", strings.Join(p.key.rawPath, "."), err)
+			}
+		}
+		slot, ok := p.columns[table.CanonicalKey(pivotKey)]
+		if !ok || pivotKey.IsNull() {
+			continue
+		}
+		for _, state := range groups[groupIdx].states[slot] {
+			if err := state.Update(row); err != nil {
+				return nil, fmt.Errorf("pivot: %w", err)
+			}
+		}
+	}
+
+	result := tableFromOutputEnv(p.OutputEnv())
+	for _, group := range groups {
+		vals := make([]table.Value, len(group.key), len(group.key)+p.width)
+		copy(vals, group.key)
+		for _, states := range group.states {
+			v, err := evalAggregateFinalExpr(p.agg, states)
+			if err != nil {
+				return nil, fmt.Errorf("pivot: %w", err)
+			}
+			vals = append(vals, v)
+		}
+		if err := result.AddRowTyped(vals); err != nil {
+			return nil, fmt.Errorf("pivot: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// execPlannedUnpivot emits one row per unpivoted column for every input row,
+// in input row order and then column list order. Null values are kept.
+func execPlannedUnpivot(p plannedUnpivot, input *table.Table) (*table.Table, error) {
+	cols, schemas := outputEnvColumns(p.OutputEnv())
+	nameTarget := len(p.kept)
+	valueTarget := nameTarget + 1
+	targets := []int{nameTarget, valueTarget}
+	result := table.NewTableWithSchemas(cols, schemas)
+	for row := 0; row < input.NumRows; row++ {
+		for i, source := range p.sources {
+			vals := make([]table.Value, len(cols))
+			for out, in := range p.kept {
+				vals[out] = input.Col(in).Get(row)
+			}
+			vals[nameTarget] = table.StrVal(p.names[i])
+			vals[valueTarget] = input.Col(source).Get(row)
+			if err := result.AddRowTypedColumns(vals, targets); err != nil {
+				return nil, fmt.Errorf("unpivot: %w", err)
+			}
+		}
+	}
+	return result, nil
+}
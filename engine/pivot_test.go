@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/parser"
+	"github.com/razeghi71/dq/table"
+)
+
+func pivotSalesTable() *table.Table {
+	t := table.NewTable([]string{"region", "month", "amount"})
+	t.AddRow([]table.Value{table.StrVal("eu"), table.StrVal("jan"), table.IntVal(10)})
+	t.AddRow([]table.Value{table.StrVal("eu"), table.StrVal("feb"), table.IntVal(5)})
+	t.AddRow([]table.Value{table.StrVal("us"), table.StrVal("jan"), table.IntVal(7)})
+	t.AddRow([]table.Value{table.StrVal("eu"), table.StrVal("jan"), table.IntVal(3)})
+	t.AddRow([]table.Value{table.StrVal("us"), table.StrVal("mar"), table.IntVal(1)})
+	return t
+}
+
+func TestPivotAggregatesListedKeys(t *testing.T) {
+	result := runQuery(t, pivotSalesTable(), `pivot month in ("jan", "feb") values amount agg sum`)
+	if strings.Join(result.Columns, ",") != "region,jan,feb" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "region", "eu", "us")
+	assertColumnStrings(t, result, "jan", "13", "7")
+	assertColumnStrings(t, result, "feb", "5", "null")
+	assertColumnSchema(t, result, "jan", "int?")
+}
+
+func TestPivotDefaultsToFirstAndSupportsCount(t *testing.T) {
+	result := runQuery(t, pivotSalesTable(), `pivot month in ("jan") values amount`)
+	assertColumnStrings(t, result, "jan", "10", "7")
+
+	result = runQuery(t, pivotSalesTable(), `select region, month | pivot month in ("jan", "mar") agg count`)
+	assertColumnStrings(t, result, "jan", "2", "1")
+	assertColumnStrings(t, result, "mar", "0", "1")
+	assertColumnSchema(t, result, "mar", "int")
+}
+
+func TestPivotIntegerKeys(t *testing.T) {
+	input := table.NewTable([]string{"id", "q", "v"})
+	input.AddRow([]table.Value{table.IntVal(1), table.IntVal(-1), table.FloatVal(1.5)})
+	input.AddRow([]table.Value{table.IntVal(1), table.IntVal(2), table.FloatVal(2.5)})
+	result := runQuery(t, input, "pivot q in (-1, 2) values v agg max")
+	if strings.Join(result.Columns, ",") != "id,-1,2" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "2", "2.5")
+}
+
+func TestPivotListedValuesUnifyWithKeyColumn(t *testing.T) {
+	input := table.NewTable([]string{"id", "q", "v"})
+	input.AddRow([]table.Value{table.IntVal(1), table.FloatVal(1), table.IntVal(10)})
+	input.AddRow([]table.Value{table.IntVal(1), table.FloatVal(2.5), table.IntVal(20)})
+	result := runQuery(t, input, "pivot q in (1, 2.5) values v agg sum")
+	if strings.Join(result.Columns, ",") != "id,1,2.5" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "1", "10")
+	assertColumnStrings(t, result, "2.5", "20")
+
+	input = table.NewTable([]string{"id", "q", "v"})
+	input.AddRow([]table.Value{table.IntVal(1), table.IntVal(2), table.IntVal(10)})
+	result = runQuery(t, input, "pivot q in (2.0, 2.5) values v agg sum")
+	assertColumnStrings(t, result, "2.0", "10")
+	assertColumnStrings(t, result, "2.5", "null")
+}
+
+func TestUnpivotEmitsNameValueRows(t *testing.T) {
+	input := table.NewTable([]string{"id", "a", "b"})
+	input.AddRow([]table.Value{table.IntVal(1), table.IntVal(2), table.FloatVal(0.5)})
+	input.AddRow([]table.Value{table.IntVal(2), table.Null(), table.FloatVal(1.5)})
+	result := runQuery(t, input, "unpivot a, b into metric, v")
+	if strings.Join(result.Columns, ",") != "id,metric,v" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "id", "1", "1", "2", "2")
+	assertColumnStrings(t, result, "metric", "a", "b", "a", "b")
+	assertColumnStrings(t, result, "v", "2", "0.5", "null", "1.5")
+	assertColumnSchema(t, result, "v", "float?")
+}
+
+func TestUnpivotDefaultColumnNamesAndPruning(t *testing.T) {
+	result := runQuery(t, pivotSalesTable(), "transform extra = amount * 2 | unpivot amount | select name, value")
+	if strings.Join(result.Columns, ",") != "name,value" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "value", "10", "5", "7", "3", "1")
+}
+
+func TestPivotAndUnpivotPlanningErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`pivot missing in ("jan") values amount`, `pivot "missing"`},
+		{`pivot month in (1) values amount`, `pivot: int value "1" does not match key column "month" of type string`},
+		{`pivot month in ("jan", "jan") values amount`, `pivot: value "jan" listed more than once`},
+		{`pivot amount in (1, 1.0) values region`, `pivot: values "1" and "1.0" are the same key`},
+		{`pivot month in ("region") values amount`, `pivot: output column "region" already exists`},
+		{`pivot month in ("jan") values amount agg upper`, `pivot: "upper" is not an aggregate function`},
+		{`pivot month in ("jan") values amount agg nope`, `pivot: unknown aggregate function "nope"`},
+		{`pivot month in ("jan") agg sum`, "pivot: sum() needs a values column"},
+		{`pivot month in ("jan") values amount agg count`, "pivot: count() takes no values column"},
+		{`pivot month in ("jan") values region agg sum`, "sum() requires a numeric column"},
+		{`pivot month in ("jan") values amount | select feb`, `"feb"`},
+		{"unpivot amount, amount", `unpivot: column "amount" listed more than once`},
+		{"unpivot missing", `unpivot "missing"`},
+		{"unpivot amount, region", `unpivot: column "region" has type string, which does not match int`},
+		{"unpivot amount into region, v", `unpivot: output column "region" already exists`},
+		{"unpivot amount into v, v", "unpivot: name and value columns must differ"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := parser.Parse("test.csv | " + tc.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, err = Execute(q, pivotSalesTable(), nil)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPivotStreamingMatchesMaterialized(t *testing.T) {
+	query := `pivot month in ("jan", "feb") values amount agg sum | unpivot jan, feb into month, total`
+	q, err := parser.Parse("test.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	streamed, err := ExecuteStreaming(q, pivotSalesTable(), nil)
+	if err != nil {
+		t.Fatalf("streaming error: %v", err)
+	}
+	materialized := runQuery(t, pivotSalesTable(), query)
+	if streamed.String() != materialized.String() {
+		t.Fatalf("streaming result differs:\n%s\nvs\n%s", streamed.String(), materialized.String())
+	}
+	assertColumnStrings(t, materialized, "total", "13", "5", "7", "null")
+}
@@ -41,6 +41,11 @@ func TestPlannedExecutionTraitsClassifyEveryPlannedOp(t *testing.T) {
 		{name: "count", op: plannedCount{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionStreamingFold},
 		{name: "describe", op: plannedDescribe{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionStreamingFold},
 		{name: "join", op: plannedJoin{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
+		{name: "window", op: plannedWindow{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
+		{name: "explode", op: plannedExplode{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
+		{name: "pivot", op: plannedPivot{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
+		{name: "unpivot", op: plannedUnpivot{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
+		{name: "union", op: plannedUnion{plannedBase: plannedBaseFromTestSchema(schema)}, wantClass: plannedExecutionMaterializedBoundary},
 	}
 
 	for _, tc := range cases {
@@ -105,16 +105,16 @@ func TestReducePlanningRejectsInvalidExpressionsBeforeRows(t *testing.T) {
 			wants: []string{"reduce", "xs", "record"},
 		},
 		{
-			name:  "aggregate_arg_must_be_column_function",
+			name:  "aggregate_arg_cannot_nest_aggregate",
 			input: usersTable(),
-			query: `filter { false } | group city | reduce bad = first(upper(name))`,
-			wants: []string{"first", "column reference"},
+			query: `filter { false } | group city | reduce bad = first(sum(age))`,
+			wants: []string{"first", "sum", "nested"},
 		},
 		{
-			name:  "aggregate_arg_must_be_column_expression",
+			name:  "aggregate_arg_expression_must_typecheck",
 			input: usersTable(),
-			query: `filter { false } | group city | reduce bad = sum(age + 1)`,
-			wants: []string{"sum", "column reference"},
+			query: `filter { false } | group city | reduce bad = sum(upper(name))`,
+			wants: []string{"sum", "numeric"},
 		},
 		{
 			name:  "aggregate_count_takes_no_args",
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+func regexLogTable() *table.Table {
+	tbl := table.NewTable([]string{"line", "pattern"})
+	tbl.AddRow([]table.Value{table.StrVal("GET /a/b 200 12ms"), table.StrVal(`\d+`)})
+	tbl.AddRow([]table.Value{table.StrVal("POST /c 500 7ms"), table.StrVal(`[a-z]+`)})
+	tbl.AddRow([]table.Value{table.StrVal("nothing"), table.Null()})
+	return tbl
+}
+
+func TestRegexExtractAndReplace(t *testing.T) {
+	result := runQuery(t, regexLogTable(), `transform
+		method = regex_extract(line, "^(\\w+) (\\S+)", 1),
+		whole = regex_extract(line, "\\d+ms"),
+		optional = regex_extract(line, "GET( x)?", 1),
+		nums = regex_extract_all(line, "(\\d+)(ms)?", 1),
+		masked = regex_replace(line, "(\\d+)ms", "${1} ms"),
+		words = regex_split(line, "\\s+"),
+		per_row = regex_extract_all(line, pattern)
+	| select method, whole, optional, nums, masked, words, per_row`)
+	assertColumnStrings(t, result, "method", "GET", "POST", "null")
+	assertColumnStrings(t, result, "whole", "12ms", "7ms", "null")
+	assertColumnStrings(t, result, "optional", "null", "null", "null")
+	assertColumnStrings(t, result, "nums", "[200, 12]", "[500, 7]", "[]")
+	assertColumnStrings(t, result, "masked", "GET /a/b 200 12 ms", "POST /c 500 7 ms", "nothing")
+	assertColumnStrings(t, result, "words", "[GET, /a/b, 200, 12ms]", "[POST, /c, 500, 7ms]", "[nothing]")
+	assertColumnStrings(t, result, "per_row", "[200, 12]", "[c, ms]", "null")
+	assertColumnSchema(t, result, "method", "string?")
+	assertColumnSchema(t, result, "nums", "list<string>")
+	assertColumnSchema(t, result, "masked", "string")
+	assertColumnSchema(t, result, "per_row", "list<string>?")
+}
+
+func TestRegexConstantPatternsFailBeforeRows(t *testing.T) {
+	empty := table.NewTable([]string{"line"})
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`filter { matches(line, "[") }`, `matches(): invalid regex "["`},
+		{`transform v = regex_extract(line, "(")`, `regex_extract(): invalid regex "("`},
+		{`transform v = regex_extract_all(line, "(a)", 2)`, `regex_extract_all(): group 2 out of range, regex "(a)" has 1 groups`},
+		{`transform v = regex_replace(line, "*", "x")`, `regex_replace(): invalid regex "*"`},
+		{`transform v = regex_split(line, "a{2,1}")`, `regex_split(): invalid regex "a{2,1}"`},
+		{`transform v = regex_extract(line, "a", "1")`, "regex_extract() group must be an int, got string"},
+		{`transform v = regex_replace(line, "a")`, "regex_replace() takes 3 arguments (string, regex, replacement), got 2"},
+	}
+	for _, tc := range cases {
+		err := runQueryExpectErr(t, empty, tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
+
+func TestRegexRowPatternErrors(t *testing.T) {
+	tbl := table.NewTable([]string{"s", "pattern", "group"})
+	tbl.AddRow([]table.Value{table.StrVal("abc"), table.StrVal("(b)"), table.IntVal(3)})
+	err := runQueryExpectErr(t, tbl, `transform v = regex_extract(s, pattern, group)`)
+	if err == nil || !strings.Contains(err.Error(), `regex_extract(): group 3 out of range, regex "(b)" has 1 groups`) {
+		t.Fatalf("group error = %v", err)
+	}
+	tbl.AddRow([]table.Value{table.StrVal("abc"), table.StrVal("["), table.IntVal(0)})
+	err = runQueryExpectErr(t, tbl, `transform v = regex_split(s, pattern)`)
+	if err == nil || !strings.Contains(err.Error(), `regex_split(): invalid regex "["`) {
+		t.Fatalf("regex error = %v", err)
+	}
+}
@@ -22,13 +22,22 @@ func plannedDivisionSchema(out *table.TypeDescriptor, denominator ast.Expr) *tab
 	return out
 }
 
+// plannedRemainderSchema keeps the operand type of %, which is null for a zero
+// divisor just like division.
+func plannedRemainderSchema(out *table.TypeDescriptor, denominator ast.Expr) *table.TypeDescriptor {
+	if out != nil && !numericExprKnownNonZero(denominator) {
+		out.Nullable = true
+	}
+	return out
+}
+
 func numericExprKnownNonZero(expr ast.Expr) bool {
 	switch e := expr.(type) {
 	case *ast.LiteralExpr:
 		switch e.Kind {
 		case "int":
 			return e.Int != 0
-		case "float":
+		case "float", "decimal":
 			return e.Float != 0
 		default:
 			return false
@@ -56,6 +65,17 @@ func nestedRecordSchemaForReduce(column string, schema *table.TypeDescriptor) (*
 	return elem, nil
 }
 
+func explodeElementSchema(column string, schema *table.TypeDescriptor) (*table.TypeDescriptor, error) {
+	schema = normalizePlanningSchema(schema)
+	if schema == nil || schema.Kind == table.TypeNull {
+		return nil, fmt.Errorf("explode: column %q has unknown schema; expected list<...>", column)
+	}
+	if schema.Kind != table.TypeList {
+		return nil, fmt.Errorf("explode: column %q must be a list, got %s", column, table.Render(schema))
+	}
+	return finalizePlanningSchema(schema).Elem, nil
+}
+
 func recordSchemaForEnv(env schemaEnv) *table.TypeDescriptor {
 	fields := make([]table.FieldDescriptor, len(env.columns))
 	for i, col := range env.columns {
@@ -104,6 +124,41 @@ func planLogicalReduceExpr(expr ast.Expr, nestedSchema *table.TypeDescriptor) (l
 	return typeCheckLogicalReduceExpression(bound)
 }
 
+func planLogicalWindowExpr(expr ast.Expr, env schemaEnv) (logicalTypedExpr, error) {
+	call, ok := expr.(*ast.FuncCallExpr)
+	if !ok {
+		return logicalTypedExpr{}, fmt.Errorf("window assignment must call a window function such as row_number() or lag(col); use transform for row-level expressions")
+	}
+	spec, ok := builtinCatalog[call.Name]
+	if !ok {
+		return logicalTypedExpr{}, fmt.Errorf("unknown function %q", call.Name)
+	}
+	if spec.Category != builtinWindow || spec.Window == nil {
+		return logicalTypedExpr{}, fmt.Errorf("function %q is not a window function; use transform for row-level expressions", call.Name)
+	}
+	if call.Filter != nil {
+		return logicalTypedExpr{}, aggregateFilterOutsideReduceError(call.Name)
+	}
+	if len(call.Args) < spec.Window.MinArity || len(call.Args) > spec.Window.MaxArity {
+		return logicalTypedExpr{}, arityRangeError(call.Name, spec.Window.MinArity, spec.Window.MaxArity, len(call.Args))
+	}
+	bound := make([]logicalBoundExpr, len(call.Args))
+	args := make([]logicalTypedExpr, len(call.Args))
+	for i, arg := range call.Args {
+		typed, err := planLogicalTransformExprInEnv(arg, env)
+		if err != nil {
+			return logicalTypedExpr{}, fmt.Errorf("%s(): %w", call.Name, err)
+		}
+		bound[i] = typed.bound
+		args[i] = typed
+	}
+	typ, err := spec.Check(logicalSignatureExprs(args))
+	if err != nil {
+		return logicalTypedExpr{}, err
+	}
+	return logicalTypedExpr{bound: &logicalBoundCall{raw: call, args: bound}, raw: call, typ: typ, args: args}, nil
+}
+
 func nullableSchema(kind table.ValueType, inputs ...*table.TypeDescriptor) *table.TypeDescriptor {
 	return &table.TypeDescriptor{Kind: kind, Nullable: anySchemaMayBeNull(inputs...)}
 }
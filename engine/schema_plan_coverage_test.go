@@ -260,14 +260,14 @@ func TestPlanReduceExprSchemaEdges(t *testing.T) {
 	}{
 		{name: "sum_string", expr: schemaPlanCall("sum", schemaPlanCol("name")), wantErr: "requires a numeric column"},
 		{name: "min_list", expr: schemaPlanCall("min", schemaPlanCol("tags")), wantErr: "requires an orderable column"},
-		{name: "unknown_aggregate", expr: schemaPlanCall("median", schemaPlanCol("age")), wantErr: "unknown function"},
+		{name: "unknown_aggregate", expr: schemaPlanCall("geomean", schemaPlanCol("age")), wantErr: "unknown function"},
 		{name: "bad_arity", expr: schemaPlanCall("count", schemaPlanCol("age")), wantErr: "count() takes no arguments"},
 		{name: "bad_binary_operand", expr: schemaPlanBinary("+", schemaPlanCall("sum", schemaPlanCol("name")), schemaPlanCall("count")), wantErr: "requires a numeric column"},
 		{name: "bad_order_operand", expr: schemaPlanBinary("<", schemaPlanCall("sum", schemaPlanCol("name")), schemaPlanCall("count")), wantErr: "requires a numeric column"},
 		{name: "bad_logical_operand", expr: schemaPlanBinary("and", schemaPlanCall("sum", schemaPlanCol("name")), schemaPlanBoolLit(true)), wantErr: "requires a numeric column"},
 		{name: "bad_unary_operand", expr: schemaPlanUnary("-", schemaPlanCall("first", schemaPlanCol("name"))), wantErr: "requires numeric operand"},
 		{name: "unknown_unary", expr: schemaPlanUnary("~", schemaPlanCall("count")), wantErr: "unknown unary operator"},
-		{name: "unknown_binary", expr: schemaPlanBinary("%", schemaPlanCall("count"), schemaPlanIntLit(2)), wantErr: "unknown operator"},
+		{name: "unknown_binary", expr: schemaPlanBinary("^", schemaPlanCall("count"), schemaPlanIntLit(2)), wantErr: "unknown operator"},
 	}
 
 	for _, tc := range invalid {
@@ -317,7 +317,7 @@ func TestPlanTransformExprSchemaEdges(t *testing.T) {
 		expr    ast.Expr
 		wantErr string
 	}{
-		{name: "unknown_binary", expr: schemaPlanBinary("%", schemaPlanIntLit(1), schemaPlanIntLit(2)), wantErr: "unknown operator"},
+		{name: "unknown_binary", expr: schemaPlanBinary("^", schemaPlanIntLit(1), schemaPlanIntLit(2)), wantErr: "unknown operator"},
 		{name: "bad_numeric_binary", expr: schemaPlanBinary("-", schemaPlanStringLit("a"), schemaPlanIntLit(2)), wantErr: "requires numeric operands"},
 		{name: "unknown_unary", expr: schemaPlanUnary("~", schemaPlanIntLit(1)), wantErr: "unknown unary operator"},
 		{name: "bad_unary", expr: schemaPlanUnary("-", schemaPlanStringLit("a")), wantErr: "requires numeric operand"},
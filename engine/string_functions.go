@@ -0,0 +1,495 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// maxStringFunctionResult caps the bytes lpad(), rpad() and repeat() may
+// build for one value, so a bad count fails instead of exhausting memory.
+const maxStringFunctionResult = 1 << 28
+
+// stringFuncParam describes one argument of a string function. The label
+// names it in errors; the string being worked on has an empty label.
+type stringFuncParam struct {
+	label string
+	kind  table.ValueType
+}
+
+var (
+	stringParam   = stringFuncParam{kind: table.TypeString}
+	splitParams   = []stringFuncParam{stringParam, {label: "separator", kind: table.TypeString}}
+	replaceParams = []stringFuncParam{stringParam, {label: "search", kind: table.TypeString}, {label: "replacement", kind: table.TypeString}}
+	padParams     = []stringFuncParam{stringParam, {label: "length", kind: table.TypeInt}, {label: "fill", kind: table.TypeString}}
+	repeatParams  = []stringFuncParam{stringParam, {label: "count", kind: table.TypeInt}}
+	indexOfParams = []stringFuncParam{stringParam, {label: "substring", kind: table.TypeString}}
+	sliceParams   = []stringFuncParam{stringParam, {label: "length", kind: table.TypeInt}}
+)
+
+func (p stringFuncParam) typeError(name, got string) error {
+	switch {
+	case p.kind == table.TypeInt:
+		return fmt.Errorf("%s(): %s must be an int, got %s", name, p.label, got)
+	case p.label == "":
+		return fmt.Errorf("%s() requires a string, got %s", name, got)
+	default:
+		return fmt.Errorf("%s() requires a string %s, got %s", name, p.label, got)
+	}
+}
+
+// stringFuncArityError lists the parameters as substr() does, with the
+// trailing optional ones in brackets: "(string, length[, fill])".
+func stringFuncArityError(name string, params []stringFuncParam, optional, got int) error {
+	if len(params) == 1 {
+		return fmt.Errorf("%s() takes 1 argument, got %d", name, got)
+	}
+	required := len(params) - optional
+	var usage strings.Builder
+	for i, p := range params {
+		label := p.label
+		if label == "" {
+			label = "string"
+		}
+		switch {
+		case i == 0:
+			usage.WriteString(label)
+		case i == required:
+			usage.WriteString("[, " + label)
+		default:
+			usage.WriteString(", " + label)
+		}
+	}
+	if optional == 0 {
+		return fmt.Errorf("%s() takes %d arguments (%s), got %d", name, len(params), usage.String(), got)
+	}
+	usage.WriteString(strings.Repeat("]", optional))
+	return fmt.Errorf("%s() takes %d or %d arguments (%s), got %d", name, required, len(params), usage.String(), got)
+}
+
+func checkStringFuncArgs(name string, params []stringFuncParam, optional int, args []typedExpr) error {
+	if len(args) < len(params)-optional || len(args) > len(params) {
+		return stringFuncArityError(name, params, optional, len(args))
+	}
+	for i, arg := range args {
+		if !schemaKindOrNull(arg.typ, params[i].kind) {
+			return params[i].typeError(name, schemaString(arg.typ))
+		}
+	}
+	return nil
+}
+
+// stringFunctionSpec types string functions whose arguments have fixed kinds.
+// The last optional parameters may be left out, and the result is nullable
+// when any argument may be null.
+func stringFunctionSpec(name string, out table.ValueType, optional int, params []stringFuncParam) func(args []typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if err := checkStringFuncArgs(name, params, optional, args); err != nil {
+			return nil, err
+		}
+		return nullableSchema(out, typedExprSchemas(args)...), nil
+	}
+}
+
+func checkSplitSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if err := checkStringFuncArgs("split", splitParams, 0, args); err != nil {
+		return nil, err
+	}
+	return &table.TypeDescriptor{
+		Kind:     table.TypeList,
+		Nullable: anySchemaMayBeNull(typedExprSchemas(args)...),
+		Elem:     &table.TypeDescriptor{Kind: table.TypeString},
+	}, nil
+}
+
+func checkConcatSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("concat() requires at least 1 argument")
+	}
+	for _, arg := range args {
+		if !schemaKindOrNull(arg.typ, table.TypeString) {
+			return nil, fmt.Errorf("concat() requires strings, got %s", schemaString(arg.typ))
+		}
+	}
+	return nullableSchema(table.TypeString, typedExprSchemas(args)...), nil
+}
+
+// typedStringFuncArgs evaluates the arguments of a stringFunctionSpec
+// function. ok is false when any argument is null, which propagates.
+func typedStringFuncArgs(name string, params []stringFuncParam, optional int, args []typedExpr, ctx *EvalContext) ([]table.Value, bool, error) {
+	if len(args) < len(params)-optional || len(args) > len(params) {
+		return nil, false, stringFuncArityError(name, params, optional, len(args))
+	}
+	values := make([]table.Value, len(args))
+	for i, arg := range args {
+		v, err := evalTypedExpression(arg, ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		if v.IsNull() {
+			return nil, false, nil
+		}
+		if v.Type != params[i].kind {
+			return nil, false, params[i].typeError(name, valueTypeName(v))
+		}
+		values[i] = v
+	}
+	return values, true, nil
+}
+
+// stringCountArg converts a length or count argument, rejecting negative
+// values the way substr() rejects a negative length.
+func stringCountArg(name, field string, v table.Value) (int, error) {
+	if v.Int < 0 {
+		return 0, fmt.Errorf("%s(): %s must not be negative", name, field)
+	}
+	if v.Int > maxStringFunctionResult {
+		return 0, fmt.Errorf("%s(): %s %d is too large", name, field, v.Int)
+	}
+	return int(v.Int), nil
+}
+
+func typedConcatEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) == 0 {
+		return table.Null(), fmt.Errorf("concat() requires at least 1 argument")
+	}
+	var b strings.Builder
+	for _, arg := range args {
+		v, err := evalTypedExpression(arg, ctx)
+		if err != nil {
+			return table.Null(), err
+		}
+		if v.IsNull() {
+			return table.Null(), nil
+		}
+		if v.Type != table.TypeString {
+			return table.Null(), fmt.Errorf("concat() requires strings, got %s", valueTypeName(v))
+		}
+		b.WriteString(v.Str)
+	}
+	return table.StrVal(b.String()), nil
+}
+
+func typedSplitEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := typedStringFuncArgs("split", splitParams, 0, args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	parts := strings.Split(values[0].Str, values[1].Str)
+	out := make([]table.Value, len(parts))
+	for i, part := range parts {
+		out[i] = table.StrVal(part)
+	}
+	return table.ListVal(out), nil
+}
+
+func typedReplaceEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := typedStringFuncArgs("replace", replaceParams, 0, args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	return table.StrVal(strings.ReplaceAll(values[0].Str, values[1].Str, values[2].Str)), nil
+}
+
+// typedPadEval pads to a length in code points, repeating fill (a space by
+// default) on the left or right. Longer strings are cut to the length, so the
+// result always has exactly that many code points.
+func typedPadEval(name string, left bool) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		values, ok, err := typedStringFuncArgs(name, padParams, 1, args, ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		length, err := stringCountArg(name, "length", values[1])
+		if err != nil {
+			return table.Null(), err
+		}
+		fill := " "
+		if len(values) > 2 {
+			fill = values[2].Str
+		}
+		s := values[0].Str
+		n := stringCodePointCount(s)
+		if n >= length {
+			return table.StrVal(substrByCodePoints(s, 0, length)), nil
+		}
+		if fill == "" {
+			return table.Null(), fmt.Errorf("%s(): fill must not be empty", name)
+		}
+		fillRunes := []rune(fill)
+		var pad strings.Builder
+		for i := 0; i < length-n; i++ {
+			pad.WriteRune(fillRunes[i%len(fillRunes)])
+		}
+		if left {
+			return table.StrVal(pad.String() + s), nil
+		}
+		return table.StrVal(s + pad.String()), nil
+	}
+}
+
+func typedRepeatEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := typedStringFuncArgs("repeat", repeatParams, 0, args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	count, err := stringCountArg("repeat", "count", values[1])
+	if err != nil {
+		return table.Null(), err
+	}
+	s := values[0].Str
+	if len(s) > 0 && count > maxStringFunctionResult/len(s) {
+		return table.Null(), fmt.Errorf("repeat(): result would exceed %d bytes", maxStringFunctionResult)
+	}
+	return table.StrVal(strings.Repeat(s, count)), nil
+}
+
+// reverseCodePoints reverses s by code point, so multi-byte characters stay
+// intact.
+func reverseCodePoints(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// typedIndexOfEval returns the 0-based code point index of the first match,
+// or -1 when the substring does not occur.
+func typedIndexOfEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	values, ok, err := typedStringFuncArgs("index_of", indexOfParams, 0, args, ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	i := strings.Index(values[0].Str, values[1].Str)
+	if i < 0 {
+		return table.IntVal(-1), nil
+	}
+	return table.IntVal(int64(utf8.RuneCountInString(values[0].Str[:i]))), nil
+}
+
+// typedStringSliceEval implements left() and right(), which keep the first or
+// last length code points.
+func typedStringSliceEval(name string, fromEnd bool) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		values, ok, err := typedStringFuncArgs(name, sliceParams, 0, args, ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		length, err := stringCountArg(name, "length", values[1])
+		if err != nil {
+			return table.Null(), err
+		}
+		s := values[0].Str
+		start := 0
+		if n := stringCodePointCount(s); fromEnd && length < n {
+			start = n - length
+		}
+		return table.StrVal(substrByCodePoints(s, start, length)), nil
+	}
+}
+
+// formatVerb is one %-directive of a format() pattern. spec is the directive
+// as written, which is handed to fmt once the argument is converted.
+type formatVerb struct {
+	spec string
+	verb byte
+}
+
+// formatPiece is literal text followed by an optional verb.
+type formatPiece struct {
+	text string
+	verb *formatVerb
+}
+
+// maxFormatWidth bounds widths and precisions in format() patterns.
+const maxFormatWidth = 1000
+
+// parseFormatPattern splits a format() pattern into literal text and verbs.
+// A verb is %[flags][width][.precision] followed by s, d, x, f, e or g; flags
+// are any of "-+ 0#", and %% is a literal percent sign.
+func parseFormatPattern(pattern string) ([]formatPiece, error) {
+	var pieces []formatPiece
+	var text strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			text.WriteByte(pattern[i])
+			continue
+		}
+		start := i
+		i++
+		if i < len(pattern) && pattern[i] == '%' {
+			text.WriteByte('%')
+			continue
+		}
+		for i < len(pattern) && strings.IndexByte("-+ 0#", pattern[i]) >= 0 {
+			i++
+		}
+		if err := skipFormatNumber(pattern, &i, "width"); err != nil {
+			return nil, err
+		}
+		if i < len(pattern) && pattern[i] == '.' {
+			i++
+			if err := skipFormatNumber(pattern, &i, "precision"); err != nil {
+				return nil, err
+			}
+		}
+		if i >= len(pattern) {
+			return nil, fmt.Errorf("format(): incomplete verb %q at end of pattern", pattern[start:])
+		}
+		if strings.IndexByte("sdxfeg", pattern[i]) < 0 {
+			r, _ := utf8.DecodeRuneInString(pattern[i:])
+			return nil, fmt.Errorf("format(): unknown verb %%%c (supported: %%s %%d %%x %%f %%e %%g)", r)
+		}
+		pieces = append(pieces, formatPiece{text: text.String(), verb: &formatVerb{spec: pattern[start : i+1], verb: pattern[i]}})
+		text.Reset()
+	}
+	if text.Len() > 0 || len(pieces) == 0 {
+		pieces = append(pieces, formatPiece{text: text.String()})
+	}
+	return pieces, nil
+}
+
+func skipFormatNumber(pattern string, i *int, field string) error {
+	start := *i
+	for *i < len(pattern) && pattern[*i] >= '0' && pattern[*i] <= '9' {
+		*i++
+	}
+	if *i == start {
+		return nil
+	}
+	if n, err := strconv.Atoi(pattern[start:*i]); err != nil || n > maxFormatWidth {
+		return fmt.Errorf("format(): %s %s is too large (max %d)", field, pattern[start:*i], maxFormatWidth)
+	}
+	return nil
+}
+
+func formatVerbCount(pieces []formatPiece) int {
+	n := 0
+	for _, p := range pieces {
+		if p.verb != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func formatArgCountError(verbs, got int) error {
+	return fmt.Errorf("format(): pattern has %d verbs but %d arguments were given", verbs, got)
+}
+
+// formatVerbAccepts reports whether a value of kind can fill verb: %s takes
+// anything, %d and %x take ints, and %f, %e and %g take any number.
+func formatVerbAccepts(verb byte, kind table.ValueType) bool {
+	switch verb {
+	case 's':
+		return true
+	case 'd', 'x':
+		return kind == table.TypeInt
+	default:
+		return kind == table.TypeInt || kind == table.TypeFloat || kind == table.TypeDecimal
+	}
+}
+
+func formatVerbError(verb *formatVerb, arg int, got string) error {
+	want := "a number"
+	if verb.verb == 'd' || verb.verb == 'x' {
+		want = "an int"
+	}
+	return fmt.Errorf("format(): %s needs %s for argument %d, got %s", verb.spec, want, arg, got)
+}
+
+// checkFormatSignature types format(pattern, args...). A literal pattern is
+// parsed while planning, so a bad verb or a mismatched argument fails before
+// any rows are read.
+func checkFormatSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("format() requires a pattern")
+	}
+	if !schemaKindOrNull(args[0].typ, table.TypeString) {
+		return nil, fmt.Errorf("format() requires a string pattern, got %s", schemaString(args[0].typ))
+	}
+	if lit, ok := args[0].raw.(*ast.LiteralExpr); ok && lit.Kind == "string" {
+		pieces, err := parseFormatPattern(lit.Str)
+		if err != nil {
+			return nil, err
+		}
+		if n := formatVerbCount(pieces); n != len(args)-1 {
+			return nil, formatArgCountError(n, len(args)-1)
+		}
+		arg := 1
+		for _, p := range pieces {
+			if p.verb == nil {
+				continue
+			}
+			typ := args[arg].typ
+			if !isNullOnly(typ) && !formatVerbAccepts(p.verb.verb, finalizePlanningSchema(typ).Kind) {
+				return nil, formatVerbError(p.verb, arg, schemaString(typ))
+			}
+			arg++
+		}
+	}
+	return nullableSchema(table.TypeString, typedExprSchemas(args)...), nil
+}
+
+func typedFormatEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	if len(args) == 0 {
+		return table.Null(), fmt.Errorf("format() requires a pattern")
+	}
+	values := make([]table.Value, len(args))
+	for i, arg := range args {
+		v, err := evalTypedExpression(arg, ctx)
+		if err != nil {
+			return table.Null(), err
+		}
+		if v.IsNull() {
+			return table.Null(), nil
+		}
+		values[i] = v
+	}
+	if values[0].Type != table.TypeString {
+		return table.Null(), fmt.Errorf("format() requires a string pattern, got %s", valueTypeName(values[0]))
+	}
+	pieces, err := parseFormatPattern(values[0].Str)
+	if err != nil {
+		return table.Null(), err
+	}
+	if n := formatVerbCount(pieces); n != len(values)-1 {
+		return table.Null(), formatArgCountError(n, len(values)-1)
+	}
+	var b strings.Builder
+	arg := 1
+	for _, p := range pieces {
+		b.WriteString(p.text)
+		if p.verb == nil {
+			continue
+		}
+		if err := writeFormatVerb(&b, p.verb, arg, values[arg]); err != nil {
+			return table.Null(), err
+		}
+		arg++
+	}
+	return table.StrVal(b.String()), nil
+}
+
+// writeFormatVerb renders one argument. Values are converted to the Go type
+// the verb expects first, so %s prints them the way dq displays them.
+func writeFormatVerb(b *strings.Builder, verb *formatVerb, arg int, v table.Value) error {
+	if !formatVerbAccepts(verb.verb, v.Type) {
+		return formatVerbError(verb, arg, valueTypeName(v))
+	}
+	switch verb.verb {
+	case 's':
+		fmt.Fprintf(b, verb.spec, v.AsString())
+	case 'd', 'x':
+		fmt.Fprintf(b, verb.spec, v.Int)
+	default:
+		f, _ := v.AsFloat()
+		fmt.Fprintf(b, verb.spec, f)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+func stringFunctionTable() *table.Table {
+	tbl := table.NewTable([]string{"name", "tags", "n", "x"})
+	tbl.AddRow([]table.Value{table.StrVal("José"), table.StrVal("a,b,,c"), table.IntVal(7), table.FloatVal(1.5)})
+	tbl.AddRow([]table.Value{table.StrVal("bob"), table.StrVal(""), table.IntVal(255), table.FloatVal(-0.25)})
+	tbl.AddRow([]table.Value{table.Null(), table.Null(), table.Null(), table.Null()})
+	return tbl
+}
+
+func TestStringFunctions(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		joined = concat(name, "/", tags),
+		piped = name || "!",
+		parts = split(tags, ","),
+		swapped = replace(name, "o", "0"),
+		lp = lpad(name, 6, "*-"),
+		rp = rpad(name, 5),
+		cut = lpad(name, 2),
+		rep = repeat(name, 2),
+		rev = reverse(name),
+		pos = index_of(name, "é"),
+		head = left(name, 2),
+		tail = right(name, 2),
+		all = right(name, 10)
+	| select joined, piped, parts, swapped, lp, rp, cut, rep, rev, pos, head, tail, all`)
+	assertColumnStrings(t, result, "joined", "José/a,b,,c", "bob/", "null")
+	assertColumnStrings(t, result, "piped", "José!", "bob!", "null")
+	assertColumnStrings(t, result, "parts", "[a, b, , c]", "[]", "null")
+	assertColumnStrings(t, result, "swapped", "J0sé", "b0b", "null")
+	assertColumnStrings(t, result, "lp", "*-José", "*-*bob", "null")
+	assertColumnStrings(t, result, "rp", "José ", "bob  ", "null")
+	assertColumnStrings(t, result, "cut", "Jo", "bo", "null")
+	assertColumnStrings(t, result, "rep", "JoséJosé", "bobbob", "null")
+	assertColumnStrings(t, result, "rev", "ésoJ", "bob", "null")
+	assertColumnStrings(t, result, "pos", "3", "-1", "null")
+	assertColumnStrings(t, result, "head", "Jo", "bo", "null")
+	assertColumnStrings(t, result, "tail", "sé", "ob", "null")
+	assertColumnStrings(t, result, "all", "José", "bob", "null")
+	assertColumnSchema(t, result, "piped", "string?")
+	assertColumnSchema(t, result, "parts", "list<string>?")
+	assertColumnSchema(t, result, "pos", "int?")
+
+	literal := runQuery(t, stringFunctionTable(), `transform s = "a" || "b", p = split("x y", " ") | select s, p`)
+	assertColumnSchema(t, literal, "s", "string")
+	assertColumnSchema(t, literal, "p", "list<string>")
+}
+
+func TestFormatFunction(t *testing.T) {
+	result := runQuery(t, stringFunctionTable(), `transform
+		line = format("%s-%d", name, n),
+		padded = format("[%5s|%-4d|%03d]", name, n, n),
+		nums = format("%x %.2f %e %g 100%%", n, x, x, n),
+		plain = format("no verbs"),
+		dynamic = format(tags || "%d", n)
+	| select line, padded, nums, plain, dynamic`)
+	assertColumnStrings(t, result, "line", "José-7", "bob-255", "null")
+	assertColumnStrings(t, result, "padded", "[ José|7   |007]", "[  bob|255 |255]", "null")
+	assertColumnStrings(t, result, "nums", "7 1.50 1.500000e+00 7 100%", "ff -0.25 -2.500000e-01 255 100%", "null")
+	assertColumnStrings(t, result, "plain", "no verbs", "no verbs", "no verbs")
+	assertColumnStrings(t, result, "dynamic", "a,b,,c7", "255", "null")
+	assertColumnSchema(t, result, "line", "string?")
+	assertColumnSchema(t, result, "plain", "string")
+
+	err := runQueryExpectErr(t, stringFunctionTable(), `transform v = format(tags || "%q", n)`)
+	if err == nil || !strings.Contains(err.Error(), "format(): unknown verb %q") {
+		t.Fatalf("runtime pattern error = %v", err)
+	}
+}
+
+func TestStringFunctionErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform v = name || n`, "operator || requires string operands, got string? and int?"},
+		{`transform v = concat()`, "concat() requires at least 1 argument"},
+		{`transform v = concat(name, n)`, "concat() requires strings, got int?"},
+		{`transform v = split(name)`, "split() takes 2 arguments (string, separator), got 1"},
+		{`transform v = replace(name, 1, "x")`, "replace() requires a string search, got int"},
+		{`transform v = lpad(name)`, "lpad() takes 2 or 3 arguments (string, length[, fill]), got 1"},
+		{`transform v = rpad(name, "3")`, "rpad(): length must be an int, got string"},
+		{`transform v = lpad(name, -1)`, "lpad(): length must not be negative"},
+		{`transform v = lpad(name, 9, "")`, "lpad(): fill must not be empty"},
+		{`transform v = repeat(name, 100000000000)`, "repeat(): count 100000000000 is too large"},
+		{`transform v = reverse(n)`, "reverse() requires a string, got int?"},
+		{`transform v = left(name, -2)`, "left(): length must not be negative"},
+		{`transform v = format(n)`, "format() requires a string pattern, got int?"},
+		{`transform v = format("%d", name)`, "format(): %d needs an int for argument 1, got string?"},
+		{`transform v = format("%.1f", name)`, "format(): %.1f needs a number for argument 1, got string?"},
+		{`transform v = format("%s %s", name)`, "format(): pattern has 2 verbs but 1 arguments were given"},
+		{`transform v = format("%v", name)`, "format(): unknown verb %v"},
+		{`transform v = format("50%", n)`, `format(): incomplete verb "%" at end of pattern`},
+		{`transform v = format("%5000s", name)`, "format(): width 5000 is too large (max 1000)"},
+	}
+	for _, tc := range cases {
+		err := runQueryExpectErr(t, stringFunctionTable(), tc.query)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.query, err, tc.want)
+		}
+	}
+}
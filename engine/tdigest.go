@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// defaultTDigestCompression bounds a digest to a few hundred centroids, which
+// keeps approx_percentile() within a fraction of a percent of the exact rank.
+const defaultTDigestCompression = 100
+
+// tdigest is a merging t-digest (Dunning and Ertl). Values are buffered and
+// periodically merged into centroids whose size is capped by the k1 scale
+// function, so centroids near the tails stay small and tail percentiles stay
+// accurate.
+type tdigest struct {
+	compression float64
+	centroids   []tdigestCentroid
+	buffer      []float64
+	weight      float64
+	min         float64
+	max         float64
+}
+
+type tdigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+func newTDigest(compression float64) *tdigest {
+	return &tdigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (d *tdigest) add(x float64) {
+	d.buffer = append(d.buffer, x)
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+	if len(d.buffer) >= int(5*d.compression) {
+		d.compress()
+	}
+}
+
+func (d *tdigest) count() float64 {
+	return d.weight + float64(len(d.buffer))
+}
+
+func (d *tdigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := make([]tdigestCentroid, 0, len(d.centroids)+len(d.buffer))
+	all = append(all, d.centroids...)
+	for _, x := range d.buffer {
+		all = append(all, tdigestCentroid{mean: x, weight: 1})
+	}
+	d.buffer = d.buffer[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	total := 0.0
+	for _, c := range all {
+		total += c.weight
+	}
+	merged := all[:1]
+	before := 0.0
+	for _, next := range all[1:] {
+		cur := &merged[len(merged)-1]
+		q0 := before / total
+		q2 := (before + cur.weight + next.weight) / total
+		if d.scale(q2)-d.scale(q0) <= 1 {
+			cur.weight += next.weight
+			cur.mean += (next.mean - cur.mean) * next.weight / cur.weight
+			continue
+		}
+		before += cur.weight
+		merged = append(merged, next)
+	}
+	d.centroids = merged
+	d.weight = total
+}
+
+// scale is the k1 scale function; a centroid may span at most one unit of k.
+func (d *tdigest) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*math.Min(math.Max(q, 0), 1)-1)
+}
+
+// quantile interpolates between centroid centres placed at the rank of their
+// middle value, with the minimum at rank 0 and the maximum at rank n-1. While
+// every centroid holds one value this is the exact linear-interpolation
+// percentile.
+func (d *tdigest) quantile(q float64) float64 {
+	d.compress()
+	if len(d.centroids) == 0 {
+		return math.NaN()
+	}
+	rank := q * (d.weight - 1)
+	prevRank, prevValue := 0.0, d.min
+	cum := 0.0
+	for _, c := range d.centroids {
+		centre := cum + (c.weight-1)/2
+		if rank <= centre {
+			return interpolateRank(rank, prevRank, prevValue, centre, c.mean)
+		}
+		prevRank, prevValue = centre, c.mean
+		cum += c.weight
+	}
+	return interpolateRank(rank, prevRank, prevValue, d.weight-1, d.max)
+}
+
+func interpolateRank(rank, loRank, loValue, hiRank, hiValue float64) float64 {
+	if hiRank <= loRank {
+		return hiValue
+	}
+	return loValue + (hiValue-loValue)*(rank-loRank)/(hiRank-loRank)
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/razeghi71/dq/table"
+)
+
+const microsPerDay = int64(24 * time.Hour / time.Microsecond)
+
+func isTemporalKind(kind table.ValueType) bool {
+	return kind == table.TypeDate || kind == table.TypeTimestamp || kind == table.TypeDuration
+}
+
+// temporalArithKind returns the result type of op over temporal operands:
+// timestamps and dates shift by durations, subtracting two timestamps or two
+// dates yields a duration, and durations add, subtract and scale by ints.
+func temporalArithKind(op string, left, right table.ValueType) (table.ValueType, bool) {
+	switch op {
+	case "+":
+		switch {
+		case left == table.TypeDuration && right == table.TypeDuration:
+			return table.TypeDuration, true
+		case left == table.TypeTimestamp && right == table.TypeDuration,
+			left == table.TypeDuration && right == table.TypeTimestamp,
+			left == table.TypeDate && right == table.TypeDuration,
+			left == table.TypeDuration && right == table.TypeDate:
+			return table.TypeTimestamp, true
+		}
+	case "-":
+		switch {
+		case left == table.TypeDuration && right == table.TypeDuration,
+			left == table.TypeTimestamp && right == table.TypeTimestamp,
+			left == table.TypeDate && right == table.TypeDate:
+			return table.TypeDuration, true
+		case left == table.TypeTimestamp && right == table.TypeDuration,
+			left == table.TypeDate && right == table.TypeDuration:
+			return table.TypeTimestamp, true
+		}
+	case "*":
+		if left == table.TypeDuration && right == table.TypeInt || left == table.TypeInt && right == table.TypeDuration {
+			return table.TypeDuration, true
+		}
+	}
+	return table.TypeNull, false
+}
+
+func checkTemporalArithSignature(op string, left, right typedExpr) (*table.TypeDescriptor, error) {
+	l := finalizePlanningSchema(left.typ)
+	r := finalizePlanningSchema(right.typ)
+	switch {
+	case isNullOnly(left.typ):
+		return table.WithNullable(r), nil
+	case isNullOnly(right.typ):
+		return table.WithNullable(l), nil
+	}
+	kind, ok := temporalArithKind(op, l.Kind, r.Kind)
+	if !ok {
+		return nil, fmt.Errorf("operator %s is not defined for %s and %s", op, schemaString(left.typ), schemaString(right.typ))
+	}
+	return nullableSchema(kind, left.typ, right.typ), nil
+}
+
+func schemaIsTemporal(schema *table.TypeDescriptor) bool {
+	final := finalizePlanningSchema(schema)
+	return final != nil && isTemporalKind(final.Kind)
+}
+
+func evalTemporalArith(op string, left, right table.Value) (table.Value, error) {
+	kind, ok := temporalArithKind(op, left.Type, right.Type)
+	if !ok {
+		return table.Null(), fmt.Errorf("cannot perform %s on %s and %s", op, table.TypeName(left.Type), table.TypeName(right.Type))
+	}
+	l, r := temporalMicros(left), temporalMicros(right)
+	var (
+		result int64
+		err    error
+	)
+	switch {
+	case op == "*":
+		result, err = evalIntArith(op, left.Int, right.Int)
+	case left.Type == table.TypeDate && right.Type == table.TypeDate:
+		result, err = evalIntArith(op, left.Int, right.Int)
+		if err == nil {
+			result, err = evalIntArith("*", result, microsPerDay)
+		}
+	default:
+		result, err = evalIntArith(op, l, r)
+	}
+	if err != nil {
+		return table.Null(), err
+	}
+	return table.Value{Type: kind, Int: result}, nil
+}
+
+// temporalMicros returns v's int storage in microseconds, widening dates to
+// midnight UTC.
+func temporalMicros(v table.Value) int64 {
+	if v.Type == table.TypeDate {
+		return v.Int * microsPerDay
+	}
+	return v.Int
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+const temporalCSV = "id,day,at,wait\n" +
+	"1,2024-03-01,2024-03-01T10:00:00Z,1h\n" +
+	"2,2024-02-28,2024-03-01 08:30:00,30m\n" +
+	"3,,,\n"
+
+func TestTemporalCSVInferenceAndDescribe(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	result := loadAndQuery(t, path+" with dates=true", "transform wait = duration(wait) | describe")
+	assertDescribeSchemaRows(t, result, map[string]describeSchemaMeta{
+		"id":   {typ: "int", rows: 3, schema: "int"},
+		"day":  {typ: "date", rows: 3, schema: "date?"},
+		"at":   {typ: "timestamp", rows: 3, schema: "timestamp?"},
+		"wait": {typ: "duration", rows: 3, schema: "duration?"},
+	})
+}
+
+func TestTemporalArithmetic(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	result := loadAndQuery(t, path+" with dates=true", `transform
+		later = at + duration(wait),
+		gap = at - timestamp("2024-03-01T00:00:00Z"),
+		days = day - date("2024-02-01"),
+		back = day - duration("12h"),
+		twice = duration(wait) * 2,
+		neg = -duration(wait)
+	| select later, gap, days, back, twice, neg`)
+	assertColumnStrings(t, result, "later", "2024-03-01T11:00:00Z", "2024-03-01T09:00:00Z", "null")
+	assertColumnStrings(t, result, "gap", "10h0m0s", "8h30m0s", "null")
+	assertColumnStrings(t, result, "days", "696h0m0s", "648h0m0s", "null")
+	assertColumnStrings(t, result, "back", "2024-02-29T12:00:00Z", "2024-02-27T12:00:00Z", "null")
+	assertColumnStrings(t, result, "twice", "2h0m0s", "1h0m0s", "null")
+	assertColumnStrings(t, result, "neg", "-1h0m0s", "-30m0s", "null")
+	assertColumnSchema(t, result, "later", "timestamp?")
+	assertColumnSchema(t, result, "days", "duration?")
+}
+
+func TestTemporalComparisonAndSort(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	result := loadAndQuery(t, path+" with dates=true", `filter { day >= date("2024-03-01") or at < timestamp("2024-03-01 09:00:00") } | sort day | select id`)
+	assertColumnStrings(t, result, "id", "2", "1")
+}
+
+func TestTemporalDatePartsAndConstructors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	result := loadAndQuery(t, path+" with dates=true", `transform
+		y = year(day), m = month(at), d = day(at),
+		local = timestamp("2024-03-01 10:00:00", "Europe/Paris"),
+		trunc = date(at)
+	| select y, m, d, local, trunc`)
+	assertColumnStrings(t, result, "y", "2024", "2024", "null")
+	assertColumnStrings(t, result, "m", "3", "3", "null")
+	assertColumnStrings(t, result, "d", "1", "1", "null")
+	assertColumnStrings(t, result, "local", "2024-03-01T09:00:00Z", "2024-03-01T09:00:00Z", "2024-03-01T09:00:00Z")
+	assertColumnStrings(t, result, "trunc", "2024-03-01", "2024-03-01", "null")
+}
+
+func TestTemporalCSVCellsStayStringsByDefault(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	result := loadAndQuery(t, path, `filter { day >= "2024-03-01" } | transform m = substr(day, 5, 2), ok = str_contains(at, "2024") | select day, m, ok`)
+	assertColumnStrings(t, result, "day", "2024-03-01")
+	assertColumnStrings(t, result, "m", "03")
+	assertColumnStrings(t, result, "ok", "true")
+	assertColumnSchema(t, result, "day", "string?")
+}
+
+func TestTemporalTypeErrors(t *testing.T) {
+	path := writeCSVInferenceFile(t, t.TempDir(), "events.csv", temporalCSV)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"transform x = at + at", "operator + is not defined for timestamp? and timestamp?"},
+		{"transform x = day - at", "operator - is not defined for date? and timestamp?"},
+		{"transform x = at * 2", "operator * is not defined for timestamp? and int"},
+		{"filter { day > at }", "date"},
+		{`transform x = duration(1)`, "duration() cannot convert int"},
+		{`transform x = timestamp(id, 1)`, "timestamp() cannot convert int"},
+		{`transform x = date("soon")`, `date(): cannot parse "soon" as a date`},
+		{`transform x = timestamp(at, "Mars/Base")`, `timestamp(): unknown time zone "Mars/Base"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			err := loadAndQueryExpectErr(t, path+" with dates=true", tc.query)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/razeghi71/dq/table"
+)
+
+func execPlannedUnion(p plannedUnion, input *table.Table) (*table.Table, error) {
+	right, err := p.right.source.Load(p.right.spec)
+	if err != nil {
+		return nil, fmt.Errorf("union: load %q: %w", p.filename, err)
+	}
+	if err := validateJoinRightInputSchema(p.right.env, right); err != nil {
+		return nil, fmt.Errorf("union: %w", err)
+	}
+
+	cols, schemas := outputEnvColumns(p.OutputEnv())
+	result := table.NewTableWithSchemas(cols, schemas)
+	if err := appendUnionRows(result, input, p.left, -1, table.Null()); err != nil {
+		return nil, err
+	}
+	if err := appendUnionRows(result, right, p.rightCol, p.tag, table.StrVal(p.filename)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// appendUnionRows copies every row of src into result. sources maps output
+// columns to src columns (-1 for none); the tag column, if any, gets tagValue.
+func appendUnionRows(result, src *table.Table, sources []int, tag int, tagValue table.Value) error {
+	for row := 0; row < src.NumRows; row++ {
+		vals := make([]table.Value, len(sources))
+		for i, col := range sources {
+			switch {
+			case i == tag:
+				vals[i] = tagValue
+			case col < 0:
+				vals[i] = table.Null()
+			default:
+				vals[i] = src.Col(col).Get(row)
+			}
+		}
+		if err := result.AddRowTyped(vals); err != nil {
+			return fmt.Errorf("union: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/table"
+)
+
+// unionArgSchema returns the schema of a union argument, or nil for a null
+// literal.
+func unionArgSchema(name string, typ *table.TypeDescriptor) (*table.TypeDescriptor, error) {
+	if !schemaKindOrNull(typ, table.TypeUnion) {
+		return nil, fmt.Errorf("%s() requires a union, got %s", name, schemaString(typ))
+	}
+	if typ.Kind != table.TypeUnion {
+		return nil, nil
+	}
+	return typ, nil
+}
+
+// unionSelectorArg reads the branch selector of a union function, which must
+// be a string literal.
+func unionSelectorArg(name string, arg typedExpr) (string, error) {
+	lit, ok := arg.raw.(*ast.LiteralExpr)
+	if !ok || lit.Kind != "string" {
+		return "", fmt.Errorf("%s() branch must be a string literal", name)
+	}
+	return lit.Str, nil
+}
+
+// unionBranchLabel names a branch the way union_branch() reports it: by its
+// source name when it has one, otherwise by its schema.
+func unionBranchLabel(branch *table.TypeDescriptor) string {
+	if branch.Name != "" {
+		return branch.Name
+	}
+	return schemaString(table.WithoutNull(branch))
+}
+
+// unionBranchMatches reports whether selector picks branch. A selector is a
+// branch name, with or without its namespace, a branch schema such as
+// "record<x:int>", or a kind such as "record".
+func unionBranchMatches(branch *table.TypeDescriptor, selector string) bool {
+	if branch.Name != "" && (branch.Name == selector || strings.HasSuffix(branch.Name, "."+selector)) {
+		return true
+	}
+	return unionBranchLabel(branch) == selector || table.TypeName(branch.Kind) == selector
+}
+
+// narrowedUnionBranch picks the branch of kind matching selector, or any
+// branch of kind when selector is empty. Exactly one branch must match.
+func narrowedUnionBranch(name string, union *table.TypeDescriptor, kind table.ValueType, selector string) (int, error) {
+	found := -1
+	count := 0
+	for i, branch := range union.Branches {
+		if branch.Kind != kind || selector != "" && !unionBranchMatches(branch, selector) {
+			continue
+		}
+		if found < 0 {
+			found = i
+		}
+		count++
+	}
+	switch {
+	case count == 0 && selector != "":
+		return -1, fmt.Errorf("%s() found no %s branch matching %q in %s", name, table.TypeName(kind), selector, schemaString(union))
+	case count == 0:
+		return -1, fmt.Errorf("%s() found no %s branch in %s", name, table.TypeName(kind), schemaString(union))
+	case count > 1:
+		return -1, fmt.Errorf("%s() matches %d branches of %s; pass a branch name or schema", name, count, schemaString(union))
+	}
+	return found, nil
+}
+
+func checkUnionBranchSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("union_branch() takes 1 argument, got %d", len(args))
+	}
+	if _, err := unionArgSchema("union_branch", args[0].typ); err != nil {
+		return nil, err
+	}
+	return &table.TypeDescriptor{Kind: table.TypeString, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+// checkUnionIsSignature types union_is(). The selector must pick at least one
+// branch, so a misspelt branch fails before any rows are read.
+func checkUnionIsSignature(args []typedExpr) (*table.TypeDescriptor, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("union_is() takes 2 arguments (union, branch), got %d", len(args))
+	}
+	union, err := unionArgSchema("union_is", args[0].typ)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := unionSelectorArg("union_is", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if union != nil {
+		matched := false
+		for _, branch := range union.Branches {
+			matched = matched || unionBranchMatches(branch, selector)
+		}
+		if !matched {
+			return nil, fmt.Errorf("union_is() branch %q matches no branch of %s", selector, schemaString(union))
+		}
+	}
+	return &table.TypeDescriptor{Kind: table.TypeBool, Nullable: anySchemaMayBeNull(args[0].typ)}, nil
+}
+
+// checkUnionNarrowSignature types the as_<kind>() functions, which return the
+// value of one branch and null when a row holds another.
+func checkUnionNarrowSignature(name string, kind table.ValueType) func([]typedExpr) (*table.TypeDescriptor, error) {
+	return func(args []typedExpr) (*table.TypeDescriptor, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, arityRangeError(name, 1, 2, len(args))
+		}
+		union, err := unionArgSchema(name, args[0].typ)
+		if err != nil {
+			return nil, err
+		}
+		selector := ""
+		if len(args) == 2 {
+			if selector, err = unionSelectorArg(name, args[1]); err != nil {
+				return nil, err
+			}
+		}
+		if union == nil {
+			return &table.TypeDescriptor{Kind: table.TypeNull, Nullable: true}, nil
+		}
+		i, err := narrowedUnionBranch(name, union, kind, selector)
+		if err != nil {
+			return nil, err
+		}
+		out := table.WithNullable(union.Branches[i])
+		out.Name = ""
+		return out, nil
+	}
+}
+
+// evalUnionArg evaluates the union argument of a union function and finds the
+// branch it holds. ok is false when it is null, which makes the call null.
+func evalUnionArg(arg typedExpr, ctx *EvalContext) (table.Value, int, bool, error) {
+	v, err := evalTypedExpression(arg, ctx)
+	if err != nil || v.IsNull() || arg.typ.Kind != table.TypeUnion {
+		return table.Null(), -1, false, err
+	}
+	i := table.UnionBranch(arg.typ, v)
+	return v, i, i >= 0, nil
+}
+
+func typedUnionBranchEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	_, i, ok, err := evalUnionArg(args[0], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	return table.StrVal(unionBranchLabel(args[0].typ.Branches[i])), nil
+}
+
+func typedUnionIsEval(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+	_, i, ok, err := evalUnionArg(args[0], ctx)
+	if err != nil || !ok {
+		return table.Null(), err
+	}
+	selector, err := unionSelectorArg("union_is", args[1])
+	if err != nil {
+		return table.Null(), err
+	}
+	return table.BoolVal(unionBranchMatches(args[0].typ.Branches[i], selector)), nil
+}
+
+func typedUnionNarrowEval(name string, kind table.ValueType) typedCallEvaluator {
+	return func(args []typedExpr, ctx *EvalContext) (table.Value, error) {
+		v, i, ok, err := evalUnionArg(args[0], ctx)
+		if err != nil || !ok {
+			return table.Null(), err
+		}
+		selector := ""
+		if len(args) == 2 {
+			if selector, err = unionSelectorArg(name, args[1]); err != nil {
+				return table.Null(), err
+			}
+		}
+		want, err := narrowedUnionBranch(name, args[0].typ, kind, selector)
+		if err != nil || i != want {
+			return table.Null(), err
+		}
+		return v.WithBranch(""), nil
+	}
+}
+
+func unionNarrowBuiltin(name string, kind table.ValueType) builtinSpec {
+	return scalarBuiltin(name, checkUnionNarrowSignature(name, kind), typedUnionNarrowEval(name, kind))
+}
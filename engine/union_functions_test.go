@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/razeghi71/dq/table"
+)
+
+// eventUnionTable holds a union column with two structurally identical named
+// record branches, the way an Avro file loaded with union_names=true does.
+func eventUnionTable(t *testing.T) *table.Table {
+	t.Helper()
+	schema, err := table.ParseSchema("union<ev.Click:record<x:int>,ev.View:record<x:int>,int>?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := func(branch string, x int64) table.Value {
+		return table.RecordVal([]table.RecordField{{Name: "x", Value: table.IntVal(x)}}).WithBranch(branch)
+	}
+	tbl := table.NewTableWithSchemas([]string{"e"}, []*table.TypeDescriptor{schema})
+	for _, v := range []table.Value{rec("ev.View", 1), rec("ev.Click", 2), table.IntVal(3), table.Null()} {
+		if err := tbl.AddRowTyped([]table.Value{v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tbl
+}
+
+func TestUnionBranchHelpers(t *testing.T) {
+	result := runQuery(t, eventUnionTable(t), `transform
+		branch = union_branch(e),
+		is_click = union_is(e, "Click"),
+		is_record = union_is(e, "record"),
+		click = as_record(e, "Click"),
+		view = as_record(e, "ev.View"),
+		n = as_int(e)
+	| transform click_x = click.x
+	| select branch, is_click, is_record, click_x, view, n`)
+	assertColumnStrings(t, result, "branch", "ev.View", "ev.Click", "int", "null")
+	assertColumnStrings(t, result, "is_click", "false", "true", "false", "null")
+	assertColumnStrings(t, result, "is_record", "true", "true", "false", "null")
+	assertColumnStrings(t, result, "click_x", "null", "2", "null", "null")
+	assertColumnStrings(t, result, "view", "{x:1}", "null", "null", "null")
+	assertColumnStrings(t, result, "n", "null", "null", "3", "null")
+	assertColumnSchema(t, result, "branch", "string?")
+	assertColumnSchema(t, result, "is_click", "bool?")
+	assertColumnSchema(t, result, "click_x", "int?")
+	assertColumnSchema(t, result, "view", "record<x:int>?")
+	assertColumnSchema(t, result, "n", "int?")
+}
+
+func TestUnionBranchTagsSurviveFilterAndCopies(t *testing.T) {
+	result := runQuery(t, eventUnionTable(t), `filter { union_is(e, "record") }
+		| transform copy = e
+		| select copy`)
+	assertColumnSchema(t, result, "copy", "union<ev.Click:record<x:int>,ev.View:record<x:int>,int>?")
+	for row, want := range []string{"ev.View", "ev.Click"} {
+		if got := result.Get(row, "copy").Branch(); got != want {
+			t.Errorf("row %d branch: got %q, want %q", row+1, got, want)
+		}
+	}
+}
+
+func TestUnionFunctionsOnUnnamedBranches(t *testing.T) {
+	schema, err := table.ParseSchema("union<int,string>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl := table.NewTableWithSchemas([]string{"u"}, []*table.TypeDescriptor{schema})
+	for _, v := range []table.Value{table.IntVal(7), table.StrVal("seven")} {
+		if err := tbl.AddRowTyped([]table.Value{v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result := runQuery(t, tbl, `transform b = union_branch(u), s = as_string(u), i = union_is(u, "int") | select b, s, i`)
+	assertColumnStrings(t, result, "b", "int", "string")
+	assertColumnStrings(t, result, "s", "null", "seven")
+	assertColumnStrings(t, result, "i", "true", "false")
+}
+
+func TestUnionFunctionErrors(t *testing.T) {
+	tbl := eventUnionTable(t)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`transform a = e.x`, "cannot access fields through union schema"},
+		{`transform a = as_record(e)`, "as_record() matches 2 branches of union<ev.Click:record<x:int>,ev.View:record<x:int>,int>?; pass a branch name or schema"},
+		{`transform a = as_record(e, "Buy")`, `as_record() found no record branch matching "Buy"`},
+		{`transform a = as_string(e)`, "as_string() found no string branch in union<"},
+		{`transform a = as_int(e, e)`, "as_int() branch must be a string literal"},
+		{`transform a = as_int(as_int(e))`, "as_int() requires a union, got int?"},
+		{`transform a = union_is(e, "string")`, `union_is() branch "string" matches no branch of union<`},
+		{`transform a = union_branch()`, "union_branch() takes 1 argument, got 0"},
+	}
+	for _, tc := range cases {
+		expectQueryErrContains(t, tbl, tc.query, tc.want)
+	}
+}
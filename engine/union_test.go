@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/ast"
+	"github.com/razeghi71/dq/parser"
+	"github.com/razeghi71/dq/table"
+)
+
+func unionOrdersTable() *table.Table {
+	t := table.NewTable([]string{"id", "amount", "region"})
+	t.AddRow([]table.Value{table.IntVal(1), table.IntVal(10), table.StrVal("eu")})
+	t.AddRow([]table.Value{table.IntVal(2), table.IntVal(20), table.StrVal("us")})
+	return t
+}
+
+func unionLoadFunc(sources map[string]*table.Table) LoadFunc {
+	return func(filename string, _ ast.LoadOptions) (*table.Table, error) {
+		tbl, ok := sources[filename]
+		if !ok {
+			return nil, fmt.Errorf("no such file %q", filename)
+		}
+		return tbl, nil
+	}
+}
+
+func runUnionQuery(t *testing.T, query string, sources map[string]*table.Table) *table.Table {
+	t.Helper()
+	q, err := parser.Parse("test.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	result, err := Execute(q, unionOrdersTable(), unionLoadFunc(sources))
+	if err != nil {
+		t.Fatalf("exec error: %v", err)
+	}
+	return result
+}
+
+func TestUnionByNameAlignsColumns(t *testing.T) {
+	more := table.NewTable([]string{"region", "id", "note"})
+	more.AddRow([]table.Value{table.StrVal("apac"), table.IntVal(3), table.StrVal("late")})
+
+	result := runUnionQuery(t, "union more.csv", map[string]*table.Table{"more.csv": more})
+	if strings.Join(result.Columns, ",") != "id,amount,region,note" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "id", "1", "2", "3")
+	assertColumnStrings(t, result, "amount", "10", "20", "null")
+	assertColumnStrings(t, result, "region", "eu", "us", "apac")
+	assertColumnStrings(t, result, "note", "null", "null", "late")
+	assertColumnSchema(t, result, "id", "int")
+	assertColumnSchema(t, result, "amount", "int?")
+	assertColumnSchema(t, result, "note", "string?")
+}
+
+func TestUnionWidensNumericAndMismatchedTypes(t *testing.T) {
+	more := table.NewTable([]string{"id", "amount", "region"})
+	more.AddRow([]table.Value{table.StrVal("x3"), table.FloatVal(2.5), table.StrVal("eu")})
+
+	result := runUnionQuery(t, "concat more.csv", map[string]*table.Table{"more.csv": more})
+	assertColumnStrings(t, result, "amount", "10", "20", "2.5")
+	assertColumnSchema(t, result, "amount", "float")
+	assertColumnStrings(t, result, "id", "1", "2", "x3")
+	assertColumnSchema(t, result, "id", "union<int,string>")
+}
+
+func TestUnionByPosition(t *testing.T) {
+	more := table.NewTable([]string{"k", "v", "r"})
+	more.AddRow([]table.Value{table.IntVal(3), table.IntVal(30), table.StrVal("apac")})
+
+	result := runUnionQuery(t, "union more.csv by position", map[string]*table.Table{"more.csv": more})
+	if strings.Join(result.Columns, ",") != "id,amount,region" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "amount", "10", "20", "30")
+	assertColumnStrings(t, result, "region", "eu", "us", "apac")
+}
+
+func TestUnionTagColumn(t *testing.T) {
+	more := table.NewTable([]string{"id"})
+	more.AddRow([]table.Value{table.IntVal(3)})
+	sources := map[string]*table.Table{"more.csv": more}
+
+	result := runUnionQuery(t, "union more.csv tag src", sources)
+	assertColumnStrings(t, result, "src", "null", "null", "more.csv")
+	assertColumnSchema(t, result, "src", "string?")
+
+	result = runUnionQuery(t, `transform src = "orders.csv" | union more.csv tag src | select id, src`, sources)
+	assertColumnStrings(t, result, "src", "orders.csv", "orders.csv", "more.csv")
+	assertColumnSchema(t, result, "src", "string")
+}
+
+func TestUnionDemandPruning(t *testing.T) {
+	more := table.NewTable([]string{"id", "amount", "extra"})
+	more.AddRow([]table.Value{table.IntVal(3), table.IntVal(30), table.BoolVal(true)})
+	sources := map[string]*table.Table{"more.csv": more}
+
+	result := runUnionQuery(t, "union more.csv | select amount", sources)
+	assertColumnStrings(t, result, "amount", "10", "20", "30")
+
+	result = runUnionQuery(t, "union more.csv | count", sources)
+	assertColumnStrings(t, result, "count", "3")
+
+	result = runUnionQuery(t, "union more.csv tag src | count", sources)
+	assertColumnStrings(t, result, "count", "3")
+}
+
+func TestUnionStreamingMatchesMaterialized(t *testing.T) {
+	more := table.NewTable([]string{"id", "amount"})
+	more.AddRow([]table.Value{table.IntVal(3), table.IntVal(30)})
+	sources := map[string]*table.Table{"more.csv": more}
+	query := "filter { amount > 10 } | union more.csv tag src | select id, src"
+
+	q, err := parser.Parse("test.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	streamed, err := ExecuteStreaming(q, unionOrdersTable(), unionLoadFunc(sources))
+	if err != nil {
+		t.Fatalf("streaming error: %v", err)
+	}
+	materialized := runUnionQuery(t, query, sources)
+	if streamed.String() != materialized.String() {
+		t.Fatalf("streaming result differs:\n%s\nvs\n%s", streamed.String(), materialized.String())
+	}
+}
+
+func TestUnionPlanningErrors(t *testing.T) {
+	more := table.NewTable([]string{"id", "src"})
+	sources := map[string]*table.Table{"more.csv": more}
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"union missing.csv", `union: load "missing.csv"`},
+		{"union more.csv by position", `union by position: pipeline has 3 columns but "more.csv" has 2`},
+		{"union more.csv tag src", `union: tag column "src" already exists in "more.csv"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := parser.Parse("test.csv | " + tc.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, err = Execute(q, unionOrdersTable(), unionLoadFunc(sources))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+
+	q, err := parser.Parse("test.csv | union more.csv")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := Execute(q, unionOrdersTable(), nil); err == nil || !strings.Contains(err.Error(), "union: source preparer not configured") {
+		t.Fatalf("error = %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/razeghi71/dq/table"
+)
+
+// windowPartition is one partition of a window stage. rows holds input row
+// indexes in window order; orderKeys holds the matching order key values and
+// is nil when the window has no order clause.
+type windowPartition struct {
+	rows      []int
+	orderKeys [][]table.Value
+}
+
+// peerOfPrevious reports whether the row at position i has the same order
+// key values as the row before it.
+func (p windowPartition) peerOfPrevious(i int) bool {
+	if i == 0 || p.orderKeys == nil {
+		return false
+	}
+	for j := range p.orderKeys[i] {
+		if compareValues(p.orderKeys[i][j], p.orderKeys[i-1][j]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func evalWindowRowNumber(partition windowPartition, _ []rowValueEvaluator, out []table.Value) error {
+	for i, row := range partition.rows {
+		out[row] = table.IntVal(int64(i + 1))
+	}
+	return nil
+}
+
+func evalWindowRank(partition windowPartition, _ []rowValueEvaluator, out []table.Value) error {
+	rank := int64(0)
+	for i, row := range partition.rows {
+		if !partition.peerOfPrevious(i) {
+			rank = int64(i + 1)
+		}
+		out[row] = table.IntVal(rank)
+	}
+	return nil
+}
+
+// windowOffsetEval builds lag (direction -1) and lead (direction 1). The
+// optional second argument is the row offset and the third is the value used
+// when the offset row falls outside the partition.
+func windowOffsetEval(name string, direction int) func(windowPartition, []rowValueEvaluator, []table.Value) error {
+	return func(partition windowPartition, args []rowValueEvaluator, out []table.Value) error {
+		for i, row := range partition.rows {
+			offset := int64(1)
+			if len(args) >= 2 {
+				v, err := args[1](row)
+				if err != nil {
+					return err
+				}
+				if v.Type != table.TypeInt {
+					return fmt.Errorf("%s() offset must be an int, got %s", name, table.TypeName(v.Type))
+				}
+				if v.Int < 0 {
+					return fmt.Errorf("%s() offset must not be negative, got %d", name, v.Int)
+				}
+				offset = v.Int
+			}
+			target := int64(i) + int64(direction)*offset
+			if target >= 0 && target < int64(len(partition.rows)) {
+				v, err := args[0](partition.rows[target])
+				if err != nil {
+					return err
+				}
+				out[row] = v
+				continue
+			}
+			if len(args) < 3 {
+				out[row] = table.Null()
+				continue
+			}
+			v, err := args[2](row)
+			if err != nil {
+				return err
+			}
+			out[row] = v
+		}
+		return nil
+	}
+}
+
+func evalWindowRunningSum(partition windowPartition, args []rowValueEvaluator, out []table.Value) error {
+	acc, err := newSumAccumulator(args)
+	if err != nil {
+		return err
+	}
+	for _, row := range partition.rows {
+		if err := acc.Update(row); err != nil {
+			return err
+		}
+		v, err := acc.Finalize()
+		if err != nil {
+			return err
+		}
+		out[row] = v
+	}
+	return nil
+}
+
+func execPlannedWindow(p plannedWindow, input *table.Table) (*table.Table, error) {
+	partitions, err := windowPartitions(p, input)
+	if err != nil {
+		return nil, err
+	}
+
+	computed := make([][]table.Value, len(p.assignments))
+	for i, assignment := range p.assignments {
+		args := make([]rowValueEvaluator, len(assignment.args))
+		for j, arg := range assignment.args {
+			args[j] = compileTypedRowValue(arg, input)
+		}
+		values := make([]table.Value, input.NumRows)
+		for _, partition := range partitions {
+			if err := assignment.window.Eval(partition, args, values); err != nil {
+				return nil, fmt.Errorf("window %q: %w", assignment.name, err)
+			}
+		}
+		computed[i] = values
+	}
+
+	cols, schemas := outputEnvColumns(p.OutputEnv())
+	targets := windowAssignmentTargets(p.assignments)
+	result := table.NewTableWithSchemas(cols, schemas)
+	for row := 0; row < input.NumRows; row++ {
+		vals := make([]table.Value, len(cols))
+		for col := 0; col < len(input.Columns); col++ {
+			vals[col] = input.Col(col).Get(row)
+		}
+		for col := len(input.Columns); col < len(cols); col++ {
+			vals[col] = table.Null()
+		}
+		for i, assignment := range p.assignments {
+			vals[assignment.target] = computed[i][row]
+		}
+		if err := result.AddRowTypedColumns(vals, targets); err != nil {
+			return nil, fmt.Errorf("window: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// windowPartitions splits input rows into partitions in first-seen order and
+// sorts each partition by the window order keys. Rows with equal order keys
+// keep their input order.
+func windowPartitions(p plannedWindow, input *table.Table) ([]windowPartition, error) {
+	var partitions []windowPartition
+	keyMap := make(map[string]int)
+	for row := 0; row < input.NumRows; row++ {
+		idx := 0
+		if len(p.partition) > 0 {
+			keyParts := make([]string, len(p.partition))
+			for i, col := range p.partition {
+				v, err := resolveBoundColumn(col, input, row)
+				if err != nil {
+					return nil, fmt.Errorf("window by %q: %w", strings.Join(col.rawPath, "."), err)
+				}
+				keyParts[i] = table.CanonicalKey(v)
+			}
+			key := canonicalTupleKey(keyParts)
+			var ok bool
+			idx, ok = keyMap[key]
+			if !ok {
+				idx = len(partitions)
+				keyMap[key] = idx
+				partitions = append(partitions, windowPartition{})
+			}
+		} else if len(partitions) == 0 {
+			partitions = append(partitions, windowPartition{})
+		}
+		partitions[idx].rows = append(partitions[idx].rows, row)
+	}
+
+	if len(p.order) == 0 {
+		return partitions, nil
+	}
+	for i := range partitions {
+		if err := sortWindowPartition(&partitions[i], p.order, input); err != nil {
+			return nil, err
+		}
+	}
+	return partitions, nil
+}
+
+func sortWindowPartition(partition *windowPartition, keys []plannedSortKey, input *table.Table) error {
+	orderKeys := make([][]table.Value, len(partition.rows))
+	for i, row := range partition.rows {
+		orderKeys[i] = make([]table.Value, len(keys))
+		for j, key := range keys {
+			v, err := resolveBoundColumn(key.column, input, row)
+			if err != nil {
+				return fmt.Errorf("window order %q: %w", strings.Join(key.column.rawPath, "."), err)
+			}
+			orderKeys[i][j] = v
+		}
+	}
+
+	perm := make([]int, len(partition.rows))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(a, b int) bool {
+		for j, key := range keys {
+			left := orderKeys[perm[a]][j]
+			right := orderKeys[perm[b]][j]
+			cmp := compareValues(left, right)
+			if cmp != 0 {
+				if left.IsNull() || right.IsNull() {
+					return cmp < 0
+				}
+				if key.desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+		}
+		return false
+	})
+
+	rows := make([]int, len(perm))
+	sortedKeys := make([][]table.Value, len(perm))
+	for i, idx := range perm {
+		rows[i] = partition.rows[idx]
+		sortedKeys[i] = orderKeys[idx]
+	}
+	partition.rows = rows
+	partition.orderKeys = sortedKeys
+	return nil
+}
+
+func windowAssignmentTargets(assignments []plannedWindowAssignment) []int {
+	targets := make([]int, len(assignments))
+	for i, assignment := range assignments {
+		targets[i] = assignment.target
+	}
+	return targets
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razeghi71/dq/parser"
+	"github.com/razeghi71/dq/table"
+)
+
+func windowEventsTable() *table.Table {
+	t := table.NewTable([]string{"user", "ts", "amount"})
+	t.AddRow([]table.Value{table.StrVal("a"), table.IntVal(3), table.IntVal(10)})
+	t.AddRow([]table.Value{table.StrVal("b"), table.IntVal(1), table.IntVal(5)})
+	t.AddRow([]table.Value{table.StrVal("a"), table.IntVal(1), table.IntVal(7)})
+	t.AddRow([]table.Value{table.StrVal("a"), table.IntVal(2), table.IntVal(7)})
+	t.AddRow([]table.Value{table.StrVal("b"), table.IntVal(2), table.IntVal(1)})
+	return t
+}
+
+func TestWindowKeepsInputRowOrder(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts n = row_number()")
+	assertColumnStrings(t, result, "user", "a", "b", "a", "a", "b")
+	assertColumnStrings(t, result, "n", "3", "1", "1", "2", "2")
+}
+
+func TestWindowLagAndLead(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts prev = lag(ts), next = lead(amount), back2 = lag(amount, 2, -1)")
+	assertColumnStrings(t, result, "prev", "2", "null", "null", "1", "1")
+	assertColumnStrings(t, result, "next", "null", "1", "7", "10", "null")
+	assertColumnStrings(t, result, "back2", "7", "-1", "-1", "-1", "-1")
+
+	assertColumnSchema(t, result, "prev", "int?")
+	assertColumnSchema(t, result, "back2", "int")
+}
+
+func TestWindowRankSharesPeers(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window order -amount r = rank(), n = row_number()")
+	assertColumnStrings(t, result, "r", "1", "4", "2", "2", "5")
+	assertColumnStrings(t, result, "n", "1", "4", "2", "3", "5")
+}
+
+func TestWindowRunningSum(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts total = running_sum(amount)")
+	assertColumnStrings(t, result, "total", "24", "5", "7", "14", "6")
+	assertColumnSchema(t, result, "total", "int")
+}
+
+func TestWindowRunningSumSkipsNulls(t *testing.T) {
+	input := table.NewTable([]string{"ts", "v"})
+	input.AddRow([]table.Value{table.IntVal(1), table.Null()})
+	input.AddRow([]table.Value{table.IntVal(2), table.FloatVal(1.5)})
+	input.AddRow([]table.Value{table.IntVal(3), table.Null()})
+	result := runQuery(t, input, "window order ts total = running_sum(v)")
+	assertColumnStrings(t, result, "total", "null", "1.5", "1.5")
+	assertColumnSchema(t, result, "total", "float?")
+}
+
+func TestWindowArgumentsAcceptRowExpressions(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts prev = lag(amount * 2) | select prev")
+	assertColumnStrings(t, result, "prev", "14", "null", "null", "14", "10")
+}
+
+func TestWindowAssignmentCanReplaceColumn(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts amount = running_sum(amount)")
+	if len(result.Columns) != 3 {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "amount", "24", "5", "7", "14", "6")
+}
+
+func TestWindowPlanningErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"transform n = row_number()", `window function "row_number" can only be used inside 'window'`},
+		{"group user | reduce n = row_number()", `non-aggregate function "row_number" in reduce context`},
+		{"window n = upper(user)", `function "upper" is not a window function`},
+		{"window n = ts + 1", "window assignment must call a window function"},
+		{"window n = sum(amount)", `function "sum" is not a window function`},
+		{"window by missing n = row_number()", `column "missing" not found`},
+		{"window order user, missing n = row_number()", `window order "missing"`},
+		{"window n = row_number(ts)", "row_number() takes no arguments, got 1"},
+		{"window n = lag()", "lag() takes 1 to 3 arguments, got 0"},
+		{`window n = lag(ts, "1")`, "lag() offset must be a non-null int"},
+		{`window n = lead(ts, 1, "none")`, "lead() default must match the value type"},
+		{"window n = running_sum(user)", "running_sum() requires a numeric column"},
+		{"window n = rank(), n = row_number()", `window target "n" assigned more than once`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := parser.Parse("test.csv | " + tc.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, err = Execute(q, windowEventsTable(), nil)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error = %v, want substring %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWindowNegativeOffsetFailsAtRuntime(t *testing.T) {
+	q, err := parser.Parse("test.csv | window order ts n = lag(ts, 0 - 1)")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := Execute(q, windowEventsTable(), nil); err == nil || !strings.Contains(err.Error(), "lag() offset must not be negative") {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+func TestWindowStreamingMatchesMaterialized(t *testing.T) {
+	query := "filter { amount > 1 } | window by user order -ts r = rank(), prev = lag(amount) | select user, ts, r, prev"
+	q, err := parser.Parse("test.csv | " + query)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	streamed, err := ExecuteStreaming(q, windowEventsTable(), nil)
+	if err != nil {
+		t.Fatalf("streaming error: %v", err)
+	}
+	materialized := runQuery(t, windowEventsTable(), query)
+	if streamed.String() != materialized.String() {
+		t.Fatalf("streaming result differs:\n%s\nvs\n%s", streamed.String(), materialized.String())
+	}
+}
+
+func TestWindowDemandPruningDropsUnusedAssignments(t *testing.T) {
+	result := runQuery(t, windowEventsTable(), "window by user order ts n = row_number(), total = running_sum(amount) | select user, n")
+	if strings.Join(result.Columns, ",") != "user,n" {
+		t.Fatalf("columns: got %v", result.Columns)
+	}
+	assertColumnStrings(t, result, "n", "3", "1", "1", "2", "2")
+
+	result = runQuery(t, windowEventsTable(), "window by user order ts n = row_number() | select user")
+	assertColumnStrings(t, result, "user", "a", "b", "a", "a", "b")
+}
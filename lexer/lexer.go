@@ -11,26 +11,31 @@ type TokenType uint8
 
 const (
 	// Structural
-	TokenPipe   TokenType = iota // |
-	TokenLBrace                  // {
-	TokenRBrace                  // }
-	TokenLParen                  // (
-	TokenRParen                  // )
-	TokenComma                   // ,
-	TokenEquals                  // = (assignment)
-	TokenDot                     // .
+	TokenPipe     TokenType = iota // |
+	TokenLBrace                    // {
+	TokenRBrace                    // }
+	TokenLParen                    // (
+	TokenRParen                    // )
+	TokenComma                     // ,
+	TokenEquals                    // = (assignment)
+	TokenDot                       // .
+	TokenLBracket                  // [
+	TokenRBracket                  // ]
 
 	// Operators
-	TokenPlus  // +
-	TokenMinus // -
-	TokenStar  // *
-	TokenSlash // /
-	TokenEq    // ==
-	TokenNeq   // !=
-	TokenLt    // <
-	TokenGt    // >
-	TokenLte   // <=
-	TokenGte   // >=
+	TokenPlus    // +
+	TokenMinus   // -
+	TokenStar    // *
+	TokenSlash   // /
+	TokenPercent // %
+	TokenConcat  // ||
+	TokenEq      // ==
+	TokenNeq     // !=
+	TokenLt      // <
+	TokenGt      // >
+	TokenLte     // <=
+	TokenGte     // >=
+	TokenArrow   // -> (lambda)
 
 	// Keywords / logical
 	TokenAnd   // and
@@ -59,9 +64,9 @@ const (
 
 var tokenNames = map[TokenType]string{
 	TokenPipe: "|", TokenLBrace: "{", TokenRBrace: "}", TokenLParen: "(", TokenRParen: ")",
-	TokenComma: ",", TokenEquals: "=", TokenDot: ".",
-	TokenPlus: "+", TokenMinus: "-", TokenStar: "*", TokenSlash: "/",
-	TokenEq: "==", TokenNeq: "!=", TokenLt: "<", TokenGt: ">", TokenLte: "<=", TokenGte: ">=",
+	TokenComma: ",", TokenEquals: "=", TokenDot: ".", TokenLBracket: "[", TokenRBracket: "]",
+	TokenPlus: "+", TokenMinus: "-", TokenStar: "*", TokenSlash: "/", TokenPercent: "%", TokenConcat: "||",
+	TokenEq: "==", TokenNeq: "!=", TokenLt: "<", TokenGt: ">", TokenLte: "<=", TokenGte: ">=", TokenArrow: "->",
 	TokenAnd: "and", TokenOr: "or", TokenNot: "not", TokenIs: "is",
 	TokenTrue: "true", TokenFalse: "false", TokenNull: "null", TokenAs: "as", TokenWith: "with",
 	TokenInt: "INT", TokenFloat: "FLOAT", TokenString: "STRING",
@@ -159,9 +164,9 @@ func (l *Lexer) isNegativeContext() bool {
 		return true
 	}
 	switch l.prev {
-	case TokenLParen, TokenComma, TokenEquals, TokenPipe, TokenLBrace,
-		TokenPlus, TokenMinus, TokenStar, TokenSlash,
-		TokenEq, TokenNeq, TokenLt, TokenGt, TokenLte, TokenGte,
+	case TokenLParen, TokenComma, TokenEquals, TokenPipe, TokenLBrace, TokenLBracket,
+		TokenPlus, TokenMinus, TokenStar, TokenSlash, TokenPercent, TokenConcat,
+		TokenEq, TokenNeq, TokenLt, TokenGt, TokenLte, TokenGte, TokenArrow,
 		TokenAnd, TokenOr, TokenNot, TokenWith:
 		return true
 	}
@@ -189,6 +194,10 @@ func (l *Lexer) nextASCII() (Token, error) {
 		pos := l.pos
 		switch ch {
 		case '|':
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '|' {
+				l.pos += 2
+				return l.emit(asciiToken(TokenConcat, "||", pos, l.pos)), nil
+			}
 			l.pos++
 			return l.emit(asciiToken(TokenPipe, "|", pos, l.pos)), nil
 		case '{':
@@ -209,10 +218,20 @@ func (l *Lexer) nextASCII() (Token, error) {
 		case '.':
 			l.pos++
 			return l.emit(asciiToken(TokenDot, ".", pos, l.pos)), nil
+		case '[':
+			l.pos++
+			return l.emit(asciiToken(TokenLBracket, "[", pos, l.pos)), nil
+		case ']':
+			l.pos++
+			return l.emit(asciiToken(TokenRBracket, "]", pos, l.pos)), nil
 		case '+':
 			l.pos++
 			return l.emit(asciiToken(TokenPlus, "+", pos, l.pos)), nil
 		case '-':
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '>' {
+				l.pos += 2
+				return l.emit(asciiToken(TokenArrow, "->", pos, l.pos)), nil
+			}
 			if l.pos+1 < len(l.input) && asciiIsDigit(l.input[l.pos+1]) && l.isNegativeContext() {
 				tok, newPos, err := lexASCIINumber(l.input, l.pos)
 				if err != nil {
@@ -235,6 +254,9 @@ func (l *Lexer) nextASCII() (Token, error) {
 			}
 			l.pos++
 			return l.emit(asciiToken(TokenSlash, "/", pos, l.pos)), nil
+		case '%':
+			l.pos++
+			return l.emit(asciiToken(TokenPercent, "%", pos, l.pos)), nil
 		case '=':
 			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '=' {
 				l.pos += 2
@@ -323,6 +345,10 @@ func (l *Lexer) nextUnicode() (Token, error) {
 		pos := l.pos
 		switch ch {
 		case '|':
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '|' {
+				l.pos += 2
+				return l.emit(asciiToken(TokenConcat, "||", pos, l.pos)), nil
+			}
 			l.pos += width
 			return l.emit(asciiToken(TokenPipe, "|", pos, l.pos)), nil
 		case '{':
@@ -343,10 +369,20 @@ func (l *Lexer) nextUnicode() (Token, error) {
 		case '.':
 			l.pos += width
 			return l.emit(asciiToken(TokenDot, ".", pos, l.pos)), nil
+		case '[':
+			l.pos += width
+			return l.emit(asciiToken(TokenLBracket, "[", pos, l.pos)), nil
+		case ']':
+			l.pos += width
+			return l.emit(asciiToken(TokenRBracket, "]", pos, l.pos)), nil
 		case '+':
 			l.pos += width
 			return l.emit(asciiToken(TokenPlus, "+", pos, l.pos)), nil
 		case '-':
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '>' {
+				l.pos += 2
+				return l.emit(asciiToken(TokenArrow, "->", pos, l.pos)), nil
+			}
 			if l.pos+1 < len(l.input) {
 				next, _ := utf8.DecodeRuneInString(l.input[l.pos+1:])
 				if unicode.IsDigit(next) && l.isNegativeContext() {
@@ -372,6 +408,9 @@ func (l *Lexer) nextUnicode() (Token, error) {
 			}
 			l.pos += width
 			return l.emit(asciiToken(TokenSlash, "/", pos, l.pos)), nil
+		case '%':
+			l.pos += width
+			return l.emit(asciiToken(TokenPercent, "%", pos, l.pos)), nil
 		case '=':
 			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '=' {
 				l.pos += 2
@@ -122,13 +122,13 @@ func TestLexNegativeNumber(t *testing.T) {
 }
 
 func TestLexOperators(t *testing.T) {
-	tokens, err := Lex("== != <= >= < > + - * /")
+	tokens, err := Lex("== != <= >= < > + - * / % || ->")
 	if err != nil {
 		t.Fatal(err)
 	}
 	expected := []TokenType{
 		TokenEq, TokenNeq, TokenLte, TokenGte, TokenLt, TokenGt,
-		TokenPlus, TokenMinus, TokenStar, TokenSlash, TokenEOF,
+		TokenPlus, TokenMinus, TokenStar, TokenSlash, TokenPercent, TokenConcat, TokenArrow, TokenEOF,
 	}
 	if len(tokens) != len(expected) {
 		t.Fatalf("expected %d tokens, got %d", len(expected), len(tokens))
@@ -140,6 +140,46 @@ func TestLexOperators(t *testing.T) {
 	}
 }
 
+func TestLexLambdaArrow(t *testing.T) {
+	for _, input := range []string{"x->-1", "x -> -1", "ä -> -1"} {
+		tokens, err := Lex(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []TokenType{TokenIdent, TokenArrow, TokenInt, TokenEOF}
+		if len(tokens) != len(expected) {
+			t.Fatalf("%q: expected %d tokens, got %d", input, len(expected), len(tokens))
+		}
+		for i, tt := range expected {
+			if tokens[i].Type != tt {
+				t.Errorf("%q token %d: expected %s, got %s", input, i, tt, tokens[i].Type)
+			}
+		}
+		if tokens[2].Val != "-1" {
+			t.Errorf("%q: expected '-1', got %q", input, tokens[2].Val)
+		}
+	}
+}
+
+func TestLexIndexBrackets(t *testing.T) {
+	tokens, err := Lex("xs[-1]-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TokenType{TokenIdent, TokenLBracket, TokenInt, TokenRBracket, TokenMinus, TokenInt, TokenEOF}
+	if len(tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, tt := range expected {
+		if tokens[i].Type != tt {
+			t.Errorf("token %d: expected %s, got %s", i, tt, tokens[i].Type)
+		}
+	}
+	if tokens[2].Val != "-1" {
+		t.Errorf("expected '-1', got %q", tokens[2].Val)
+	}
+}
+
 func TestLexIsNull(t *testing.T) {
 	tokens, err := Lex("age is not null")
 	if err != nil {
@@ -419,6 +459,8 @@ func TestLexSingleCharacterTokensAtEOF(t *testing.T) {
 		{"-", TokenMinus, "-"},
 		{"*", TokenStar, "*"},
 		{"/", TokenSlash, "/"},
+		{"%", TokenPercent, "%"},
+		{"||", TokenConcat, "||"},
 		{"=", TokenEquals, "="},
 		{"<", TokenLt, "<"},
 		{">", TokenGt, ">"},
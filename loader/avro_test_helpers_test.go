@@ -5,25 +5,25 @@ import (
 )
 
 func avroValue(v any, schema any, namespace string) table.Value {
-	return newAvroSchemaContext(schema, namespace).value(v, schema, namespace)
+	return newAvroSchemaContext(schema, namespace, false).value(v, schema, namespace)
 }
 
 func avroSchemaName(schema any, namespace string) string {
-	return newAvroSchemaContext(schema, namespace).schemaName(schema, namespace)
+	return newAvroSchemaContext(schema, namespace, false).schemaName(schema, namespace)
 }
 
 func avroRecordValue(v any, schema map[string]any, namespace string) table.Value {
-	return newAvroSchemaContext(schema, namespace).recordValue(v, schema, namespace)
+	return newAvroSchemaContext(schema, namespace, false).recordValue(v, schema, namespace)
 }
 
 func avroArrayValue(v any, itemSchema any, namespace string) table.Value {
-	return newAvroSchemaContext(itemSchema, namespace).arrayValue(v, itemSchema, namespace)
+	return newAvroSchemaContext(itemSchema, namespace, false).arrayValue(v, itemSchema, namespace)
 }
 
 func avroFieldSchemaDescriptor(schema any, namespace string) *table.TypeDescriptor {
-	return newAvroSchemaContext(schema, namespace).fieldSchemaDescriptor(schema, namespace, nil)
+	return newAvroSchemaContext(schema, namespace, false).fieldSchemaDescriptor(schema, namespace, nil)
 }
 
 func avroRecordSchemaDescriptor(schema map[string]any, namespace string) *table.TypeDescriptor {
-	return newAvroSchemaContext(schema, namespace).recordSchemaDescriptor(schema, namespace, nil)
+	return newAvroSchemaContext(schema, namespace, false).recordSchemaDescriptor(schema, namespace, nil)
 }
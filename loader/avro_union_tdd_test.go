@@ -54,6 +54,7 @@ func TestAvroUnionSchemaDescriptorTDD(t *testing.T) {
 		{name: "two_record_branches", schema: []any{recordBranch, otherRecordBranch}, want: "union<record<x:int>,record<y:string>>"},
 		{name: "nullable_two_record_branches", schema: []any{"null", recordBranch, otherRecordBranch}, want: "union<record<x:int>,record<y:string>>?"},
 		{name: "nested_union_in_list_of_records", schema: map[string]any{"type": "array", "items": map[string]any{"type": "record", "name": "Item", "fields": []any{map[string]any{"name": "u", "type": []any{"int", "string"}}}}}, want: "list<record<u:union<int,string>>>"},
+		{name: "union_with_map", schema: []any{"string", map[string]any{"type": "map", "values": "string"}}, want: "union<string,map<string,string>>"},
 	}
 
 	for _, tc := range cases {
@@ -70,7 +71,7 @@ func TestAvroUnionSchemaDescriptorRejectsUnsupportedBranchesTDD(t *testing.T) {
 		schema any
 	}{
 		{name: "union_with_unknown_primitive", schema: []any{"int", "decimal"}},
-		{name: "union_with_unsupported_map", schema: []any{"string", map[string]any{"type": "map", "values": "string"}}},
+		{name: "union_with_unsupported_map_value", schema: []any{"string", map[string]any{"type": "map", "values": "decimal"}}},
 		{name: "array_item_union_with_unknown_branch", schema: map[string]any{"type": "array", "items": []any{"int", "decimal"}}},
 	}
 	for _, tc := range cases {
@@ -155,14 +156,21 @@ func TestAvroValueWrapperEntryPointsTDD(t *testing.T) {
 	}
 }
 
-func TestLoadAvroUnsupportedMapSchemaErrorsTDD(t *testing.T) {
-	path := writeAvroUnionTDDFile(t, avroUnionTDDRowSchema(`{"name":"attrs","type":{"type":"map","values":"string"}}`), nil)
-	_, err := Load(path, Options{Format: "avro"})
-	if err == nil {
-		t.Fatal("expected unsupported Avro map schema error")
+func TestLoadAvroMapColumnsTDD(t *testing.T) {
+	path := writeAvroUnionTDDFile(t, avroUnionTDDRowSchema(`{"name":"attrs","type":{"type":"map","values":["null","long"]}}`), []map[string]any{
+		{"attrs": map[string]any{"b": goavro.Union("long", int64(2)), "a": nil}},
+		{"attrs": map[string]any{}},
+	})
+	tbl, err := Load(path, Options{Format: "avro"})
+	if err != nil {
+		t.Fatal(err)
 	}
-	if got, want := err.Error(), `unsupported Avro schema for field "attrs"`; !strings.Contains(got, want) {
-		t.Fatalf("error: got %q, want substring %q", got, want)
+	requireLoaderSchemaString(t, tbl.Schema().Columns[0].Type, "map<string,int?>")
+	if got := tbl.Col(0).Get(0); got.Type != table.TypeMap || got.AsString() != "{a:null, b:2}" {
+		t.Fatalf("row 1: got %s", got.AsString())
+	}
+	if got := tbl.Col(0).Get(1).AsString(); got != "{}" {
+		t.Fatalf("row 2: got %s", got)
 	}
 }
 
@@ -473,6 +481,35 @@ func TestLoadAvroUnionColumnsTDD(t *testing.T) {
 	})
 }
 
+func TestLoadAvroUnionNamesTDD(t *testing.T) {
+	schema := avroUnionTDDRowSchema(`{"name":"u","type":["null",{"type":"record","name":"Click","namespace":"ev","fields":[{"name":"x","type":"long"}]},{"type":"record","name":"View","namespace":"ev","fields":[{"name":"x","type":"long"}]},"string"]}`)
+	path := writeAvroUnionTDDFile(t, schema, []map[string]any{
+		{"u": goavro.Union("ev.View", map[string]any{"x": int64(1)})},
+		{"u": goavro.Union("ev.Click", map[string]any{"x": int64(2)})},
+		{"u": goavro.Union("string", "s")},
+		{"u": nil},
+	})
+
+	tbl, err := Load(path, Options{Format: "avro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireAvroUnionColumn(t, tbl, "u", 4, "union", "union<record<x:int>,string>?")
+
+	on := true
+	tbl, err = Load(path, Options{Format: "avro", UnionNames: &on})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireAvroUnionColumn(t, tbl, "u", 4, "union", "union<ev.Click:record<x:int>,ev.View:record<x:int>,string>?")
+	for row, want := range []string{"ev.View", "ev.Click", "", ""} {
+		if got := tbl.Get(row, "u").Branch(); got != want {
+			t.Errorf("row %d branch: got %q, want %q", row+1, got, want)
+		}
+	}
+	requireAvroRecordValue(t, tbl.Get(0, "u"), table.RecordField{Name: "x", Value: table.IntVal(1)})
+}
+
 func TestLoadAvroRejectsRecursiveNamedRecordsTDD(t *testing.T) {
 	cases := []struct {
 		name string
@@ -527,7 +564,7 @@ func TestAvroRecursiveNamedReferenceShapesTDD(t *testing.T) {
 			},
 		},
 	}
-	ctx := newAvroSchemaContext(root, "")
+	ctx := newAvroSchemaContext(root, "", false)
 
 	for _, schema := range []any{
 		"Node",
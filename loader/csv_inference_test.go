@@ -279,6 +279,73 @@ func TestLoadCSVInferenceStringPreservesRawSampleCells(t *testing.T) {
 	}
 }
 
+func TestLoadCSVInfersDatesAndTimestamps(t *testing.T) {
+	data := "day,at,mixed\n2024-03-01,2024-03-01 10:00:00,2024-03-01\n2024-03-02,2024-03-02T11:00:00+01:00,2024-03-02T00:00:00Z\n"
+	dates := true
+	tbl, err := LoadReader(strings.NewReader(data), Options{Format: "csv", Dates: &dates})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for col, want := range map[string]table.ValueType{"day": table.TypeDate, "at": table.TypeTimestamp, "mixed": table.TypeString} {
+		if got := tbl.Col(tbl.ColIndex(col)).ColType(); got != want {
+			t.Errorf("%s type: got %v, want %v", col, got, want)
+		}
+	}
+	if got := tbl.Get(1, "at").AsString(); got != "2024-03-02T10:00:00Z" {
+		t.Fatalf("zoned timestamp: got %q", got)
+	}
+	if got := tbl.Get(0, "mixed").Str; got != "2024-03-01" {
+		t.Fatalf("mixed sample cell: got %q", got)
+	}
+
+	plain, err := LoadReader(strings.NewReader(data), Options{Format: "csv"})
+	if err != nil {
+		t.Fatalf("load without dates: %v", err)
+	}
+	if got := plain.Col(plain.ColIndex("day")).ColType(); got != table.TypeString {
+		t.Fatalf("day without dates: got %v, want string", got)
+	}
+
+	_, err = LoadReader(strings.NewReader("day\n2024-03-01\n2024-03-01 10:00:00\n"), Options{Format: "csv", Dates: &dates, InferRows: 1})
+	if err == nil || !strings.Contains(err.Error(), `column "day" expected date`) {
+		t.Fatalf("late timestamp in date column: got %v", err)
+	}
+}
+
+func TestLoadCSVInfersDecimalsWhenEnabled(t *testing.T) {
+	data := "price,qty,mixed,sci\n12.50,1,1,1.5\n-0.125,2,2.5,2e3\n,3,3,4\n"
+	decimals := true
+	tbl, err := LoadReader(strings.NewReader(data), Options{Format: "csv", Decimals: &decimals})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := map[string]string{"price": "decimal(5,3)?", "qty": "int", "mixed": "decimal(2,1)", "sci": "float"}
+	for _, col := range tbl.Schema().Columns {
+		if got := col.Type.String(); got != want[col.Name] {
+			t.Errorf("%s schema: got %s, want %s", col.Name, got, want[col.Name])
+		}
+	}
+	if got := tbl.Get(0, "price").AsString(); got != "12.500" {
+		t.Fatalf("price rescaled to column scale: got %q", got)
+	}
+	if got := tbl.Get(0, "mixed"); got.Type != table.TypeDecimal || got.AsString() != "1.0" {
+		t.Fatalf("int cell in decimal column: got %v %q", got.Type, got.AsString())
+	}
+
+	plain, err := LoadReader(strings.NewReader(data), Options{Format: "csv"})
+	if err != nil {
+		t.Fatalf("load without decimals: %v", err)
+	}
+	if got := plain.Col(plain.ColIndex("price")).ColType(); got != table.TypeFloat {
+		t.Fatalf("price without decimals: got %v", got)
+	}
+
+	_, err = LoadReader(strings.NewReader("price\n1.5\n123.25\n"), Options{Format: "csv", Decimals: &decimals, InferRows: 1})
+	if err == nil || !strings.Contains(err.Error(), `column "price" expected decimal`) {
+		t.Fatalf("late wider decimal: got %v", err)
+	}
+}
+
 func TestLoadCSVAllNullColumnsInferString(t *testing.T) {
 	tbl, err := LoadReader(strings.NewReader("name,age\n,\nnull,NULL\n"), Options{Format: "csv"})
 	if err != nil {
@@ -761,3 +761,59 @@ func TestLoadJSONInferenceCompressedGlobAndStdin(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadJSONMapsOption(t *testing.T) {
+	input := `{"id":1,"counts":{"2024-01":3,"2024-02":5},"items":[{"attrs":{"a":{"n":1}}}]}` + "\n" +
+		`{"id":2,"counts":{"2024-03":1.5},"items":[]}` + "\n"
+	opts := Options{Format: "jsonl", Maps: []string{"counts", "items[].attrs"}}
+	tbl, err := LoadReader(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := tbl.Col(tbl.ColIndex("counts")).Schema().String(); got != "map<string,float>" {
+		t.Fatalf("counts schema: got %q", got)
+	}
+	if got := tbl.Col(tbl.ColIndex("items")).Schema().String(); got != "list<record<attrs:map<string,record<n:int>>>>" {
+		t.Fatalf("items schema: got %q", got)
+	}
+	if got := tbl.Get(0, "counts"); got.Type != table.TypeMap || got.AsString() != "{2024-01:3, 2024-02:5}" {
+		t.Fatalf("counts row 1: got %s", got.AsString())
+	}
+
+	dir := t.TempDir()
+	writeJSONInferenceFile(t, dir, "a.json", `[{"m":{"x":{"y":1}}}]`)
+	writeJSONInferenceFile(t, dir, "b.json", `[{"m":{"z":{"y":2}}}]`)
+	tbl, err = Load(filepath.Join(dir, "*.json"), Options{Format: "json", Maps: []string{"m", "m{}"}})
+	if err != nil {
+		t.Fatalf("load glob: %v", err)
+	}
+	if got := tbl.Col(0).Schema().String(); got != "map<string,map<string,int>>" {
+		t.Fatalf("nested map schema: got %q", got)
+	}
+	if got := tbl.Get(1, "m").AsString(); got != "{z:{y:2}}" {
+		t.Fatalf("nested map row 2: got %s", got)
+	}
+}
+
+func TestLoadJSONMapsOptionRejectsUnusablePaths(t *testing.T) {
+	input := `{"id":1,"m":{"a":1,"b":"x"},"n":{"a":1}}` + "\n" + `{"id":2,"m":{"a":2},"n":null}` + "\n"
+	path := writeJSONInferenceFile(t, t.TempDir(), "maps.jsonl", input)
+	tests := []struct {
+		maps string
+		want string
+	}{
+		{maps: "m", want: "maps: values of m have no common type"},
+		{maps: "missing", want: "maps: no field missing in the input"},
+		{maps: "n.b", want: "maps: no field n.b in the input"},
+		{maps: "id", want: "maps: id is int, not an object"},
+	}
+	for _, tc := range tests {
+		opts := Options{Format: "jsonl", Maps: []string{tc.maps}}
+		if _, err := LoadReader(strings.NewReader(input), opts); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("LoadReader maps=%s: got %v, want %q", tc.maps, err, tc.want)
+		}
+		if _, err := Load(path, opts); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("Load maps=%s: got %v, want %q", tc.maps, err, tc.want)
+		}
+	}
+}
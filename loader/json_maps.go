@@ -0,0 +1,110 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/razeghi71/dq/table"
+)
+
+// jsonMapPaths holds the field paths named by the maps load option. Objects at
+// these paths load as map<string,T> instead of records, so dynamic keys such as
+// dates share one value type rather than adding a field each. Paths use the
+// schema path form: "a.b" for a nested field, "a[]" for list elements and
+// "a{}" for map values.
+type jsonMapPaths map[string]struct{}
+
+func newJSONMapPaths(paths []string) jsonMapPaths {
+	if len(paths) == 0 {
+		return nil
+	}
+	out := make(jsonMapPaths, len(paths))
+	for _, path := range paths {
+		out[path] = struct{}{}
+	}
+	return out
+}
+
+func (m jsonMapPaths) apply(fields []table.RecordField) []table.RecordField {
+	if len(m) == 0 {
+		return fields
+	}
+	for i, field := range fields {
+		fields[i].Value = m.convert(field.Value, field.Name)
+	}
+	return fields
+}
+
+func (m jsonMapPaths) convert(v table.Value, path string) table.Value {
+	switch v.Type {
+	case table.TypeRecord:
+		if _, ok := m[path]; ok {
+			entries := make([]table.RecordField, len(v.Fields))
+			for i, field := range v.Fields {
+				entries[i] = table.RecordField{Name: field.Name, Value: m.convert(field.Value, path+"{}")}
+			}
+			return table.MapVal(entries)
+		}
+		fields := make([]table.RecordField, len(v.Fields))
+		for i, field := range v.Fields {
+			fields[i] = table.RecordField{Name: field.Name, Value: m.convert(field.Value, path+"."+field.Name)}
+		}
+		return table.RecordVal(fields)
+	case table.TypeList:
+		elems := make([]table.Value, len(v.List))
+		for i, elem := range v.List {
+			elems[i] = m.convert(elem, path+"[]")
+		}
+		return table.ListVal(elems)
+	}
+	return v
+}
+
+// validate checks the inferred column schemas against the map paths: every
+// path must name a field of the input that holds objects, and the objects'
+// values must share one type, since map functions cannot return mixed values.
+func (m jsonMapPaths) validate(columns []string, schemas []*table.TypeDescriptor) error {
+	if len(m) == 0 {
+		return nil
+	}
+	found := make(map[string]*table.TypeDescriptor)
+	for i, col := range columns {
+		collectSchemaPaths(schemas[i], col, found)
+	}
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		schema, ok := found[path]
+		switch {
+		case !ok:
+			return fmt.Errorf("maps: no field %s in the input", path)
+		case schema.Kind == table.TypeNull:
+		case schema.Kind != table.TypeMap:
+			return fmt.Errorf("maps: %s is %s, not an object", path, table.Render(table.WithoutNull(schema)))
+		case schema.Elem != nil && schema.Elem.Kind == table.TypeMixed:
+			return fmt.Errorf("maps: values of %s have no common type; load it without maps to keep it a record", path)
+		}
+	}
+	return nil
+}
+
+func collectSchemaPaths(schema *table.TypeDescriptor, path string, found map[string]*table.TypeDescriptor) {
+	schema = table.FinalizeSchema(schema)
+	if schema == nil {
+		return
+	}
+	found[path] = schema
+	switch schema.Kind {
+	case table.TypeRecord:
+		for _, field := range schema.Fields {
+			collectSchemaPaths(field.Type, path+"."+field.Name, found)
+		}
+	case table.TypeList:
+		collectSchemaPaths(schema.Elem, path+"[]", found)
+	case table.TypeMap:
+		collectSchemaPaths(schema.Elem, path+"{}", found)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -87,7 +88,7 @@ func loadFile(filename string, opts Options, csvColumns []string) (*table.Table,
 		if compression != "" {
 			return nil, fmt.Errorf("compression=%s applies only to csv, json, and jsonl formats", compression)
 		}
-		return loadAvro(filename)
+		return loadAvro(filename, avroUnionNames(opts))
 	case "parquet":
 		if compression != "" {
 			return nil, fmt.Errorf("compression=%s applies only to csv, json, and jsonl formats", compression)
@@ -256,6 +257,8 @@ type csvLoadConfig struct {
 	delim               rune
 	allowJaggedRows     bool
 	ignoreUnknownValues bool
+	decimals            bool
+	dates               bool
 	compression         string
 	inferRows           int
 	maxBadRecords       int
@@ -266,6 +269,7 @@ type jsonLoadConfig struct {
 	compression   string
 	inferRows     int
 	maxBadRecords int
+	maps          jsonMapPaths
 	source        string
 }
 
@@ -274,6 +278,7 @@ func jsonConfigFromOptions(opts Options, source string) jsonLoadConfig {
 		compression:   opts.Compression,
 		inferRows:     opts.InferRows,
 		maxBadRecords: opts.MaxBadRecords,
+		maps:          newJSONMapPaths(opts.Maps),
 		source:        source,
 	}
 }
@@ -299,6 +304,12 @@ func csvConfigFromOptions(opts Options, columns []string) csvLoadConfig {
 	if opts.IgnoreUnknownValues != nil {
 		cfg.ignoreUnknownValues = *opts.IgnoreUnknownValues
 	}
+	if opts.Decimals != nil {
+		cfg.decimals = *opts.Decimals
+	}
+	if opts.Dates != nil {
+		cfg.dates = *opts.Dates
+	}
 	return cfg
 }
 
@@ -339,6 +350,10 @@ func validateOptionsForFormat(opts Options, format string) error {
 		Delim:               opts.Delim,
 		AllowJaggedRows:     opts.AllowJaggedRows,
 		IgnoreUnknownValues: opts.IgnoreUnknownValues,
+		Decimals:            opts.Decimals,
+		Dates:               opts.Dates,
+		Maps:                opts.Maps,
+		UnionNames:          opts.UnionNames,
 		InferRows:           intPtrIfSet(opts.InferRows, opts.InferRowsSet || opts.InferRows != defaultInferRows),
 		MaxBadRecords:       intPtrIfSet(opts.MaxBadRecords, opts.MaxBadRecordsSet || opts.MaxBadRecords != 0),
 	}, format, "")
@@ -766,10 +781,10 @@ func materializeCSVGroups(columns []string, groups []csvRowGroup, cfg csvLoadCon
 			return nil, err
 		}
 	}
-	types := inferCSVColumnTypes(columns, groups, cfg.inferRows)
+	types, decimals := inferCSVColumnTypes(columns, groups, cfg.inferRows, cfg.decimals, cfg.dates)
 	totalRows := csvRowGroupCount(groups)
 	nullableAll := csvCollectedInferenceNeedsConservativeNullability(cfg.inferRows, totalRows)
-	schemas := csvSchemasFromTypes(columns, types, csvNullableColumns(columns, groups), nullableAll)
+	schemas := csvSchemasFromTypes(columns, types, decimals, csvNullableColumns(columns, groups), nullableAll)
 	mat, err := csvMaterializationFor(columns, types, schemas, table.AllColumns(), cfg.source)
 	if err != nil {
 		return nil, err
@@ -795,7 +810,7 @@ type csvMaterialization struct {
 
 func csvMaterializationFor(columns []string, types []table.ValueType, schemas []*table.TypeDescriptor, projection table.ColumnSelection, source string) (csvMaterialization, error) {
 	if schemas == nil {
-		schemas = csvSchemasFromTypes(columns, types, nil, false)
+		schemas = csvSchemasFromTypes(columns, types, nil, nil, false)
 	}
 	if projection.IsAll() {
 		positionByColumn := make([]int, len(columns))
@@ -887,14 +902,20 @@ func sourcePrefix(source string) string {
 // CSV inference intentionally parallels parseValue and table widening without
 // reusing table.Append: inference chooses a fixed load schema first, then
 // materialization strictly converts every semantically read post-inference cell
-// to that schema.
-func inferCSVColumnTypes(columns []string, groups []csvRowGroup, inferRows int) []table.ValueType {
+// to that schema. With decimals enabled, plain decimal cells infer decimal and
+// the returned shapes size each decimal column; otherwise shapes is nil. With
+// dates enabled, ISO date and timestamp cells infer date and timestamp.
+func inferCSVColumnTypes(columns []string, groups []csvRowGroup, inferRows int, decimals, dates bool) ([]table.ValueType, []csvDecimalShape) {
 	types := make([]table.ValueType, len(columns))
+	var shapes []csvDecimalShape
+	if decimals {
+		shapes = make([]csvDecimalShape, len(columns))
+	}
 	if inferRows == 0 {
 		for i := range types {
 			types[i] = table.TypeString
 		}
-		return types
+		return types, shapes
 	}
 	sampled := 0
 	for _, group := range groups {
@@ -903,22 +924,31 @@ func inferCSVColumnTypes(columns []string, groups []csvRowGroup, inferRows int)
 			if inferRows > 0 && sampled >= inferRows {
 				break
 			}
-			applyCSVInferenceRow(types, mapping, row)
+			applyCSVInferenceRow(types, shapes, mapping, row, dates)
 			sampled++
 		}
 		if inferRows > 0 && sampled >= inferRows {
 			break
 		}
 	}
+	finalizeCSVInferredTypes(types, shapes)
+	return types, shapes
+}
+
+// finalizeCSVInferredTypes reads all-null columns as strings and falls back
+// to float for decimal columns wider than table.MaxDecimalPrecision.
+func finalizeCSVInferredTypes(types []table.ValueType, shapes []csvDecimalShape) {
 	for i := range types {
-		if types[i] == table.TypeNull {
+		switch {
+		case types[i] == table.TypeNull:
 			types[i] = table.TypeString
+		case types[i] == table.TypeDecimal && shapes[i].precision() > table.MaxDecimalPrecision:
+			types[i] = table.TypeFloat
 		}
 	}
-	return types
 }
 
-func applyCSVInferenceRow(types []table.ValueType, mapping []int, row csvRawRow) {
+func applyCSVInferenceRow(types []table.ValueType, shapes []csvDecimalShape, mapping []int, row csvRawRow, dates bool) {
 	for srcIdx, dst := range mapping {
 		if dst < 0 || srcIdx >= len(row.record) {
 			continue
@@ -926,16 +956,58 @@ func applyCSVInferenceRow(types []table.ValueType, mapping []int, row csvRawRow)
 		if types[dst] == table.TypeString {
 			continue
 		}
-		v := rowValueForInference(row, srcIdx)
+		v := rowValueForInference(row, srcIdx, shapes != nil, dates)
 		if v.Type == table.TypeNull {
 			continue
 		}
 		types[dst] = csvWidenInferredType(types[dst], v.Type)
+		if shapes != nil {
+			shapes[dst].observe(v)
+		}
+	}
+}
+
+func rowValueForInference(row csvRawRow, srcIdx int, decimals, dates bool) table.Value {
+	cell := strings.TrimSpace(row.record[srcIdx])
+	v := parseValue(cell)
+	if decimals && v.Type == table.TypeFloat {
+		if d, ok := table.ParseDecimal(cell); ok {
+			return d
+		}
+	}
+	if dates && v.Type == table.TypeString {
+		if d, ok := table.ParseDate(cell); ok {
+			return d
+		}
+		if ts, ok := table.ParseTimestamp(cell); ok {
+			return ts
+		}
 	}
+	return v
 }
 
-func rowValueForInference(row csvRawRow, srcIdx int) table.Value {
-	return parseValue(strings.TrimSpace(row.record[srcIdx]))
+// csvDecimalShape tracks the widest integer part and scale among a column's
+// int and decimal cells, which together size its decimal(p,s) schema.
+type csvDecimalShape struct {
+	intDigits int
+	scale     int
+}
+
+func (s *csvDecimalShape) observe(v table.Value) {
+	if v.Type != table.TypeInt && v.Type != table.TypeDecimal {
+		return
+	}
+	_, scale, _ := v.Decimal()
+	s.intDigits = max(s.intDigits, table.DecimalPrecision(v)-scale)
+	s.scale = max(s.scale, scale)
+}
+
+func (s csvDecimalShape) precision() int {
+	return max(s.intDigits+s.scale, 1)
+}
+
+func (s csvDecimalShape) schema() *table.TypeDescriptor {
+	return table.DecimalSchema(s.precision(), s.scale)
 }
 
 func csvWidenInferredType(existing, incoming table.ValueType) table.ValueType {
@@ -948,6 +1020,12 @@ func csvWidenInferredType(existing, incoming table.ValueType) table.ValueType {
 	if (existing == table.TypeInt && incoming == table.TypeFloat) || (existing == table.TypeFloat && incoming == table.TypeInt) {
 		return table.TypeFloat
 	}
+	if (existing == table.TypeInt && incoming == table.TypeDecimal) || (existing == table.TypeDecimal && incoming == table.TypeInt) {
+		return table.TypeDecimal
+	}
+	if (existing == table.TypeDecimal && incoming == table.TypeFloat) || (existing == table.TypeFloat && incoming == table.TypeDecimal) {
+		return table.TypeFloat
+	}
 	return table.TypeString
 }
 
@@ -979,6 +1057,11 @@ func csvTypedRowValues(row csvRawRow, mapping []int, source string, columns []st
 		}
 		cell := strings.TrimSpace(row.record[srcIdx])
 		v, err := csvCellValueAsType(cell, types[dst])
+		if err == nil && v.Type == table.TypeDecimal {
+			// Decimals take the column's scale; cells past the inference
+			// sample may not fit its precision.
+			v, err = table.CoerceValueToSchema(v, mat.schemas[outIdx])
+		}
 		if err != nil {
 			return nil, csvTypeError(row, source, columns[dst], types[dst], cell)
 		}
@@ -1053,6 +1136,21 @@ func parseCSVCellAsType(cell string, typ table.ValueType) (table.Value, error) {
 			return table.BoolVal(false), nil
 		}
 		return table.Null(), fmt.Errorf("invalid bool")
+	case table.TypeDate:
+		if v, ok := table.ParseDate(cell); ok {
+			return v, nil
+		}
+		return table.Null(), fmt.Errorf("invalid date")
+	case table.TypeTimestamp:
+		if v, ok := table.ParseTimestamp(cell); ok {
+			return v, nil
+		}
+		return table.Null(), fmt.Errorf("invalid timestamp")
+	case table.TypeDecimal:
+		if v, ok := table.ParseDecimal(cell); ok {
+			return v, nil
+		}
+		return table.Null(), fmt.Errorf("invalid decimal")
 	default:
 		return parseValue(cell), nil
 	}
@@ -1076,6 +1174,8 @@ func csvTypeName(typ table.ValueType) string {
 		return "string"
 	case table.TypeBool:
 		return "bool"
+	case table.TypeDate, table.TypeTimestamp, table.TypeDecimal:
+		return table.TypeName(typ)
 	default:
 		return "null"
 	}
@@ -1201,10 +1301,10 @@ func loadCSVReaderStreaming(r io.Reader, cfg csvLoadConfig) (*table.Table, error
 	}
 
 	group := csvRowGroup{columns: append([]string(nil), columns...), source: cfg.source, rows: window.sampleRows}
-	types := inferCSVColumnTypes(columns, []csvRowGroup{group}, cfg.inferRows)
+	types, decimals := inferCSVColumnTypes(columns, []csvRowGroup{group}, cfg.inferRows, cfg.decimals, cfg.dates)
 	totalRows := len(window.sampleRows) + len(window.pendingRows)
 	nullableAll := csvStreamingInferenceNeedsConservativeNullability(cfg.inferRows, totalRows, window.sampleExhausted)
-	schemas := csvSchemasFromTypes(columns, types, csvNullableColumns(columns, []csvRowGroup{group}), nullableAll)
+	schemas := csvSchemasFromTypes(columns, types, decimals, csvNullableColumns(columns, []csvRowGroup{group}), nullableAll)
 	mat, err := csvMaterializationFor(columns, types, schemas, table.AllColumns(), cfg.source)
 	if err != nil {
 		return nil, err
@@ -1440,18 +1540,18 @@ func collectJSONFileRecords(filename string, cfg jsonLoadConfig) ([]jsonLogicalR
 		return nil, err
 	}
 	defer f.Close()
-	return collectJSONRecords(f, filename)
+	return collectJSONRecords(f, filename, cfg.maps)
 }
 
 func loadJSONReader(r io.Reader, cfg jsonLoadConfig) (*table.Table, error) {
-	records, err := collectJSONRecords(r, cfg.source)
+	records, err := collectJSONRecords(r, cfg.source, cfg.maps)
 	if err != nil {
 		return nil, err
 	}
 	return buildTableFromJSONRecords(records, cfg)
 }
 
-func collectJSONRecords(r io.Reader, source string) ([]jsonLogicalRecord, error) {
+func collectJSONRecords(r io.Reader, source string, maps jsonMapPaths) ([]jsonLogicalRecord, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read JSON: %w", err)
@@ -1478,7 +1578,7 @@ func collectJSONRecords(r io.Reader, source string) ([]jsonLogicalRecord, error)
 			records[i] = jsonLogicalRecord{loc: loc, source: source, err: fmt.Errorf("expected JSON object")}
 			continue
 		}
-		fields, err := buildJSONRecordFields(rec)
+		fields, err := buildJSONRecordFields(rec, maps)
 		if err != nil {
 			records[i] = jsonLogicalRecord{loc: loc, source: source, err: err}
 			continue
@@ -1502,18 +1602,18 @@ func collectJSONLFileRecords(filename string, cfg jsonLoadConfig) ([]jsonLogical
 		return nil, err
 	}
 	defer f.Close()
-	return collectJSONLRecords(f, filename)
+	return collectJSONLRecords(f, filename, cfg.maps)
 }
 
 func loadJSONLReader(r io.Reader, cfg jsonLoadConfig) (*table.Table, error) {
-	records, err := collectJSONLRecords(r, cfg.source)
+	records, err := collectJSONLRecords(r, cfg.source, cfg.maps)
 	if err != nil {
 		return nil, err
 	}
 	return buildTableFromJSONRecords(records, cfg)
 }
 
-func collectJSONLRecords(r io.Reader, source string) ([]jsonLogicalRecord, error) {
+func collectJSONLRecords(r io.Reader, source string, maps jsonMapPaths) ([]jsonLogicalRecord, error) {
 	scanner := newJSONLScanner(r)
 	var records []jsonLogicalRecord
 	lineNum := 0
@@ -1551,7 +1651,7 @@ func collectJSONLRecords(r io.Reader, source string) ([]jsonLogicalRecord, error
 			})
 			continue
 		}
-		fields, err := buildJSONRecordFields(rec)
+		fields, err := buildJSONRecordFields(rec, maps)
 		if err != nil {
 			records = append(records, jsonLogicalRecord{
 				loc:    fmt.Sprintf("line %d", lineNum),
@@ -1629,6 +1729,11 @@ func buildTableFromJSONRecords(records []jsonLogicalRecord, cfg jsonLoadConfig)
 		inferredGood[i] = true
 	}
 
+	if state.goodRows > 0 {
+		if err := cfg.maps.validate(state.columns, state.schemas); err != nil {
+			return nil, err
+		}
+	}
 	if jsonInferenceNeedsConservativeNullability(cfg.inferRows, inferSeen, len(records)) {
 		state.schemas = deepNullableSchemas(state.schemas)
 	}
@@ -1859,7 +1964,7 @@ func anyToValue(v interface{}) table.Value {
 	case bool:
 		return table.BoolVal(val)
 	case json.Number:
-		v, err := jsonNumberToValue(val)
+		v, err := table.JSONNumberValue(val)
 		if err != nil {
 			return table.Null()
 		}
@@ -1895,28 +2000,6 @@ func anyToValue(v interface{}) table.Value {
 	}
 }
 
-func jsonNumberToValue(n json.Number) (table.Value, error) {
-	if i, err := n.Int64(); err == nil {
-		return table.IntVal(i), nil
-	}
-	if f, err := n.Float64(); err == nil {
-		return table.FloatVal(f), nil
-	}
-	return table.Null(), jsonNumberError{Value: n.String()}
-}
-
-type jsonNumberError struct {
-	Path  string
-	Value string
-}
-
-func (e jsonNumberError) Error() string {
-	if e.Path == "" {
-		return fmt.Sprintf("unrepresentable JSON number %q", e.Value)
-	}
-	return fmt.Sprintf("%s unrepresentable JSON number %q", e.Path, e.Value)
-}
-
 // buildRecordFields creates a sorted []RecordField from a map.
 func buildRecordFields(m map[string]interface{}) []table.RecordField {
 	keys := make([]string, 0, len(m))
@@ -1931,82 +2014,12 @@ func buildRecordFields(m map[string]interface{}) []table.RecordField {
 	return fields
 }
 
-func buildJSONRecordFields(m map[string]interface{}) ([]table.RecordField, error) {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	fields := make([]table.RecordField, len(keys))
-	for i, k := range keys {
-		v, err := jsonValueToValue(m[k], k)
-		if err != nil {
-			return nil, err
-		}
-		fields[i] = table.RecordField{Name: k, Value: v}
-	}
-	return fields, nil
-}
-
-func jsonValueToValue(v interface{}, path string) (table.Value, error) {
-	switch val := v.(type) {
-	case nil:
-		return table.Null(), nil
-	case bool:
-		return table.BoolVal(val), nil
-	case json.Number:
-		out, err := jsonNumberToValue(val)
-		if err != nil {
-			if numberErr, ok := err.(jsonNumberError); ok {
-				numberErr.Path = path
-				return table.Null(), numberErr
-			}
-			return table.Null(), err
-		}
-		return out, nil
-	case string:
-		return table.StrVal(val), nil
-	case []interface{}:
-		elems := make([]table.Value, len(val))
-		for i, elem := range val {
-			out, err := jsonValueToValue(elem, path+"[]")
-			if err != nil {
-				return table.Null(), err
-			}
-			elems[i] = out
-		}
-		return table.ListVal(elems), nil
-	case map[string]interface{}:
-		fields, err := buildJSONRecordFieldsAtPath(val, path)
-		if err != nil {
-			return table.Null(), err
-		}
-		return table.RecordVal(fields), nil
-	default:
-		b, _ := json.Marshal(val)
-		return table.StrVal(string(b)), nil
-	}
-}
-
-func buildJSONRecordFieldsAtPath(m map[string]interface{}, path string) ([]table.RecordField, error) {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	fields := make([]table.RecordField, len(keys))
-	for i, k := range keys {
-		fieldPath := k
-		if path != "" {
-			fieldPath = path + "." + k
-		}
-		v, err := jsonValueToValue(m[k], fieldPath)
-		if err != nil {
-			return nil, err
-		}
-		fields[i] = table.RecordField{Name: k, Value: v}
+func buildJSONRecordFields(m map[string]interface{}, maps jsonMapPaths) ([]table.RecordField, error) {
+	fields, err := table.JSONRecordFields(m, "")
+	if err != nil {
+		return nil, err
 	}
-	return fields, nil
+	return maps.apply(fields), nil
 }
 
 func parquetValue(v any, schema *table.TypeDescriptor) table.Value {
@@ -2017,11 +2030,44 @@ func parquetValue(v any, schema *table.TypeDescriptor) table.Value {
 			return parquetListValue(v, schema.Elem)
 		case table.TypeRecord:
 			return parquetRecordValue(v, schema)
+		case table.TypeMap:
+			return parquetMapValue(v, schema.Elem)
+		case table.TypeDate, table.TypeTimestamp, table.TypeDuration:
+			return parquetTemporalValue(v, schema.Kind)
+		case table.TypeDecimal:
+			return parquetDecimalValue(v)
+		case table.TypeUnion:
+			return parquetUnionValue(v, schema)
 		}
 	}
 	return parquetUnknownValue(v)
 }
 
+// parquetUnionValue reads a union written as a member0..memberN group: the
+// value is that of the one member that is set.
+func parquetUnionValue(v any, schema *table.TypeDescriptor) table.Value {
+	val, ok := v.(map[string]any)
+	if !ok {
+		return table.Null()
+	}
+	for i, branch := range schema.Branches {
+		if member := val[fmt.Sprintf("member%d", i)]; member != nil {
+			return parquetValue(member, branch)
+		}
+	}
+	return table.Null()
+}
+
+// parquetColumnValue converts a reconstructed row's column value, first
+// rewriting any logical types listed in logical.
+func parquetColumnValue(row map[string]any, col string, schema *table.TypeDescriptor, logical map[string]parquet.Node) table.Value {
+	raw := row[col]
+	if node, ok := logical[col]; ok {
+		raw = parquetLogicalNative(raw, node)
+	}
+	return parquetValue(raw, schema)
+}
+
 func parquetListValue(v any, elemSchema *table.TypeDescriptor) table.Value {
 	if v == nil {
 		return table.Null()
@@ -2089,6 +2135,73 @@ func parquetRecordValue(v any, schema *table.TypeDescriptor) table.Value {
 	return table.RecordVal(fields)
 }
 
+func parquetMapValue(v any, valueSchema *table.TypeDescriptor) table.Value {
+	if v == nil {
+		return table.Null()
+	}
+	val, ok := v.(map[string]any)
+	if !ok {
+		return parquetUnknownValue(v)
+	}
+	keys := make([]string, 0, len(val))
+	for k := range val {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := make([]table.RecordField, len(keys))
+	for i, k := range keys {
+		entries[i] = table.RecordField{Name: k, Value: parquetListElementValue(parquetMapEntry(val[k]), valueSchema)}
+	}
+	return table.MapVal(entries)
+}
+
+// parquetMapEntry converts a map value from Reconstruct, which builds Go
+// pointers and structs for map values rather than the generic forms used for
+// columns, into nil, map[string]any and []any.
+func parquetMapEntry(v any) any {
+	return parquetGenericValue(reflect.ValueOf(v))
+}
+
+func parquetGenericValue(rv reflect.Value) any {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return parquetGenericValue(rv.Elem())
+	case reflect.Struct:
+		out := make(map[string]any, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("parquet"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+			}
+			out[name] = parquetGenericValue(rv.Field(i))
+		}
+		return out
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface()
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = parquetGenericValue(rv.Index(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = parquetGenericValue(iter.Value())
+		}
+		return out
+	}
+	return rv.Interface()
+}
+
 func parquetUnknownValue(v any) table.Value {
 	switch val := v.(type) {
 	case []any:
@@ -2116,7 +2229,11 @@ func parquetUnknownValue(v any) table.Value {
 	}
 }
 
-const parquetColumnOrderMetadataKey = "dq.column_order"
+const (
+	parquetColumnOrderMetadataKey     = "dq.column_order"
+	parquetDurationColumnsMetadataKey = "dq.duration_columns"
+	parquetUnionColumnsMetadataKey    = "dq.union_columns"
+)
 
 func asMap(v any) (map[string]any, bool) {
 	m, ok := v.(map[string]any)
@@ -2136,10 +2253,18 @@ type avroNamedType struct {
 
 type avroSchemaContext struct {
 	names map[string]avroNamedType
+	// unionNames keeps the full names of named union branches on branch
+	// schemas and values, so structurally identical branches stay apart.
+	unionNames bool
+}
+
+// avroUnionNames reports whether union_names=true was given.
+func avroUnionNames(opts Options) bool {
+	return opts.UnionNames != nil && *opts.UnionNames
 }
 
-func newAvroSchemaContext(schema any, namespace string) *avroSchemaContext {
-	ctx := &avroSchemaContext{names: make(map[string]avroNamedType)}
+func newAvroSchemaContext(schema any, namespace string, unionNames bool) *avroSchemaContext {
+	ctx := &avroSchemaContext{names: make(map[string]avroNamedType), unionNames: unionNames}
 	ctx.collectNamedTypes(schema, namespace)
 	return ctx
 }
@@ -2205,7 +2330,11 @@ func (ctx *avroSchemaContext) value(v any, schema any, namespace string) table.V
 			for k, inner := range m {
 				for _, branch := range branches {
 					if avroNameMatches(ctx.schemaName(branch, namespace), k) {
-						return ctx.value(inner, branch, ctx.schemaNamespace(branch, namespace))
+						val := ctx.value(inner, branch, ctx.schemaNamespace(branch, namespace))
+						if name := ctx.unionBranchName(branch, namespace); name != "" && !val.IsNull() {
+							val = val.WithBranch(name)
+						}
+						return val
 					}
 				}
 			}
@@ -2226,7 +2355,12 @@ func (ctx *avroSchemaContext) value(v any, schema any, namespace string) table.V
 				return ctx.recordValue(v, s, namespace)
 			case "array":
 				return ctx.arrayValue(v, s["items"], namespace)
+			case "map":
+				return ctx.mapValue(v, s["values"], namespace)
 			default:
+				if logical := avroLogicalSchema(s); logical != nil {
+					return avroLogicalValue(v, s)
+				}
 				return ctx.value(v, ts, namespace)
 			}
 		default:
@@ -2307,6 +2441,19 @@ func avroTypeNamespace(schema any, parentNamespace string) string {
 	return parentNamespace
 }
 
+// unionBranchName returns the full name of a named union branch, a record,
+// enum or fixed type, when union_names is on, and "" otherwise.
+func (ctx *avroSchemaContext) unionBranchName(branch any, namespace string) string {
+	if !ctx.unionNames {
+		return ""
+	}
+	name := ctx.schemaName(branch, namespace)
+	if _, ok := ctx.names[name]; !ok {
+		return ""
+	}
+	return name
+}
+
 func (ctx *avroSchemaContext) schemaName(schema any, namespace string) string {
 	if s, ok := schema.(string); ok {
 		if isAvroPrimitiveName(s) {
@@ -2331,6 +2478,11 @@ func (ctx *avroSchemaContext) schemaName(schema any, namespace string) string {
 				if named, ok := ctx.resolveNamedType(typ, namespace); ok {
 					return named.fullName
 				}
+				if logical, ok := s["logicalType"].(string); ok {
+					if name, ok := avroLogicalBranchName(typ, logical); ok {
+						return name
+					}
+				}
 				return typ
 			}
 		}
@@ -2517,6 +2669,23 @@ func (ctx *avroSchemaContext) arrayValue(v any, itemSchema any, namespace string
 	return table.ListVal(values)
 }
 
+func (ctx *avroSchemaContext) mapValue(v any, valueSchema any, namespace string) table.Value {
+	m, ok := asMap(v)
+	if !ok {
+		return anyToValue(v)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := make([]table.RecordField, len(keys))
+	for i, k := range keys {
+		entries[i] = table.RecordField{Name: k, Value: ctx.value(m[k], valueSchema, namespace)}
+	}
+	return table.MapVal(entries)
+}
+
 func (ctx *avroSchemaContext) fieldSchemaDescriptor(schema any, namespace string, resolving map[string]bool) *table.TypeDescriptor {
 	switch s := schema.(type) {
 	case string:
@@ -2542,6 +2711,7 @@ func (ctx *avroSchemaContext) fieldSchemaDescriptor(schema any, namespace string
 			if next == nil {
 				return nil
 			}
+			next.Name = ctx.unionBranchName(branch, namespace)
 			branches = append(branches, next)
 		}
 		return table.UnionSchema(branches, nullable)
@@ -2558,8 +2728,15 @@ func (ctx *avroSchemaContext) fieldSchemaDescriptor(schema any, namespace string
 				}
 				return &table.TypeDescriptor{Kind: table.TypeList, Elem: elem}
 			case "map":
-				return nil
+				elem := ctx.fieldSchemaDescriptor(s["values"], namespace, resolving)
+				if elem == nil {
+					return nil
+				}
+				return &table.TypeDescriptor{Kind: table.TypeMap, Elem: elem}
 			default:
+				if logical := avroLogicalSchema(s); logical != nil {
+					return logical
+				}
 				return ctx.fieldSchemaDescriptor(typ, namespace, resolving)
 			}
 		case []any:
@@ -2622,7 +2799,7 @@ func (ctx *avroSchemaContext) recordSchemaDescriptor(schema map[string]any, name
 	return &table.TypeDescriptor{Kind: table.TypeRecord, Fields: fields}
 }
 
-func loadAvro(filename string) (*table.Table, error) {
+func loadAvro(filename string, unionNames bool) (*table.Table, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("cannot open %s: %w", filename, err)
@@ -2638,7 +2815,7 @@ func loadAvro(filename string) (*table.Table, error) {
 	codec := ocfr.Codec()
 	schema := codec.Schema()
 
-	columns, schemas, fieldSchemas, err := avroSchemaParts(schema)
+	columns, schemas, fieldSchemas, err := avroSchemaParts(schema, unionNames)
 	if err != nil {
 		return nil, err
 	}
@@ -2684,14 +2861,14 @@ type avroFieldSchemas struct {
 	schemas       map[string]any
 }
 
-func avroSchemaParts(schema string) ([]string, []*table.TypeDescriptor, avroFieldSchemas, error) {
+func avroSchemaParts(schema string, unionNames bool) ([]string, []*table.TypeDescriptor, avroFieldSchemas, error) {
 	var rootSchema any
 	if err := json.Unmarshal([]byte(schema), &rootSchema); err != nil {
 		return nil, nil, avroFieldSchemas{}, fmt.Errorf("cannot parse Avro schema: %w", err)
 	}
 	rootMap, _ := asMap(rootSchema)
 	rootNamespace := avroTypeNamespace(rootMap, "")
-	avroCtx := newAvroSchemaContext(rootSchema, rootNamespace)
+	avroCtx := newAvroSchemaContext(rootSchema, rootNamespace, unionNames)
 
 	var schemaDef struct {
 		Fields []struct {
@@ -2734,7 +2911,8 @@ func loadParquet(filename string) (*table.Table, error) {
 
 	schema := reader.Schema()
 	columns := parquetColumns(schema, reader)
-	schemas := parquetColumnSchemas(schema, columns)
+	schemas := parquetColumnSchemas(reader, columns)
+	logical := parquetLogicalColumns(schema)
 	t := table.NewTableWithSchemas(columns, schemas)
 
 	buf := make([]any, 128)
@@ -2747,7 +2925,7 @@ func loadParquet(filename string) (*table.Table, error) {
 			}
 			vals := make([]table.Value, len(columns))
 			for j, col := range columns {
-				vals[j] = parquetValue(row[col], schemas[j])
+				vals[j] = parquetColumnValue(row, col, schemas[j], logical)
 			}
 			if err := addMetadataTypedRow(t, vals, "Parquet row"); err != nil {
 				return nil, err
@@ -2770,18 +2948,61 @@ func addMetadataTypedRow(t *table.Table, vals []table.Value, context string) err
 	return nil
 }
 
-func parquetColumnSchemas(schema *parquet.Schema, columns []string) []*table.TypeDescriptor {
+func parquetColumnSchemas(reader *parquet.GenericReader[any], columns []string) []*table.TypeDescriptor {
+	schema := reader.Schema()
 	fieldsByName := make(map[string]parquet.Field, len(schema.Fields()))
 	for _, field := range schema.Fields() {
 		fieldsByName[field.Name()] = field
 	}
+	durations := parquetMetadataColumns(reader, parquetDurationColumnsMetadataKey)
+	unions := parquetMetadataColumns(reader, parquetUnionColumnsMetadataKey)
 	schemas := make([]*table.TypeDescriptor, len(columns))
 	for i, column := range columns {
 		schemas[i] = parquetNodeSchemaDescriptor(fieldsByName[column])
+		if durations[column] && schemas[i] != nil && schemas[i].Kind == table.TypeInt {
+			schemas[i] = &table.TypeDescriptor{Kind: table.TypeDuration, Nullable: schemas[i].Nullable}
+		}
+		if unions[column] {
+			schemas[i] = parquetUnionSchema(schemas[i])
+		}
 	}
 	return schemas
 }
 
+// parquetMetadataColumns returns the columns dq listed under key: int64
+// columns written as durations, or groups written as unions.
+func parquetMetadataColumns(reader *parquet.GenericReader[any], key string) map[string]bool {
+	out := map[string]bool{}
+	if file := reader.File(); file != nil {
+		if names, ok := file.Lookup(key); ok && names != "" {
+			for _, name := range strings.Split(names, ",") {
+				out[name] = true
+			}
+		}
+	}
+	return out
+}
+
+// parquetUnionSchema turns the record schema of a member0..memberN group back
+// into the union dq wrote. Any other record is left as it is.
+func parquetUnionSchema(schema *table.TypeDescriptor) *table.TypeDescriptor {
+	if schema == nil || schema.Kind != table.TypeRecord || len(schema.Fields) < 2 {
+		return schema
+	}
+	branches := make([]*table.TypeDescriptor, len(schema.Fields))
+	for _, field := range schema.Fields {
+		var i int
+		if _, err := fmt.Sscanf(field.Name, "member%d", &i); err != nil || i < 0 || i >= len(branches) || branches[i] != nil || field.Name != fmt.Sprintf("member%d", i) {
+			return schema
+		}
+		branches[i] = table.WithoutNull(field.Type)
+	}
+	// Keep one branch per member, so memberN decodes with branch N. Branches
+	// that only differed by name, which Parquet does not keep, are merged
+	// when the table normalizes the schema.
+	return &table.TypeDescriptor{Kind: table.TypeUnion, Nullable: schema.Nullable, Branches: branches}
+}
+
 func parquetNodeSchemaDescriptor(node parquet.Node) *table.TypeDescriptor {
 	if node == nil {
 		return nil
@@ -2812,6 +3033,11 @@ func parquetNodeSchemaDescriptorNonNull(node parquet.Node) *table.TypeDescriptor
 			}
 			return &table.TypeDescriptor{Kind: table.TypeList, Elem: elem}
 		}
+		if node.Type().String() == "MAP" {
+			if elem := parquetMapValueSchemaDescriptor(node); elem != nil {
+				return &table.TypeDescriptor{Kind: table.TypeMap, Elem: elem}
+			}
+		}
 		fields := node.Fields()
 		out := make([]table.FieldDescriptor, 0, len(fields))
 		for _, field := range fields {
@@ -2825,6 +3051,13 @@ func parquetNodeSchemaDescriptorNonNull(node parquet.Node) *table.TypeDescriptor
 		return &table.TypeDescriptor{Kind: table.TypeRecord, Fields: out}
 	}
 
+	return parquetLeafSchemaDescriptor(node)
+}
+
+func parquetLeafSchemaDescriptor(node parquet.Node) *table.TypeDescriptor {
+	if logical := parquetLogicalSchema(node); logical != nil {
+		return logical
+	}
 	switch node.Type().Kind() {
 	case parquet.Boolean:
 		return &table.TypeDescriptor{Kind: table.TypeBool}
@@ -2841,18 +3074,7 @@ func parquetNodeSchemaDescriptorNonNull(node parquet.Node) *table.TypeDescriptor
 
 func parquetNodeSchemaDescriptorRepeatedElem(node parquet.Node) *table.TypeDescriptor {
 	if node.Leaf() {
-		switch node.Type().Kind() {
-		case parquet.Boolean:
-			return &table.TypeDescriptor{Kind: table.TypeBool}
-		case parquet.Int32, parquet.Int64, parquet.Int96:
-			return &table.TypeDescriptor{Kind: table.TypeInt}
-		case parquet.Float, parquet.Double:
-			return &table.TypeDescriptor{Kind: table.TypeFloat}
-		case parquet.ByteArray, parquet.FixedLenByteArray:
-			return &table.TypeDescriptor{Kind: table.TypeString}
-		default:
-			return nil
-		}
+		return parquetLeafSchemaDescriptor(node)
 	}
 	fields := node.Fields()
 	out := make([]table.FieldDescriptor, 0, len(fields))
@@ -2867,6 +3089,29 @@ func parquetNodeSchemaDescriptorRepeatedElem(node parquet.Node) *table.TypeDescr
 	return &table.TypeDescriptor{Kind: table.TypeRecord, Fields: out}
 }
 
+// parquetMapValueSchemaDescriptor returns the value type of a MAP group whose
+// repeated key_value group has string keys, or nil for any other layout. A
+// value group holding only an optional "element" is the wrapper dq writes for
+// logical types and is read through.
+func parquetMapValueSchemaDescriptor(node parquet.Node) *table.TypeDescriptor {
+	fields := node.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() || fields[0].Leaf() {
+		return nil
+	}
+	kv := fields[0].Fields()
+	if len(kv) != 2 || !kv[0].Leaf() {
+		return nil
+	}
+	key := parquetLeafSchemaDescriptor(kv[0])
+	if key == nil || key.Kind != table.TypeString {
+		return nil
+	}
+	if wrapped := kv[1].Fields(); len(wrapped) == 1 && wrapped[0].Name() == "element" && parquetNullablePrimitiveElementWrapper(wrapped[0]) {
+		return parquetNodeSchemaDescriptor(wrapped[0])
+	}
+	return parquetNodeSchemaDescriptor(kv[1])
+}
+
 func parquetListElementSchemaDescriptor(node parquet.Node) *table.TypeDescriptor {
 	fields := node.Fields()
 	if len(fields) == 0 {
@@ -1702,6 +1702,7 @@ func TestAvroFieldSchemaDescriptorBranches(t *testing.T) {
 		{name: "type union in map", schema: map[string]any{"type": []any{"null", "long"}}, want: "int?"},
 		{name: "nested type map", schema: map[string]any{"type": map[string]any{"type": "array", "items": "float"}}, want: "list<float>"},
 		{name: "record", schema: recordSchema, want: "record<a:string?, z:int>"},
+		{name: "map", schema: map[string]any{"type": "map", "values": "string"}, want: "map<string,string>"},
 	}
 
 	for _, tc := range cases {
@@ -1716,7 +1717,7 @@ func TestAvroFieldSchemaDescriptorBranches(t *testing.T) {
 		schema any
 	}{
 		{name: "array unknown item", schema: map[string]any{"type": "array", "items": "decimal"}},
-		{name: "map unsupported", schema: map[string]any{"type": "map", "values": "string"}},
+		{name: "map unknown value", schema: map[string]any{"type": "map", "values": "decimal"}},
 		{name: "unknown primitive map", schema: map[string]any{"type": "decimal"}},
 		{name: "unknown nested type", schema: map[string]any{"type": map[string]any{"type": "decimal"}}},
 		{name: "unsupported shape", schema: 42},
@@ -0,0 +1,349 @@
+package loader
+
+import (
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	parquet "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/razeghi71/dq/table"
+)
+
+// Logical types annotate a physical int, long or byte array with a meaning.
+// Dates, timestamps and times map to dq's temporal types and decimals to
+// decimal(p,s). UUIDs have no dq counterpart and are read as their canonical
+// text.
+
+const julianDayOfUnixEpoch = 2440588
+
+// avroLogicalSchema returns the dq schema of an Avro type annotated with a
+// logicalType, or nil when the annotation is absent or unknown.
+func avroLogicalSchema(schema map[string]any) *table.TypeDescriptor {
+	typ, _ := schema["type"].(string)
+	if typ == "long" && schema["dq.type"] == "duration" {
+		return &table.TypeDescriptor{Kind: table.TypeDuration}
+	}
+	logical, _ := schema["logicalType"].(string)
+	switch {
+	case typ == "int" && logical == "date":
+		return &table.TypeDescriptor{Kind: table.TypeDate}
+	case typ == "long" && avroTimestampUnit(logical) != 0:
+		return &table.TypeDescriptor{Kind: table.TypeTimestamp}
+	case typ == "int" && logical == "time-millis", typ == "long" && logical == "time-micros":
+		return &table.TypeDescriptor{Kind: table.TypeDuration}
+	case (typ == "bytes" || typ == "fixed") && logical == "decimal":
+		precision, _ := schema["precision"].(float64)
+		scale, _ := schema["scale"].(float64)
+		return table.DecimalSchema(int(precision), int(scale))
+	case (typ == "string" || typ == "fixed") && logical == "uuid":
+		return &table.TypeDescriptor{Kind: table.TypeString}
+	default:
+		return nil
+	}
+}
+
+// avroTimestampUnit returns the tick length of an Avro
+// timestamp logical type, or 0 for other logical types. Local timestamps are
+// read as UTC.
+func avroTimestampUnit(logical string) time.Duration {
+	switch logical {
+	case "timestamp-millis", "local-timestamp-millis":
+		return time.Millisecond
+	case "timestamp-micros", "local-timestamp-micros":
+		return time.Microsecond
+	case "timestamp-nanos", "local-timestamp-nanos":
+		return time.Nanosecond
+	default:
+		return 0
+	}
+}
+
+// avroLogicalBranchName returns the union branch name goavro uses for a
+// logical type it decodes natively. Logical types goavro does not know fall
+// back to the branch name of their physical type.
+func avroLogicalBranchName(typ, logical string) (string, bool) {
+	switch typ + "." + logical {
+	case "int.date", "int.time-millis", "long.time-micros", "long.timestamp-millis", "long.timestamp-micros", "bytes.decimal":
+		return typ + "." + logical, true
+	default:
+		return "", false
+	}
+}
+
+func avroLogicalValue(v any, schema map[string]any) table.Value {
+	logical := avroLogicalSchema(schema)
+	switch val := v.(type) {
+	case time.Time:
+		if logical.Kind == table.TypeDate {
+			return table.DateFromTime(val.UTC())
+		}
+		return table.TimestampFromTime(val)
+	case time.Duration:
+		return table.DurationFromTime(val)
+	case *big.Rat:
+		scale, _ := schema["scale"].(float64)
+		if d, ok := table.ParseDecimal(val.FloatString(int(scale))); ok {
+			return d
+		}
+	case []byte:
+		if schema["logicalType"] == "uuid" && len(val) == 16 {
+			return table.StrVal(formatUUID(val))
+		}
+	case int64:
+		if unit := avroTimestampUnit(stringField(schema, "logicalType")); unit != 0 && logical.Kind == table.TypeTimestamp {
+			return table.TimestampFromTime(timeFromUnits(val, unit))
+		}
+		return table.Value{Type: logical.Kind, Int: val}
+	case int32:
+		return table.Value{Type: logical.Kind, Int: int64(val)}
+	}
+	return anyToValue(v)
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// parquetLogicalSchema returns the dq schema of a Parquet leaf whose logical
+// type dq maps specially, or nil for plain physical columns. Legacy INT96
+// columns are Impala/Spark timestamps.
+func parquetLogicalSchema(node parquet.Node) *table.TypeDescriptor {
+	logical := node.Type().LogicalType()
+	if logical == nil {
+		if node.Type().Kind() == parquet.Int96 {
+			return &table.TypeDescriptor{Kind: table.TypeTimestamp}
+		}
+		return nil
+	}
+	switch {
+	case logical.Date != nil:
+		return &table.TypeDescriptor{Kind: table.TypeDate}
+	case logical.Timestamp != nil:
+		return &table.TypeDescriptor{Kind: table.TypeTimestamp}
+	case logical.Time != nil:
+		return &table.TypeDescriptor{Kind: table.TypeDuration}
+	case logical.Decimal != nil:
+		return table.DecimalSchema(int(logical.Decimal.Precision), int(logical.Decimal.Scale))
+	case logical.UUID != nil:
+		return &table.TypeDescriptor{Kind: table.TypeString}
+	default:
+		return nil
+	}
+}
+
+// parquetLogicalColumns returns the columns whose values need logical type
+// conversion before parquetValue, keyed by column name.
+func parquetLogicalColumns(schema *parquet.Schema) map[string]parquet.Node {
+	out := map[string]parquet.Node{}
+	for _, field := range schema.Fields() {
+		if parquetNeedsLogicalConversion(field) {
+			out[field.Name()] = field
+		}
+	}
+	return out
+}
+
+func parquetNeedsLogicalConversion(node parquet.Node) bool {
+	if !node.Leaf() {
+		for _, field := range node.Fields() {
+			if parquetNeedsLogicalConversion(field) {
+				return true
+			}
+		}
+		return false
+	}
+	logical := node.Type().LogicalType()
+	if logical == nil {
+		return node.Type().Kind() == parquet.Int96
+	}
+	// Dates and microsecond timestamps already use dq's storage units.
+	return logical.Timestamp != nil && logical.Timestamp.Unit.Micros == nil ||
+		logical.Time != nil || logical.Decimal != nil || logical.UUID != nil
+}
+
+// parquetLogicalNative rewrites the physical values of a reconstructed Parquet
+// value into the native forms parquetValue understands: time.Time for
+// timestamps, time.Duration for times, decimal text for decimals, and strings
+// for UUIDs.
+func parquetLogicalNative(v any, node parquet.Node) any {
+	if v == nil {
+		return nil
+	}
+	if node.Repeated() {
+		items, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		for i, item := range items {
+			items[i] = parquetLogicalNativeOne(item, node)
+		}
+		return items
+	}
+	return parquetLogicalNativeOne(v, node)
+}
+
+func parquetLogicalNativeOne(v any, node parquet.Node) any {
+	if v == nil {
+		return nil
+	}
+	if node.Leaf() {
+		return parquetLogicalLeafNative(v, node)
+	}
+	if node.Type().String() == "LIST" {
+		items, ok := v.([]any)
+		fields := node.Fields()
+		if !ok || len(fields) != 1 {
+			return v
+		}
+		item := fields[0]
+		if listFields := item.Fields(); len(listFields) == 1 {
+			item = listFields[0]
+		}
+		for i, elem := range items {
+			items[i] = parquetLogicalNativeOne(elem, item)
+		}
+		return items
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for _, field := range node.Fields() {
+		if val, ok := m[field.Name()]; ok {
+			m[field.Name()] = parquetLogicalNative(val, field)
+		}
+	}
+	return m
+}
+
+func parquetLogicalLeafNative(v any, node parquet.Node) any {
+	logical := node.Type().LogicalType()
+	if logical == nil {
+		if i96, ok := v.(deprecated.Int96); ok {
+			nanos := int64(uint64(i96[1])<<32 | uint64(i96[0]))
+			days := int64(i96[2]) - julianDayOfUnixEpoch
+			return time.Unix(days*86400, nanos).UTC()
+		}
+		return v
+	}
+	switch {
+	case logical.Timestamp != nil:
+		n, ok := parquetInt64(v)
+		if !ok {
+			return v
+		}
+		return timeFromUnits(n, parquetTimeUnit(logical.Timestamp.Unit)).UTC()
+	case logical.Time != nil:
+		n, ok := parquetInt64(v)
+		if !ok {
+			return v
+		}
+		return time.Duration(n) * parquetTimeUnit(logical.Time.Unit)
+	case logical.Decimal != nil:
+		var unscaled *big.Int
+		switch val := v.(type) {
+		case []byte:
+			unscaled = bigIntFromTwosComplement(val)
+		default:
+			n, ok := parquetInt64(v)
+			if !ok {
+				return v
+			}
+			unscaled = big.NewInt(n)
+		}
+		return formatUnscaledDecimal(unscaled, int(logical.Decimal.Scale))
+	case logical.UUID != nil:
+		if b, ok := v.([]byte); ok && len(b) == 16 {
+			return formatUUID(b)
+		}
+	}
+	return v
+}
+
+func parquetTimeUnit(unit format.TimeUnit) time.Duration {
+	switch {
+	case unit.Millis != nil:
+		return time.Millisecond
+	case unit.Nanos != nil:
+		return time.Nanosecond
+	default:
+		return time.Microsecond
+	}
+}
+
+// timeFromUnits converts n ticks of unit since the Unix epoch without
+// overflowing time.Duration for far-off millisecond timestamps.
+func timeFromUnits(n int64, unit time.Duration) time.Time {
+	switch unit {
+	case time.Millisecond:
+		return time.UnixMilli(n)
+	case time.Nanosecond:
+		return time.Unix(0, n)
+	default:
+		return time.UnixMicro(n)
+	}
+}
+
+func parquetInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func parquetTemporalValue(v any, kind table.ValueType) table.Value {
+	var n int64
+	switch val := v.(type) {
+	case time.Time:
+		if kind == table.TypeDate {
+			return table.DateFromTime(val.UTC())
+		}
+		return table.TimestampFromTime(val)
+	case time.Duration:
+		return table.DurationFromTime(val)
+	case int32:
+		n = int64(val)
+	case int64:
+		n = val
+	default:
+		return parquetUnknownValue(v)
+	}
+	return table.Value{Type: kind, Int: n}
+}
+
+// bigIntFromTwosComplement decodes a big-endian two's complement integer, the
+// encoding of byte-array decimals in both Avro and Parquet.
+func bigIntFromTwosComplement(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return n
+}
+
+func formatUnscaledDecimal(unscaled *big.Int, scale int) string {
+	return table.DecimalVal(unscaled, scale).Str
+}
+
+// parquetDecimalValue converts the decimal text parquetLogicalNative produces
+// into a decimal value.
+func parquetDecimalValue(v any) table.Value {
+	if s, ok := v.(string); ok {
+		if d, ok := table.ParseDecimal(s); ok {
+			return d
+		}
+	}
+	return parquetUnknownValue(v)
+}
+
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
@@ -0,0 +1,137 @@
+package loader
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/razeghi71/dq/table"
+)
+
+type parquetLogicalFixtureRow struct {
+	Millis  int64            `parquet:"millis,timestamp(millisecond)"`
+	Nanos   int64            `parquet:"nanos,timestamp(nanosecond)"`
+	Legacy  deprecated.Int96 `parquet:"legacy"`
+	Clock   int32            `parquet:"clock,time(millisecond)"`
+	Price   int64            `parquet:"price,decimal(2:18)"`
+	Big     [9]byte          `parquet:"big,decimal(3:20)"`
+	ID      [16]byte         `parquet:"id,uuid"`
+	History []struct {
+		At *time.Time `parquet:"element,timestamp(millisecond)"`
+	} `parquet:"history,list"`
+	Meta struct {
+		Seen int64 `parquet:"seen,timestamp(millisecond)"`
+	} `parquet:"meta"`
+}
+
+func TestLoadParquetLogicalTypes(t *testing.T) {
+	hour := uint64(time.Hour)
+	second := time.UnixMilli(1000)
+	row := parquetLogicalFixtureRow{
+		Millis: 1700000000123,
+		Nanos:  1700000000123456789,
+		// 2024-03-01 (Julian day 2460371) plus one hour.
+		Legacy: deprecated.Int96{uint32(hour), uint32(hour >> 32), 2460371},
+		Clock:  int32((90 * time.Minute) / time.Millisecond),
+		Price:  -12345,
+		Big:    [9]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xcf, 0xc7},
+		ID:     [16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+	}
+	row.History = append(row.History, struct {
+		At *time.Time `parquet:"element,timestamp(millisecond)"`
+	}{At: &second})
+	row.Meta.Seen = 2000
+	path := writeParquetFixture(t, []parquetLogicalFixtureRow{row})
+
+	for _, load := range []func() (*table.Table, error){
+		func() (*table.Table, error) { return Load(path, Options{Format: "parquet"}) },
+		func() (*table.Table, error) { return loadPreparedParquetFixture(path) },
+	} {
+		tbl, err := load()
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		want := map[string][2]string{
+			"millis":  {"timestamp", "2023-11-14T22:13:20.123Z"},
+			"nanos":   {"timestamp", "2023-11-14T22:13:20.123456Z"},
+			"legacy":  {"timestamp", "2024-03-01T01:00:00Z"},
+			"clock":   {"duration", "1h30m0s"},
+			"price":   {"decimal(18,2)", "-123.45"},
+			"big":     {"decimal(20,3)", "-12.345"},
+			"id":      {"string", "12345678-9abc-def0-1234-56789abcdef0"},
+			"history": {"list<timestamp?>", "[1970-01-01T00:00:01Z]"},
+			"meta":    {"record<seen:timestamp>", "{seen:1970-01-01T00:00:02Z}"},
+		}
+		requireLogicalColumns(t, tbl, want)
+	}
+}
+
+func loadPreparedParquetFixture(path string) (*table.Table, error) {
+	prepared, err := Prepare(path, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return prepared.LoadSpec(SourceLoadSpec{ReadColumns: table.AllColumns(), OutputColumns: table.AllColumns()})
+}
+
+func TestLoadAvroLogicalTypes(t *testing.T) {
+	schema := `{
+	  "type":"record","name":"Row","fields":[
+	    {"name":"millis","type":{"type":"long","logicalType":"timestamp-millis"}},
+	    {"name":"local","type":{"type":"long","logicalType":"local-timestamp-micros"}},
+	    {"name":"nanos","type":["null",{"type":"long","logicalType":"timestamp-nanos"}]},
+	    {"name":"day","type":["null",{"type":"int","logicalType":"date"}]},
+	    {"name":"clock","type":{"type":"int","logicalType":"time-millis"}},
+	    {"name":"price","type":["null",{"type":"bytes","logicalType":"decimal","precision":18,"scale":2}]},
+	    {"name":"big","type":{"type":"fixed","name":"Big","size":9,"logicalType":"decimal","precision":20,"scale":3}},
+	    {"name":"id","type":{"type":"string","logicalType":"uuid"}},
+	    {"name":"raw_id","type":{"type":"fixed","name":"Raw","size":16,"logicalType":"uuid"}}
+	  ]}`
+	path := writeAvroUnionTDDFile(t, schema, []map[string]any{{
+		"millis": time.UnixMilli(1700000000123).UTC(),
+		"local":  int64(1700000000123456),
+		"nanos":  map[string]any{"long": int64(1700000000123456789)},
+		"day":    map[string]any{"int.date": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		"clock":  90 * time.Minute,
+		"price":  map[string]any{"bytes.decimal": big.NewRat(-12345, 100)},
+		"big":    big.NewRat(-12345, 1000),
+		"id":     "12345678-9abc-def0-1234-56789abcdef0",
+		"raw_id": []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+	}})
+	tbl, err := Load(path, Options{Format: "avro"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	requireLogicalColumns(t, tbl, map[string][2]string{
+		"millis": {"timestamp", "2023-11-14T22:13:20.123Z"},
+		"local":  {"timestamp", "2023-11-14T22:13:20.123456Z"},
+		"nanos":  {"timestamp?", "2023-11-14T22:13:20.123456Z"},
+		"day":    {"date?", "2024-03-01"},
+		"clock":  {"duration", "1h30m0s"},
+		"price":  {"decimal(18,2)?", "-123.45"},
+		"big":    {"decimal(20,3)", "-12.345"},
+		"id":     {"string", "12345678-9abc-def0-1234-56789abcdef0"},
+		"raw_id": {"string", "12345678-9abc-def0-1234-56789abcdef0"},
+	})
+}
+
+func requireLogicalColumns(t *testing.T, tbl *table.Table, want map[string][2]string) {
+	t.Helper()
+	schema := tbl.Schema()
+	for _, col := range schema.Columns {
+		w, ok := want[col.Name]
+		if !ok {
+			continue
+		}
+		if got := col.Type.String(); got != w[0] {
+			t.Errorf("%s schema: got %s, want %s", col.Name, got, w[0])
+		}
+		if got := tbl.Get(0, col.Name).AsString(); got != w[1] {
+			t.Errorf("%s value: got %q, want %q", col.Name, got, w[1])
+		}
+	}
+	if len(schema.Columns) != len(want) {
+		t.Errorf("columns: got %v", tbl.Columns)
+	}
+}
@@ -17,8 +17,12 @@ type Options struct {
 	Delim               string
 	AllowJaggedRows     *bool
 	IgnoreUnknownValues *bool
-	InferRows           int  // csv/json/jsonl; default 20480. Set InferRowsSet=true to use 0 for csv.
-	InferRowsSet        bool // distinguishes explicit infer_rows=0 from the default.
+	Decimals            *bool    // csv only; infer decimal(p,s) instead of float for plain decimals.
+	Dates               *bool    // csv only; infer date and timestamp for ISO date and timestamp cells.
+	Maps                []string // json/jsonl only; field paths whose objects load as map<string,T>.
+	UnionNames          *bool    // avro only; keep named union branches apart and tag values with their branch.
+	InferRows           int      // csv/json/jsonl; default 20480. Set InferRowsSet=true to use 0 for csv.
+	InferRowsSet        bool     // distinguishes explicit infer_rows=0 from the default.
 	MaxBadRecords       int
 	MaxBadRecordsSet    bool
 }
@@ -45,6 +49,10 @@ func FromAST(o ast.LoadOptions) Options {
 		Delim:               o.Delim,
 		AllowJaggedRows:     o.AllowJaggedRows,
 		IgnoreUnknownValues: o.IgnoreUnknownValues,
+		Decimals:            o.Decimals,
+		Dates:               o.Dates,
+		Maps:                o.Maps,
+		UnionNames:          o.UnionNames,
 	}
 	if o.InferRows != nil {
 		opts.InferRows = *o.InferRows
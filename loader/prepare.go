@@ -158,7 +158,7 @@ func prepareReplayable(filename string, opts Options) (*PreparedSource, error) {
 		if compression != "" {
 			return nil, fmt.Errorf("compression=%s applies only to csv, json, and jsonl formats", compression)
 		}
-		schema, err := inspectAvroSchema(filename)
+		schema, err := inspectAvroSchema(filename, avroUnionNames(opts))
 		if err != nil {
 			return nil, err
 		}
@@ -220,10 +220,10 @@ func prepareCSVSourceReader(f io.ReadCloser, cfg csvLoadConfig) (*PreparedSource
 	}
 
 	groups := []csvRowGroup{{columns: append([]string(nil), columns...), source: cfg.source, rows: window.sampleRows}}
-	types := inferCSVColumnTypes(columns, groups, cfg.inferRows)
+	types, decimals := inferCSVColumnTypes(columns, groups, cfg.inferRows, cfg.decimals, cfg.dates)
 	totalRows := len(window.sampleRows) + len(window.pendingRows)
 	nullableAll := csvStreamingInferenceNeedsConservativeNullability(cfg.inferRows, totalRows, window.sampleExhausted)
-	schema := csvSchemaFromTypes(columns, types, csvNullableColumns(columns, groups), nullableAll)
+	schema := csvSchemaFromTypes(columns, types, decimals, csvNullableColumns(columns, groups), nullableAll)
 	return &PreparedSource{
 		Schema: schema,
 		csv: &preparedCSVSource{
@@ -258,11 +258,11 @@ func prepareJSONLike(filename, format string, cfg jsonLoadConfig) (*PreparedSour
 	}, nil
 }
 
-func csvSchemaFromTypes(columns []string, types []table.ValueType, nullable []bool, nullableAll bool) table.Schema {
-	return table.NewSchema(columns, csvSchemasFromTypes(columns, types, nullable, nullableAll))
+func csvSchemaFromTypes(columns []string, types []table.ValueType, decimals []csvDecimalShape, nullable []bool, nullableAll bool) table.Schema {
+	return table.NewSchema(columns, csvSchemasFromTypes(columns, types, decimals, nullable, nullableAll))
 }
 
-func csvSchemasFromTypes(columns []string, types []table.ValueType, nullable []bool, nullableAll bool) []*table.TypeDescriptor {
+func csvSchemasFromTypes(columns []string, types []table.ValueType, decimals []csvDecimalShape, nullable []bool, nullableAll bool) []*table.TypeDescriptor {
 	schemas := make([]*table.TypeDescriptor, len(columns))
 	for i := range columns {
 		typ := table.TypeString
@@ -270,6 +270,9 @@ func csvSchemasFromTypes(columns []string, types []table.ValueType, nullable []b
 			typ = types[i]
 		}
 		schemas[i] = table.ScalarSchema(typ)
+		if typ == table.TypeDecimal && i < len(decimals) {
+			schemas[i] = decimals[i].schema()
+		}
 		if nullableAll || (i < len(nullable) && nullable[i]) {
 			schemas[i] = table.WithNullable(schemas[i])
 		}
@@ -938,7 +941,7 @@ func newJSONArrayRecordStream(closer io.Closer, cfg jsonLoadConfig, skip int) (*
 			}
 			return stream, nil
 		}
-		if _, err := decodeJSONElementRecord(dec, cfg.source, i); err != nil {
+		if _, err := decodeJSONElementRecord(dec, cfg.source, i, cfg.maps); err != nil {
 			return nil, err
 		}
 		stream.rowIdx++
@@ -959,7 +962,7 @@ func (s *jsonArrayRecordStream) Next() (jsonLogicalRecord, bool, error) {
 		}
 		return jsonLogicalRecord{}, false, nil
 	}
-	rec, err := decodeJSONElementRecord(s.dec, s.cfg.source, s.rowIdx)
+	rec, err := decodeJSONElementRecord(s.dec, s.cfg.source, s.rowIdx, s.cfg.maps)
 	if err != nil {
 		return jsonLogicalRecord{}, false, err
 	}
@@ -994,13 +997,14 @@ type jsonlRecordStream struct {
 	closer  io.Closer
 	scanner *bufio.Scanner
 	source  string
+	maps    jsonMapPaths
 	lineNum int
 	closed  bool
 }
 
 func newJSONLRecordStream(closer io.Closer, cfg jsonLoadConfig, skip int) *jsonlRecordStream {
 	r := closer.(io.Reader)
-	stream := &jsonlRecordStream{closer: closer, scanner: newJSONLScanner(r), source: cfg.source}
+	stream := &jsonlRecordStream{closer: closer, scanner: newJSONLScanner(r), source: cfg.source, maps: cfg.maps}
 	for skipped := 0; skipped < skip && stream.scanner.Scan(); {
 		stream.lineNum++
 		if strings.TrimSpace(stream.scanner.Text()) == "" {
@@ -1021,7 +1025,7 @@ func (s *jsonlRecordStream) Next() (jsonLogicalRecord, bool, error) {
 		if line == "" {
 			continue
 		}
-		return decodeJSONLLineRecord(line, s.source, s.lineNum), true, nil
+		return decodeJSONLLineRecord(line, s.source, s.lineNum, s.maps), true, nil
 	}
 	if err := s.scanner.Err(); err != nil {
 		return jsonLogicalRecord{}, false, fmt.Errorf("error reading JSONL: %w", err)
@@ -1052,7 +1056,7 @@ func (p *preparedFileSource) load(spec SourceLoadSpec) (*table.Table, error) {
 	format, _ := resolveFormatCompression(p.filename, p.opts)
 	switch format {
 	case "avro":
-		return loadPreparedAvroSource(p.filename, plan)
+		return loadPreparedAvroSource(p.filename, plan, avroUnionNames(p.opts))
 	case "parquet":
 		return loadPreparedParquetSource(p.filename, plan)
 	default:
@@ -1072,7 +1076,7 @@ func (p *preparedFileSource) stream(spec SourceLoadSpec) (rowstream.Stream, erro
 	format, _ := resolveFormatCompression(p.filename, p.opts)
 	switch format {
 	case "avro":
-		return streamPreparedAvroSource(p.filename, plan)
+		return streamPreparedAvroSource(p.filename, plan, avroUnionNames(p.opts))
 	case "parquet":
 		return streamPreparedParquetSource(p.filename, plan)
 	default:
@@ -1127,7 +1131,7 @@ func inspectJSONSchemaFromReader(r io.Reader, cfg jsonLoadConfig) (preparedJSONI
 		}
 		rowIdx := inferSeen
 		inferSeen++
-		rec, err := decodeJSONElementRecord(dec, cfg.source, rowIdx)
+		rec, err := decodeJSONElementRecord(dec, cfg.source, rowIdx, cfg.maps)
 		if err != nil {
 			return preparedJSONInspection{}, err
 		}
@@ -1143,6 +1147,11 @@ func inspectJSONSchemaFromReader(r io.Reader, cfg jsonLoadConfig) (preparedJSONI
 	} else if err := validateJSONArraySyntaxRemainder(dec); err != nil {
 		return preparedJSONInspection{}, err
 	}
+	if state.goodRows > 0 {
+		if err := cfg.maps.validate(state.columns, state.schemas); err != nil {
+			return preparedJSONInspection{}, err
+		}
+	}
 	if jsonPreparedInferenceNeedsConservativeNullability(cfg.inferRows, inferSeen, sampleExhausted) {
 		state.schemas = deepNullableSchemas(state.schemas)
 	}
@@ -1173,7 +1182,7 @@ func validateJSONArrayEnd(dec *json.Decoder) error {
 	return nil
 }
 
-func decodeJSONElementRecord(dec *json.Decoder, source string, rowIdx int) (jsonLogicalRecord, error) {
+func decodeJSONElementRecord(dec *json.Decoder, source string, rowIdx int, maps jsonMapPaths) (jsonLogicalRecord, error) {
 	loc := fmt.Sprintf("row %d", rowIdx+1)
 	var value interface{}
 	if err := dec.Decode(&value); err != nil {
@@ -1183,7 +1192,7 @@ func decodeJSONElementRecord(dec *json.Decoder, source string, rowIdx int) (json
 	if !ok || rec == nil {
 		return jsonLogicalRecord{loc: loc, source: source, err: fmt.Errorf("expected JSON object")}, nil
 	}
-	fields, err := buildJSONRecordFields(rec)
+	fields, err := buildJSONRecordFields(rec, maps)
 	if err != nil {
 		return jsonLogicalRecord{loc: loc, source: source, err: err}, nil
 	}
@@ -1211,7 +1220,7 @@ func inspectJSONLSchemaFromReader(r io.Reader, cfg jsonLoadConfig) (preparedJSON
 		}
 		rowIdx := inferSeen
 		inferSeen++
-		rec := decodeJSONLLineRecord(line, cfg.source, lineNum)
+		rec := decodeJSONLLineRecord(line, cfg.source, lineNum, cfg.maps)
 		records = append(records, rec)
 		if err := inferPreparedJSONRecord(&state, rec, rowIdx, cfg, &badRecords); err != nil {
 			return preparedJSONInspection{}, err
@@ -1220,6 +1229,11 @@ func inspectJSONLSchemaFromReader(r io.Reader, cfg jsonLoadConfig) (preparedJSON
 	if err := scanner.Err(); err != nil {
 		return preparedJSONInspection{}, fmt.Errorf("error reading JSONL: %w", err)
 	}
+	if state.goodRows > 0 {
+		if err := cfg.maps.validate(state.columns, state.schemas); err != nil {
+			return preparedJSONInspection{}, err
+		}
+	}
 	if jsonPreparedInferenceNeedsConservativeNullability(cfg.inferRows, inferSeen, sampleExhausted) {
 		state.schemas = deepNullableSchemas(state.schemas)
 	}
@@ -1230,7 +1244,7 @@ func inspectJSONLSchemaFromReader(r io.Reader, cfg jsonLoadConfig) (preparedJSON
 	}, nil
 }
 
-func decodeJSONLLineRecord(line, source string, lineNum int) jsonLogicalRecord {
+func decodeJSONLLineRecord(line, source string, lineNum int, maps jsonMapPaths) jsonLogicalRecord {
 	loc := fmt.Sprintf("line %d", lineNum)
 	var value interface{}
 	dec := json.NewDecoder(strings.NewReader(line))
@@ -1245,7 +1259,7 @@ func decodeJSONLLineRecord(line, source string, lineNum int) jsonLogicalRecord {
 	if !ok || rec == nil {
 		return jsonLogicalRecord{loc: loc, source: source, err: fmt.Errorf("expected JSON object")}
 	}
-	fields, err := buildJSONRecordFields(rec)
+	fields, err := buildJSONRecordFields(rec, maps)
 	if err != nil {
 		return jsonLogicalRecord{loc: loc, source: source, err: err}
 	}
@@ -1385,7 +1399,7 @@ func cloneStringIntMap(in map[string]int) map[string]int {
 	return out
 }
 
-func inspectAvroSchema(filename string) (table.Schema, error) {
+func inspectAvroSchema(filename string, unionNames bool) (table.Schema, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return table.Schema{}, fmt.Errorf("cannot open %s: %w", filename, err)
@@ -1396,7 +1410,7 @@ func inspectAvroSchema(filename string) (table.Schema, error) {
 	if err != nil {
 		return table.Schema{}, fmt.Errorf("cannot read Avro OCF from %s: %w", filename, err)
 	}
-	columns, schemas, _, err := avroSchemaParts(ocfr.Codec().Schema())
+	columns, schemas, _, err := avroSchemaParts(ocfr.Codec().Schema(), unionNames)
 	if err != nil {
 		return table.Schema{}, err
 	}
@@ -1415,11 +1429,11 @@ func inspectParquetSchema(filename string) (table.Schema, error) {
 
 	schema := reader.Schema()
 	columns := parquetColumns(schema, reader)
-	schemas := parquetColumnSchemas(schema, columns)
+	schemas := parquetColumnSchemas(reader, columns)
 	return table.NewSchema(columns, schemas), nil
 }
 
-func loadPreparedAvroSource(filename string, plan preparedSourceLoadPlan) (*table.Table, error) {
+func loadPreparedAvroSource(filename string, plan preparedSourceLoadPlan, unionNames bool) (*table.Table, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("cannot open %s: %w", filename, err)
@@ -1430,7 +1444,7 @@ func loadPreparedAvroSource(filename string, plan preparedSourceLoadPlan) (*tabl
 	if err != nil {
 		return nil, fmt.Errorf("cannot read Avro OCF from %s: %w", filename, err)
 	}
-	columns, schemas, fieldSchemas, err := avroSchemaParts(ocfr.Codec().Schema())
+	columns, schemas, fieldSchemas, err := avroSchemaParts(ocfr.Codec().Schema(), unionNames)
 	if err != nil {
 		return nil, err
 	}
@@ -1485,10 +1499,11 @@ func loadPreparedParquetSource(filename string, plan preparedSourceLoadPlan) (*t
 
 	schema := reader.Schema()
 	columns := parquetColumns(schema, reader)
-	schemas := parquetColumnSchemas(schema, columns)
+	schemas := parquetColumnSchemas(reader, columns)
 	if err := validatePreparedSourceSchema(plan, table.NewSchema(columns, schemas)); err != nil {
 		return nil, err
 	}
+	logical := parquetLogicalColumns(schema)
 
 	t := table.NewTableWithSchemas(plan.outputColumns, plan.outputSchemas)
 	buf := make([]any, 128)
@@ -1502,7 +1517,7 @@ func loadPreparedParquetSource(filename string, plan preparedSourceLoadPlan) (*t
 			readVals := make([]table.Value, len(plan.readSourceIndexes))
 			for readIdx, sourceIdx := range plan.readSourceIndexes {
 				col := plan.sourceColumns[sourceIdx]
-				val := parquetValue(row[col], plan.sourceSchemas[sourceIdx])
+				val := parquetColumnValue(row, col, schemas[sourceIdx], logical)
 				cv, err := table.CoerceValueToSchemaAtPath(val, plan.sourceSchemas[sourceIdx], col)
 				if err != nil {
 					return nil, fmt.Errorf("error materializing Parquet row: %w", err)
@@ -1523,7 +1538,7 @@ func loadPreparedParquetSource(filename string, plan preparedSourceLoadPlan) (*t
 	return t, nil
 }
 
-func streamPreparedAvroSource(filename string, plan preparedSourceLoadPlan) (rowstream.Stream, error) {
+func streamPreparedAvroSource(filename string, plan preparedSourceLoadPlan, unionNames bool) (rowstream.Stream, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("cannot open %s: %w", filename, err)
@@ -1533,7 +1548,7 @@ func streamPreparedAvroSource(filename string, plan preparedSourceLoadPlan) (row
 		_ = f.Close()
 		return nil, fmt.Errorf("cannot read Avro OCF from %s: %w", filename, err)
 	}
-	columns, schemas, fieldSchemas, err := avroSchemaParts(ocfr.Codec().Schema())
+	columns, schemas, fieldSchemas, err := avroSchemaParts(ocfr.Codec().Schema(), unionNames)
 	if err != nil {
 		_ = f.Close()
 		return nil, err
@@ -1622,18 +1637,20 @@ func streamPreparedParquetSource(filename string, plan preparedSourceLoadPlan) (
 	reader := parquet.NewGenericReader[any](f)
 	schema := reader.Schema()
 	columns := parquetColumns(schema, reader)
-	schemas := parquetColumnSchemas(schema, columns)
+	schemas := parquetColumnSchemas(reader, columns)
 	if err := validatePreparedSourceSchema(plan, table.NewSchema(columns, schemas)); err != nil {
 		_ = reader.Close()
 		_ = f.Close()
 		return nil, err
 	}
 	return &parquetPreparedStream{
-		file:   f,
-		reader: reader,
-		plan:   plan,
-		buf:    make([]any, 128),
-		schema: table.NewSchema(plan.outputColumns, plan.outputSchemas),
+		file:    f,
+		reader:  reader,
+		plan:    plan,
+		schemas: schemas,
+		logical: parquetLogicalColumns(schema),
+		buf:     make([]any, 128),
+		schema:  table.NewSchema(plan.outputColumns, plan.outputSchemas),
 	}, nil
 }
 
@@ -1641,6 +1658,8 @@ type parquetPreparedStream struct {
 	file     io.Closer
 	reader   *parquet.GenericReader[any]
 	plan     preparedSourceLoadPlan
+	schemas  []*table.TypeDescriptor // file column schemas; a union keeps one branch per member
+	logical  map[string]parquet.Node
 	buf      []any
 	bufN     int
 	bufAt    int
@@ -1664,7 +1683,7 @@ func (s *parquetPreparedStream) Next() (rowstream.Row, bool, error) {
 			readVals := make([]table.Value, len(s.plan.readSourceIndexes))
 			for readIdx, sourceIdx := range s.plan.readSourceIndexes {
 				col := s.plan.sourceColumns[sourceIdx]
-				val := parquetValue(row[col], s.plan.sourceSchemas[sourceIdx])
+				val := parquetColumnValue(row, col, s.schemas[sourceIdx], s.logical)
 				cv, err := table.CoerceValueToSchemaAtPath(val, s.plan.sourceSchemas[sourceIdx], col)
 				if err != nil {
 					return nil, false, fmt.Errorf("error materializing Parquet row: %w", err)
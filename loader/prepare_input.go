@@ -309,11 +309,11 @@ func prepareGlobCSV(pattern string, matches []string, opts Options) (*PreparedSo
 	if err != nil {
 		return nil, err
 	}
-	types, nullable, nullableAll, err := inferCSVGlobSchema(columns, anchor, shards, cfg)
+	types, decimals, nullable, nullableAll, err := inferCSVGlobSchema(columns, anchor, shards, cfg)
 	if err != nil {
 		return nil, err
 	}
-	schemas := csvSchemasFromTypes(columns, types, nullable, nullableAll)
+	schemas := csvSchemasFromTypes(columns, types, decimals, nullable, nullableAll)
 	schema := table.NewSchema(columns, schemas)
 	return &PreparedSource{
 		Schema: schema,
@@ -404,8 +404,12 @@ func inspectCSVGlobHeaderlessPlans(matches []string, cfg csvLoadConfig) ([]strin
 	return columns, anchor, shards, nil
 }
 
-func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg csvLoadConfig) ([]table.ValueType, []bool, bool, error) {
+func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg csvLoadConfig) ([]table.ValueType, []csvDecimalShape, []bool, bool, error) {
 	types := make([]table.ValueType, len(columns))
+	var decimals []csvDecimalShape
+	if cfg.decimals {
+		decimals = make([]csvDecimalShape, len(columns))
+	}
 	if cfg.inferRows == 0 {
 		for i := range types {
 			types[i] = table.TypeString
@@ -423,18 +427,18 @@ func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg
 		}
 		rows, err := openCSVGlobShardRows(shard.path, anchor, cfg)
 		if err != nil {
-			return nil, nil, false, fmt.Errorf("loading glob %q: loading %q: %w", cfg.source, shard.path, err)
+			return nil, nil, nil, false, fmt.Errorf("loading glob %q: loading %q: %w", cfg.source, shard.path, err)
 		}
 		if !sameColumns(rows.columns, shard.columns) {
 			_ = rows.Close()
-			return nil, nil, false, fmt.Errorf("%s: glob shard schema changed after planning", shard.path)
+			return nil, nil, nil, false, fmt.Errorf("%s: glob shard schema changed after planning", shard.path)
 		}
 		mapping := csvColumnMapping(columns, rows.columns)
 		for {
 			row, ok, err := rows.Next()
 			if err != nil {
 				_ = rows.Close()
-				return nil, nil, false, fmt.Errorf("loading glob %q: loading %q: %w", cfg.source, shard.path, err)
+				return nil, nil, nil, false, fmt.Errorf("loading glob %q: loading %q: %w", cfg.source, shard.path, err)
 			}
 			if !ok {
 				break
@@ -446,7 +450,7 @@ func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg
 				break
 			}
 			if cfg.inferRows < 0 || sampled < cfg.inferRows {
-				applyCSVInferenceRow(types, mapping, row)
+				applyCSVInferenceRow(types, decimals, mapping, row, cfg.dates)
 				applyCSVNullabilityRow(nullable, mapping, row)
 				sampled++
 				continue
@@ -456,14 +460,10 @@ func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg
 			break
 		}
 		if err := rows.Close(); err != nil {
-			return nil, nil, false, err
-		}
-	}
-	for i := range types {
-		if types[i] == table.TypeNull {
-			types[i] = table.TypeString
+			return nil, nil, nil, false, err
 		}
 	}
+	finalizeCSVInferredTypes(types, decimals)
 	nullableAll := false
 	switch {
 	case !dataSeen:
@@ -475,7 +475,7 @@ func inferCSVGlobSchema(columns, anchor []string, shards []csvGlobShardPlan, cfg
 	default:
 		nullableAll = !sampleExhausted
 	}
-	return types, nullable, nullableAll, nil
+	return types, decimals, nullable, nullableAll, nil
 }
 
 func applyCSVNullabilityRow(nullable []bool, mapping []int, row csvRawRow) {
@@ -936,7 +936,7 @@ func prepareGlobFile(pattern string, matches []string, format string, opts Optio
 		)
 		switch format {
 		case "avro":
-			schema, err = inspectAvroSchema(path)
+			schema, err = inspectAvroSchema(path, avroUnionNames(opts))
 		case "parquet":
 			schema, err = inspectParquetSchema(path)
 		default:
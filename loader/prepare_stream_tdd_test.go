@@ -1298,7 +1298,7 @@ func TestPrepareStreamTDDErrorBranches(t *testing.T) {
 	})
 
 	t.Run("avro_stream_open_error", func(t *testing.T) {
-		_, err := streamPreparedAvroSource("missing.avro", preparedSourceLoadPlan{})
+		_, err := streamPreparedAvroSource("missing.avro", preparedSourceLoadPlan{}, false)
 		if err == nil || !strings.Contains(strings.ToLower(err.Error()), "cannot open") {
 			t.Fatalf("missing avro stream error: got %v", err)
 		}
@@ -1306,7 +1306,7 @@ func TestPrepareStreamTDDErrorBranches(t *testing.T) {
 
 	t.Run("avro_stream_invalid_file", func(t *testing.T) {
 		path := writeSourceProjectionTDDFile(t, "not-avro.avro", "not avro")
-		_, err := streamPreparedAvroSource(path, preparedSourceLoadPlan{})
+		_, err := streamPreparedAvroSource(path, preparedSourceLoadPlan{}, false)
 		if err == nil || !strings.Contains(strings.ToLower(err.Error()), "cannot read avro") {
 			t.Fatalf("invalid avro stream error: got %v", err)
 		}
@@ -1368,7 +1368,7 @@ func TestPrepareStreamTDDErrorBranches(t *testing.T) {
 				{Kind: table.TypeInt},
 			},
 		}
-		_, err := streamPreparedAvroSource(testdataDir+"/users.avro", plan)
+		_, err := streamPreparedAvroSource(testdataDir+"/users.avro", plan, false)
 		if err == nil || !strings.Contains(err.Error(), "schema column count changed") {
 			t.Fatalf("avro schema mismatch error: got %v", err)
 		}
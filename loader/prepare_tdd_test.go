@@ -720,11 +720,11 @@ func TestPrepareTDDValidatePreparedSourceSchemaDiagnostics(t *testing.T) {
 
 func TestPrepareTDDMetadataInspectAndLoadErrors(t *testing.T) {
 	missing := filepath.Join(t.TempDir(), "missing.avro")
-	if _, err := inspectAvroSchema(missing); err == nil || !strings.Contains(err.Error(), "cannot open") {
+	if _, err := inspectAvroSchema(missing, false); err == nil || !strings.Contains(err.Error(), "cannot open") {
 		t.Fatalf("missing avro inspect error: got %v", err)
 	}
 	invalidAvro := writeSourceProjectionTDDFile(t, "bad.avro", "not-avro")
-	if _, err := inspectAvroSchema(invalidAvro); err == nil || !strings.Contains(strings.ToLower(err.Error()), "avro") {
+	if _, err := inspectAvroSchema(invalidAvro, false); err == nil || !strings.Contains(strings.ToLower(err.Error()), "avro") {
 		t.Fatalf("invalid avro inspect error: got %v", err)
 	}
 	plan := preparedSourceLoadPlan{
@@ -735,7 +735,7 @@ func TestPrepareTDDMetadataInspectAndLoadErrors(t *testing.T) {
 		outputSchemas:     []*table.TypeDescriptor{{Kind: table.TypeInt}},
 		outputFromRead:    []int{0},
 	}
-	if _, err := loadPreparedAvroSource(missing, plan); err == nil || !strings.Contains(err.Error(), "cannot open") {
+	if _, err := loadPreparedAvroSource(missing, plan, false); err == nil || !strings.Contains(err.Error(), "cannot open") {
 		t.Fatalf("missing avro load error: got %v", err)
 	}
 	if _, err := (&preparedJSONSource{format: "xml"}).load(SourceLoadSpec{}); err == nil || !strings.Contains(err.Error(), "unsupported format") {
@@ -121,6 +121,11 @@ func exprPackedSpan(expr ast.Expr) ast.PackedSpan {
 			return 0
 		}
 		return e.SourceSpan
+	case *ast.LambdaExpr:
+		if e == nil {
+			return 0
+		}
+		return e.SourceSpan
 	default:
 		if expr == nil {
 			return 0
@@ -362,6 +367,10 @@ var loadOptionKeys = map[string]bool{
 	"delim":                 true,
 	"allow_jagged_rows":     true,
 	"ignore_unknown_values": true,
+	"decimals":              true,
+	"dates":                 true,
+	"maps":                  true,
+	"union_names":           true,
 	"infer_rows":            true,
 	"max_bad_records":       true,
 }
@@ -409,7 +418,7 @@ func (p *Parser) parseWithClause() (ast.LoadOptions, error) {
 				return ast.LoadOptions{}, fmt.Errorf("with: compression value must be an identifier, got %s", valTok.Type)
 			}
 			opts.Compression = strings.ToLower(valTok.Val)
-		case "header", "allow_jagged_rows", "ignore_unknown_values":
+		case "header", "allow_jagged_rows", "ignore_unknown_values", "decimals", "dates", "union_names":
 			switch valTok.Type {
 			case lexer.TokenTrue:
 				v := true
@@ -418,6 +427,12 @@ func (p *Parser) parseWithClause() (ast.LoadOptions, error) {
 					opts.Header = &v
 				case "allow_jagged_rows":
 					opts.AllowJaggedRows = &v
+				case "decimals":
+					opts.Decimals = &v
+				case "dates":
+					opts.Dates = &v
+				case "union_names":
+					opts.UnionNames = &v
 				default:
 					opts.IgnoreUnknownValues = &v
 				}
@@ -428,6 +443,12 @@ func (p *Parser) parseWithClause() (ast.LoadOptions, error) {
 					opts.Header = &v
 				case "allow_jagged_rows":
 					opts.AllowJaggedRows = &v
+				case "decimals":
+					opts.Decimals = &v
+				case "dates":
+					opts.Dates = &v
+				case "union_names":
+					opts.UnionNames = &v
 				default:
 					opts.IgnoreUnknownValues = &v
 				}
@@ -442,6 +463,17 @@ func (p *Parser) parseWithClause() (ast.LoadOptions, error) {
 				return ast.LoadOptions{}, fmt.Errorf("with: delim cannot be empty")
 			}
 			opts.Delim = valTok.Val
+		case "maps":
+			if valTok.Type != lexer.TokenString {
+				return ast.LoadOptions{}, fmt.Errorf("with: maps value must be a string of comma-separated field paths, got %s", valTok.Type)
+			}
+			for _, path := range strings.Split(valTok.Val, ",") {
+				path = strings.TrimSpace(path)
+				if path == "" {
+					return ast.LoadOptions{}, fmt.Errorf("with: maps value has an empty field path")
+				}
+				opts.Maps = append(opts.Maps, path)
+			}
 		case "infer_rows", "max_bad_records":
 			if valTok.Type != lexer.TokenInt {
 				return ast.LoadOptions{}, fmt.Errorf("with: %s value must be an integer, got %s", keyTok.Val, valTok.Type)
@@ -505,6 +537,16 @@ func (p *Parser) parseOp() (ast.Op, error) {
 		return p.parseRemove()
 	case "join":
 		return p.parseJoin()
+	case "window":
+		return p.parseWindow()
+	case "explode", "unnest":
+		return p.parseExplode()
+	case "pivot":
+		return p.parsePivot()
+	case "unpivot":
+		return p.parseUnpivot()
+	case "union", "concat":
+		return p.parseUnion()
 	default:
 		return nil, fmt.Errorf("unknown operation %q at position %d", tok.Val, tok.Pos)
 	}
@@ -703,11 +745,300 @@ func (p *Parser) parseRename() (ast.Op, error) {
 	return &ast.RenameOp{Pairs: pairs, SourceSpan: p.spanFrom(start)}, nil
 }
 
+func (p *Parser) parseWindow() (ast.Op, error) {
+	start := p.advance() // consume "window"
+	op := &ast.WindowOp{}
+
+	if p.peekWindowClause("by") {
+		p.advance() // consume "by"
+		for {
+			path, err := p.parseOneColumnPath()
+			if err != nil {
+				return nil, fmt.Errorf("window: by: %w", err)
+			}
+			op.PartitionBy = append(op.PartitionBy, path)
+			if p.peek().Type != lexer.TokenComma {
+				break
+			}
+			p.advance() // consume comma
+		}
+	}
+
+	if p.peekWindowClause("order") {
+		p.advance() // consume "order"
+		for {
+			desc := false
+			if p.peek().Type == lexer.TokenMinus {
+				p.advance()
+				desc = true
+			}
+			path, err := p.parseOneColumnPath()
+			if err != nil {
+				return nil, fmt.Errorf("window: order: %w", err)
+			}
+			op.OrderBy = append(op.OrderBy, ast.SortKey{Path: path, Desc: desc})
+			if p.peek().Type != lexer.TokenComma {
+				break
+			}
+			p.advance() // consume comma
+		}
+	}
+
+	assignments, err := p.parseAssignments()
+	if err != nil {
+		return nil, fmt.Errorf("window: %w", err)
+	}
+	op.Assignments = assignments
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
+// peekWindowClause reports whether the next token starts a window clause
+// keyword rather than an assignment to a column with the same name.
+func (p *Parser) peekWindowClause(word string) bool {
+	tok := p.peek()
+	return tok.Type == lexer.TokenIdent && tok.Val == word && p.peekAt(1).Type != lexer.TokenEquals
+}
+
+func (p *Parser) parseExplode() (ast.Op, error) {
+	start := p.advance() // consume "explode" or "unnest"
+	opName := start.Val
+	op := &ast.ExplodeOp{}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "outer" &&
+		(p.peekAt(1).Type == lexer.TokenIdent || p.peekAt(1).Type == lexer.TokenBacktickIdent) {
+		p.advance() // consume "outer"
+		op.Outer = true
+	}
+
+	path, err := p.parseOneColumnPath()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", opName, err)
+	}
+	op.Column = path
+
+	if p.peek().Type == lexer.TokenAs {
+		p.advance() // consume "as"
+		nameTok := p.advance()
+		if nameTok.Type != lexer.TokenIdent && nameTok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("%s: expected element column name after 'as', got %s", opName, nameTok.Type)
+		}
+		op.As = nameTok.Val
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "at" {
+		p.advance() // consume "at"
+		nameTok := p.advance()
+		if nameTok.Type != lexer.TokenIdent && nameTok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("%s: expected position column name after 'at', got %s", opName, nameTok.Type)
+		}
+		op.Position = nameTok.Val
+	}
+
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
+func (p *Parser) parsePivot() (ast.Op, error) {
+	start := p.advance() // consume "pivot"
+	op := &ast.PivotOp{}
+
+	key, err := p.parseOneColumnPath()
+	if err != nil {
+		return nil, fmt.Errorf("pivot: %w", err)
+	}
+	op.Key = key
+
+	if tok := p.peek(); tok.Type != lexer.TokenIdent || tok.Val != "in" {
+		return nil, fmt.Errorf("pivot: expected 'in (...)' value list after key column, got %s (%q); pivot output columns must be listed up front", tok.Type, tok.Val)
+	}
+	p.advance() // consume "in"
+	if tok := p.advance(); tok.Type != lexer.TokenLParen {
+		return nil, fmt.Errorf("pivot: expected '(' after 'in', got %s (%q)", tok.Type, tok.Val)
+	}
+	for {
+		lit, err := p.parsePivotValue()
+		if err != nil {
+			return nil, fmt.Errorf("pivot: in: %w", err)
+		}
+		op.In = append(op.In, lit)
+		tok := p.advance()
+		if tok.Type == lexer.TokenRParen {
+			break
+		}
+		if tok.Type != lexer.TokenComma {
+			return nil, fmt.Errorf("pivot: in: expected ',' or ')', got %s (%q)", tok.Type, tok.Val)
+		}
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "values" {
+		p.advance() // consume "values"
+		values, err := p.parseOneColumnPath()
+		if err != nil {
+			return nil, fmt.Errorf("pivot: values: %w", err)
+		}
+		op.Values = values
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "agg" {
+		p.advance() // consume "agg"
+		nameTok := p.advance()
+		if nameTok.Type != lexer.TokenIdent {
+			return nil, fmt.Errorf("pivot: expected aggregate function name after 'agg', got %s (%q)", nameTok.Type, nameTok.Val)
+		}
+		op.Agg = nameTok.Val
+	}
+
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
+// parsePivotValue reads one string, number, or boolean literal from a pivot
+// value list. A leading minus is folded into number literals.
+func (p *Parser) parsePivotValue() (*ast.LiteralExpr, error) {
+	if p.peek().Type == lexer.TokenMinus {
+		minus := p.advance()
+		tok := p.peek()
+		if tok.Type != lexer.TokenInt && tok.Type != lexer.TokenFloat {
+			return nil, fmt.Errorf("expected number after '-', got %s (%q)", tok.Type, tok.Val)
+		}
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		lit := expr.(*ast.LiteralExpr)
+		lit.Int = -lit.Int
+		lit.Float = -lit.Float
+		if lit.Kind == "float" {
+			lit.Text = "-" + lit.Text
+		}
+		lit.SourceSpan = p.spanFrom(minus)
+		return lit, nil
+	}
+	switch tok := p.peek(); tok.Type {
+	case lexer.TokenString, lexer.TokenInt, lexer.TokenFloat, lexer.TokenTrue, lexer.TokenFalse:
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return expr.(*ast.LiteralExpr), nil
+	default:
+		return nil, fmt.Errorf("expected string, number, or boolean literal, got %s (%q)", tok.Type, tok.Val)
+	}
+}
+
+func (p *Parser) parseUnpivot() (ast.Op, error) {
+	start := p.advance() // consume "unpivot"
+	op := &ast.UnpivotOp{NameCol: "name", ValueCol: "value"}
+
+	for {
+		tok := p.advance()
+		if tok.Type != lexer.TokenIdent && tok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("unpivot: expected column name, got %s (%q)", tok.Type, tok.Val)
+		}
+		if p.peek().Type == lexer.TokenDot {
+			return nil, fmt.Errorf("unpivot: dot paths not supported, got %q", tok.Val+".")
+		}
+		op.Columns = append(op.Columns, tok.Val)
+		if p.peek().Type != lexer.TokenComma {
+			break
+		}
+		p.advance() // consume comma
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "into" {
+		p.advance() // consume "into"
+		nameTok := p.advance()
+		if nameTok.Type != lexer.TokenIdent && nameTok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("unpivot: expected name column after 'into', got %s (%q)", nameTok.Type, nameTok.Val)
+		}
+		if comma := p.advance(); comma.Type != lexer.TokenComma {
+			return nil, fmt.Errorf("unpivot: expected ',' between name and value columns, got %s (%q)", comma.Type, comma.Val)
+		}
+		valueTok := p.advance()
+		if valueTok.Type != lexer.TokenIdent && valueTok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("unpivot: expected value column after ',', got %s (%q)", valueTok.Type, valueTok.Val)
+		}
+		op.NameCol = nameTok.Val
+		op.ValueCol = valueTok.Val
+	}
+
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
+func (p *Parser) parseUnion() (ast.Op, error) {
+	start := p.advance() // consume "union" or "concat"
+
+	// See parseJoin: ScanSource bypasses the lookahead buffer.
+	if len(p.buf) != 0 {
+		return nil, fmt.Errorf("union: internal error: lookahead buffer not empty before filename")
+	}
+	filename, err := p.lexer.ScanSource()
+	if err != nil {
+		return nil, fmt.Errorf("union: %w", err)
+	}
+	switch filename.Type {
+	case lexer.TokenIdent:
+	case lexer.TokenStdin:
+		return nil, fmt.Errorf("union: stdin is not supported as union source")
+	default:
+		return nil, fmt.Errorf("union: expected filename at position %d", filename.Pos)
+	}
+	if filename.Val == "" {
+		return nil, fmt.Errorf("union: expected filename")
+	}
+
+	load, err := p.parseOptionalWithClause()
+	if err != nil {
+		return nil, fmt.Errorf("union: %w", err)
+	}
+	if err := ast.ValidateLoadOptionsForFilename(filename.Val, load); err != nil {
+		return nil, fmt.Errorf("union: %w", err)
+	}
+	op := &ast.UnionOp{Filename: filename.Val, Load: load}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "by" {
+		p.advance() // consume "by"
+		mode := p.advance()
+		switch {
+		case mode.Type == lexer.TokenIdent && mode.Val == "name":
+		case mode.Type == lexer.TokenIdent && mode.Val == "position":
+			op.ByPosition = true
+		default:
+			return nil, fmt.Errorf("union: expected 'name' or 'position' after 'by', got %q", mode.Val)
+		}
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "tag" {
+		p.advance() // consume "tag"
+		tagTok := p.advance()
+		if tagTok.Type != lexer.TokenIdent && tagTok.Type != lexer.TokenBacktickIdent {
+			return nil, fmt.Errorf("union: expected column name after 'tag', got %s (%q)", tagTok.Type, tagTok.Val)
+		}
+		if p.peek().Type == lexer.TokenDot {
+			return nil, fmt.Errorf("union: dot paths not supported for tag column, got %q", tagTok.Val+".")
+		}
+		op.Tag = tagTok.Val
+	}
+
+	if tok := p.peek(); tok.Type == lexer.TokenWith {
+		return nil, fmt.Errorf("union: with clause must appear right after the filename")
+	}
+
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
 var joinKinds = map[string]bool{
 	"inner": true,
 	"left":  true,
 	"right": true,
 	"full":  true,
+	"semi":  true,
+	"anti":  true,
+	"cross": true,
+	"asof":  true,
 }
 
 func (p *Parser) parseJoin() (ast.Op, error) {
@@ -767,6 +1098,17 @@ func (p *Parser) parseJoin() (ast.Op, error) {
 		return nil, fmt.Errorf("join: %w", err)
 	}
 
+	if kind == "cross" {
+		if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "on" {
+			return nil, fmt.Errorf("join: cross join takes no 'on' clause")
+		}
+		return &ast.JoinOp{Kind: kind, Filename: filename.Val, Load: load, SourceSpan: p.spanFrom(start)}, nil
+	}
+
+	if kind == "asof" {
+		return p.parseAsofJoinRest(start, filename.Val, load)
+	}
+
 	if !seenOn {
 		onTok := p.advance()
 		if onTok.Type != lexer.TokenIdent || onTok.Val != "on" {
@@ -777,39 +1119,122 @@ func (p *Parser) parseJoin() (ast.Op, error) {
 		}
 	}
 
-	keys, err := p.parseJoinKeys()
+	cond, err := p.parseExpr()
 	if err != nil {
 		return nil, fmt.Errorf("join: %w", err)
 	}
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("join: expected at least one join key")
+	if p.peek().Type == lexer.TokenWith {
+		return nil, fmt.Errorf("join: with clause must appear before on")
 	}
+	keys, rest := splitJoinCondition(cond)
 
-	return &ast.JoinOp{Kind: kind, Filename: filename.Val, Keys: keys, Load: load, SourceSpan: p.spanFrom(start)}, nil
+	return &ast.JoinOp{Kind: kind, Filename: filename.Val, Keys: keys, Condition: rest, Load: load, SourceSpan: p.spanFrom(start)}, nil
 }
 
-func (p *Parser) parseJoinKeys() ([]ast.JoinKey, error) {
-	var keys []ast.JoinKey
-	for {
-		left, err := p.parseOneColumnPath()
+// parseAsofJoinRest parses "[on keys] match left [>= right] [tolerance t]"
+// after the source of an asof join. The tolerance is a number or, for date
+// and timestamp match columns, a duration("...") call.
+func (p *Parser) parseAsofJoinRest(start lexer.Token, filename string, load ast.LoadOptions) (ast.Op, error) {
+	op := &ast.JoinOp{Kind: "asof", Filename: filename, Load: load}
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "on" {
+		p.advance() // consume "on"
+		cond, err := p.parseExpr()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("join: %w", err)
 		}
-		right := append([]string(nil), left...)
-		if p.peek().Type == lexer.TokenEq {
-			p.advance()
-			right, err = p.parseOneColumnPath()
-			if err != nil {
-				return nil, err
-			}
+		keys, rest := splitJoinCondition(cond)
+		if rest != nil {
+			return nil, fmt.Errorf("join: asof join keys must be column equalities")
 		}
-		keys = append(keys, ast.JoinKey{Left: left, Right: right})
-		if p.peek().Type != lexer.TokenAnd {
-			break
+		op.Keys = keys
+	}
+
+	tok := p.advance()
+	if tok.Type != lexer.TokenIdent || tok.Val != "match" {
+		if tok.Type == lexer.TokenWith {
+			return nil, fmt.Errorf("join: with clause must appear before on")
 		}
-		p.advance()
+		return nil, fmt.Errorf("join: asof join needs 'match <column>', got %q", tok.Val)
+	}
+	left, err := p.parseOneColumnPath()
+	if err != nil {
+		return nil, fmt.Errorf("join: match: %w", err)
+	}
+	right := append([]string(nil), left...)
+	if p.peek().Type == lexer.TokenGte {
+		p.advance() // consume ">="
+		right, err = p.parseOneColumnPath()
+		if err != nil {
+			return nil, fmt.Errorf("join: match: %w", err)
+		}
+	}
+	op.Match = &ast.JoinKey{Left: left, Right: right}
+
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "tolerance" {
+		p.advance() // consume "tolerance"
+		tok := p.peek()
+		isDuration := tok.Type == lexer.TokenIdent && tok.Val == "duration" && p.peekAt(1).Type == lexer.TokenLParen
+		if tok.Type != lexer.TokenInt && tok.Type != lexer.TokenFloat && !isDuration {
+			return nil, fmt.Errorf("join: tolerance must be a non-negative number or duration(...), got %q", tok.Val)
+		}
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, fmt.Errorf("join: tolerance: %w", err)
+		}
+		op.Tolerance = expr
+	}
+
+	op.SourceSpan = p.spanFrom(start)
+	return op, nil
+}
+
+// splitJoinCondition separates the column keys of an on condition from the
+// rest. Each top-level and-ed term of the form "a == b" (column paths) or a
+// bare column path "a" (meaning a == a) becomes a key; the remaining terms are
+// joined back with and, or nil when there are none.
+func splitJoinCondition(cond ast.Expr) ([]ast.JoinKey, ast.Expr) {
+	var keys []ast.JoinKey
+	var rest ast.Expr
+	for _, term := range splitConjuncts(cond) {
+		if key, ok := joinKeyFromTerm(term); ok {
+			keys = append(keys, key)
+			continue
+		}
+		if rest == nil {
+			rest = term
+			continue
+		}
+		span := rest.Span()
+		span.End = term.Span().End
+		rest = &ast.BinaryExpr{Op: "and", Left: rest, Right: term, SourceSpan: span.Pack()}
+	}
+	return keys, rest
+}
+
+func splitConjuncts(expr ast.Expr) []ast.Expr {
+	if b, ok := expr.(*ast.BinaryExpr); ok && b.Op == "and" {
+		return append(splitConjuncts(b.Left), splitConjuncts(b.Right)...)
+	}
+	return []ast.Expr{expr}
+}
+
+func joinKeyFromTerm(term ast.Expr) (ast.JoinKey, bool) {
+	switch t := term.(type) {
+	case *ast.ColumnExpr:
+		return ast.JoinKey{Left: t.Path, Right: append([]string(nil), t.Path...)}, true
+	case *ast.BinaryExpr:
+		if t.Op != "==" {
+			return ast.JoinKey{}, false
+		}
+		left, lok := t.Left.(*ast.ColumnExpr)
+		right, rok := t.Right.(*ast.ColumnExpr)
+		if !lok || !rok {
+			return ast.JoinKey{}, false
+		}
+		return ast.JoinKey{Left: left.Path, Right: right.Path}, true
+	default:
+		return ast.JoinKey{}, false
 	}
-	return keys, nil
 }
 
 func (p *Parser) parseOneColumnPath() ([]string, error) {
@@ -1104,10 +1529,14 @@ func (p *Parser) peekBinaryOp() (string, int, bool) {
 		return "+", precAdd, true
 	case lexer.TokenMinus:
 		return "-", precAdd, true
+	case lexer.TokenConcat:
+		return "||", precAdd, true
 	case lexer.TokenStar:
 		return "*", precMul, true
 	case lexer.TokenSlash:
 		return "/", precMul, true
+	case lexer.TokenPercent:
+		return "%", precMul, true
 	}
 	return "", 0, false
 }
@@ -1141,6 +1570,9 @@ func (p *Parser) parseUnary() (ast.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
+	if primary, err = p.parsePostfixIndex(primary); err != nil {
+		return nil, err
+	}
 	if isLiteralExpr(primary) {
 		return primary, nil
 	}
@@ -1151,6 +1583,41 @@ func (p *Parser) parseUnary() (ast.Expr, error) {
 	return left, nil
 }
 
+// parsePostfixIndex parses any run of xs[i] and .field after a primary
+// expression. Indexing is sugar for list_get(xs, i) and field access for
+// record_get(r, "field"), so xs[0][1] reads the second element of the first
+// inner list and orders[0].amount the amount of the first order. Dotted
+// column paths are already consumed by parsePrimary.
+func (p *Parser) parsePostfixIndex(expr ast.Expr) (ast.Expr, error) {
+	for {
+		switch p.peek().Type {
+		case lexer.TokenLBracket:
+			p.advance() // consume [
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, fmt.Errorf("in index: %w", err)
+			}
+			rbracket, err := p.expect(lexer.TokenRBracket)
+			if err != nil {
+				return nil, fmt.Errorf("in index: %w", err)
+			}
+			span := ast.PackSpan(packedSpanStart(exprPackedSpan(expr)), int(rbracket.End))
+			expr = &ast.FuncCallExpr{Name: "list_get", Args: []ast.Expr{expr, index}, SourceSpan: span}
+		case lexer.TokenDot:
+			p.advance() // consume .
+			seg := p.advance()
+			if seg.Type != lexer.TokenIdent && seg.Type != lexer.TokenBacktickIdent {
+				return nil, fmt.Errorf("expected field name after '.', got %s (%q)", seg.Type, seg.Val)
+			}
+			name := &ast.LiteralExpr{Kind: "string", Str: seg.Val, SourceSpan: tokenSpan(seg)}
+			span := ast.PackSpan(packedSpanStart(exprPackedSpan(expr)), int(seg.End))
+			expr = &ast.FuncCallExpr{Name: "record_get", Args: []ast.Expr{expr, name}, SourceSpan: span}
+		default:
+			return expr, nil
+		}
+	}
+}
+
 func isLiteralExpr(e ast.Expr) bool {
 	_, ok := e.(*ast.LiteralExpr)
 	return ok
@@ -1174,7 +1641,7 @@ func (p *Parser) parsePrimary() (ast.Expr, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid float %q: %w", tok.Val, err)
 		}
-		return &ast.LiteralExpr{Kind: "float", Float: v, SourceSpan: tokenSpan(tok)}, nil
+		return &ast.LiteralExpr{Kind: "float", Float: v, Text: tok.Val, SourceSpan: tokenSpan(tok)}, nil
 
 	case lexer.TokenString:
 		p.advance()
@@ -1217,6 +1684,9 @@ func (p *Parser) parsePrimary() (ast.Expr, error) {
 			if tok.Val == "list" {
 				return p.parseListExpr(firstTok)
 			}
+			if tok.Val == "cast" || tok.Val == "try_cast" {
+				return p.parseCastExpr(firstTok)
+			}
 			return p.parseFuncCall(firstTok)
 		}
 		lastTok := firstTok
@@ -1326,6 +1796,54 @@ func (p *Parser) parseListExpr(nameTok lexer.Token) (ast.Expr, error) {
 	return &ast.ListExpr{Elements: elements, SourceSpan: ast.PackSpan(nameTok.Pos, int(rparen.End))}, nil
 }
 
+// parseCastExpr parses cast(expr as type) and try_cast(expr as type). The
+// type is passed on as a string literal second argument, such as "int" or
+// "decimal(10,2)", so the engine plans the call like any other builtin.
+func (p *Parser) parseCastExpr(nameTok lexer.Token) (ast.Expr, error) {
+	p.advance() // consume (
+	name := nameTok.Val
+
+	operand, err := p.parseExpr()
+	if err != nil {
+		return nil, fmt.Errorf("in %s: %w", name, err)
+	}
+	if _, err := p.expect(lexer.TokenAs); err != nil {
+		return nil, fmt.Errorf("in %s: %w", name, err)
+	}
+	typeTok, err := p.expect(lexer.TokenIdent)
+	if err != nil {
+		return nil, fmt.Errorf("in %s: expected a type after 'as': %w", name, err)
+	}
+	typeName := typeTok.Val
+	typeEnd := typeTok
+	if p.peek().Type == lexer.TokenLParen {
+		p.advance() // consume (
+		var params []string
+		for {
+			param, err := p.expect(lexer.TokenInt)
+			if err != nil {
+				return nil, fmt.Errorf("in %s: type %s: %w", name, typeName, err)
+			}
+			params = append(params, param.Val)
+			if p.peek().Type != lexer.TokenComma {
+				break
+			}
+			p.advance() // consume comma
+		}
+		if typeEnd, err = p.expect(lexer.TokenRParen); err != nil {
+			return nil, fmt.Errorf("in %s: type %s: %w", name, typeName, err)
+		}
+		typeName += "(" + strings.Join(params, ",") + ")"
+	}
+	rparen, err := p.expect(lexer.TokenRParen)
+	if err != nil {
+		return nil, fmt.Errorf("in %s: %w", name, err)
+	}
+
+	target := &ast.LiteralExpr{Kind: "string", Str: typeName, SourceSpan: spanFrom(typeTok, typeEnd)}
+	return &ast.FuncCallExpr{Name: name, Args: []ast.Expr{operand, target}, SourceSpan: ast.PackSpan(nameTok.Pos, int(rparen.End))}, nil
+}
+
 func (p *Parser) parseFuncCall(nameTok lexer.Token) (ast.Expr, error) {
 	p.advance() // consume (
 	name := nameTok.Val
@@ -1333,7 +1851,7 @@ func (p *Parser) parseFuncCall(nameTok lexer.Token) (ast.Expr, error) {
 	var args []ast.Expr
 	if p.peek().Type != lexer.TokenRParen {
 		for {
-			arg, err := p.parseExpr()
+			arg, err := p.parseFuncArg()
 			if err != nil {
 				return nil, fmt.Errorf("in function %s: %w", name, err)
 			}
@@ -1352,6 +1870,82 @@ func (p *Parser) parseFuncCall(nameTok lexer.Token) (ast.Expr, error) {
 	if err != nil {
 		return nil, fmt.Errorf("in function %s: %w", name, err)
 	}
+	end := int(rparen.End)
+
+	// "where { cond }" filters the rows an aggregate sees.
+	var filter ast.Expr
+	if tok := p.peek(); tok.Type == lexer.TokenIdent && tok.Val == "where" && p.peekAt(1).Type == lexer.TokenLBrace {
+		p.advance() // consume "where"
+		p.advance() // consume {
+		filter, err = p.parseExprBefore(lexer.TokenRBrace)
+		if err != nil {
+			return nil, fmt.Errorf("in function %s: where: %w", name, err)
+		}
+		end = int(p.advance().End) // consume }
+	}
+
+	return &ast.FuncCallExpr{Name: name, Args: args, Filter: filter, SourceSpan: ast.PackSpan(nameTok.Pos, end)}, nil
+}
+
+// parseFuncArg parses one function argument, which may be a lambda: "x ->
+// body" or "(acc, x) -> body". A parenthesised list is only a lambda when the
+// arrow follows its closing parenthesis, so "(a)" stays a grouped expression.
+func (p *Parser) parseFuncArg() (ast.Expr, error) {
+	n := p.lambdaParamTokens()
+	if n == 0 {
+		return p.parseExpr()
+	}
+	start := p.peek()
+	var params []string
+	for i := 0; i < n; i++ {
+		tok := p.advance()
+		if tok.Type != lexer.TokenIdent && tok.Type != lexer.TokenBacktickIdent {
+			continue
+		}
+		for _, seen := range params {
+			if seen == tok.Val {
+				return nil, fmt.Errorf("lambda: duplicate parameter %q", tok.Val)
+			}
+		}
+		params = append(params, tok.Val)
+	}
+	p.advance() // consume ->
+	body, err := p.parseExpr()
+	if err != nil {
+		return nil, fmt.Errorf("lambda: %w", err)
+	}
+	return &ast.LambdaExpr{Params: params, Body: body, SourceSpan: spanFromStartToExpr(start, body)}, nil
+}
+
+// lambdaParamTokens reports how many tokens before the arrow make up a
+// lambda's parameter list, or 0 when the next tokens do not start a lambda.
+func (p *Parser) lambdaParamTokens() int {
+	if isIdentToken(p.peek()) && p.peekAt(1).Type == lexer.TokenArrow {
+		return 1
+	}
+	if p.peek().Type != lexer.TokenLParen {
+		return 0
+	}
+	i := 1
+	for {
+		if !isIdentToken(p.peekAt(i)) {
+			return 0
+		}
+		i++
+		switch p.peekAt(i).Type {
+		case lexer.TokenComma:
+			i++
+		case lexer.TokenRParen:
+			if p.peekAt(i+1).Type != lexer.TokenArrow {
+				return 0
+			}
+			return i + 1
+		default:
+			return 0
+		}
+	}
+}
 
-	return &ast.FuncCallExpr{Name: name, Args: args, SourceSpan: ast.PackSpan(nameTok.Pos, int(rparen.End))}, nil
+func isIdentToken(tok lexer.Token) bool {
+	return tok.Type == lexer.TokenIdent || tok.Type == lexer.TokenBacktickIdent
 }
@@ -38,9 +38,9 @@ func TestParseFailClosedOnLexerErrorsAfterExpressionPrefix(t *testing.T) {
 		wants []string
 	}{
 		{
-			name:  "transform_modulo_before_output",
-			query: `users.csv | transform out = age % 2 | json`,
-			wants: []string{"lex error", "%"},
+			name:  "transform_caret_before_output",
+			query: `users.csv | transform out = age ^ 2 | json`,
+			wants: []string{"lex error", "^"},
 		},
 		{
 			name:  "transform_at_before_output",
@@ -48,19 +48,19 @@ func TestParseFailClosedOnLexerErrorsAfterExpressionPrefix(t *testing.T) {
 			wants: []string{"lex error", "@"},
 		},
 		{
-			name:  "transform_modulo_before_eof",
-			query: `users.csv | transform out = age % 2`,
-			wants: []string{"lex error", "%"},
+			name:  "transform_caret_before_eof",
+			query: `users.csv | transform out = age ^ 2`,
+			wants: []string{"lex error", "^"},
 		},
 		{
-			name:  "reduce_modulo_before_output",
-			query: `users.csv | group city | reduce total = age % 2 | json`,
-			wants: []string{"lex error", "%"},
+			name:  "reduce_caret_before_output",
+			query: `users.csv | group city | reduce total = age ^ 2 | json`,
+			wants: []string{"lex error", "^"},
 		},
 		{
-			name:  "function_arg_modulo_before_comma",
-			query: `users.csv | transform out = if(age % 2, "odd", "even") | json`,
-			wants: []string{"lex error", "%"},
+			name:  "function_arg_caret_before_comma",
+			query: `users.csv | transform out = if(age ^ 2, "odd", "even") | json`,
+			wants: []string{"lex error", "^"},
 		},
 		{
 			name:  "list_element_at_before_comma",
@@ -68,9 +68,9 @@ func TestParseFailClosedOnLexerErrorsAfterExpressionPrefix(t *testing.T) {
 			wants: []string{"lex error", "@"},
 		},
 		{
-			name:  "struct_field_modulo_before_comma",
-			query: `users.csv | transform out = struct(bucket = age % 2, city = city) | json`,
-			wants: []string{"lex error", "%"},
+			name:  "struct_field_caret_before_comma",
+			query: `users.csv | transform out = struct(bucket = age ^ 2, city = city) | json`,
+			wants: []string{"lex error", "^"},
 		},
 	}
 
@@ -107,6 +107,171 @@ func TestParseTransform(t *testing.T) {
 	}
 }
 
+func TestParseModuloBindsLikeMultiplication(t *testing.T) {
+	q, err := Parse("users.csv | transform x = age + age % 7 * 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, ok := q.Ops[0].(*ast.TransformOp).Assignments[0].Expr.(*ast.BinaryExpr)
+	if !ok || sum.Op != "+" {
+		t.Fatalf("expected + at the top, got %#v", q.Ops[0].(*ast.TransformOp).Assignments[0].Expr)
+	}
+	mul, ok := sum.Right.(*ast.BinaryExpr)
+	if !ok || mul.Op != "*" {
+		t.Fatalf("expected (age %% 7) * 2 on the right, got %#v", sum.Right)
+	}
+	if mod, ok := mul.Left.(*ast.BinaryExpr); !ok || mod.Op != "%" {
+		t.Fatalf("expected age %% 7, got %#v", mul.Left)
+	}
+}
+
+func TestParseConcatBindsLikeAddition(t *testing.T) {
+	q, err := Parse(`users.csv | transform x = name || "-" || upper(city) == "a"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq, ok := q.Ops[0].(*ast.TransformOp).Assignments[0].Expr.(*ast.BinaryExpr)
+	if !ok || eq.Op != "==" {
+		t.Fatalf("expected == at the top, got %#v", q.Ops[0].(*ast.TransformOp).Assignments[0].Expr)
+	}
+	outer, ok := eq.Left.(*ast.BinaryExpr)
+	if !ok || outer.Op != "||" {
+		t.Fatalf("expected (name || \"-\") || upper(city) on the left, got %#v", eq.Left)
+	}
+	if inner, ok := outer.Left.(*ast.BinaryExpr); !ok || inner.Op != "||" {
+		t.Fatalf("expected name || \"-\", got %#v", outer.Left)
+	}
+}
+
+func TestParseCastExpr(t *testing.T) {
+	q, err := Parse("users.csv | transform a = cast(age + 1 as decimal(10, 2)), b = try_cast(name as int)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignments := q.Ops[0].(*ast.TransformOp).Assignments
+	cast, ok := assignments[0].Expr.(*ast.FuncCallExpr)
+	if !ok || cast.Name != "cast" || len(cast.Args) != 2 {
+		t.Fatalf("expected cast call, got %#v", assignments[0].Expr)
+	}
+	if _, ok := cast.Args[0].(*ast.BinaryExpr); !ok {
+		t.Fatalf("expected age + 1 as the operand, got %#v", cast.Args[0])
+	}
+	if lit, ok := cast.Args[1].(*ast.LiteralExpr); !ok || lit.Kind != "string" || lit.Str != "decimal(10,2)" {
+		t.Fatalf("expected type literal decimal(10,2), got %#v", cast.Args[1])
+	}
+	if lit := assignments[1].Expr.(*ast.FuncCallExpr).Args[1].(*ast.LiteralExpr); lit.Str != "int" {
+		t.Fatalf("try_cast type = %q", lit.Str)
+	}
+
+	for _, bad := range []string{
+		"users.csv | transform a = cast(age, \"int\")",
+		"users.csv | transform a = cast(age as)",
+		"users.csv | transform a = cast(age as decimal(10, x))",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseLambdaArgs(t *testing.T) {
+	query := "users.csv | transform big = list_filter(orders, o -> o.amount > 100), total = list_reduce(orders, 0, (acc, o) -> acc + o.amount), grouped = f((a))"
+	q, err := Parse(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignments := q.Ops[0].(*ast.TransformOp).Assignments
+	filter := assignments[0].Expr.(*ast.FuncCallExpr)
+	lambda, ok := filter.Args[1].(*ast.LambdaExpr)
+	if !ok || len(lambda.Params) != 1 || lambda.Params[0] != "o" {
+		t.Fatalf("expected lambda o -> ..., got %#v", filter.Args[1])
+	}
+	if body, ok := lambda.Body.(*ast.BinaryExpr); !ok || body.Op != ">" {
+		t.Fatalf("expected comparison body, got %#v", lambda.Body)
+	}
+	if got, want := lambda.Span(), strings.Index(query, "o ->"); got.Start != want || got.End != strings.Index(query, "), total") {
+		t.Fatalf("lambda span = %v, want start %d", got, want)
+	}
+	reduce := assignments[1].Expr.(*ast.FuncCallExpr)
+	if lambda, ok := reduce.Args[2].(*ast.LambdaExpr); !ok || strings.Join(lambda.Params, ",") != "acc,o" {
+		t.Fatalf("expected (acc, o) lambda, got %#v", reduce.Args[2])
+	}
+	if _, ok := assignments[2].Expr.(*ast.FuncCallExpr).Args[0].(*ast.ColumnExpr); !ok {
+		t.Fatalf("expected (a) to stay a grouped column, got %#v", assignments[2].Expr)
+	}
+
+	for _, bad := range []string{
+		"users.csv | transform a = x -> x",
+		"users.csv | transform a = list_map(xs, (x, x) -> x)",
+		"users.csv | transform a = list_map(xs, (x, 1) -> x)",
+		"users.csv | transform a = list_map(xs, x ->)",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestParseIndexExpr(t *testing.T) {
+	query := "users.csv | transform a = xs[0][i + 1], b = -list(1, 2)[-1], c = xs[0] is null"
+	q, err := Parse(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignments := q.Ops[0].(*ast.TransformOp).Assignments
+	outer, ok := assignments[0].Expr.(*ast.FuncCallExpr)
+	if !ok || outer.Name != "list_get" || len(outer.Args) != 2 {
+		t.Fatalf("expected list_get call, got %#v", assignments[0].Expr)
+	}
+	if inner, ok := outer.Args[0].(*ast.FuncCallExpr); !ok || inner.Name != "list_get" {
+		t.Fatalf("expected nested list_get, got %#v", outer.Args[0])
+	}
+	if _, ok := outer.Args[1].(*ast.BinaryExpr); !ok {
+		t.Fatalf("expected binary index, got %#v", outer.Args[1])
+	}
+	if got := outer.Span(); got.Start != strings.Index(query, "xs[0]") || got.End != strings.Index(query, ", b") {
+		t.Fatalf("index span = %v", got)
+	}
+	neg, ok := assignments[1].Expr.(*ast.UnaryExpr)
+	if !ok || neg.Op != "-" {
+		t.Fatalf("expected negation of the indexed value, got %#v", assignments[1].Expr)
+	}
+	if call, ok := neg.Operand.(*ast.FuncCallExpr); !ok || call.Args[1].(*ast.LiteralExpr).Int != -1 {
+		t.Fatalf("expected list_get(..., -1), got %#v", neg.Operand)
+	}
+	if isNull, ok := assignments[2].Expr.(*ast.IsNullExpr); !ok || isNull.Operand.(*ast.FuncCallExpr).Name != "list_get" {
+		t.Fatalf("expected is null over list_get, got %#v", assignments[2].Expr)
+	}
+
+	field, err := Parse("users.csv | transform a = orders[0].amount[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get, ok := field.Ops[0].(*ast.TransformOp).Assignments[0].Expr.(*ast.FuncCallExpr)
+	if !ok || get.Name != "list_get" {
+		t.Fatalf("expected list_get over the field, got %#v", get)
+	}
+	rec, ok := get.Args[0].(*ast.FuncCallExpr)
+	if !ok || rec.Name != "record_get" || rec.Args[1].(*ast.LiteralExpr).Str != "amount" {
+		t.Fatalf("expected record_get(orders[0], \"amount\"), got %#v", get.Args[0])
+	}
+	if inner, ok := rec.Args[0].(*ast.FuncCallExpr); !ok || inner.Name != "list_get" {
+		t.Fatalf("expected list_get under record_get, got %#v", rec.Args[0])
+	}
+
+	for _, bad := range []string{
+		"users.csv | transform a = xs[]",
+		"users.csv | transform a = xs[0",
+		"users.csv | transform a = [1]",
+		"users.csv | transform a = xs[0].",
+		"users.csv | transform a = xs[0].1",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
 func TestParseStructExpr(t *testing.T) {
 	q, err := Parse("users.csv | transform rec = struct(a = 1, b = name, nested = struct(`weird name` = null, `and` = true))")
 	if err != nil {
@@ -419,6 +584,35 @@ func TestParseReduceWithNestedName(t *testing.T) {
 	}
 }
 
+func TestParseReduceAggregateWhere(t *testing.T) {
+	q, err := Parse(`users.csv | group name | reduce adults = count() where { age >= 18 }, total = sum(age) where { city == "NY" } * 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := q.Ops[1].(*ast.ReduceOp)
+	if len(r.Assignments) != 2 {
+		t.Fatalf("expected 2 assignments, got %d", len(r.Assignments))
+	}
+	call, ok := r.Assignments[0].Expr.(*ast.FuncCallExpr)
+	if !ok || call.Name != "count" {
+		t.Fatalf("expected count() call, got %#v", r.Assignments[0].Expr)
+	}
+	if cond, ok := call.Filter.(*ast.BinaryExpr); !ok || cond.Op != ">=" {
+		t.Fatalf("expected where condition age >= 18, got %#v", call.Filter)
+	}
+	bin, ok := r.Assignments[1].Expr.(*ast.BinaryExpr)
+	if !ok || bin.Op != "*" {
+		t.Fatalf("expected filtered sum times 2, got %#v", r.Assignments[1].Expr)
+	}
+	if sum, ok := bin.Left.(*ast.FuncCallExpr); !ok || sum.Filter == nil {
+		t.Fatalf("expected filtered sum on the left, got %#v", bin.Left)
+	}
+
+	if _, err := Parse("users.csv | group name | reduce n = count() where { age > 1"); err == nil || !strings.Contains(err.Error(), "where") {
+		t.Fatalf("expected unterminated where error, got %v", err)
+	}
+}
+
 func TestParseRename(t *testing.T) {
 	q, err := Parse("users.csv | rename `first name`=first_name, `last name`=last_name")
 	if err != nil {
@@ -726,6 +920,186 @@ func TestParseSortMixedDirections(t *testing.T) {
 	}
 }
 
+func TestParseWindow(t *testing.T) {
+	q, err := Parse("events.csv | window by user, meta.region order ts, -id prev = lag(ts), n = row_number()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := q.Ops[0].(*ast.WindowOp)
+	if len(w.PartitionBy) != 2 || w.PartitionBy[0][0] != "user" || strings.Join(w.PartitionBy[1], ".") != "meta.region" {
+		t.Errorf("unexpected partition keys %v", w.PartitionBy)
+	}
+	if len(w.OrderBy) != 2 || w.OrderBy[0].Path[0] != "ts" || w.OrderBy[0].Desc || w.OrderBy[1].Path[0] != "id" || !w.OrderBy[1].Desc {
+		t.Errorf("unexpected order keys %+v", w.OrderBy)
+	}
+	if len(w.Assignments) != 2 || w.Assignments[0].Column != "prev" || w.Assignments[1].Column != "n" {
+		t.Errorf("unexpected assignments %+v", w.Assignments)
+	}
+}
+
+func TestParseWindowClauseNamesAsTargets(t *testing.T) {
+	q, err := Parse("events.csv | window by = row_number(), order = rank()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := q.Ops[0].(*ast.WindowOp)
+	if len(w.PartitionBy) != 0 || len(w.OrderBy) != 0 {
+		t.Errorf("expected no clauses, got by=%v order=%v", w.PartitionBy, w.OrderBy)
+	}
+	if len(w.Assignments) != 2 || w.Assignments[0].Column != "by" || w.Assignments[1].Column != "order" {
+		t.Errorf("unexpected assignments %+v", w.Assignments)
+	}
+}
+
+func TestParseWindowErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		msg   string
+	}{
+		{"events.csv | window by", "window: by:"},
+		{"events.csv | window order", "window: order:"},
+		{"events.csv | window by user", "window:"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.query); err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%q: error = %v, want substring %q", tc.query, err, tc.msg)
+		}
+	}
+}
+
+func TestParseExplode(t *testing.T) {
+	q, err := Parse("nested.json | explode outer meta.orders as order at pos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := q.Ops[0].(*ast.ExplodeOp)
+	if strings.Join(e.Column, ".") != "meta.orders" || e.As != "order" || e.Position != "pos" || !e.Outer {
+		t.Errorf("unexpected explode %+v", e)
+	}
+}
+
+func TestParseExplodeDefaultsAndUnnestAlias(t *testing.T) {
+	q, err := Parse("nested.json | unnest orders | explode outer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := q.Ops[0].(*ast.ExplodeOp)
+	if e.Column[0] != "orders" || e.As != "" || e.Position != "" || e.Outer {
+		t.Errorf("unexpected unnest %+v", e)
+	}
+	outer := q.Ops[1].(*ast.ExplodeOp)
+	if outer.Column[0] != "outer" || outer.Outer {
+		t.Errorf("expected column named outer, got %+v", outer)
+	}
+}
+
+func TestParseExplodeErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		msg   string
+	}{
+		{"nested.json | explode", "explode: expected column name"},
+		{"nested.json | unnest orders as", "unnest: expected element column name"},
+		{"nested.json | explode orders at 1", "explode: expected position column name"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.query); err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%q: error = %v, want substring %q", tc.query, err, tc.msg)
+		}
+	}
+}
+
+func TestParsePivot(t *testing.T) {
+	q, err := Parse(`sales.csv | pivot month in ("jan", -1, true, -2.50) values amount agg sum`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pv := q.Ops[0].(*ast.PivotOp)
+	if pv.Key[0] != "month" || pv.Values[0] != "amount" || pv.Agg != "sum" {
+		t.Errorf("unexpected pivot %+v", pv)
+	}
+	if len(pv.In) != 4 || pv.In[0].Str != "jan" || pv.In[1].Int != -1 || pv.In[2].Kind != "bool" || pv.In[3].Float != -2.5 || pv.In[3].Text != "-2.50" {
+		t.Errorf("unexpected pivot values %+v", pv.In)
+	}
+
+	q, err = Parse(`sales.csv | pivot month in ("jan") agg count`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pv := q.Ops[0].(*ast.PivotOp); pv.Values != nil || pv.Agg != "count" {
+		t.Errorf("unexpected pivot %+v", pv)
+	}
+}
+
+func TestParseUnpivot(t *testing.T) {
+	q, err := Parse("sales.csv | unpivot jan, feb into month, amount | unpivot a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := q.Ops[0].(*ast.UnpivotOp)
+	if strings.Join(u.Columns, ",") != "jan,feb" || u.NameCol != "month" || u.ValueCol != "amount" {
+		t.Errorf("unexpected unpivot %+v", u)
+	}
+	if u := q.Ops[1].(*ast.UnpivotOp); u.NameCol != "name" || u.ValueCol != "value" {
+		t.Errorf("expected default name/value columns, got %+v", u)
+	}
+}
+
+func TestParsePivotAndUnpivotErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		msg   string
+	}{
+		{"sales.csv | pivot month values amount", "pivot: expected 'in (...)'"},
+		{"sales.csv | pivot month in \"jan\"", "pivot: expected '(' after 'in'"},
+		{"sales.csv | pivot month in (-\"a\")", "pivot: in: expected number after '-'"},
+		{"sales.csv | pivot month in (null)", "pivot: in: expected string, number, or boolean literal"},
+		{"sales.csv | pivot month in (\"a\" \"b\")", "pivot: in: expected ',' or ')'"},
+		{"sales.csv | pivot month in (\"a\") agg 1", "pivot: expected aggregate function name"},
+		{"sales.csv | unpivot", "unpivot: expected column name"},
+		{"sales.csv | unpivot a.b", "unpivot: dot paths not supported"},
+		{"sales.csv | unpivot a into n", "unpivot: expected ','"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.query); err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%q: error = %v, want substring %q", tc.query, err, tc.msg)
+		}
+	}
+}
+
+func TestParseUnion(t *testing.T) {
+	q, err := Parse("a.csv | filter { x > 1 } | union data/b-2.csv with format=csv by position tag src | concat c.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := q.Ops[1].(*ast.UnionOp)
+	if u.Filename != "data/b-2.csv" || u.Load.Format != "csv" || !u.ByPosition || u.Tag != "src" {
+		t.Errorf("unexpected union %+v", u)
+	}
+	if u := q.Ops[2].(*ast.UnionOp); u.Filename != "c.json" || u.ByPosition || u.Tag != "" {
+		t.Errorf("unexpected concat %+v", u)
+	}
+}
+
+func TestParseUnionErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		msg   string
+	}{
+		{"a.csv | union -", "union: stdin is not supported"},
+		{"a.csv | union", "union: expected filename"},
+		{"a.csv | union b.csv by row", "union: expected 'name' or 'position' after 'by'"},
+		{"a.csv | union b.csv tag 1", "union: expected column name after 'tag'"},
+		{"a.csv | union b.csv tag a.b", "union: dot paths not supported"},
+		{"a.csv | union b.csv by name with format=csv", "union: with clause must appear right after the filename"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.query); err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%q: error = %v, want substring %q", tc.query, err, tc.msg)
+		}
+	}
+}
+
 func TestParseFullQuery(t *testing.T) {
 	q, err := Parse(`sales.csv | filter { year(date) == 2024 } | transform revenue = coalesce(quantity, 0) * coalesce(price, 0) | group category, city | reduce total_revenue = sum(revenue), order_count = count() | remove grouped | filter { total_revenue > 1000 } | sort -total_revenue | head 3 | select category, city, total_revenue, order_count`)
 	if err != nil {
@@ -1024,6 +1398,103 @@ func TestParseJoinLeft(t *testing.T) {
 	}
 }
 
+func TestParseJoinSemiAntiCross(t *testing.T) {
+	q, err := Parse("users.csv | join semi orders.csv on name == user_name | join anti returns.csv on id | join cross sizes.csv with format=csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := q.Ops[0].(*ast.JoinOp); j.Kind != "semi" || len(j.Keys) != 1 {
+		t.Errorf("unexpected semi join %+v", j)
+	}
+	if j := q.Ops[1].(*ast.JoinOp); j.Kind != "anti" || j.Filename != "returns.csv" {
+		t.Errorf("unexpected anti join %+v", j)
+	}
+	if j := q.Ops[2].(*ast.JoinOp); j.Kind != "cross" || j.Filename != "sizes.csv" || len(j.Keys) != 0 || j.Load.Format != "csv" {
+		t.Errorf("unexpected cross join %+v", j)
+	}
+
+	if _, err := Parse("users.csv | join cross sizes.csv on id"); err == nil || !strings.Contains(err.Error(), "cross join takes no 'on' clause") {
+		t.Errorf("error = %v", err)
+	}
+	if _, err := Parse("users.csv | join anti orders.csv"); err == nil || !strings.Contains(err.Error(), "join: expected 'on'") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestParseJoinCondition(t *testing.T) {
+	q, err := Parse("users.csv | join orders.csv on id == user_id and ts >= start and region and ts < end")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := q.Ops[0].(*ast.JoinOp)
+	if len(j.Keys) != 2 || j.Keys[0].Right[0] != "user_id" || j.Keys[1].Left[0] != "region" {
+		t.Fatalf("unexpected keys %+v", j.Keys)
+	}
+	cond, ok := j.Condition.(*ast.BinaryExpr)
+	if !ok || cond.Op != "and" {
+		t.Fatalf("expected and-ed residual condition, got %#v", j.Condition)
+	}
+	if l, ok := cond.Left.(*ast.BinaryExpr); !ok || l.Op != ">=" {
+		t.Errorf("unexpected first residual term %#v", cond.Left)
+	}
+	if r, ok := cond.Right.(*ast.BinaryExpr); !ok || r.Op != "<" {
+		t.Errorf("unexpected second residual term %#v", cond.Right)
+	}
+
+	q, err = Parse("users.csv | join orders.csv on lower(email) == lower(mail)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := q.Ops[0].(*ast.JoinOp); len(j.Keys) != 0 || j.Condition == nil {
+		t.Errorf("expected expression condition without column keys, got %+v", j)
+	}
+
+	q, err = Parse("users.csv | join orders.csv on id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := q.Ops[0].(*ast.JoinOp); len(j.Keys) != 1 || j.Condition != nil {
+		t.Errorf("expected a single key and no condition, got %+v", j)
+	}
+}
+
+func TestParseJoinAsof(t *testing.T) {
+	q, err := Parse(`trades.csv | join asof quotes.parquet on symbol match ts tolerance 5 | join asof events.csv match at >= deployed_at | join asof ticks.csv match at tolerance duration("5m")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := q.Ops[0].(*ast.JoinOp)
+	if j.Kind != "asof" || len(j.Keys) != 1 || j.Match == nil || j.Match.Left[0] != "ts" || j.Match.Right[0] != "ts" {
+		t.Fatalf("unexpected asof join %+v", j)
+	}
+	if lit, ok := j.Tolerance.(*ast.LiteralExpr); !ok || lit.Int != 5 {
+		t.Errorf("expected tolerance 5, got %+v", j.Tolerance)
+	}
+	j = q.Ops[1].(*ast.JoinOp)
+	if len(j.Keys) != 0 || j.Match.Left[0] != "at" || j.Match.Right[0] != "deployed_at" || j.Tolerance != nil {
+		t.Errorf("unexpected asof join %+v", j)
+	}
+	j = q.Ops[2].(*ast.JoinOp)
+	if call, ok := j.Tolerance.(*ast.FuncCallExpr); !ok || call.Name != "duration" || len(call.Args) != 1 {
+		t.Errorf("expected duration tolerance, got %+v", j.Tolerance)
+	}
+
+	cases := []struct {
+		query string
+		msg   string
+	}{
+		{"t.csv | join asof q.csv on symbol", "join: asof join needs 'match <column>'"},
+		{"t.csv | join asof q.csv on symbol and ts > 1 match ts", "join: asof join keys must be column equalities"},
+		{"t.csv | join asof q.csv match ts tolerance x", "join: tolerance must be a non-negative number or duration(...)"},
+		{"t.csv | join asof q.csv match ts tolerance upper(x)", "join: tolerance must be a non-negative number or duration(...)"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.query); err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%q: error = %v, want substring %q", tc.query, err, tc.msg)
+		}
+	}
+}
+
 func TestParseJoinShorthandKey(t *testing.T) {
 	q, err := Parse("a.csv | join b.csv on id")
 	if err != nil {
@@ -1659,6 +2130,46 @@ func TestParseSourceWithLoadOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("csv_decimals_option", func(t *testing.T) {
+		q, err := Parse(`prices.csv with decimals=true | count`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Source.Load.Decimals == nil || *q.Source.Load.Decimals != true {
+			t.Errorf("decimals: got %v", q.Source.Load.Decimals)
+		}
+	})
+
+	t.Run("csv_dates_option", func(t *testing.T) {
+		q, err := Parse(`events.csv with dates=true | count`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Source.Load.Dates == nil || *q.Source.Load.Dates != true {
+			t.Errorf("dates: got %v", q.Source.Load.Dates)
+		}
+	})
+
+	t.Run("json_maps_option", func(t *testing.T) {
+		q, err := Parse(`events.jsonl with maps="counts, meta.by_day" | count`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(q.Source.Load.Maps, "|"); got != "counts|meta.by_day" {
+			t.Errorf("maps: got %q", got)
+		}
+	})
+
+	t.Run("avro_union_names_option", func(t *testing.T) {
+		q, err := Parse(`events.avro with union_names=true | count`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u := q.Source.Load.UnionNames; u == nil || !*u {
+			t.Errorf("union_names: got %v", u)
+		}
+	})
+
 	t.Run("gzip_compression_option", func(t *testing.T) {
 		q, err := Parse(`events.gz with format=jsonl, compression=gzip | count`)
 		if err != nil {
@@ -1878,6 +2389,14 @@ func TestParseWithLoadOptionsRejected(t *testing.T) {
 		{"csv_delim_on_json", "data.json with format=json, delim=\";\" | head", "delim"},
 		{"csv_allow_jagged_on_json", "data.json with format=json, allow_jagged_rows=true | head", "allow_jagged_rows"},
 		{"csv_ignore_unknown_on_json", "data.json with format=json, ignore_unknown_values=true | head", "ignore_unknown_values"},
+		{"csv_decimals_on_json", "data.json with format=json, decimals=true | head", "decimals"},
+		{"decimals_not_bool", "data.csv with decimals=1 | head", "true or false"},
+		{"csv_dates_on_json", "data.json with format=json, dates=true | head", "dates"},
+		{"maps_on_csv", "data.csv with maps=\"a\" | head", "maps applies only to json and jsonl"},
+		{"maps_not_string", "data.json with maps=a | head", "comma-separated field paths"},
+		{"maps_empty_path", "data.json with maps=\"a,,b\" | head", "empty field path"},
+		{"union_names_on_json", "data.json with union_names=true | head", "union_names applies only to avro"},
+		{"union_names_not_bool", "data.avro with union_names=1 | head", "union_names value must be true or false"},
 		{"inferred_json_header", "data.json with header=false | head", "header"},
 		{"inferred_json_delim", "data.json with delim=\";\" | head", "delim"},
 		{"join_inferred_json_header", "users.csv | join data.json with header=false on id", "header"},
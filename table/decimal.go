@@ -0,0 +1,262 @@
+package table
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MaxDecimalPrecision is the widest decimal dq stores. It matches the 16-byte
+// decimals of Parquet, Avro and most warehouses.
+const MaxDecimalPrecision = 38
+
+// Int64DecimalPrecision is the number of digits needed to hold any int64, the
+// precision ints take on when they meet decimals.
+const Int64DecimalPrecision = 19
+
+// Decimal values keep their canonical text in Str: an optional minus sign, the
+// integer digits and exactly scale fractional digits, so "12.50" has scale 2.
+// The text carries the scale; precision lives on the column schema.
+
+// DecimalSchema returns a decimal(precision, scale) descriptor.
+func DecimalSchema(precision, scale int) *TypeDescriptor {
+	return &TypeDescriptor{Kind: TypeDecimal, Precision: precision, Scale: scale}
+}
+
+// DecimalVal creates a decimal value of unscaled * 10^-scale.
+func DecimalVal(unscaled *big.Int, scale int) Value {
+	digits := new(big.Int).Abs(unscaled).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	var b strings.Builder
+	if unscaled.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(digits[:len(digits)-scale])
+	if scale > 0 {
+		b.WriteByte('.')
+		b.WriteString(digits[len(digits)-scale:])
+	}
+	return Value{Type: TypeDecimal, Str: b.String()}
+}
+
+// ParseDecimal parses plain decimal notation such as -12.50. The scale is the
+// number of digits after the point; exponents are not accepted.
+func ParseDecimal(s string) (Value, bool) {
+	s = strings.TrimSpace(s)
+	body := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	intPart, frac, _ := strings.Cut(body, ".")
+	if intPart == "" && frac == "" || !allDigits(intPart) || !allDigits(frac) || len(frac) > MaxDecimalPrecision {
+		return Null(), false
+	}
+	unscaled, ok := new(big.Int).SetString(intPart+frac, 10)
+	if !ok {
+		return Null(), false
+	}
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+	return DecimalVal(unscaled, len(frac)), true
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal returns the unscaled integer and scale of a decimal value. Ints are
+// decimals of scale 0.
+func (v Value) Decimal() (*big.Int, int, bool) {
+	switch v.Type {
+	case TypeInt:
+		return big.NewInt(v.Int), 0, true
+	case TypeDecimal:
+		intPart, frac, _ := strings.Cut(v.Str, ".")
+		unscaled, ok := new(big.Int).SetString(intPart+frac, 10)
+		return unscaled, len(frac), ok
+	default:
+		return nil, 0, false
+	}
+}
+
+// DecimalPrecision returns the number of digits v needs at its own scale.
+func DecimalPrecision(v Value) int {
+	unscaled, scale, ok := v.Decimal()
+	if !ok {
+		return 0
+	}
+	return max(decimalDigits(unscaled), scale, 1)
+}
+
+func decimalDigits(unscaled *big.Int) int {
+	if unscaled.Sign() == 0 {
+		return 1
+	}
+	return len(new(big.Int).Abs(unscaled).String())
+}
+
+// RescaleDecimal returns v (a decimal or int) at scale, rounding half away
+// from zero when digits are dropped.
+func RescaleDecimal(v Value, scale int) Value {
+	unscaled, from, ok := v.Decimal()
+	if !ok {
+		return v
+	}
+	return DecimalVal(RescaleDecimalUnscaled(unscaled, from, scale), scale)
+}
+
+// RescaleDecimalUnscaled moves an unscaled decimal integer from scale from to
+// scale to, rounding half away from zero when digits are dropped.
+func RescaleDecimalUnscaled(unscaled *big.Int, from, to int) *big.Int {
+	switch {
+	case to > from:
+		return new(big.Int).Mul(unscaled, Pow10(to-from))
+	case to < from:
+		return DivRoundHalfAway(unscaled, Pow10(from-to))
+	default:
+		return unscaled
+	}
+}
+
+// DivRoundHalfAway divides a by b, rounding half away from zero.
+func DivRoundHalfAway(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(b)) >= 0 {
+		if a.Sign()*b.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Pow10 returns 10^n as a big integer.
+func Pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// CompareDecimal compares two decimal or int values numerically.
+func CompareDecimal(a, b Value) int {
+	au, as, _ := a.Decimal()
+	bu, bs, _ := b.Decimal()
+	scale := max(as, bs)
+	return RescaleDecimalUnscaled(au, as, scale).Cmp(RescaleDecimalUnscaled(bu, bs, scale))
+}
+
+// decimalAsFloat returns the nearest float64 to a decimal value.
+func decimalAsFloat(v Value) float64 {
+	f, _, err := big.ParseFloat(v.Str, 10, 64, big.ToNearestEven)
+	if err != nil {
+		return 0
+	}
+	out, _ := f.Float64()
+	return out
+}
+
+// decimalCanonicalText strips trailing fractional zeros so numerically equal
+// decimals of different scales share one key.
+func decimalCanonicalText(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func writeDecimalSchema(b *strings.Builder, schema *TypeDescriptor) {
+	fmt.Fprintf(b, "decimal(%d,%d)", schema.Precision, schema.Scale)
+}
+
+func validateDecimalSchema(schema *TypeDescriptor, path string) error {
+	if schema.Precision < 1 || schema.Precision > MaxDecimalPrecision || schema.Scale < 0 || schema.Scale > schema.Precision {
+		if path == "" {
+			path = "<value>"
+		}
+		return fmt.Errorf("%s invalid decimal(%d,%d): precision must be 1 to %d and scale 0 to precision", path, schema.Precision, schema.Scale, MaxDecimalPrecision)
+	}
+	return nil
+}
+
+// DecimalWiden returns the narrowest decimal holding both decimal(p1,s1) and
+// decimal(p2,s2), capped at MaxDecimalPrecision.
+func DecimalWiden(p1, s1, p2, s2 int) (int, int) {
+	scale := max(s1, s2)
+	precision := max(p1-s1, p2-s2) + scale
+	return min(precision, MaxDecimalPrecision), scale
+}
+
+// decimalOperandSchema views an int or decimal descriptor as a decimal.
+func decimalOperandSchema(schema *TypeDescriptor) (int, int, bool) {
+	switch schema.Kind {
+	case TypeDecimal:
+		return schema.Precision, schema.Scale, true
+	case TypeInt:
+		return Int64DecimalPrecision, 0, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// mergeDecimalSchemas merges descriptors when at least one is a decimal:
+// decimals widen to hold both operands, ints join as decimal(19,0) and floats
+// win like they do over ints. ok is false for non-numeric pairings.
+func mergeDecimalSchemas(a, b *TypeDescriptor) (*TypeDescriptor, bool) {
+	if a.Kind != TypeDecimal && b.Kind != TypeDecimal {
+		return nil, false
+	}
+	nullable := a.Nullable || b.Nullable
+	if a.Kind == TypeFloat || b.Kind == TypeFloat {
+		return &TypeDescriptor{Kind: TypeFloat, Nullable: nullable}, true
+	}
+	p1, s1, ok1 := decimalOperandSchema(a)
+	p2, s2, ok2 := decimalOperandSchema(b)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	out := DecimalSchema(DecimalWiden(p1, s1, p2, s2))
+	out.Nullable = nullable
+	return out, true
+}
+
+// decimalAssignable reports whether every value of actual fits target without
+// rounding.
+func decimalAssignable(target, actual *TypeDescriptor, mode SchemaAssignMode) bool {
+	if actual.Kind == TypeDecimal && mode == AssignExactMode {
+		return target.Precision == actual.Precision && target.Scale == actual.Scale
+	}
+	p, s, ok := decimalOperandSchema(actual)
+	if !ok || mode != AssignCoerciveMode && actual.Kind != TypeDecimal {
+		return false
+	}
+	if actual.Kind == TypeInt {
+		return true
+	}
+	return s <= target.Scale && p-s <= target.Precision-target.Scale
+}
+
+// coerceDecimalToSchema rescales a decimal or int value to schema's scale and
+// rejects values with more integer digits than schema allows.
+func coerceDecimalToSchema(v Value, schema *TypeDescriptor, path string) (Value, error) {
+	out := RescaleDecimal(v, schema.Scale)
+	if DecimalPrecision(out) > schema.Precision {
+		if path == "" {
+			path = "<value>"
+		}
+		return Null(), fmt.Errorf("%s decimal value %s does not fit %s", path, v.AsString(), Render(WithoutNull(schema)))
+	}
+	return out, nil
+}
@@ -0,0 +1,137 @@
+package table
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := map[string]string{
+		"12.50":  "12.50",
+		"-0.05":  "-0.05",
+		"+3":     "3",
+		".5":     "0.5",
+		"7.":     "7",
+		"-0.000": "0.000",
+		"00042":  "42",
+	}
+	for in, want := range cases {
+		v, ok := ParseDecimal(in)
+		if !ok || v.Type != TypeDecimal || v.AsString() != want {
+			t.Errorf("ParseDecimal(%q) = %q, %v; want %q", in, v.AsString(), ok, want)
+		}
+	}
+	for _, bad := range []string{"", "-", ".", "1e3", "1.2.3", "abc", "NaN", "1,5"} {
+		if _, ok := ParseDecimal(bad); ok {
+			t.Errorf("ParseDecimal(%q) accepted", bad)
+		}
+	}
+	v, _ := ParseDecimal("-12.50")
+	unscaled, scale, ok := v.Decimal()
+	if !ok || unscaled.Int64() != -1250 || scale != 2 || DecimalPrecision(v) != 4 {
+		t.Fatalf("Decimal() = %v, %d, %v; precision %d", unscaled, scale, ok, DecimalPrecision(v))
+	}
+	if f, ok := v.AsFloat(); !ok || f != -12.5 {
+		t.Fatalf("AsFloat = %v, %v", f, ok)
+	}
+}
+
+func TestRescaleDecimalRoundsHalfAwayFromZero(t *testing.T) {
+	cases := []struct {
+		in    string
+		scale int
+		want  string
+	}{
+		{"2.345", 2, "2.35"},
+		{"-2.345", 2, "-2.35"},
+		{"2.344", 2, "2.34"},
+		{"0.5", 0, "1"},
+		{"-0.5", 0, "-1"},
+		{"1.5", 3, "1.500"},
+	}
+	for _, tc := range cases {
+		v, _ := ParseDecimal(tc.in)
+		if got := RescaleDecimal(v, tc.scale).AsString(); got != tc.want {
+			t.Errorf("RescaleDecimal(%s, %d) = %s, want %s", tc.in, tc.scale, got, tc.want)
+		}
+	}
+	if got := RescaleDecimal(IntVal(-7), 2).AsString(); got != "-7.00" {
+		t.Fatalf("int rescale = %s", got)
+	}
+	if got := DecimalVal(big.NewInt(-5), 3).AsString(); got != "-0.005" {
+		t.Fatalf("DecimalVal = %s", got)
+	}
+}
+
+func TestDecimalCompareAndKeys(t *testing.T) {
+	a, _ := ParseDecimal("1.50")
+	b, _ := ParseDecimal("1.5")
+	c, _ := ParseDecimal("-2")
+	if CompareDecimal(a, b) != 0 || CanonicalKey(a) != CanonicalKey(b) {
+		t.Fatalf("1.50 and 1.5 should be equal with one key: %q %q", CanonicalKey(a), CanonicalKey(b))
+	}
+	if CompareDecimal(c, a) >= 0 || CompareDecimal(a, IntVal(1)) <= 0 {
+		t.Fatal("decimal ordering is wrong")
+	}
+	if got, err := CompareStrict(a, c); err != nil || got <= 0 {
+		t.Fatalf("CompareStrict = %d, %v", got, err)
+	}
+	if Equal(a, StrVal("1.50")) {
+		t.Fatal("decimal should not equal string")
+	}
+}
+
+func TestDecimalSchemas(t *testing.T) {
+	if got := DecimalSchema(10, 2).String(); got != "decimal(10,2)" {
+		t.Fatalf("String = %s", got)
+	}
+	v, _ := ParseDecimal("-123.45")
+	if got := InferValueSchema(v).String(); got != "decimal(5,2)" {
+		t.Fatalf("InferValueSchema = %s", got)
+	}
+	merged, err := MergeSchemasPermissive(DecimalSchema(5, 2), DecimalSchema(4, 3))
+	if err != nil || merged.String() != "decimal(6,3)" {
+		t.Fatalf("merge decimals = %v, %v", merged, err)
+	}
+	merged, err = MergeSchemasPermissive(DecimalSchema(5, 2), ScalarSchema(TypeInt))
+	if err != nil || merged.String() != "decimal(21,2)" {
+		t.Fatalf("merge with int = %v, %v", merged, err)
+	}
+	merged, err = MergeSchemasPermissive(DecimalSchema(5, 2), ScalarSchema(TypeFloat))
+	if err != nil || merged.String() != "float" {
+		t.Fatalf("merge with float = %v, %v", merged, err)
+	}
+	if err := ValidateSchema(DecimalSchema(39, 2)); err == nil {
+		t.Fatal("decimal(39,2) should be invalid")
+	}
+	if err := ValidateSchema(DecimalSchema(2, 3)); err == nil {
+		t.Fatal("decimal(2,3) should be invalid")
+	}
+
+	out, err := CoerceValueToSchema(IntVal(12), DecimalSchema(5, 2))
+	if err != nil || out.AsString() != "12.00" {
+		t.Fatalf("coerce int = %q, %v", out.AsString(), err)
+	}
+	wide, _ := ParseDecimal("12345.6")
+	if _, err := CoerceValueToSchema(wide, DecimalSchema(5, 2)); err == nil || !strings.Contains(err.Error(), "does not fit decimal(5,2)") {
+		t.Fatalf("coerce overflow: got %v", err)
+	}
+}
+
+func TestDecimalColumnRescalesOnAppend(t *testing.T) {
+	tbl := NewTableWithSchemas([]string{"price"}, []*TypeDescriptor{DecimalSchema(6, 2)})
+	one, _ := ParseDecimal("1.5")
+	two, _ := ParseDecimal("20.25")
+	tbl.AddRow([]Value{one})
+	tbl.AddRow([]Value{two})
+	tbl.AddRow([]Value{Null()})
+	for i, want := range []string{"1.50", "20.25", "null"} {
+		if got := tbl.Get(i, "price").AsString(); got != want {
+			t.Errorf("row %d = %s, want %s", i, got, want)
+		}
+	}
+	if got := tbl.Schema().Columns[0].Type.String(); got != "decimal(6,2)?" {
+		t.Fatalf("schema = %s", got)
+	}
+}
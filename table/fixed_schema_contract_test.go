@@ -138,7 +138,7 @@ func TestFixedSchemaContractAddRowTypedExactRejectsTopLevelMixed(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected top-level mixed schema error")
 	}
-	if got, want := err.Error(), "any mixed schema is only valid inside list elements"; got != want {
+	if got, want := err.Error(), "any mixed schema is only valid inside list elements and map values"; got != want {
 		t.Fatalf("top-level mixed exact append error: got %q, want %q", got, want)
 	}
 	if tbl.NumRows != 0 || tbl.Col(0).Len() != 0 {
@@ -156,7 +156,7 @@ func TestFixedSchemaContractAddRowTypedRejectsTopLevelMixed(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected top-level mixed schema error")
 	}
-	if got, want := err.Error(), "any mixed schema is only valid inside list elements"; got != want {
+	if got, want := err.Error(), "any mixed schema is only valid inside list elements and map values"; got != want {
 		t.Fatalf("top-level mixed typed append error: got %q, want %q", got, want)
 	}
 	if tbl.NumRows != 0 || tbl.Col(0).Len() != 0 {
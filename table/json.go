@@ -0,0 +1,133 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ValueToJSON converts a value to the Go form encoding/json writes. Decimals
+// stay exact as json.Number; dates, timestamps and durations are written as
+// their display strings.
+func ValueToJSON(v Value) interface{} {
+	switch v.Type {
+	case TypeNull:
+		return nil
+	case TypeInt:
+		return v.Int
+	case TypeFloat:
+		return v.Float
+	case TypeDecimal:
+		return json.Number(v.Str)
+	case TypeString:
+		return v.Str
+	case TypeBool:
+		return v.Bool
+	case TypeList:
+		elems := make([]interface{}, len(v.List))
+		for i, e := range v.List {
+			elems[i] = ValueToJSON(e)
+		}
+		return elems
+	case TypeRecord, TypeMap:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			obj[f.Name] = ValueToJSON(f.Value)
+		}
+		return obj
+	default:
+		return v.AsString()
+	}
+}
+
+// ValueFromJSON converts a value decoded with json.Decoder.UseNumber. Objects
+// become records with sorted fields, and numbers become ints when they fit
+// int64 and floats otherwise. path names the value in errors.
+func ValueFromJSON(v interface{}, path string) (Value, error) {
+	switch val := v.(type) {
+	case nil:
+		return Null(), nil
+	case bool:
+		return BoolVal(val), nil
+	case json.Number:
+		out, err := JSONNumberValue(val)
+		if err != nil {
+			if numberErr, ok := err.(JSONNumberError); ok {
+				numberErr.Path = path
+				return Null(), numberErr
+			}
+			return Null(), err
+		}
+		return out, nil
+	case string:
+		return StrVal(val), nil
+	case []interface{}:
+		elems := make([]Value, len(val))
+		for i, elem := range val {
+			out, err := ValueFromJSON(elem, path+"[]")
+			if err != nil {
+				return Null(), err
+			}
+			elems[i] = out
+		}
+		return ListVal(elems), nil
+	case map[string]interface{}:
+		fields, err := JSONRecordFields(val, path)
+		if err != nil {
+			return Null(), err
+		}
+		return RecordVal(fields), nil
+	default:
+		b, _ := json.Marshal(val)
+		return StrVal(string(b)), nil
+	}
+}
+
+// JSONRecordFields converts a decoded JSON object to record fields sorted by
+// name. Field paths extend path with ".name".
+func JSONRecordFields(m map[string]interface{}, path string) ([]RecordField, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]RecordField, len(keys))
+	for i, k := range keys {
+		fieldPath := k
+		if path != "" {
+			fieldPath = path + "." + k
+		}
+		v, err := ValueFromJSON(m[k], fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = RecordField{Name: k, Value: v}
+	}
+	return fields, nil
+}
+
+// JSONNumberValue converts a JSON number to an int when it fits int64 and to
+// a float otherwise.
+func JSONNumberValue(n json.Number) (Value, error) {
+	if i, err := n.Int64(); err == nil {
+		return IntVal(i), nil
+	}
+	if f, err := n.Float64(); err == nil {
+		return FloatVal(f), nil
+	}
+	return Null(), JSONNumberError{Value: n.String()}
+}
+
+// JSONNumberError reports a JSON number that neither int64 nor float64 can
+// hold, such as 1e10000.
+type JSONNumberError struct {
+	Path  string
+	Value string
+}
+
+func (e JSONNumberError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("unrepresentable JSON number %q", e.Value)
+	}
+	return fmt.Sprintf("%s unrepresentable JSON number %q", e.Path, e.Value)
+}
@@ -8,11 +8,14 @@ import (
 
 // TypeDescriptor describes a possibly nested table value type.
 type TypeDescriptor struct {
-	Kind     ValueType
-	Nullable bool
-	Fields   []FieldDescriptor
-	Elem     *TypeDescriptor
-	Branches []*TypeDescriptor
+	Kind      ValueType
+	Nullable  bool
+	Fields    []FieldDescriptor
+	Elem      *TypeDescriptor // TypeList element or TypeMap value; map keys are strings
+	Branches  []*TypeDescriptor
+	Name      string // source type name of a union branch, such as an Avro record's full name
+	Precision int    // TypeDecimal total digits
+	Scale     int    // TypeDecimal fractional digits
 }
 
 // FieldDescriptor describes one named record field.
@@ -94,26 +97,40 @@ func InferValueSchema(v Value) *TypeDescriptor {
 		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
 		return &TypeDescriptor{Kind: TypeRecord, Fields: fields}
 	case TypeList:
-		var elem *TypeDescriptor
-		for _, item := range v.List {
-			itemSchema := InferValueSchema(item)
-			if elem == nil {
-				elem = itemSchema
-				continue
-			}
-			merged, err := mergeListElementSchemas(elem, itemSchema)
-			if err != nil {
-				elem = &TypeDescriptor{Kind: TypeMixed, Nullable: true}
-				break
-			}
-			elem = merged
-		}
-		return &TypeDescriptor{Kind: TypeList, Elem: elem}
+		return &TypeDescriptor{Kind: TypeList, Elem: inferElemSchema(v.List)}
+	case TypeMap:
+		values := make([]Value, len(v.Fields))
+		for i, entry := range v.Fields {
+			values[i] = entry.Value
+		}
+		return &TypeDescriptor{Kind: TypeMap, Elem: inferElemSchema(values)}
+	case TypeDecimal:
+		_, scale, _ := v.Decimal()
+		return DecimalSchema(DecimalPrecision(v), scale)
 	default:
 		return &TypeDescriptor{Kind: v.Type}
 	}
 }
 
+// inferElemSchema merges the schemas of list elements or map values. Values
+// that do not merge make the element mixed.
+func inferElemSchema(values []Value) *TypeDescriptor {
+	var elem *TypeDescriptor
+	for _, item := range values {
+		itemSchema := InferValueSchema(item)
+		if elem == nil {
+			elem = itemSchema
+			continue
+		}
+		merged, err := mergeListElementSchemas(elem, itemSchema)
+		if err != nil {
+			return &TypeDescriptor{Kind: TypeMixed, Nullable: true}
+		}
+		elem = merged
+	}
+	return elem
+}
+
 // MergeSchemasPermissive merges schemas using existing dq widening behavior.
 func MergeSchemasPermissive(a, b *TypeDescriptor) (*TypeDescriptor, error) {
 	return UnifySchemas(a, b, UnifyPermissiveMode)
@@ -183,7 +200,7 @@ func WithDeepNullable(t *TypeDescriptor) *TypeDescriptor {
 		for i, field := range normalized.Fields {
 			out.Fields[i] = FieldDescriptor{Name: field.Name, Type: WithDeepNullable(field.Type)}
 		}
-	case TypeList:
+	case TypeList, TypeMap:
 		out.Elem = WithDeepNullable(normalized.Elem)
 	case TypeUnion:
 		out.Branches = make([]*TypeDescriptor, len(normalized.Branches))
@@ -195,8 +212,8 @@ func WithDeepNullable(t *TypeDescriptor) *TypeDescriptor {
 }
 
 // UnionSchema builds a normalized union descriptor from branch descriptors.
-// Storage-equivalent branches are collapsed. Distinct structural branches stay
-// ordered, but source-level branch names/tags are not represented.
+// Storage-equivalent branches are collapsed unless their names differ.
+// Distinct branches stay ordered.
 func UnionSchema(branches []*TypeDescriptor, nullable bool) *TypeDescriptor {
 	return UnionOf(branches, nullable)
 }
@@ -243,6 +260,7 @@ func unionSchema(branches []*TypeDescriptor, nullable bool) *TypeDescriptor {
 	if len(out) == 1 {
 		only := cloneTypeDescriptor(out[0])
 		only.Nullable = only.Nullable || nullable
+		only.Name = ""
 		return only
 	}
 	return &TypeDescriptor{Kind: TypeUnion, Nullable: nullable, Branches: out}
@@ -259,8 +277,17 @@ func addUnionBranch(branches []*TypeDescriptor, next *TypeDescriptor) []*TypeDes
 	return append(branches, next)
 }
 
+// mergeCompatibleUnionBranch merges two union branches that can share storage.
+// Branches with different names stay apart even when their structure matches.
 func mergeCompatibleUnionBranch(a, b *TypeDescriptor) (*TypeDescriptor, bool) {
-	return mergeCompatibleUnionBranchWith(a, b, true)
+	if a != nil && b != nil && a.Name != b.Name {
+		return nil, false
+	}
+	out, ok := mergeCompatibleUnionBranchWith(a, b, true)
+	if ok && a != nil {
+		out.Name = a.Name
+	}
+	return out, ok
 }
 
 func mergeCompatibleUnionBranchWith(a, b *TypeDescriptor, allowNumericPromotion bool) (*TypeDescriptor, bool) {
@@ -282,6 +309,11 @@ func mergeCompatibleUnionBranchWith(a, b *TypeDescriptor, allowNumericPromotion
 	if allowNumericPromotion && (a.Kind == TypeInt && b.Kind == TypeFloat || a.Kind == TypeFloat && b.Kind == TypeInt) {
 		return &TypeDescriptor{Kind: TypeFloat, Nullable: a.Nullable || b.Nullable}, true
 	}
+	if allowNumericPromotion || a.Kind == TypeDecimal && b.Kind == TypeDecimal {
+		if merged, ok := mergeDecimalSchemas(a, b); ok {
+			return merged, true
+		}
+	}
 	if a.Kind != b.Kind {
 		return nil, false
 	}
@@ -322,7 +354,7 @@ func mergeCompatibleUnionBranchWith(a, b *TypeDescriptor, allowNumericPromotion
 			fields = append(fields, FieldDescriptor{Name: name, Type: merged})
 		}
 		out.Fields = fields
-	case TypeList:
+	case TypeList, TypeMap:
 		merged, ok := mergeCompatibleUnionBranchWith(a.Elem, b.Elem, false)
 		if !ok {
 			return nil, false
@@ -355,7 +387,7 @@ func SchemaContainsUnion(schema *TypeDescriptor) bool {
 				return true
 			}
 		}
-	case TypeList:
+	case TypeList, TypeMap:
 		return SchemaContainsUnion(schema.Elem)
 	}
 	return false
@@ -385,7 +417,7 @@ func IsComparable(t *TypeDescriptor) bool {
 		return false
 	}
 	switch t.Kind {
-	case TypeInt, TypeFloat, TypeString, TypeBool, TypeList, TypeRecord:
+	case TypeInt, TypeFloat, TypeString, TypeBool, TypeDate, TypeTimestamp, TypeDuration, TypeDecimal, TypeList, TypeRecord, TypeMap:
 		return true
 	default:
 		return false
@@ -398,7 +430,7 @@ func IsOrderable(t *TypeDescriptor) bool {
 		return false
 	}
 	switch t.Kind {
-	case TypeInt, TypeFloat, TypeString:
+	case TypeInt, TypeFloat, TypeString, TypeDate, TypeTimestamp, TypeDuration, TypeDecimal:
 		return true
 	default:
 		return false
@@ -492,6 +524,9 @@ func unifyStrictAtPath(a, b *TypeDescriptor, path string, allowMixed bool) (*Typ
 	if a.Kind == TypeInt && b.Kind == TypeFloat || a.Kind == TypeFloat && b.Kind == TypeInt {
 		return &TypeDescriptor{Kind: TypeFloat, Nullable: a.Nullable || b.Nullable}, nil
 	}
+	if merged, ok := mergeDecimalSchemas(a, b); ok {
+		return merged, nil
+	}
 	if a.Kind != b.Kind {
 		return nil, &SchemaError{Path: path, Expected: a, Actual: b}
 	}
@@ -510,6 +545,12 @@ func unifyStrictAtPath(a, b *TypeDescriptor, path string, allowMixed bool) (*Typ
 			return nil, err
 		}
 		out.Elem = elem
+	case TypeMap:
+		elem, err := unifyStrictAtPath(a.Elem, b.Elem, appendSchemaPath(path, "{}"), true)
+		if err != nil {
+			return nil, err
+		}
+		out.Elem = elem
 	}
 	return out, nil
 }
@@ -570,15 +611,24 @@ func mergeSchemaIntoUnionBranch(branch, schema *TypeDescriptor) (*TypeDescriptor
 	if branch == nil || schema == nil {
 		return nil, false
 	}
+	if branch.Name != "" {
+		name := branch.Name
+		branch.Name = ""
+		out, ok := mergeSchemaIntoUnionBranch(branch, schema)
+		if ok {
+			out.Name = name
+		}
+		return out, ok
+	}
 	if branch.Kind == TypeRecord && schema.Kind == TypeRecord {
 		return mergeRecordBranchForSchemaFit(branch, schema)
 	}
-	if branch.Kind == TypeList && schema.Kind == TypeList {
+	if (branch.Kind == TypeList || branch.Kind == TypeMap) && schema.Kind == branch.Kind {
 		elem, ok := mergeSchemaIntoUnionBranch(branch.Elem, schema.Elem)
 		if !ok {
 			return nil, false
 		}
-		return &TypeDescriptor{Kind: TypeList, Nullable: branch.Nullable || schema.Nullable, Elem: elem}, true
+		return &TypeDescriptor{Kind: branch.Kind, Nullable: branch.Nullable || schema.Nullable, Elem: elem}, true
 	}
 	return mergeCompatibleUnionBranch(branch, schema)
 }
@@ -684,9 +734,12 @@ func schemaAssignable(target, actual *TypeDescriptor, mode SchemaAssignMode) boo
 		}
 		return true
 	}
-	if mode == AssignCoerciveMode && target.Kind == TypeFloat && actual.Kind == TypeInt {
+	if mode == AssignCoerciveMode && target.Kind == TypeFloat && (actual.Kind == TypeInt || actual.Kind == TypeDecimal) {
 		return true
 	}
+	if target.Kind == TypeDecimal {
+		return decimalAssignable(target, actual, mode)
+	}
 	if target.Kind != actual.Kind {
 		return false
 	}
@@ -722,7 +775,7 @@ func schemaAssignable(target, actual *TypeDescriptor, mode SchemaAssignMode) boo
 				}
 			}
 		}
-	case TypeList:
+	case TypeList, TypeMap:
 		return schemaAssignable(target.Elem, actual.Elem, mode)
 	}
 	return true
@@ -823,14 +876,16 @@ func mergeValueSchemaStrict(schema *TypeDescriptor, v Value, path string) (*Type
 		}
 		schema.Nullable = true
 		return schema, nil
-	case TypeInt, TypeFloat, TypeString, TypeBool:
+	case TypeInt, TypeFloat, TypeString, TypeBool, TypeDate, TypeTimestamp, TypeDuration:
 		return mergeSchemaKindStrict(schema, v.Type, path)
+	case TypeDecimal:
+		return mergeSchemaDescriptorStrict(schema, InferValueSchema(v), path)
 	case TypeRecord:
 		if err := validateStrictMergeRecordFields(v, path); err != nil {
 			return nil, err
 		}
 		return mergeRecordValueSchemaStrict(schema, v.Fields, path)
-	case TypeList:
+	case TypeList, TypeMap:
 		if err := validateStrictMergeRecordFields(v, path); err != nil {
 			return nil, err
 		}
@@ -890,6 +945,15 @@ func validateStrictMergeRecordFields(v Value, path string) error {
 				return err
 			}
 		}
+	case TypeMap:
+		if err := validateUniqueRecordFieldNames(v.Fields, path); err != nil {
+			return err
+		}
+		for _, entry := range v.Fields {
+			if err := validateStrictMergeRecordFields(entry.Value, appendSchemaPath(path, "{}")); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -1050,6 +1114,9 @@ func mergeSchemaDescriptorStrict(schema, next *TypeDescriptor, path string) (*Ty
 		schema.Nullable = schema.Nullable || next.Nullable
 		return schema, nil
 	}
+	if merged, ok := mergeDecimalSchemas(schema, next); ok {
+		return merged, nil
+	}
 	if schema.Kind != next.Kind {
 		return nil, &SchemaError{Path: path, Expected: FinalizeSchema(schema), Actual: FinalizeSchema(next)}
 	}
@@ -1074,6 +1141,12 @@ func mergeSchemaDescriptorStrict(schema, next *TypeDescriptor, path string) (*Ty
 			return nil, err
 		}
 		schema.Elem = elem
+	case TypeMap:
+		elem, err := mergeSchemaDescriptorStrict(schema.Elem, next.Elem, path+"{}")
+		if err != nil {
+			return nil, err
+		}
+		schema.Elem = elem
 	}
 	return schema, nil
 }
@@ -1148,6 +1221,9 @@ func mergeSchemas(a, b *TypeDescriptor, strict bool, path string) (*TypeDescript
 	if a.Kind == TypeInt && b.Kind == TypeFloat || a.Kind == TypeFloat && b.Kind == TypeInt {
 		return &TypeDescriptor{Kind: TypeFloat, Nullable: a.Nullable || b.Nullable}, nil
 	}
+	if merged, ok := mergeDecimalSchemas(a, b); ok {
+		return merged, nil
+	}
 
 	if a.Kind != b.Kind {
 		if strict {
@@ -1170,6 +1246,12 @@ func mergeSchemas(a, b *TypeDescriptor, strict bool, path string) (*TypeDescript
 			return nil, err
 		}
 		out.Elem = elem
+	case TypeMap:
+		elem, err := mergeSchemas(a.Elem, b.Elem, strict, path+"{}")
+		if err != nil {
+			return nil, err
+		}
+		out.Elem = elem
 	}
 	return out, nil
 }
@@ -1237,6 +1319,9 @@ func mergeSchemasForMixedList(a, b *TypeDescriptor, path string) (*TypeDescripto
 	if a.Kind == TypeInt && b.Kind == TypeFloat || a.Kind == TypeFloat && b.Kind == TypeInt {
 		return &TypeDescriptor{Kind: TypeFloat, Nullable: a.Nullable || b.Nullable}, nil
 	}
+	if merged, ok := mergeDecimalSchemas(a, b); ok {
+		return merged, nil
+	}
 	if a.Kind != b.Kind {
 		return &TypeDescriptor{Kind: TypeMixed, Nullable: a.Nullable || b.Nullable}, nil
 	}
@@ -1255,6 +1340,12 @@ func mergeSchemasForMixedList(a, b *TypeDescriptor, path string) (*TypeDescripto
 			return nil, err
 		}
 		out.Elem = elem
+	case TypeMap:
+		elem, err := mergeSchemasForMixedList(a.Elem, b.Elem, path+"{}")
+		if err != nil {
+			return nil, err
+		}
+		out.Elem = elem
 	}
 	return out, nil
 }
@@ -1369,7 +1460,7 @@ func finalizeSchemaInPlace(schema *TypeDescriptor) {
 		for i := range schema.Fields {
 			schema.Fields[i].Type = FinalizeSchema(schema.Fields[i].Type)
 		}
-	case TypeList:
+	case TypeList, TypeMap:
 		if schema.Elem == nil {
 			schema.Elem = &TypeDescriptor{Kind: TypeString, Nullable: true}
 		} else {
@@ -1405,8 +1496,15 @@ func coerceValueToSchema(v Value, schema *TypeDescriptor, path string) (Value, e
 	if schema.Kind == TypeUnion {
 		return coerceUnionValueToSchema(v, schema, path)
 	}
-	if schema.Kind == TypeFloat && v.Type == TypeInt {
-		return FloatVal(float64(v.Int)), nil
+	if schema.Kind == TypeFloat && (v.Type == TypeInt || v.Type == TypeDecimal) {
+		f, _ := v.AsFloat()
+		return FloatVal(f), nil
+	}
+	if schema.Kind == TypeDecimal && (v.Type == TypeDecimal || v.Type == TypeInt) {
+		return coerceDecimalToSchema(v, schema, path)
+	}
+	if schema.Kind == TypeMap && v.Type == TypeRecord {
+		return coerceMapEntries(MapVal(v.Fields), schema, path, coerceValueToSchema)
 	}
 	if v.Type != schema.Kind {
 		return Null(), &SchemaError{Path: path, Expected: schema, Actual: FinalizeSchema(InferValueSchema(v))}
@@ -1414,6 +1512,8 @@ func coerceValueToSchema(v Value, schema *TypeDescriptor, path string) (Value, e
 	switch schema.Kind {
 	case TypeRecord:
 		return coerceRecordToSchema(v, schema, path)
+	case TypeMap:
+		return coerceMapEntries(v, schema, path, coerceValueToSchema)
 	case TypeList:
 		var items []Value
 		for i, item := range v.List {
@@ -1441,29 +1541,61 @@ func coerceValueToSchema(v Value, schema *TypeDescriptor, path string) (Value, e
 }
 
 func coerceUnionValueToSchema(v Value, schema *TypeDescriptor, path string) (Value, error) {
-	for _, branch := range schema.Branches {
-		cv, err := coerceValueToExactUnionBranch(v, branch, path)
-		if err == nil {
-			return cv, nil
+	i, cv, err := coerceToUnionBranch(v, schema, path, true)
+	if err != nil {
+		return Null(), err
+	}
+	return tagUnionValue(cv, schema.Branches[i]), nil
+}
+
+func coerceUnionValueToExactSchema(v Value, schema *TypeDescriptor, path string) (Value, error) {
+	i, cv, err := coerceToUnionBranch(v, schema, path, false)
+	if err != nil {
+		return Null(), err
+	}
+	return tagUnionValue(cv, schema.Branches[i]), nil
+}
+
+// coerceToUnionBranch finds the branch of union schema that holds v: the
+// branch v is tagged with, else the first branch v fits exactly, else, when
+// coercive is set, the first branch v can be coerced to.
+func coerceToUnionBranch(v Value, schema *TypeDescriptor, path string, coercive bool) (int, Value, error) {
+	order := make([]int, 0, len(schema.Branches))
+	tag := v.Branch()
+	if tag != "" {
+		for i, branch := range schema.Branches {
+			if branch != nil && branch.Name == tag {
+				order = append(order, i)
+			}
+		}
+	}
+	for i, branch := range schema.Branches {
+		if branch == nil || tag == "" || branch.Name != tag {
+			order = append(order, i)
+		}
+	}
+	for _, i := range order {
+		if cv, err := coerceValueToExactUnionBranch(v, schema.Branches[i], path); err == nil {
+			return i, cv, nil
 		}
 	}
-	for _, branch := range schema.Branches {
-		cv, err := coerceValueToUnionBranch(v, branch, path)
-		if err == nil {
-			return cv, nil
+	if coercive {
+		for _, i := range order {
+			if cv, err := coerceValueToUnionBranch(v, schema.Branches[i], path); err == nil {
+				return i, cv, nil
+			}
 		}
 	}
-	return Null(), &SchemaError{Path: path, Expected: schema, Actual: FinalizeSchema(InferValueSchema(v))}
+	return -1, Null(), &SchemaError{Path: path, Expected: schema, Actual: FinalizeSchema(InferValueSchema(v))}
 }
 
-func coerceUnionValueToExactSchema(v Value, schema *TypeDescriptor, path string) (Value, error) {
-	for _, branch := range schema.Branches {
-		cv, err := coerceValueToExactUnionBranch(v, branch, path)
-		if err == nil {
-			return cv, nil
-		}
+// tagUnionValue records the branch a union value was stored as, so a named
+// branch survives copies into other columns and output formats.
+func tagUnionValue(v Value, branch *TypeDescriptor) Value {
+	if v.Type == TypeNull || branch == nil || branch.Kind == TypeUnion {
+		return v
 	}
-	return Null(), &SchemaError{Path: path, Expected: schema, Actual: FinalizeSchema(InferValueSchema(v))}
+	return v.WithBranch(branch.Name)
 }
 
 func coerceValueToExactUnionBranch(v Value, schema *TypeDescriptor, path string) (Value, error) {
@@ -1485,6 +1617,8 @@ func coerceValueToExactUnionBranch(v Value, schema *TypeDescriptor, path string)
 	switch schema.Kind {
 	case TypeRecord:
 		return coerceRecordToExactSchemaNoTypeCoercion(v, schema, path)
+	case TypeMap:
+		return coerceMapEntries(v, schema, path, coerceValueToExactUnionBranch)
 	case TypeList:
 		var items []Value
 		for i, item := range v.List {
@@ -1524,8 +1658,12 @@ func coerceValueToUnionBranch(v Value, schema *TypeDescriptor, path string) (Val
 	if schema.Kind == TypeUnion {
 		return coerceUnionValueToSchema(v, schema, path)
 	}
-	if schema.Kind == TypeFloat && v.Type == TypeInt {
-		return FloatVal(float64(v.Int)), nil
+	if schema.Kind == TypeFloat && (v.Type == TypeInt || v.Type == TypeDecimal) {
+		f, _ := v.AsFloat()
+		return FloatVal(f), nil
+	}
+	if schema.Kind == TypeDecimal && (v.Type == TypeDecimal || v.Type == TypeInt) {
+		return coerceDecimalToSchema(v, schema, path)
 	}
 	if v.Type != schema.Kind {
 		return Null(), &SchemaError{Path: path, Expected: schema, Actual: FinalizeSchema(InferValueSchema(v))}
@@ -1533,6 +1671,8 @@ func coerceValueToUnionBranch(v Value, schema *TypeDescriptor, path string) (Val
 	switch schema.Kind {
 	case TypeRecord:
 		return coerceRecordToAssignableSchema(v, schema, path)
+	case TypeMap:
+		return coerceMapEntries(v, schema, path, coerceValueToUnionBranch)
 	case TypeList:
 		var items []Value
 		for i, item := range v.List {
@@ -1559,6 +1699,39 @@ func coerceValueToUnionBranch(v Value, schema *TypeDescriptor, path string) (Val
 	}
 }
 
+// coerceMapEntries coerces every map value to the map schema's value type.
+// Keys must be unique; a record coerced to a map gives its fields as entries.
+func coerceMapEntries(
+	v Value,
+	schema *TypeDescriptor,
+	path string,
+	coerceValue func(Value, *TypeDescriptor, string) (Value, error),
+) (Value, error) {
+	if err := validateUniqueRecordFieldNames(v.Fields, path); err != nil {
+		return Null(), err
+	}
+	var entries []RecordField
+	for i, entry := range v.Fields {
+		cv, err := coerceValue(entry.Value, schema.Elem, path+"{}")
+		if err != nil {
+			return Null(), err
+		}
+		if entries != nil {
+			entries[i] = RecordField{Name: entry.Name, Value: cv}
+			continue
+		}
+		if !sameCoercedValue(cv, entry.Value) {
+			entries = make([]RecordField, len(v.Fields))
+			copy(entries, v.Fields[:i])
+			entries[i] = RecordField{Name: entry.Name, Value: cv}
+		}
+	}
+	if entries != nil {
+		return MapVal(entries), nil
+	}
+	return v, nil
+}
+
 func coerceRecordToExactSchemaNoTypeCoercion(v Value, schema *TypeDescriptor, path string) (Value, error) {
 	return coerceRecordToExactSchemaWith(v, schema, path, coerceValueToExactUnionBranch)
 }
@@ -1716,11 +1889,11 @@ func sameCoercedValue(a, b Value) bool {
 	switch a.Type {
 	case TypeNull:
 		return true
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		return a.Int == b.Int
 	case TypeFloat:
 		return a.Float == b.Float
-	case TypeString:
+	case TypeString, TypeDecimal:
 		return a.Str == b.Str
 	case TypeBool:
 		return a.Bool == b.Bool
@@ -1729,7 +1902,7 @@ func sameCoercedValue(a, b Value) bool {
 			return false
 		}
 		return len(a.List) == 0 || &a.List[0] == &b.List[0]
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		if len(a.Fields) != len(b.Fields) {
 			return false
 		}
@@ -1790,15 +1963,25 @@ func writeSchemaString(b *strings.Builder, schema *TypeDescriptor) {
 		b.WriteString("list<")
 		writeSchemaString(b, schema.Elem)
 		b.WriteString(">")
+	case TypeMap:
+		b.WriteString("map<string,")
+		writeSchemaString(b, schema.Elem)
+		b.WriteString(">")
 	case TypeUnion:
 		b.WriteString("union<")
 		for i, branch := range schema.Branches {
 			if i > 0 {
 				b.WriteString(",")
 			}
+			if branch != nil && branch.Name != "" {
+				b.WriteString(branch.Name)
+				b.WriteString(":")
+			}
 			writeSchemaString(b, branch)
 		}
 		b.WriteString(">")
+	case TypeDecimal:
+		writeDecimalSchema(b, schema)
 	default:
 		b.WriteString(TypeName(schema.Kind))
 	}
@@ -1831,7 +2014,7 @@ func normalizeDescriptorInPlace(schema *TypeDescriptor) {
 			}
 			return schema.Fields[i].Name < schema.Fields[j].Name
 		})
-	case TypeList:
+	case TypeList, TypeMap:
 		schema.Elem = normalizeDescriptorForCompare(schema.Elem)
 	case TypeUnion:
 		canonical := unionSchema(schema.Branches, schema.Nullable)
@@ -1843,7 +2026,7 @@ func sameNormalized(a, b *TypeDescriptor) bool {
 	if a == nil || b == nil {
 		return a == nil && b == nil
 	}
-	if a.Kind != b.Kind || a.Nullable != b.Nullable {
+	if a.Kind != b.Kind || a.Nullable != b.Nullable || a.Precision != b.Precision || a.Scale != b.Scale || a.Name != b.Name {
 		return false
 	}
 	switch a.Kind {
@@ -1856,7 +2039,7 @@ func sameNormalized(a, b *TypeDescriptor) bool {
 				return false
 			}
 		}
-	case TypeList:
+	case TypeList, TypeMap:
 		return sameNormalized(a.Elem, b.Elem)
 	case TypeUnion:
 		if len(a.Branches) != len(b.Branches) {
@@ -1876,8 +2059,11 @@ func cloneTypeDescriptor(schema *TypeDescriptor) *TypeDescriptor {
 		return nil
 	}
 	out := &TypeDescriptor{
-		Kind:     schema.Kind,
-		Nullable: schema.Nullable,
+		Kind:      schema.Kind,
+		Nullable:  schema.Nullable,
+		Name:      schema.Name,
+		Precision: schema.Precision,
+		Scale:     schema.Scale,
 	}
 	if len(schema.Fields) > 0 {
 		out.Fields = make([]FieldDescriptor, len(schema.Fields))
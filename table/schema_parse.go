@@ -0,0 +1,247 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSchema reads a schema written in the notation String renders, such as
+// "record<id:int, tags:list<string>?>", "map<string,int>", "decimal(10,2)?" or
+// "union<Click:record<x:int>,View:record<x:int>>" with named union branches,
+// so schemas shown by describe can be pasted back into a query. Spaces between
+// tokens are ignored and record fields may be listed in any order.
+func ParseSchema(s string) (*TypeDescriptor, error) {
+	p := &schemaParser{src: s}
+	schema, err := p.parseType()
+	if err == nil {
+		p.skipSpaces()
+		if p.pos < len(p.src) {
+			err = p.errorf("unexpected %q", p.src[p.pos:])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateSchema(schema); err != nil {
+		return nil, fmt.Errorf("invalid schema %q: %w", s, err)
+	}
+	return NormalizeSchema(schema), nil
+}
+
+type schemaParser struct {
+	src string
+	pos int
+}
+
+var schemaScalarNames = map[string]ValueType{
+	"int":       TypeInt,
+	"float":     TypeFloat,
+	"string":    TypeString,
+	"bool":      TypeBool,
+	"date":      TypeDate,
+	"timestamp": TypeTimestamp,
+	"duration":  TypeDuration,
+	"mixed":     TypeMixed,
+}
+
+func (p *schemaParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid schema %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *schemaParser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// accept consumes c after optional spaces and reports whether it was there.
+func (p *schemaParser) accept(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *schemaParser) expect(c byte) error {
+	if !p.accept(c) {
+		if p.pos >= len(p.src) {
+			return p.errorf("expected %q, got end of schema", c)
+		}
+		return p.errorf("expected %q, got %q", c, p.src[p.pos])
+	}
+	return nil
+}
+
+func (p *schemaParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) && (p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *schemaParser) parseType() (*TypeDescriptor, error) {
+	start := p.pos
+	name := p.word()
+	var schema *TypeDescriptor
+	switch name {
+	case "record":
+		fields, err := p.parseFields()
+		if err != nil {
+			return nil, err
+		}
+		schema = &TypeDescriptor{Kind: TypeRecord, Fields: fields}
+	case "list":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		schema = &TypeDescriptor{Kind: TypeList, Elem: elem}
+	case "map":
+		elem, err := p.parseMapValue()
+		if err != nil {
+			return nil, err
+		}
+		schema = &TypeDescriptor{Kind: TypeMap, Elem: elem}
+	case "union":
+		branches, err := p.parseBranches()
+		if err != nil {
+			return nil, err
+		}
+		schema = &TypeDescriptor{Kind: TypeUnion, Branches: branches}
+	case "decimal":
+		precision, scale, err := p.parseDecimalParams()
+		if err != nil {
+			return nil, err
+		}
+		schema = DecimalSchema(precision, scale)
+	default:
+		kind, ok := schemaScalarNames[name]
+		if !ok {
+			p.pos = start
+			p.skipSpaces()
+			if name == "" {
+				return nil, p.errorf("expected a type")
+			}
+			return nil, p.errorf("unknown type %q", name)
+		}
+		schema = &TypeDescriptor{Kind: kind}
+	}
+	schema.Nullable = p.accept('?')
+	return schema, nil
+}
+
+func (p *schemaParser) parseFields() ([]FieldDescriptor, error) {
+	if err := p.expect('<'); err != nil {
+		return nil, err
+	}
+	var fields []FieldDescriptor
+	if p.accept('>') {
+		return fields, nil
+	}
+	for {
+		p.skipSpaces()
+		end := strings.IndexAny(p.src[p.pos:], ":<>,")
+		if end < 0 || p.src[p.pos+end] != ':' {
+			return nil, p.errorf("expected field name followed by ':'")
+		}
+		name := strings.TrimSpace(p.src[p.pos : p.pos+end])
+		if name == "" {
+			return nil, p.errorf("empty field name")
+		}
+		p.pos += end + 1
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, FieldDescriptor{Name: name, Type: typ})
+		if p.accept('>') {
+			return fields, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseMapValue reads "<string,T>" and returns T; map keys are always strings.
+func (p *schemaParser) parseMapValue() (*TypeDescriptor, error) {
+	if err := p.expect('<'); err != nil {
+		return nil, err
+	}
+	if key := p.word(); key != "string" {
+		return nil, p.errorf("map keys must be string, got %q", key)
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect('>'); err != nil {
+		return nil, err
+	}
+	return elem, nil
+}
+
+func (p *schemaParser) parseBranches() ([]*TypeDescriptor, error) {
+	if err := p.expect('<'); err != nil {
+		return nil, err
+	}
+	var branches []*TypeDescriptor
+	for {
+		p.skipSpaces()
+		name := ""
+		if end := strings.IndexAny(p.src[p.pos:], ":<>,"); end >= 0 && p.src[p.pos+end] == ':' {
+			name = strings.TrimSpace(p.src[p.pos : p.pos+end])
+			if name == "" {
+				return nil, p.errorf("empty branch name")
+			}
+			p.pos += end + 1
+		}
+		branch, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		branch.Name = name
+		branches = append(branches, branch)
+		if p.accept('>') {
+			return branches, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *schemaParser) parseDecimalParams() (int, int, error) {
+	if err := p.expect('('); err != nil {
+		return 0, 0, err
+	}
+	precision, err := strconv.Atoi(p.word())
+	if err != nil {
+		return 0, 0, p.errorf("expected decimal precision")
+	}
+	if err := p.expect(','); err != nil {
+		return 0, 0, err
+	}
+	scale, err := strconv.Atoi(p.word())
+	if err != nil {
+		return 0, 0, p.errorf("expected decimal scale")
+	}
+	if err := p.expect(')'); err != nil {
+		return 0, 0, err
+	}
+	return precision, scale, nil
+}
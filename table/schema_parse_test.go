@@ -0,0 +1,55 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSchemaRoundTripsRenderedSchemas(t *testing.T) {
+	for _, s := range []string{
+		"int",
+		"string?",
+		"decimal(10,2)?",
+		"list<list<int?>>",
+		"list<mixed>",
+		"record<a:int, b:list<record<c:bool?>>?>",
+		"record<>",
+		"map<string,list<int>?>?",
+		"map<string,mixed>",
+		"union<int,string>?",
+		"union<ev.Click:record<x:int>,ev.View:record<x:int>,int>?",
+	} {
+		schema, err := ParseSchema(s)
+		if err != nil {
+			t.Fatalf("ParseSchema(%q): %v", s, err)
+		}
+		if got := schema.String(); got != s {
+			t.Errorf("ParseSchema(%q).String() = %q", s, got)
+		}
+	}
+	schema, err := ParseSchema(" record< z : int , a:list< string >? > ")
+	if err != nil || schema.String() != "record<a:list<string>?, z:int>" {
+		t.Fatalf("spaced schema = %v, %v", schema, err)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                     "expected a type",
+		"varchar":              `unknown type "varchar"`,
+		"list<int":             `expected '>', got end of schema`,
+		"record<a int>":        "expected field name followed by ':'",
+		"record<a:int, a:int>": "duplicate record field",
+		"decimal(40,2)":        "invalid decimal(40,2)",
+		"decimal(x,2)":         "expected decimal precision",
+		"int int":              `unexpected "int"`,
+		"map<int,string>":      `map keys must be string, got "int"`,
+		"union<:int,string>":   "empty branch name",
+		"mixed":                "mixed schema is only valid inside list elements and map values",
+	}
+	for in, want := range cases {
+		if _, err := ParseSchema(in); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseSchema(%q) error = %v, want %q", in, err, want)
+		}
+	}
+}
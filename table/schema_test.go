@@ -483,6 +483,47 @@ func TestMergeValueSchemaStrictListsAndMixedElements(t *testing.T) {
 	requireSchemaString(t, mixed, "list<mixed>")
 }
 
+func TestMergeValueSchemaStrictMapValues(t *testing.T) {
+	schema, err := MergeValueSchemaStrictAtPath(nil, MapVal([]RecordField{
+		{Name: "2024-01", Value: IntVal(3)},
+		{Name: "2024-02", Value: Null()},
+	}), "counts")
+	if err != nil {
+		t.Fatalf("merge map: %v", err)
+	}
+	requireSchemaString(t, schema, "map<string,int?>")
+
+	schema, err = MergeValueSchemaStrictAtPath(schema, MapVal([]RecordField{{Name: "2024-03", Value: FloatVal(1.5)}}), "counts")
+	if err != nil {
+		t.Fatalf("merge map numeric promotion: %v", err)
+	}
+	requireSchemaString(t, schema, "map<string,float?>")
+
+	_, err = MergeValueSchemaStrictAtPath(schema, RecordVal([]RecordField{{Name: "a", Value: IntVal(1)}}), "counts")
+	requireSchemaError(t, err, "counts", "map<string,float?>", "record<a:int>")
+
+	mixed, err := MergeValueSchemaStrictAtPath(nil, MapVal([]RecordField{
+		{Name: "a", Value: IntVal(1)},
+		{Name: "b", Value: StrVal("two")},
+	}), "attrs")
+	if err != nil {
+		t.Fatalf("merge mixed map: %v", err)
+	}
+	requireSchemaString(t, mixed, "map<string,mixed>")
+}
+
+func TestCoerceValueToSchemaConvertsRecordsToMaps(t *testing.T) {
+	schema := &TypeDescriptor{Kind: TypeMap, Elem: td(TypeFloat)}
+	got, err := CoerceValueToSchema(RecordVal([]RecordField{{Name: "a", Value: IntVal(1)}}), schema)
+	if err != nil {
+		t.Fatalf("CoerceValueToSchema returned error: %v", err)
+	}
+	want := MapVal([]RecordField{{Name: "a", Value: FloatVal(1)}})
+	if !Equal(got, want) {
+		t.Fatalf("coerced value: got %s, want %s", got.AsString(), want.AsString())
+	}
+}
+
 func TestMergeValueSchemaStrictListRecordFieldUnion(t *testing.T) {
 	schema, err := MergeValueSchemaStrictAtPath(nil, ListVal([]Value{
 		RecordVal([]RecordField{{Name: "a", Value: IntVal(1)}}),
@@ -15,10 +15,15 @@ const (
 	TypeFloat
 	TypeString
 	TypeBool
-	TypeList   // List  []Value
-	TypeRecord // Fields []RecordField
-	TypeUnion  // active branch values stored in unions
-	TypeMixed  // schema-only marker for heterogeneous nested list elements
+	TypeDate      // Int days since 1970-01-01
+	TypeTimestamp // Int microseconds since the Unix epoch, UTC
+	TypeDuration  // Int microseconds
+	TypeDecimal   // Str canonical decimal text, e.g. "12.50"
+	TypeList      // List  []Value
+	TypeRecord    // Fields []RecordField
+	TypeMap       // Fields []RecordField, one per key
+	TypeUnion     // active branch values stored in unions
+	TypeMixed     // schema-only marker for heterogeneous nested list elements
 )
 
 // RecordField is a named field in a record value.
@@ -35,6 +40,7 @@ type Value struct {
 	Float  float64
 	Str    string
 	Bool   bool
+	branch uint32 // interned union branch name (see WithBranch); fits in Bool's padding
 	List   []Value
 	Fields []RecordField
 }
@@ -74,6 +80,12 @@ func RecordVal(fields []RecordField) Value {
 	return Value{Type: TypeRecord, Fields: fields}
 }
 
+// MapVal creates a map value from key/value entries, which must have unique
+// keys in ascending order.
+func MapVal(entries []RecordField) Value {
+	return Value{Type: TypeMap, Fields: entries}
+}
+
 // IsNull returns true if the value is null.
 func (v Value) IsNull() bool {
 	return v.Type == TypeNull
@@ -86,6 +98,8 @@ func (v Value) AsFloat() (float64, bool) {
 		return float64(v.Int), true
 	case TypeFloat:
 		return v.Float, true
+	case TypeDecimal:
+		return decimalAsFloat(v), true
 	default:
 		return 0, false
 	}
@@ -100,20 +114,26 @@ func (v Value) AsString() string {
 		return fmt.Sprintf("%d", v.Int)
 	case TypeFloat:
 		return fmt.Sprintf("%g", v.Float)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		return v.Str
 	case TypeBool:
 		if v.Bool {
 			return "true"
 		}
 		return "false"
+	case TypeDate:
+		return formatDate(v.Int)
+	case TypeTimestamp:
+		return formatTimestamp(v.Int)
+	case TypeDuration:
+		return formatDuration(v.Int)
 	case TypeList:
 		parts := make([]string, len(v.List))
 		for i, e := range v.List {
 			parts[i] = e.AsString()
 		}
 		return "[" + strings.Join(parts, ", ") + "]"
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		parts := make([]string, len(v.Fields))
 		for i, f := range v.Fields {
 			parts[i] = f.Name + ":" + f.Value.AsString()
@@ -346,9 +366,9 @@ type Column struct {
 	schema   *TypeDescriptor
 	hasUnion bool
 	nulls    []bool          // true = null at that row index
-	ints     []int64         // TypeInt
+	ints     []int64         // TypeInt and the temporal types
 	floats   []float64       // TypeFloat
-	strs     []string        // TypeString
+	strs     []string        // TypeString and TypeDecimal
 	bools    []bool          // TypeBool
 	lists    [][]Value       // TypeList
 	records  [][]RecordField // TypeRecord
@@ -363,16 +383,20 @@ func (c *Column) Get(i int) Value {
 	switch c.typ {
 	case TypeInt:
 		return IntVal(c.ints[i])
+	case TypeDate, TypeTimestamp, TypeDuration:
+		return Value{Type: c.typ, Int: c.ints[i]}
 	case TypeFloat:
 		return FloatVal(c.floats[i])
 	case TypeString:
 		return StrVal(c.strs[i])
+	case TypeDecimal:
+		return Value{Type: TypeDecimal, Str: c.strs[i]}
 	case TypeBool:
 		return BoolVal(c.bools[i])
 	case TypeList:
 		return ListVal(c.lists[i])
-	case TypeRecord:
-		return RecordVal(c.records[i])
+	case TypeRecord, TypeMap:
+		return Value{Type: c.typ, Fields: c.records[i]}
 	case TypeUnion:
 		return c.unions[i]
 	default:
@@ -407,7 +431,13 @@ func (c *Column) Append(v Value) {
 	if newType != c.typ {
 		c.convertTo(newType)
 	}
-	if c.typ == TypeList || c.typ == TypeRecord {
+	if c.typ == TypeDecimal {
+		if schemaMerge.changed {
+			c.rescaleStoredDecimals()
+		}
+		v = RescaleDecimal(v, c.schema.Scale)
+	}
+	if c.typ == TypeList || c.typ == TypeRecord || c.typ == TypeMap {
 		schema := c.Schema()
 		if schemaMerge.changed {
 			c.normalizeStoredValuesToSchema(schema)
@@ -418,18 +448,18 @@ func (c *Column) Append(v Value) {
 	}
 	c.nulls = append(c.nulls, false)
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = append(c.ints, v.Int)
 	case TypeFloat:
 		f, _ := v.AsFloat()
 		c.floats = append(c.floats, f)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		c.strs = append(c.strs, v.AsString())
 	case TypeBool:
 		c.bools = append(c.bools, v.Bool)
 	case TypeList:
 		c.lists = append(c.lists, v.List)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		c.records = append(c.records, v.Fields)
 	case TypeUnion:
 		c.unions = append(c.unions, v)
@@ -441,13 +471,13 @@ func (c *Column) appendFastScalar(v Value) bool {
 		return false
 	}
 	switch v.Type {
-	case TypeInt, TypeFloat, TypeString, TypeBool:
+	case TypeInt, TypeFloat, TypeString, TypeBool, TypeDate, TypeTimestamp, TypeDuration:
 	default:
 		return false
 	}
 	c.nulls = append(c.nulls, false)
 	switch v.Type {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = append(c.ints, v.Int)
 	case TypeFloat:
 		c.floats = append(c.floats, v.Float)
@@ -500,7 +530,10 @@ func (c *Column) mergeSchema(v Value) schemaMergeResult {
 		return schemaMergeResult{changed: changed}
 	}
 
-	if v.Type != TypeList && v.Type != TypeRecord {
+	if v.Type == TypeDecimal || v.Type == TypeInt && c.schema != nil && c.schema.Kind == TypeDecimal {
+		return schemaMergeResult{changed: c.mergeDecimalSchema(v)}
+	}
+	if v.Type != TypeList && v.Type != TypeRecord && v.Type != TypeMap {
 		return schemaMergeResult{changed: c.mergeScalarSchema(v.Type)}
 	}
 
@@ -514,7 +547,7 @@ func (c *Column) mergeSchema(v Value) schemaMergeResult {
 			c.schema.Nullable = true
 			c.hasUnion = SchemaContainsUnion(c.schema)
 			return schemaMergeResult{changed: true}
-		case TypeInt, TypeFloat, TypeBool:
+		case TypeInt, TypeFloat, TypeBool, TypeDate, TypeTimestamp, TypeDuration, TypeDecimal:
 			c.schema = &TypeDescriptor{Kind: TypeString, Nullable: c.schema.Nullable}
 			c.hasUnion = false
 			return schemaMergeResult{changed: true}
@@ -557,7 +590,7 @@ func schemaFitsWithoutPermissiveCoercion(actual, target *TypeDescriptor) bool {
 	}
 
 	switch target.Kind {
-	case TypeList:
+	case TypeList, TypeMap:
 		return schemaFitsWithoutPermissiveCoercion(actual.Elem, target.Elem)
 	case TypeRecord:
 		actualFields := make(map[string]*TypeDescriptor, len(actual.Fields))
@@ -595,13 +628,13 @@ func (c *Column) normalizeStoredValuesToSchema(schema *TypeDescriptor) {
 				c.lists[i] = v.List
 			}
 		}
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		for i := range c.records {
 			if c.nulls[i] {
 				continue
 			}
-			v := coerceValueToSchemaPermissive(RecordVal(c.records[i]), schema)
-			if v.Type == TypeRecord {
+			v := coerceValueToSchemaPermissive(Value{Type: c.typ, Fields: c.records[i]}, schema)
+			if v.Type == c.typ {
 				c.records[i] = v.Fields
 			}
 		}
@@ -626,12 +659,17 @@ func coerceValueToSchemaPermissive(v Value, schema *TypeDescriptor) Value {
 			return v
 		}
 	case TypeFloat:
-		if v.Type == TypeInt {
-			return FloatVal(float64(v.Int))
+		if v.Type == TypeInt || v.Type == TypeDecimal {
+			f, _ := v.AsFloat()
+			return FloatVal(f)
 		}
 		if v.Type == TypeFloat {
 			return v
 		}
+	case TypeDecimal:
+		if v.Type == TypeDecimal || v.Type == TypeInt {
+			return RescaleDecimal(v, schema.Scale)
+		}
 	case TypeString:
 		return StrVal(v.AsString())
 	case TypeBool:
@@ -647,6 +685,15 @@ func coerceValueToSchemaPermissive(v Value, schema *TypeDescriptor) Value {
 			items[i] = coerceValueToSchemaPermissive(item, schema.Elem)
 		}
 		return ListVal(items)
+	case TypeMap:
+		if v.Type != TypeMap {
+			return v
+		}
+		entries := make([]RecordField, len(v.Fields))
+		for i, entry := range v.Fields {
+			entries[i] = RecordField{Name: entry.Name, Value: coerceValueToSchemaPermissive(entry.Value, schema.Elem)}
+		}
+		return MapVal(entries)
 	case TypeRecord:
 		if v.Type != TypeRecord {
 			return v
@@ -715,6 +762,15 @@ func (c *Column) mergeScalarSchema(kind ValueType) bool {
 		return true
 	case TypeString:
 		return false
+	case TypeDecimal:
+		if kind == TypeFloat {
+			c.schema = &TypeDescriptor{Kind: TypeFloat, Nullable: nullable}
+			c.hasUnion = false
+			return true
+		}
+		c.schema = &TypeDescriptor{Kind: TypeString, Nullable: nullable}
+		c.hasUnion = false
+		return true
 	default:
 		c.schema = &TypeDescriptor{Kind: TypeString, Nullable: nullable}
 		c.hasUnion = false
@@ -722,21 +778,57 @@ func (c *Column) mergeScalarSchema(kind ValueType) bool {
 	}
 }
 
+// mergeDecimalSchema widens the column schema for a decimal value, or for an
+// int joining a decimal column. Each value contributes its own digits, so a
+// column of prices loaded row by row becomes the narrowest decimal holding
+// them all.
+func (c *Column) mergeDecimalSchema(v Value) bool {
+	_, scale, _ := v.Decimal()
+	incoming := DecimalSchema(DecimalPrecision(v), scale)
+	if c.schema == nil || c.schema.Kind == TypeNull {
+		incoming.Nullable = c.schema != nil
+		c.schema = incoming
+		c.hasUnion = false
+		return true
+	}
+	if c.schema.Kind == TypeString {
+		return false
+	}
+	merged, ok := mergeDecimalSchemas(c.schema, incoming)
+	if !ok {
+		c.schema = &TypeDescriptor{Kind: TypeString, Nullable: c.schema.Nullable}
+		c.hasUnion = false
+		return true
+	}
+	changed := !sameNormalized(c.schema, merged)
+	c.schema = merged
+	c.hasUnion = false
+	return changed
+}
+
+func (c *Column) rescaleStoredDecimals() {
+	for i := range c.strs {
+		if !c.nulls[i] {
+			c.strs[i] = RescaleDecimal(Value{Type: TypeDecimal, Str: c.strs[i]}, c.schema.Scale).Str
+		}
+	}
+}
+
 func (c *Column) appendNull() {
 	c.nulls = append(c.nulls, true)
 	// keep typed slice length in sync (TypeNull has no slice yet)
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = append(c.ints, 0)
 	case TypeFloat:
 		c.floats = append(c.floats, 0)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		c.strs = append(c.strs, "")
 	case TypeBool:
 		c.bools = append(c.bools, false)
 	case TypeList:
 		c.lists = append(c.lists, nil)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		c.records = append(c.records, nil)
 	case TypeUnion:
 		c.unions = append(c.unions, Null())
@@ -757,6 +849,14 @@ func widenType(existing, incoming ValueType) ValueType {
 	if (existing == TypeInt && incoming == TypeFloat) || (existing == TypeFloat && incoming == TypeInt) {
 		return TypeFloat
 	}
+	if existing == TypeDecimal || incoming == TypeDecimal {
+		switch {
+		case existing == TypeInt || incoming == TypeInt:
+			return TypeDecimal
+		case existing == TypeFloat || incoming == TypeFloat:
+			return TypeFloat
+		}
+	}
 	return TypeString
 }
 
@@ -766,17 +866,34 @@ func (c *Column) convertTo(newType ValueType) {
 	oldType := c.typ
 
 	switch newType {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = make([]int64, n)
 	case TypeFloat:
 		newFloats := make([]float64, n)
-		if oldType == TypeInt {
+		switch oldType {
+		case TypeInt:
 			for i, v := range c.ints {
 				newFloats[i] = float64(v)
 			}
 			c.ints = nil
+		case TypeDecimal:
+			for i, s := range c.strs {
+				newFloats[i] = decimalAsFloat(Value{Type: TypeDecimal, Str: s})
+			}
+			c.strs = nil
 		}
 		c.floats = newFloats
+	case TypeDecimal:
+		newStrs := make([]string, n)
+		if oldType == TypeInt {
+			for i, v := range c.ints {
+				if !c.nulls[i] {
+					newStrs[i] = RescaleDecimal(IntVal(v), c.schema.Scale).Str
+				}
+			}
+			c.ints = nil
+		}
+		c.strs = newStrs
 	case TypeString:
 		newStrs := make([]string, n)
 		for i := range c.nulls {
@@ -784,6 +901,10 @@ func (c *Column) convertTo(newType ValueType) {
 				switch oldType {
 				case TypeInt:
 					newStrs[i] = fmt.Sprintf("%d", c.ints[i])
+				case TypeDate, TypeTimestamp, TypeDuration:
+					newStrs[i] = Value{Type: oldType, Int: c.ints[i]}.AsString()
+				case TypeDecimal:
+					newStrs[i] = c.strs[i]
 				case TypeFloat:
 					newStrs[i] = fmt.Sprintf("%g", c.floats[i])
 				case TypeBool:
@@ -794,8 +915,8 @@ func (c *Column) convertTo(newType ValueType) {
 					}
 				case TypeList:
 					newStrs[i] = ListVal(c.lists[i]).AsString()
-				case TypeRecord:
-					newStrs[i] = RecordVal(c.records[i]).AsString()
+				case TypeRecord, TypeMap:
+					newStrs[i] = Value{Type: oldType, Fields: c.records[i]}.AsString()
 				case TypeUnion:
 					newStrs[i] = c.unions[i].AsString()
 				}
@@ -812,7 +933,7 @@ func (c *Column) convertTo(newType ValueType) {
 		c.bools = make([]bool, n)
 	case TypeList:
 		c.lists = make([][]Value, n)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		c.records = make([][]RecordField, n)
 	case TypeUnion:
 		c.unions = make([]Value, n)
@@ -1145,17 +1266,17 @@ func storageTypeForSchema(schema *TypeDescriptor) ValueType {
 func preallocateColumnStorage(c *Column, capacity int) {
 	c.nulls = make([]bool, 0, capacity)
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = make([]int64, 0, capacity)
 	case TypeFloat:
 		c.floats = make([]float64, 0, capacity)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		c.strs = make([]string, 0, capacity)
 	case TypeBool:
 		c.bools = make([]bool, 0, capacity)
 	case TypeList:
 		c.lists = make([][]Value, 0, capacity)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		c.records = make([][]RecordField, 0, capacity)
 	case TypeUnion:
 		c.unions = make([]Value, 0, capacity)
@@ -1253,6 +1374,14 @@ func exactValueFitsSchema(v Value, schema *TypeDescriptor, path string, allowMix
 		if v.Type == TypeBool {
 			return nil
 		}
+	case TypeDate, TypeTimestamp, TypeDuration:
+		if v.Type == schema.Kind {
+			return nil
+		}
+	case TypeDecimal:
+		if _, scale, ok := v.Decimal(); ok && v.Type == TypeDecimal && scale == schema.Scale && DecimalPrecision(v) <= schema.Precision {
+			return nil
+		}
 	case TypeList:
 		if v.Type != TypeList {
 			return exactValueSchemaError(path, schema, v)
@@ -1263,6 +1392,16 @@ func exactValueFitsSchema(v Value, schema *TypeDescriptor, path string, allowMix
 			}
 		}
 		return nil
+	case TypeMap:
+		if v.Type != TypeMap {
+			return exactValueSchemaError(path, schema, v)
+		}
+		for _, entry := range v.Fields {
+			if err := exactValueFitsSchema(entry.Value, schema.Elem, appendSchemaPath(path, "{}"), allowMixed); err != nil {
+				return err
+			}
+		}
+		return nil
 	case TypeRecord:
 		if v.Type != TypeRecord {
 			return exactValueSchemaError(path, schema, v)
@@ -1332,8 +1471,13 @@ func coerceValueForTypedAppend(v Value, schema *TypeDescriptor, path string) (Va
 		switch v.Type {
 		case TypeFloat:
 			return v, nil
-		case TypeInt:
-			return FloatVal(float64(v.Int)), nil
+		case TypeInt, TypeDecimal:
+			f, _ := v.AsFloat()
+			return FloatVal(f), nil
+		}
+	case TypeDecimal:
+		if v.Type == TypeDecimal || v.Type == TypeInt {
+			return coerceDecimalToSchema(v, schema, path)
 		}
 	case TypeString:
 		if v.Type == TypeString {
@@ -1343,6 +1487,10 @@ func coerceValueForTypedAppend(v Value, schema *TypeDescriptor, path string) (Va
 		if v.Type == TypeBool {
 			return v, nil
 		}
+	case TypeDate, TypeTimestamp, TypeDuration:
+		if v.Type == schema.Kind {
+			return v, nil
+		}
 	case TypeMixed:
 		return v, nil
 	default:
@@ -1394,6 +1542,13 @@ func valueMayChangeSchemaNullability(schema *TypeDescriptor, v Value) bool {
 			}
 		}
 		return false
+	case TypeMap:
+		for _, entry := range v.Fields {
+			if valueMayChangeSchemaNullability(schema.Elem, entry.Value) {
+				return true
+			}
+		}
+		return false
 	default:
 		return false
 	}
@@ -1403,7 +1558,7 @@ func valueContainsNull(v Value) bool {
 	switch v.Type {
 	case TypeNull:
 		return true
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		for _, field := range v.Fields {
 			if valueContainsNull(field.Value) {
 				return true
@@ -1429,18 +1584,18 @@ func (c *Column) appendCoerced(v Value) {
 	}
 	c.nulls = append(c.nulls, false)
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		c.ints = append(c.ints, v.Int)
 	case TypeFloat:
 		f, _ := v.AsFloat()
 		c.floats = append(c.floats, f)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		c.strs = append(c.strs, v.AsString())
 	case TypeBool:
 		c.bools = append(c.bools, v.Bool)
 	case TypeList:
 		c.lists = append(c.lists, v.List)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		c.records = append(c.records, v.Fields)
 	case TypeUnion:
 		c.unions = append(c.unions, v)
@@ -1487,13 +1642,13 @@ func sliceColumn(c *Column, from, to int) *Column {
 	nc := &Column{name: c.name, typ: c.typ, schema: schema, hasUnion: SchemaContainsUnion(schema), nulls: make([]bool, n)}
 	copy(nc.nulls, c.nulls[from:to])
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		nc.ints = make([]int64, n)
 		copy(nc.ints, c.ints[from:to])
 	case TypeFloat:
 		nc.floats = make([]float64, n)
 		copy(nc.floats, c.floats[from:to])
-	case TypeString:
+	case TypeString, TypeDecimal:
 		nc.strs = make([]string, n)
 		copy(nc.strs, c.strs[from:to])
 	case TypeBool:
@@ -1502,7 +1657,7 @@ func sliceColumn(c *Column, from, to int) *Column {
 	case TypeList:
 		nc.lists = make([][]Value, n)
 		copy(nc.lists, c.lists[from:to])
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		nc.records = make([][]RecordField, n)
 		copy(nc.records, c.records[from:to])
 	case TypeUnion:
@@ -1535,7 +1690,7 @@ func permuteColumn(c *Column, perm []int) *Column {
 		nc.nulls[i] = c.nulls[p]
 	}
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		nc.ints = make([]int64, n)
 		for i, p := range perm {
 			nc.ints[i] = c.ints[p]
@@ -1545,7 +1700,7 @@ func permuteColumn(c *Column, perm []int) *Column {
 		for i, p := range perm {
 			nc.floats[i] = c.floats[p]
 		}
-	case TypeString:
+	case TypeString, TypeDecimal:
 		nc.strs = make([]string, n)
 		for i, p := range perm {
 			nc.strs[i] = c.strs[p]
@@ -1560,7 +1715,7 @@ func permuteColumn(c *Column, perm []int) *Column {
 		for i, p := range perm {
 			nc.lists[i] = c.lists[p]
 		}
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		nc.records = make([][]RecordField, n)
 		for i, p := range perm {
 			nc.records[i] = c.records[p]
@@ -1750,13 +1905,13 @@ func cloneColumn(c *Column) *Column {
 	nc := &Column{name: c.name, typ: c.typ, schema: schema, hasUnion: SchemaContainsUnion(schema), nulls: make([]bool, n)}
 	copy(nc.nulls, c.nulls)
 	switch c.typ {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		nc.ints = make([]int64, n)
 		copy(nc.ints, c.ints)
 	case TypeFloat:
 		nc.floats = make([]float64, n)
 		copy(nc.floats, c.floats)
-	case TypeString:
+	case TypeString, TypeDecimal:
 		nc.strs = make([]string, n)
 		copy(nc.strs, c.strs)
 	case TypeBool:
@@ -1765,7 +1920,7 @@ func cloneColumn(c *Column) *Column {
 	case TypeList:
 		nc.lists = make([][]Value, n)
 		copy(nc.lists, c.lists)
-	case TypeRecord:
+	case TypeRecord, TypeMap:
 		nc.records = make([][]RecordField, n)
 		copy(nc.records, c.records)
 	case TypeUnion:
@@ -0,0 +1,148 @@
+package table
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the accepted timestamp spellings. Layouts without a
+// zone are read as UTC; zoned layouts are converted to UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Temporal values share int storage: dates count days since the Unix epoch,
+// timestamps count microseconds since the Unix epoch in UTC, and durations
+// count microseconds.
+
+// DateVal creates a date value from days since 1970-01-01.
+func DateVal(days int64) Value {
+	return Value{Type: TypeDate, Int: days}
+}
+
+// TimestampVal creates a timestamp value from microseconds since the Unix epoch.
+func TimestampVal(micros int64) Value {
+	return Value{Type: TypeTimestamp, Int: micros}
+}
+
+// DurationVal creates a duration value from a count of microseconds.
+func DurationVal(micros int64) Value {
+	return Value{Type: TypeDuration, Int: micros}
+}
+
+// DateFromTime returns the date of t in t's own location.
+func DateFromTime(t time.Time) Value {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return DateVal(floorDiv(midnight.Unix(), 86400))
+}
+
+// TimestampFromTime returns t as a UTC timestamp truncated to microseconds.
+func TimestampFromTime(t time.Time) Value {
+	return TimestampVal(t.UnixMicro())
+}
+
+// DurationFromTime converts d to a duration truncated to microseconds.
+func DurationFromTime(d time.Duration) Value {
+	return DurationVal(d.Microseconds())
+}
+
+// Time returns the UTC instant of a date (midnight) or timestamp value.
+func (v Value) Time() (time.Time, bool) {
+	switch v.Type {
+	case TypeDate:
+		return time.Unix(v.Int*86400, 0).UTC(), true
+	case TypeTimestamp:
+		return time.UnixMicro(v.Int).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// ParseDate parses an ISO-8601 calendar date such as 2024-03-01.
+func ParseDate(s string) (Value, bool) {
+	if !looksLikeISODate(s) || len(s) != len("2006-01-02") {
+		return Null(), false
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Null(), false
+	}
+	return DateFromTime(t), true
+}
+
+// ParseTimestamp parses an ISO-8601 timestamp with a T or space separator and
+// optional fractional seconds. An explicit zone offset is honored; timestamps
+// without one are taken to be UTC.
+func ParseTimestamp(s string) (Value, bool) {
+	return ParseTimestampIn(s, time.UTC)
+}
+
+// ParseTimestampIn is like ParseTimestamp but reads timestamps without a zone
+// offset in loc.
+func ParseTimestampIn(s string, loc *time.Location) (Value, bool) {
+	if !looksLikeISODate(s) || len(s) < len("2006-01-02T15:04:05") {
+		return Null(), false
+	}
+	if sep := s[10]; sep != 'T' && sep != ' ' {
+		return Null(), false
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return TimestampFromTime(t), true
+		}
+	}
+	return Null(), false
+}
+
+// ParseDuration parses a Go-style duration such as 1h30m or 250ms.
+func ParseDuration(s string) (Value, bool) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return Null(), false
+	}
+	return DurationFromTime(d), true
+}
+
+func looksLikeISODate(s string) bool {
+	if len(s) < len("2006-01-02") || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	for _, i := range []int{0, 1, 2, 3, 5, 6, 8, 9} {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func formatDate(days int64) string {
+	return time.Unix(days*86400, 0).UTC().Format("2006-01-02")
+}
+
+func formatTimestamp(micros int64) string {
+	return time.UnixMicro(micros).UTC().Format(time.RFC3339Nano)
+}
+
+// formatDuration renders d like time.Duration. Durations beyond
+// time.Duration's ±292 year range fall back to a microsecond count.
+func formatDuration(micros int64) string {
+	const limit = math.MaxInt64 / 1000
+	if micros > limit || micros < -limit {
+		return strconv.FormatInt(micros, 10) + "us"
+	}
+	return time.Duration(micros * 1000).String()
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
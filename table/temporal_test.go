@@ -0,0 +1,90 @@
+package table
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTemporalValues(t *testing.T) {
+	d, ok := ParseDate("2024-03-01")
+	if !ok || d.Type != TypeDate || d.AsString() != "2024-03-01" {
+		t.Fatalf("ParseDate = %v, %v", d, ok)
+	}
+	if pre, ok := ParseDate("1969-12-31"); !ok || pre.Int != -1 {
+		t.Fatalf("pre-epoch date = %v, %v", pre, ok)
+	}
+	for _, bad := range []string{"2024-3-01", "2024-02-30", "2024-03-01T00:00:00Z", "20240301"} {
+		if _, ok := ParseDate(bad); ok {
+			t.Errorf("ParseDate(%q) accepted", bad)
+		}
+	}
+
+	cases := map[string]string{
+		"2024-03-01T10:30:00Z":             "2024-03-01T10:30:00Z",
+		"2024-03-01 10:30:00":              "2024-03-01T10:30:00Z",
+		"2024-03-01T10:30:00.25+02:00":     "2024-03-01T08:30:00.25Z",
+		"2024-03-01T10:30:00.123456789":    "2024-03-01T10:30:00.123456Z",
+		"2024-03-01 10:30:00.000001-01:00": "2024-03-01T11:30:00.000001Z",
+	}
+	for in, want := range cases {
+		ts, ok := ParseTimestamp(in)
+		if !ok || ts.Type != TypeTimestamp || ts.AsString() != want {
+			t.Errorf("ParseTimestamp(%q) = %q, %v; want %q", in, ts.AsString(), ok, want)
+		}
+	}
+	if _, ok := ParseTimestamp("2024-03-01"); ok {
+		t.Error("ParseTimestamp accepted a bare date")
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	if ts, ok := ParseTimestampIn("2024-03-01 10:30:00", ny); !ok || ts.AsString() != "2024-03-01T15:30:00Z" {
+		t.Fatalf("ParseTimestampIn = %q, %v", ts.AsString(), ok)
+	}
+}
+
+func TestDurationValues(t *testing.T) {
+	d, ok := ParseDuration("1h30m")
+	if !ok || d.Type != TypeDuration || d.Int != int64(90*time.Minute/time.Microsecond) {
+		t.Fatalf("ParseDuration = %v, %v", d, ok)
+	}
+	if got := d.AsString(); got != "1h30m0s" {
+		t.Fatalf("AsString = %q", got)
+	}
+	if _, ok := ParseDuration("soon"); ok {
+		t.Fatal("ParseDuration accepted junk")
+	}
+	if got := DurationVal(1 << 62).AsString(); got != "4611686018427387904us" {
+		t.Fatalf("out of range duration = %q", got)
+	}
+}
+
+func TestTemporalValuesCompareAndKeyByType(t *testing.T) {
+	early, _ := ParseDate("2024-01-01")
+	late, _ := ParseDate("2024-02-01")
+	if c, err := CompareStrict(early, late); err != nil || c >= 0 {
+		t.Fatalf("CompareStrict(date) = %d, %v", c, err)
+	}
+	if _, err := CompareStrict(early, TimestampVal(0)); err == nil {
+		t.Fatal("expected date and timestamp to be incomparable")
+	}
+	if CanonicalKey(DateVal(1)) == CanonicalKey(IntVal(1)) || CanonicalKey(DateVal(1)) == CanonicalKey(DurationVal(1)) {
+		t.Fatal("temporal canonical keys collide with other kinds")
+	}
+	if !IsOrderable(&TypeDescriptor{Kind: TypeTimestamp}) || !IsComparable(&TypeDescriptor{Kind: TypeDuration}) {
+		t.Fatal("temporal kinds should be comparable and orderable")
+	}
+}
+
+func TestTemporalColumnStorage(t *testing.T) {
+	ts, _ := ParseTimestamp("2024-03-01T10:30:00Z")
+	tbl := NewTable([]string{"ts"})
+	tbl.AddRow([]Value{ts})
+	tbl.AddRow([]Value{Null()})
+	if got := tbl.Get(0, "ts"); got.Type != TypeTimestamp || got.Int != ts.Int {
+		t.Fatalf("Get = %v", got)
+	}
+	requireSchemaString(t, tbl.Schema().Columns[0].Type, "timestamp?")
+}
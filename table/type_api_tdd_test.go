@@ -90,7 +90,7 @@ func TestCentralTypeAPIValidateSchemaRestrictsMixedToListElements(t *testing.T)
 			if err == nil {
 				t.Fatal("expected ValidateSchema to reject mixed outside list elements")
 			}
-			want := tc.path + " mixed schema is only valid inside list elements"
+			want := tc.path + " mixed schema is only valid inside list elements and map values"
 			if err.Error() != want {
 				t.Fatalf("ValidateSchema error: got %q, want %q", err.Error(), want)
 			}
@@ -125,6 +125,22 @@ func TestCentralTypeAPIStructuralUnionContract(t *testing.T) {
 		t.Fatalf("UnionOf should be associative for stable branch order: left=%s right=%s", Render(leftGrouped), Render(rightGrouped))
 	}
 
+	click := &TypeDescriptor{Kind: TypeRecord, Name: "Click", Fields: []FieldDescriptor{{Name: "x", Type: td(TypeInt)}}}
+	view := WithoutNull(click)
+	view.Name = "View"
+	named := UnionOf([]*TypeDescriptor{click, view, click}, false)
+	requireSchemaString(t, named, "union<Click:record<x:int>,View:record<x:int>>")
+	if i := UnionBranch(named, RecordVal([]RecordField{{Name: "x", Value: IntVal(1)}}).WithBranch("View")); i != 1 {
+		t.Fatalf("UnionBranch of a View value = %d, want 1", i)
+	}
+	if i := UnionBranch(named, RecordVal([]RecordField{{Name: "x", Value: IntVal(1)}})); i != 0 {
+		t.Fatalf("UnionBranch of an untagged value = %d, want the first fitting branch", i)
+	}
+	if got := IntVal(1).WithBranch("View").WithBranch("").Branch(); got != "" {
+		t.Fatalf("cleared branch tag = %q", got)
+	}
+	requireSchemaString(t, UnionOf([]*TypeDescriptor{click}, true), "record<x:int>?")
+
 	reordered := UnionOf([]*TypeDescriptor{td(TypeString), td(TypeInt)}, false)
 	if EquivalentSchema(UnionOf([]*TypeDescriptor{td(TypeInt), td(TypeString)}, false), reordered) {
 		t.Fatal("union branch order is intentionally significant for coercion behavior")
@@ -257,7 +273,7 @@ func TestCentralTypeAPICoercionModes(t *testing.T) {
 
 	if _, err := CoerceValueToSchema(BoolVal(true), mixedType()); err == nil {
 		t.Fatal("top-level mixed coercion should be rejected by schema validation")
-	} else if got, want := err.Error(), "<value> mixed schema is only valid inside list elements"; got != want {
+	} else if got, want := err.Error(), "<value> mixed schema is only valid inside list elements and map values"; got != want {
 		t.Fatalf("top-level mixed coercion error: got %q, want %q", got, want)
 	}
 }
@@ -391,7 +407,7 @@ func TestCentralTypeAPIUnifyStrictRejectsTopLevelMixedAsInvalidSchema(t *testing
 	if err == nil {
 		t.Fatal("expected UnifyStrict to reject top-level mixed before unification")
 	}
-	if got, want := err.Error(), "<value> mixed schema is only valid inside list elements"; got != want {
+	if got, want := err.Error(), "<value> mixed schema is only valid inside list elements and map values"; got != want {
 		t.Fatalf("UnifyStrict top-level mixed error: got %q, want %q", got, want)
 	}
 }
@@ -53,12 +53,28 @@ func EquivalentSchema(a, b *TypeDescriptor) bool {
 	return sameNormalized(NormalizeSchema(a), NormalizeSchema(b))
 }
 
-// UnionOf builds a normalized structural union descriptor. Source-level branch
-// tags are not retained; structurally equivalent branches collapse.
+// UnionOf builds a normalized structural union descriptor. Structurally
+// equivalent branches collapse unless they carry different Names, which keeps
+// source-level branch names such as Avro record names apart.
 func UnionOf(branches []*TypeDescriptor, nullable bool) *TypeDescriptor {
 	return unionSchema(branches, nullable)
 }
 
+// UnionBranch returns the index of the branch of a union schema that holds v:
+// the branch v is tagged with (see WithBranch), else the first branch v fits exactly, else the
+// first branch v can be coerced to. It returns -1 for null values and values
+// no branch accepts.
+func UnionBranch(schema *TypeDescriptor, v Value) int {
+	if v.IsNull() || schema == nil || schema.Kind != TypeUnion {
+		return -1
+	}
+	i, _, err := coerceToUnionBranch(v, schema, "", true)
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
 // UnifySchemas merges two schemas using the selected type-system mode.
 func UnifySchemas(a, b *TypeDescriptor, mode SchemaUnifyMode) (*TypeDescriptor, error) {
 	return UnifySchemasAtPath(a, b, mode, "")
@@ -153,7 +169,8 @@ func coerceValueToPermissiveSchemaAtPath(v Value, schema *TypeDescriptor, path s
 }
 
 // ValidateSchema rejects ambiguous descriptors such as records with duplicate
-// field names, and descriptors that place mixed outside a list element subtree.
+// field names, and descriptors that place mixed outside a list element or map
+// value subtree.
 // Nil schemas are accepted as unknown schemas.
 func ValidateSchema(schema *TypeDescriptor) error {
 	return ValidateSchemaAtPath(schema, "")
@@ -174,7 +191,7 @@ func validateSchemaAtPath(schema *TypeDescriptor, path string, allowMixed bool)
 			if path == "" {
 				path = "<value>"
 			}
-			return fmt.Errorf("%s mixed schema is only valid inside list elements", path)
+			return fmt.Errorf("%s mixed schema is only valid inside list elements and map values", path)
 		}
 	case TypeRecord:
 		seen := make(map[string]struct{}, len(schema.Fields))
@@ -189,6 +206,10 @@ func validateSchemaAtPath(schema *TypeDescriptor, path string, allowMixed bool)
 		}
 	case TypeList:
 		return validateSchemaAtPath(schema.Elem, appendSchemaPath(path, "[]"), true)
+	case TypeMap:
+		return validateSchemaAtPath(schema.Elem, appendSchemaPath(path, "{}"), true)
+	case TypeDecimal:
+		return validateDecimalSchema(schema, path)
 	case TypeUnion:
 		for _, branch := range schema.Branches {
 			if err := validateSchemaAtPath(branch, path, allowMixed); err != nil {
@@ -0,0 +1,47 @@
+package table
+
+import "sync"
+
+// unionBranchNames interns the branch names union values are tagged with, so
+// a value carries a 4-byte id rather than the name itself. Names come from
+// source schemas such as Avro named records, so the table stays small.
+var unionBranchNames = struct {
+	sync.RWMutex
+	ids   map[string]uint32
+	names []string
+}{ids: map[string]uint32{"": 0}, names: []string{""}}
+
+// WithBranch returns v tagged as a value of the union branch called name, so
+// structurally identical named branches, such as two Avro records with the
+// same fields, stay apart through copies and output. An empty name clears the
+// tag.
+func (v Value) WithBranch(name string) Value {
+	if name == "" {
+		v.branch = 0
+		return v
+	}
+	unionBranchNames.RLock()
+	id, ok := unionBranchNames.ids[name]
+	unionBranchNames.RUnlock()
+	if !ok {
+		unionBranchNames.Lock()
+		if id, ok = unionBranchNames.ids[name]; !ok {
+			id = uint32(len(unionBranchNames.names))
+			unionBranchNames.ids[name] = id
+			unionBranchNames.names = append(unionBranchNames.names, name)
+		}
+		unionBranchNames.Unlock()
+	}
+	v.branch = id
+	return v
+}
+
+// Branch returns the name of the union branch v is tagged with, or "".
+func (v Value) Branch() string {
+	if v.branch == 0 {
+		return ""
+	}
+	unionBranchNames.RLock()
+	defer unionBranchNames.RUnlock()
+	return unionBranchNames.names[v.branch]
+}
@@ -20,10 +20,20 @@ func TypeName(t ValueType) string {
 		return "string"
 	case TypeBool:
 		return "bool"
+	case TypeDate:
+		return "date"
+	case TypeTimestamp:
+		return "timestamp"
+	case TypeDuration:
+		return "duration"
+	case TypeDecimal:
+		return "decimal"
 	case TypeList:
 		return "list"
 	case TypeRecord:
 		return "record"
+	case TypeMap:
+		return "map"
 	case TypeUnion:
 		return "union"
 	case TypeMixed:
@@ -63,6 +73,13 @@ func writeCanonicalKey(b *strings.Builder, v Value) {
 		} else {
 			b.WriteString("false")
 		}
+	case TypeDate, TypeTimestamp, TypeDuration:
+		b.WriteString(TypeName(v.Type))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatInt(v.Int, 10))
+	case TypeDecimal:
+		b.WriteString("decimal:")
+		b.WriteString(decimalCanonicalText(v.Str))
 	case TypeList:
 		b.WriteString("list:[")
 		for i, elem := range v.List {
@@ -73,31 +90,41 @@ func writeCanonicalKey(b *strings.Builder, v Value) {
 		}
 		b.WriteByte(']')
 	case TypeRecord:
-		b.WriteString("record:{")
-		if len(v.Fields) > 0 {
-			idxs := make([]int, len(v.Fields))
-			for i := range idxs {
-				idxs[i] = i
+		b.WriteString("record:")
+		writeCanonicalFields(b, v.Fields)
+	case TypeMap:
+		b.WriteString("map:")
+		writeCanonicalFields(b, v.Fields)
+	default:
+		b.WriteString("unknown")
+	}
+}
+
+// writeCanonicalFields writes record fields or map entries sorted by name, so
+// the key does not depend on their order.
+func writeCanonicalFields(b *strings.Builder, fields []RecordField) {
+	b.WriteByte('{')
+	if len(fields) > 0 {
+		idxs := make([]int, len(fields))
+		for i := range idxs {
+			idxs[i] = i
+		}
+		sort.SliceStable(idxs, func(i, j int) bool {
+			if fields[idxs[i]].Name == fields[idxs[j]].Name {
+				return idxs[i] < idxs[j]
 			}
-			sort.SliceStable(idxs, func(i, j int) bool {
-				if v.Fields[idxs[i]].Name == v.Fields[idxs[j]].Name {
-					return idxs[i] < idxs[j]
-				}
-				return v.Fields[idxs[i]].Name < v.Fields[idxs[j]].Name
-			})
-			for i, idx := range idxs {
-				if i > 0 {
-					b.WriteByte(',')
-				}
-				b.WriteString(strconv.Quote(v.Fields[idx].Name))
-				b.WriteByte(':')
-				writeCanonicalKey(b, v.Fields[idx].Value)
+			return fields[idxs[i]].Name < fields[idxs[j]].Name
+		})
+		for i, idx := range idxs {
+			if i > 0 {
+				b.WriteByte(',')
 			}
+			b.WriteString(strconv.Quote(fields[idx].Name))
+			b.WriteByte(':')
+			writeCanonicalKey(b, fields[idx].Value)
 		}
-		b.WriteByte('}')
-	default:
-		b.WriteString("unknown")
 	}
+	b.WriteByte('}')
 }
 
 // Equal reports whether two values have the same type and structural value.
@@ -123,7 +150,7 @@ func CompareStrict(a, b Value) (int, error) {
 	}
 
 	switch a.Type {
-	case TypeInt:
+	case TypeInt, TypeDate, TypeTimestamp, TypeDuration:
 		switch {
 		case a.Int < b.Int:
 			return -1, nil
@@ -143,9 +170,11 @@ func CompareStrict(a, b Value) (int, error) {
 		}
 	case TypeString:
 		return strings.Compare(a.Str, b.Str), nil
+	case TypeDecimal:
+		return CompareDecimal(a, b), nil
 	case TypeBool:
 		return 0, fmt.Errorf("bool values are not orderable")
-	case TypeList, TypeRecord:
+	case TypeList, TypeRecord, TypeMap:
 		return 0, fmt.Errorf("%s values are not orderable", TypeName(a.Type))
 	case TypeNull:
 		return 0, fmt.Errorf("null values are not orderable")
@@ -35,6 +35,19 @@ func TestCanonicalKeyIgnoresRecordFieldOrder(t *testing.T) {
 	}
 }
 
+func TestCanonicalKeySeparatesMapsFromRecords(t *testing.T) {
+	fields := []RecordField{{Name: "a", Value: IntVal(1)}}
+	if Equal(MapVal(fields), RecordVal(fields)) {
+		t.Fatal("expected a map and a record with the same entries to be unequal")
+	}
+	if !Equal(MapVal(fields), MapVal([]RecordField{{Name: "a", Value: IntVal(1)}})) {
+		t.Fatal("expected maps with the same entries to be equal")
+	}
+	if _, err := CompareStrict(MapVal(fields), MapVal(fields)); err == nil {
+		t.Fatal("expected maps to be unorderable")
+	}
+}
+
 func TestEqualStrict(t *testing.T) {
 	if ok, err := EqualStrict(IntVal(1), IntVal(1)); err != nil || !ok {
 		t.Fatalf("expected equal ints, got ok=%v err=%v", ok, err)
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	goavro "github.com/linkedin/goavro/v2"
@@ -15,11 +18,17 @@ import (
 )
 
 type inferredType struct {
-	typ      table.ValueType
-	nullable bool
-	fields   []inferredField
-	elem     *inferredType
-	avroName string
+	typ       table.ValueType
+	nullable  bool
+	fields    []inferredField
+	elem      *inferredType
+	avroName  string
+	precision int // decimal only
+	scale     int // decimal only
+
+	name     string                // source name of a union branch, if any
+	branches []*inferredType       // union only
+	union    *table.TypeDescriptor // union only; picks each value's branch
 }
 
 type inferredField struct {
@@ -27,12 +36,13 @@ type inferredField struct {
 	typ  *inferredType
 }
 
-const parquetColumnOrderMetadataKey = "dq.column_order"
+const (
+	parquetColumnOrderMetadataKey     = "dq.column_order"
+	parquetDurationColumnsMetadataKey = "dq.duration_columns"
+	parquetUnionColumnsMetadataKey    = "dq.union_columns"
+)
 
 func writeAvro(w io.Writer, t *table.Table) error {
-	if err := rejectUnionBinaryOutput("Avro", t); err != nil {
-		return err
-	}
 	return writeAvroWithTypes(w, t, inferTableTypes(t))
 }
 
@@ -80,22 +90,9 @@ func writeAvroWithTypes(w io.Writer, t *table.Table, types []*inferredType) erro
 }
 
 func writeParquet(w io.Writer, t *table.Table) error {
-	if err := rejectUnionBinaryOutput("Parquet", t); err != nil {
-		return err
-	}
 	return writeParquetWithTypes(w, t, inferTableTypes(t))
 }
 
-func rejectUnionBinaryOutput(format string, t *table.Table) error {
-	schema := t.Schema()
-	for _, col := range schema.Columns {
-		if table.SchemaContainsUnion(col.Type) {
-			return fmt.Errorf("%s output does not support union schema in column %q", format, col.Name)
-		}
-	}
-	return nil
-}
-
 func writeParquetWithTypes(w io.Writer, t *table.Table, types []*inferredType) error {
 	if len(t.Columns) == 0 {
 		return fmt.Errorf("Parquet output requires at least one column")
@@ -104,6 +101,12 @@ func writeParquetWithTypes(w io.Writer, t *table.Table, types []*inferredType) e
 	schema := parquet.SchemaOf(reflect.New(rowType).Interface())
 	pw := parquet.NewGenericWriter[any](w, schema)
 	pw.SetKeyValueMetadata(parquetColumnOrderMetadataKey, strings.Join(t.Columns, ","))
+	if durations := parquetDurationColumns(t.Columns, types); len(durations) > 0 {
+		pw.SetKeyValueMetadata(parquetDurationColumnsMetadataKey, strings.Join(durations, ","))
+	}
+	if unions := parquetUnionColumns(t.Columns, types); len(unions) > 0 {
+		pw.SetKeyValueMetadata(parquetUnionColumnsMetadataKey, strings.Join(unions, ","))
+	}
 
 	rows := make([]any, t.NumRows)
 	for i := 0; i < t.NumRows; i++ {
@@ -128,6 +131,34 @@ func writeParquetWithTypes(w io.Writer, t *table.Table, types []*inferredType) e
 	return nil
 }
 
+// parquetDurationColumns lists the top-level duration columns. Parquet has no
+// microsecond duration type, so durations are written as plain int64 and named
+// in the file metadata for readers that want them back.
+func parquetDurationColumns(columns []string, types []*inferredType) []string {
+	var out []string
+	for i, col := range columns {
+		if types[i].typ == table.TypeDuration {
+			out = append(out, col)
+		}
+	}
+	return out
+}
+
+// parquetUnionColumns lists the top-level union columns. Parquet has no union
+// type, so a union is written as a group with one optional field per branch,
+// member0, member1 and so on, of which only the active branch is set. This is
+// the layout parquet-avro uses for Avro unions; the metadata lets dq read
+// these columns back as unions rather than records.
+func parquetUnionColumns(columns []string, types []*inferredType) []string {
+	var out []string
+	for i, col := range columns {
+		if types[i].typ == table.TypeUnion {
+			out = append(out, col)
+		}
+	}
+	return out
+}
+
 func buildParquetRowStruct(columns []string, types []*inferredType) reflect.Type {
 	return parquetStructType(columns, types)
 }
@@ -142,7 +173,10 @@ func parquetStructType(names []string, types []*inferredType) reflect.Type {
 }
 
 func parquetStructField(name string, typ *inferredType, fieldName string) reflect.StructField {
-	tag := fmt.Sprintf(`parquet:"%s"`, name)
+	tag := fmt.Sprintf(`parquet:"%s%s"`, name, parquetLogicalTag(typ))
+	if typ.typ == table.TypeDecimal && typ.nullable {
+		tag = fmt.Sprintf(`parquet:"%s%s,optional"`, name, parquetLogicalTag(typ))
+	}
 	if typ.typ == table.TypeList {
 		tag = fmt.Sprintf(`parquet:"%s,list"`, name)
 	}
@@ -153,6 +187,22 @@ func parquetStructField(name string, typ *inferredType, fieldName string) reflec
 	}
 }
 
+// parquetLogicalTag returns the tag option annotating dates and timestamps,
+// which are carried as time.Time values, and decimals, which are carried as
+// fixed-length two's complement bytes.
+func parquetLogicalTag(typ *inferredType) string {
+	switch typ.typ {
+	case table.TypeDate:
+		return ",date"
+	case table.TypeTimestamp:
+		return ",timestamp(microsecond:utc)"
+	case table.TypeDecimal:
+		return fmt.Sprintf(",decimal(%d:%d)", typ.scale, typ.precision)
+	default:
+		return ""
+	}
+}
+
 func uniqueExportedFieldName(name string, used map[string]bool) string {
 	base := exportedFieldName(name)
 	candidate := base
@@ -197,6 +247,9 @@ func parquetReflectType(typ *inferredType) reflect.Type {
 	switch typ.typ {
 	case table.TypeList:
 		return reflect.SliceOf(parquetListElemReflectType(typ.elem))
+	case table.TypeDecimal:
+		// Decimal tags reject pointers; a nil slice marks null instead.
+		return parquetReflectBaseType(typ)
 	case table.TypeRecord:
 		base := parquetRecordStructType(typ.fields)
 		if typ.nullable {
@@ -217,10 +270,14 @@ func parquetReflectBaseType(typ *inferredType) reflect.Type {
 		return reflect.TypeOf("")
 	}
 	switch typ.typ {
-	case table.TypeInt:
+	case table.TypeInt, table.TypeDuration:
 		return reflect.TypeOf(int64(0))
 	case table.TypeFloat:
 		return reflect.TypeOf(float64(0))
+	case table.TypeDate, table.TypeTimestamp:
+		return reflect.TypeOf(time.Time{})
+	case table.TypeDecimal:
+		return reflect.TypeOf([]byte(nil))
 	case table.TypeString, table.TypeNull:
 		return reflect.TypeOf("")
 	case table.TypeBool:
@@ -229,11 +286,41 @@ func parquetReflectBaseType(typ *inferredType) reflect.Type {
 		return reflect.SliceOf(parquetListElemReflectType(typ.elem))
 	case table.TypeRecord:
 		return parquetRecordStructType(typ.fields)
+	case table.TypeMap:
+		return reflect.MapOf(reflect.TypeOf(""), parquetMapValueReflectType(typ.elem))
+	case table.TypeUnion:
+		return parquetUnionStructType(typ.branches)
 	default:
 		return reflect.TypeOf("")
 	}
 }
 
+// parquetUnionStructType builds the member0..memberN group of a union. Every
+// member is optional, lists and maps included, so an empty list in the active
+// branch stays distinct from an unset member.
+func parquetUnionStructType(branches []*inferredType) reflect.Type {
+	fields := make([]reflect.StructField, len(branches))
+	for i, branch := range branches {
+		name := fmt.Sprintf("member%d", i)
+		typ := parquetReflectBaseType(branch)
+		tag := name + parquetLogicalTag(branch) + ",optional"
+		switch branch.typ {
+		case table.TypeList:
+			tag = name + ",optional,list"
+		case table.TypeDecimal, table.TypeMap:
+		default:
+			typ = reflect.PointerTo(typ)
+			tag = name + parquetLogicalTag(branch)
+		}
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Member%d", i),
+			Type: typ,
+			Tag:  reflect.StructTag(`parquet:"` + tag + `"`),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
 func parquetRecordStructType(fields []inferredField) reflect.Type {
 	names := make([]string, len(fields))
 	types := make([]*inferredType, len(fields))
@@ -245,7 +332,10 @@ func parquetRecordStructType(fields []inferredField) reflect.Type {
 }
 
 func setParquetStructField(field reflect.Value, v table.Value, typ *inferredType) error {
-	if typ.nullable && typ.typ != table.TypeList {
+	if typ.typ == table.TypeDecimal && v.IsNull() {
+		return nil
+	}
+	if typ.nullable && typ.typ != table.TypeList && typ.typ != table.TypeDecimal {
 		if v.IsNull() {
 			return nil
 		}
@@ -265,6 +355,10 @@ func setParquetValue(field reflect.Value, v table.Value, typ *inferredType) erro
 			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
 			return nil
 		}
+		if field.Kind() == reflect.Map {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
 		field.Set(reflect.Zero(field.Type()))
 		return nil
 	}
@@ -290,6 +384,33 @@ func setParquetValue(field reflect.Value, v table.Value, typ *inferredType) erro
 			}
 		}
 		field.Set(slice)
+	case table.TypeMap:
+		if v.Type != table.TypeMap {
+			return fmt.Errorf("expected map, got %v", v.Type)
+		}
+		m := reflect.MakeMapWithSize(field.Type(), len(v.Fields))
+		for _, entry := range v.Fields {
+			value := reflect.New(field.Type().Elem()).Elem()
+			if err := setParquetListElement(value, entry.Value, typ.elem); err != nil {
+				return fmt.Errorf("{%s}: %w", entry.Name, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(entry.Name), value)
+		}
+		field.Set(m)
+	case table.TypeUnion:
+		i := table.UnionBranch(typ.union, v)
+		if i < 0 {
+			return fmt.Errorf("%s value fits no branch of %s", table.TypeName(v.Type), table.Render(typ.union))
+		}
+		member := field.Field(i)
+		if member.Kind() != reflect.Pointer {
+			return setParquetValue(member, v, typ.branches[i])
+		}
+		ptr := reflect.New(member.Type().Elem())
+		if err := setParquetValue(ptr.Elem(), v, typ.branches[i]); err != nil {
+			return err
+		}
+		member.Set(ptr)
 	default:
 		return setParquetScalar(field, v, typ)
 	}
@@ -301,6 +422,18 @@ func parquetListElemReflectType(elem *inferredType) reflect.Type {
 		return reflect.TypeOf("")
 	}
 	base := parquetReflectBaseType(elem)
+	if elem.typ == table.TypeDecimal {
+		return reflect.StructOf([]reflect.StructField{{
+			Name: "Element",
+			Type: base,
+			Tag:  reflect.StructTag(`parquet:"element` + parquetLogicalTag(elem) + `,optional"`),
+		}})
+	}
+	if tag := parquetLogicalTag(elem); tag != "" {
+		// List element tags cannot carry a logical type, so dates,
+		// timestamps and decimals always use the element wrapper.
+		return parquetElementWrapperType(base, tag)
+	}
 	if !elem.nullable {
 		return base
 	}
@@ -310,9 +443,26 @@ func parquetListElemReflectType(elem *inferredType) reflect.Type {
 	return reflect.PointerTo(base)
 }
 
+// parquetMapValueReflectType marks nullable map values optional directly.
+// Values with a logical type keep the element wrapper lists use, since map
+// values cannot carry tags.
+func parquetMapValueReflectType(elem *inferredType) reflect.Type {
+	if elem == nil {
+		return reflect.TypeOf("")
+	}
+	if parquetLogicalTag(elem) != "" {
+		return parquetListElemReflectType(elem)
+	}
+	base := parquetReflectBaseType(elem)
+	if elem.nullable && elem.typ != table.TypeList && elem.typ != table.TypeMap {
+		return reflect.PointerTo(base)
+	}
+	return base
+}
+
 func parquetNullablePrimitive(typ *inferredType) bool {
 	switch typ.typ {
-	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool, table.TypeNull:
+	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool, table.TypeNull, table.TypeDuration:
 		return true
 	default:
 		return false
@@ -320,10 +470,14 @@ func parquetNullablePrimitive(typ *inferredType) bool {
 }
 
 func parquetNullablePrimitiveWrapperType(base reflect.Type) reflect.Type {
+	return parquetElementWrapperType(base, "")
+}
+
+func parquetElementWrapperType(base reflect.Type, tag string) reflect.Type {
 	return reflect.StructOf([]reflect.StructField{{
 		Name: "Element",
 		Type: reflect.PointerTo(base),
-		Tag:  `parquet:"element"`,
+		Tag:  reflect.StructTag(`parquet:"element` + tag + `"`),
 	}})
 }
 
@@ -336,6 +490,9 @@ func isParquetNullablePrimitiveWrapper(t reflect.Type) bool {
 }
 
 func setParquetListElement(field reflect.Value, v table.Value, typ *inferredType) error {
+	if typ != nil && typ.typ == table.TypeDecimal {
+		return setParquetStructField(field.Field(0), v, typ)
+	}
 	if isParquetNullablePrimitiveWrapper(field.Type()) {
 		elemField := field.Field(0)
 		if v.IsNull() {
@@ -383,12 +540,57 @@ func setParquetScalar(field reflect.Value, v table.Value, typ *inferredType) err
 			return fmt.Errorf("expected bool, got %v", v.Type)
 		}
 		field.SetBool(v.Bool)
+	case table.TypeDate, table.TypeTimestamp:
+		t, ok := v.Time()
+		if !ok || v.Type != typ.typ {
+			return fmt.Errorf("expected %s, got %v", table.TypeName(typ.typ), v.Type)
+		}
+		field.Set(reflect.ValueOf(t))
+	case table.TypeDuration:
+		if v.Type != table.TypeDuration {
+			return fmt.Errorf("expected duration, got %v", v.Type)
+		}
+		field.SetInt(v.Int)
+	case table.TypeDecimal:
+		unscaled, err := decimalUnscaled(v, typ)
+		if err != nil {
+			return err
+		}
+		field.SetBytes(decimalTwosComplement(unscaled, parquetDecimalSize(typ.precision)))
 	default:
 		field.SetString(v.AsString())
 	}
 	return nil
 }
 
+// decimalUnscaled returns v (a decimal or int) as an unscaled integer at
+// typ's scale.
+func decimalUnscaled(v table.Value, typ *inferredType) (*big.Int, error) {
+	if v.Type != table.TypeDecimal && v.Type != table.TypeInt {
+		return nil, fmt.Errorf("expected decimal, got %v", v.Type)
+	}
+	unscaled, _, _ := table.RescaleDecimal(v, typ.scale).Decimal()
+	return unscaled, nil
+}
+
+// parquetDecimalSize is the fixed byte width Parquet gives decimals of
+// precision: the fewest bytes whose two's complement range holds every value.
+func parquetDecimalSize(precision int) int {
+	return int(math.Ceil((math.Log10(2) + float64(precision)) / math.Log10(256)))
+}
+
+// decimalTwosComplement encodes unscaled as a big-endian two's complement
+// integer of size bytes.
+func decimalTwosComplement(unscaled *big.Int, size int) []byte {
+	out := make([]byte, size)
+	n := unscaled
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), uint(size*8)), n)
+	}
+	n.FillBytes(out)
+	return out
+}
+
 func inferTableTypes(t *table.Table) []*inferredType {
 	types := make([]*inferredType, len(t.Columns))
 	schema := t.Schema()
@@ -413,11 +615,13 @@ func inferredFromSchema(schema *table.TypeDescriptor) *inferredType {
 	switch schema.Kind {
 	case table.TypeNull:
 		return &inferredType{typ: table.TypeNull, nullable: schema.Nullable}
-	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool:
+	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool, table.TypeDate, table.TypeTimestamp, table.TypeDuration:
 		return &inferredType{typ: schema.Kind, nullable: schema.Nullable}
-	case table.TypeList:
+	case table.TypeDecimal:
+		return &inferredType{typ: table.TypeDecimal, nullable: schema.Nullable, precision: schema.Precision, scale: schema.Scale}
+	case table.TypeList, table.TypeMap:
 		return &inferredType{
-			typ:      table.TypeList,
+			typ:      schema.Kind,
 			nullable: schema.Nullable,
 			elem:     inferredFromSchema(schema.Elem),
 		}
@@ -428,6 +632,13 @@ func inferredFromSchema(schema *table.TypeDescriptor) *inferredType {
 		}
 		sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
 		return &inferredType{typ: table.TypeRecord, nullable: schema.Nullable, fields: fields}
+	case table.TypeUnion:
+		branches := make([]*inferredType, len(schema.Branches))
+		for i, branch := range schema.Branches {
+			branches[i] = inferredFromSchema(branch)
+			branches[i].name = branch.Name
+		}
+		return &inferredType{typ: table.TypeUnion, nullable: schema.Nullable, branches: branches, union: schema}
 	case table.TypeMixed:
 		return &inferredType{typ: table.TypeString, nullable: schema.Nullable}
 	default:
@@ -439,14 +650,23 @@ func inferValue(v table.Value) *inferredType {
 	switch v.Type {
 	case table.TypeNull:
 		return &inferredType{typ: table.TypeNull, nullable: true}
-	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool:
+	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool, table.TypeDate, table.TypeTimestamp, table.TypeDuration:
 		return &inferredType{typ: v.Type}
+	case table.TypeDecimal:
+		_, scale, _ := v.Decimal()
+		return &inferredType{typ: table.TypeDecimal, precision: table.DecimalPrecision(v), scale: scale}
 	case table.TypeList:
 		var elem *inferredType
 		for _, e := range v.List {
 			elem = mergeInferred(elem, inferValue(e))
 		}
 		return &inferredType{typ: table.TypeList, elem: elem}
+	case table.TypeMap:
+		var elem *inferredType
+		for _, entry := range v.Fields {
+			elem = mergeInferred(elem, inferValue(entry.Value))
+		}
+		return &inferredType{typ: table.TypeMap, elem: elem}
 	case table.TypeRecord:
 		fields := make([]inferredField, 0, len(v.Fields))
 		for _, f := range v.Fields {
@@ -477,12 +697,26 @@ func mergeInferred(a, b *inferredType) *inferredType {
 		return out
 	}
 	nullable := a.nullable || b.nullable
+	if a.typ == table.TypeUnion || b.typ == table.TypeUnion {
+		// Union types only come from the table schema, which already
+		// accounts for every value.
+		out := a
+		if b.typ == table.TypeUnion {
+			out = b
+		}
+		out = cloneInferred(out)
+		out.nullable = nullable
+		return out
+	}
 	if a.typ == b.typ {
 		switch a.typ {
 		case table.TypeRecord:
 			return &inferredType{typ: table.TypeRecord, nullable: nullable, fields: mergeFields(a.fields, b.fields)}
-		case table.TypeList:
-			return &inferredType{typ: table.TypeList, nullable: nullable, elem: mergeInferred(a.elem, b.elem)}
+		case table.TypeList, table.TypeMap:
+			return &inferredType{typ: a.typ, nullable: nullable, elem: mergeInferred(a.elem, b.elem)}
+		case table.TypeDecimal:
+			precision, scale := table.DecimalWiden(a.precision, a.scale, b.precision, b.scale)
+			return &inferredType{typ: table.TypeDecimal, nullable: nullable, precision: precision, scale: scale}
 		default:
 			out := cloneInferred(a)
 			out.nullable = nullable
@@ -492,9 +726,31 @@ func mergeInferred(a, b *inferredType) *inferredType {
 	if (a.typ == table.TypeInt && b.typ == table.TypeFloat) || (a.typ == table.TypeFloat && b.typ == table.TypeInt) {
 		return &inferredType{typ: table.TypeFloat, nullable: nullable}
 	}
+	if a.typ == table.TypeDecimal || b.typ == table.TypeDecimal {
+		return mergeInferredDecimal(a, b, nullable)
+	}
 	return &inferredType{typ: table.TypeString, nullable: nullable}
 }
 
+// mergeInferredDecimal widens a decimal with an int (as decimal(19,0)) or
+// gives way to a float, matching how dq schemas merge decimals.
+func mergeInferredDecimal(a, b *inferredType, nullable bool) *inferredType {
+	if a.typ == table.TypeFloat || b.typ == table.TypeFloat {
+		return &inferredType{typ: table.TypeFloat, nullable: nullable}
+	}
+	if a.typ == table.TypeInt {
+		a = &inferredType{typ: table.TypeDecimal, precision: table.Int64DecimalPrecision}
+	}
+	if b.typ == table.TypeInt {
+		b = &inferredType{typ: table.TypeDecimal, precision: table.Int64DecimalPrecision}
+	}
+	if a.typ != table.TypeDecimal || b.typ != table.TypeDecimal {
+		return &inferredType{typ: table.TypeString, nullable: nullable}
+	}
+	precision, scale := table.DecimalWiden(a.precision, a.scale, b.precision, b.scale)
+	return &inferredType{typ: table.TypeDecimal, nullable: nullable, precision: precision, scale: scale}
+}
+
 func mergeFields(a, b []inferredField) []inferredField {
 	out := make([]inferredField, len(a))
 	copy(out, a)
@@ -534,8 +790,12 @@ func finalizeInferred(typ *inferredType) *inferredType {
 		for i := range typ.fields {
 			typ.fields[i].typ = finalizeInferred(typ.fields[i].typ)
 		}
-	case table.TypeList:
+	case table.TypeList, table.TypeMap:
 		typ.elem = finalizeInferred(typ.elem)
+	case table.TypeUnion:
+		for i := range typ.branches {
+			typ.branches[i] = finalizeInferred(typ.branches[i])
+		}
 	}
 	return typ
 }
@@ -545,10 +805,14 @@ func cloneInferred(typ *inferredType) *inferredType {
 		return nil
 	}
 	out := &inferredType{
-		typ:      typ.typ,
-		nullable: typ.nullable,
-		elem:     cloneInferred(typ.elem),
-		avroName: typ.avroName,
+		typ:       typ.typ,
+		nullable:  typ.nullable,
+		elem:      cloneInferred(typ.elem),
+		avroName:  typ.avroName,
+		precision: typ.precision,
+		scale:     typ.scale,
+		name:      typ.name,
+		union:     typ.union,
 	}
 	if len(typ.fields) > 0 {
 		out.fields = make([]inferredField, len(typ.fields))
@@ -556,6 +820,12 @@ func cloneInferred(typ *inferredType) *inferredType {
 			out.fields[i] = inferredField{name: f.name, typ: cloneInferred(f.typ)}
 		}
 	}
+	if len(typ.branches) > 0 {
+		out.branches = make([]*inferredType, len(typ.branches))
+		for i, branch := range typ.branches {
+			out.branches[i] = cloneInferred(branch)
+		}
+	}
 	return out
 }
 
@@ -593,6 +863,17 @@ func nativeValue(v table.Value, typ *inferredType) (any, error) {
 			return nil, fmt.Errorf("expected bool, got %v", v.Type)
 		}
 		return v.Bool, nil
+	case table.TypeDate, table.TypeTimestamp, table.TypeDuration:
+		if v.Type != typ.typ {
+			return nil, fmt.Errorf("expected %s, got %v", table.TypeName(typ.typ), v.Type)
+		}
+		return v.Int, nil
+	case table.TypeDecimal:
+		unscaled, err := decimalUnscaled(v, typ)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).SetFrac(unscaled, table.Pow10(typ.scale)), nil
 	case table.TypeRecord:
 		if v.Type != table.TypeRecord {
 			return nil, fmt.Errorf("expected record, got %v", v.Type)
@@ -630,6 +911,19 @@ func nativeValue(v table.Value, typ *inferredType) (any, error) {
 			items[i] = value
 		}
 		return items, nil
+	case table.TypeMap:
+		if v.Type != table.TypeMap {
+			return nil, fmt.Errorf("expected map, got %v", v.Type)
+		}
+		m := make(map[string]any, len(v.Fields))
+		for _, entry := range v.Fields {
+			value, err := nativeValue(entry.Value, typ.elem)
+			if err != nil {
+				return nil, fmt.Errorf("{%s}: %w", entry.Name, err)
+			}
+			m[entry.Name] = value
+		}
+		return m, nil
 	default:
 		return v.AsString(), nil
 	}
@@ -675,6 +969,17 @@ func avroField(name string, typ *inferredType) map[string]any {
 }
 
 func avroTypeSchema(typ *inferredType) any {
+	if typ.typ == table.TypeUnion {
+		// Avro unions cannot nest, so null joins the branches directly.
+		var out []any
+		if typ.nullable {
+			out = append(out, "null")
+		}
+		for _, branch := range typ.branches {
+			out = append(out, avroNonNullSchema(branch))
+		}
+		return out
+	}
 	if typ.nullable {
 		return []any{"null", avroNonNullSchema(typ)}
 	}
@@ -691,11 +996,26 @@ func avroNonNullSchema(typ *inferredType) any {
 		return "string"
 	case table.TypeBool:
 		return "boolean"
+	case table.TypeDate:
+		return map[string]any{"type": "int", "logicalType": "date"}
+	case table.TypeTimestamp:
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}
+	case table.TypeDuration:
+		// Avro's duration logical type is a months/days/millis fixed, so
+		// microsecond durations are a long marked with a dq property.
+		return map[string]any{"type": "long", "dq.type": "duration"}
+	case table.TypeDecimal:
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": typ.precision, "scale": typ.scale}
 	case table.TypeList:
 		return map[string]any{
 			"type":  "array",
 			"items": avroTypeSchema(typ.elem),
 		}
+	case table.TypeMap:
+		return map[string]any{
+			"type":   "map",
+			"values": avroTypeSchema(typ.elem),
+		}
 	case table.TypeRecord:
 		fields := make([]map[string]any, len(typ.fields))
 		for i, f := range typ.fields {
@@ -706,6 +1026,8 @@ func avroNonNullSchema(typ *inferredType) any {
 			"name":   typ.avroName,
 			"fields": fields,
 		}
+	case table.TypeUnion:
+		return avroTypeSchema(typ)
 	default:
 		return "string"
 	}
@@ -715,6 +1037,9 @@ func avroNullableValue(v table.Value, typ *inferredType) (any, error) {
 	if v.IsNull() {
 		return nil, nil
 	}
+	if typ.typ == table.TypeUnion {
+		return avroUnionValue(v, typ)
+	}
 	value, err := avroNonNullValue(v, typ)
 	if err != nil {
 		return nil, err
@@ -725,9 +1050,22 @@ func avroNullableValue(v table.Value, typ *inferredType) (any, error) {
 	return value, nil
 }
 
+// avroUnionValue encodes v as the union branch that holds it.
+func avroUnionValue(v table.Value, typ *inferredType) (any, error) {
+	i := table.UnionBranch(typ.union, v)
+	if i < 0 {
+		return nil, fmt.Errorf("%s value fits no branch of %s", table.TypeName(v.Type), table.Render(typ.union))
+	}
+	value, err := avroNonNullValue(v, typ.branches[i])
+	if err != nil {
+		return nil, err
+	}
+	return goavro.Union(avroUnionName(typ.branches[i]), value), nil
+}
+
 func avroNonNullValue(v table.Value, typ *inferredType) (any, error) {
 	switch typ.typ {
-	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool:
+	case table.TypeInt, table.TypeFloat, table.TypeString, table.TypeBool, table.TypeDate, table.TypeTimestamp, table.TypeDuration, table.TypeDecimal:
 		return nativeValue(v, typ)
 	case table.TypeList:
 		if v.Type != table.TypeList {
@@ -742,6 +1080,19 @@ func avroNonNullValue(v table.Value, typ *inferredType) (any, error) {
 			items[i] = value
 		}
 		return items, nil
+	case table.TypeMap:
+		if v.Type != table.TypeMap {
+			return nil, fmt.Errorf("expected map, got %v", v.Type)
+		}
+		m := make(map[string]any, len(v.Fields))
+		for _, entry := range v.Fields {
+			value, err := avroNullableValue(entry.Value, typ.elem)
+			if err != nil {
+				return nil, fmt.Errorf("{%s}: %w", entry.Name, err)
+			}
+			m[entry.Name] = value
+		}
+		return m, nil
 	case table.TypeRecord:
 		if v.Type != table.TypeRecord {
 			return nil, fmt.Errorf("expected record, got %v", v.Type)
@@ -756,6 +1107,8 @@ func avroNonNullValue(v table.Value, typ *inferredType) (any, error) {
 			row[f.name] = value
 		}
 		return row, nil
+	case table.TypeUnion:
+		return avroUnionValue(v, typ)
 	default:
 		return v.AsString(), nil
 	}
@@ -763,7 +1116,7 @@ func avroNonNullValue(v table.Value, typ *inferredType) (any, error) {
 
 func avroUnionName(typ *inferredType) string {
 	switch typ.typ {
-	case table.TypeInt:
+	case table.TypeInt, table.TypeDuration:
 		return "long"
 	case table.TypeFloat:
 		return "double"
@@ -771,8 +1124,16 @@ func avroUnionName(typ *inferredType) string {
 		return "string"
 	case table.TypeBool:
 		return "boolean"
+	case table.TypeDate:
+		return "int.date"
+	case table.TypeTimestamp:
+		return "long.timestamp-micros"
+	case table.TypeDecimal:
+		return "bytes.decimal"
 	case table.TypeList:
 		return "array"
+	case table.TypeMap:
+		return "map"
 	case table.TypeRecord:
 		return typ.avroName
 	default:
@@ -819,6 +1180,22 @@ func assignAvroRecordName(typ *inferredType, path string, used map[string]int) {
 		}
 	case table.TypeList:
 		assignAvroRecordName(typ.elem, path+"_item", used)
+	case table.TypeMap:
+		assignAvroRecordName(typ.elem, path+"_value", used)
+	case table.TypeUnion:
+		for i, branch := range typ.branches {
+			// A named record branch keeps its source name, so a file read
+			// with union_names=true writes back with the same branches.
+			if branch.typ == table.TypeRecord && isAvroFullName(branch.name) && used[branch.name] == 0 {
+				used[branch.name]++
+				branch.avroName = branch.name
+				for _, f := range branch.fields {
+					assignAvroRecordName(f.typ, path+"_"+avroPath(branch.name)+"_"+avroPath(f.name), used)
+				}
+				continue
+			}
+			assignAvroRecordName(branch, fmt.Sprintf("%s_branch%d", path, i), used)
+		}
 	}
 }
 
@@ -847,10 +1224,59 @@ func validateAvroNestedFields(typ *inferredType, path string) error {
 		}
 	case table.TypeList:
 		return validateAvroNestedFields(typ.elem, path+"[]")
+	case table.TypeMap:
+		return validateAvroNestedFields(typ.elem, path+"{}")
+	case table.TypeUnion:
+		return validateAvroUnion(typ, path)
 	}
 	return nil
 }
 
+// validateAvroUnion rejects unions Avro cannot express: Avro allows one
+// branch per unnamed type, so int and duration branches, or two list
+// branches, cannot share a union.
+func validateAvroUnion(typ *inferredType, path string) error {
+	seen := map[string]int{}
+	for i, branch := range typ.branches {
+		if base := avroUnionBaseType(branch); base != "" {
+			if j, ok := seen[base]; ok {
+				return fmt.Errorf("Avro output cannot write %s in %s: branches %s and %s are both Avro %s",
+					table.Render(typ.union), path, table.Render(typ.union.Branches[j]), table.Render(typ.union.Branches[i]), base)
+			}
+			seen[base] = i
+		}
+		if err := validateAvroNestedFields(branch, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// avroUnionBaseType is the unnamed Avro type a union branch is written as, or
+// "" for records, which are named.
+func avroUnionBaseType(typ *inferredType) string {
+	switch typ.typ {
+	case table.TypeInt, table.TypeTimestamp, table.TypeDuration:
+		return "long"
+	case table.TypeDate:
+		return "int"
+	case table.TypeFloat:
+		return "double"
+	case table.TypeBool:
+		return "boolean"
+	case table.TypeDecimal:
+		return "bytes"
+	case table.TypeList:
+		return "array"
+	case table.TypeMap:
+		return "map"
+	case table.TypeRecord:
+		return ""
+	default:
+		return "string"
+	}
+}
+
 func isAvroName(s string) bool {
 	if s == "" {
 		return false
@@ -869,6 +1295,17 @@ func isAvroName(s string) bool {
 	return true
 }
 
+// isAvroFullName reports whether s is a dotted Avro full name such as
+// "ev.Click".
+func isAvroFullName(s string) bool {
+	for _, part := range strings.Split(s, ".") {
+		if !isAvroName(part) {
+			return false
+		}
+	}
+	return true
+}
+
 func isAvroNameStart(r rune) bool {
 	return (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '_'
 }
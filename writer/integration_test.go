@@ -843,6 +843,90 @@ func TestIntegrationNestedBinaryRoundTrip(t *testing.T) {
 	}
 }
 
+func TestIntegrationTemporalBinaryRoundTrip(t *testing.T) {
+	src := writeTempOutput(t, []byte("day,at,wait\n2024-03-01,2024-03-01T10:30:00.000001Z,90m\n,,\n"), "events.csv")
+	query := "transform day = date(day), at = timestamp(at), wait = duration(wait) | transform days = list(day, day) | select day, at, wait, days"
+	for _, format := range []string{"avro", "parquet"} {
+		t.Run(format, func(t *testing.T) {
+			out := queryAndWriteBytes(t, src, query, format)
+			path := writeTempOutput(t, out, "events."+format)
+
+			tbl, err := loader.Load(path, loader.Options{Format: format})
+			if err != nil {
+				t.Fatalf("reload %s: %v", format, err)
+			}
+			want := map[string]string{"day": "date?", "at": "timestamp?", "wait": "duration?"}
+			for _, col := range tbl.Schema().Columns {
+				if w, ok := want[col.Name]; ok && col.Type.String() != w {
+					t.Errorf("%s schema: want %s, got %s", col.Name, w, col.Type)
+				}
+			}
+			for col, w := range map[string]string{"day": "2024-03-01", "at": "2024-03-01T10:30:00.000001Z", "wait": "1h30m0s"} {
+				if got := tbl.Get(0, col); got.AsString() != w || got.Type == table.TypeString {
+					t.Errorf("%s: want %s, got %v %q", col, w, got.Type, got.AsString())
+				}
+				if got := tbl.Get(1, col); !got.IsNull() {
+					t.Errorf("%s row 1: want null, got %q", col, got.AsString())
+				}
+			}
+			days := tbl.Get(0, "days")
+			if days.Type != table.TypeList || len(days.List) != 2 || days.List[0].Type != table.TypeDate {
+				t.Fatalf("days: want list of dates, got %v", days)
+			}
+		})
+	}
+}
+
+func TestIntegrationDecimalBinaryRoundTrip(t *testing.T) {
+	src := writeTempOutput(t, []byte("price,big\n12.50,12345678901234567890.125\n,-0.5\n-0.05,3\n"), "prices.csv")
+	decimals := true
+	tbl, err := loader.Load(src, loader.Options{Decimals: &decimals})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	q, err := parser.Parse(src + " | transform prices = list(price, price) | select price, big, prices")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	result, err := engine.Execute(q, tbl, nil)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	for _, format := range []string{"avro", "parquet"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, result, format); err != nil {
+				t.Fatalf("write %s: %v", format, err)
+			}
+			path := writeTempOutput(t, buf.Bytes(), "prices."+format)
+			got, err := loader.Load(path, loader.Options{Format: format})
+			if err != nil {
+				t.Fatalf("reload %s: %v", format, err)
+			}
+			want := map[string]string{"price": "decimal(4,2)?", "big": "decimal(23,3)"}
+			for _, col := range got.Schema().Columns {
+				if w, ok := want[col.Name]; ok && col.Type.String() != w {
+					t.Errorf("%s schema: want %s, got %s", col.Name, w, col.Type)
+				}
+			}
+			for i, w := range []string{"12.50", "null", "-0.05"} {
+				if v := got.Get(i, "price").AsString(); v != w {
+					t.Errorf("price row %d: want %s, got %s", i, w, v)
+				}
+			}
+			for i, w := range []string{"12345678901234567890.125", "-0.500", "3.000"} {
+				if v := got.Get(i, "big"); v.Type != table.TypeDecimal || v.AsString() != w {
+					t.Errorf("big row %d: want %s, got %v %s", i, w, v.Type, v.AsString())
+				}
+			}
+			prices := got.Get(2, "prices")
+			if prices.Type != table.TypeList || len(prices.List) != 2 || prices.List[0].AsString() != "-0.05" {
+				t.Fatalf("prices: want list of decimals, got %v", prices)
+			}
+		})
+	}
+}
+
 func TestIntegrationAvroEmptyResultRoundTrip(t *testing.T) {
 	out := queryAndWriteBytes(t, testdataDir+"/users.csv", "filter { age > 100 } | select name, age", "avro")
 	path := writeTempOutput(t, out, "empty.avro")
@@ -1328,3 +1412,171 @@ func TestWritePreservesTypes(t *testing.T) {
 		t.Errorf("null: want nil, got %v", row["n"])
 	}
 }
+
+func TestIntegrationMapBinaryRoundTrip(t *testing.T) {
+	counts, _ := table.ParseSchema("map<string,int?>?")
+	days, _ := table.ParseSchema("map<string,date>")
+	day, _ := table.ParseDate("2024-03-01")
+	tbl := table.NewTableWithSchemas([]string{"counts", "days"}, []*table.TypeDescriptor{counts, days})
+	if err := tbl.AddRowTyped([]table.Value{
+		table.MapVal([]table.RecordField{{Name: "a", Value: table.IntVal(1)}, {Name: "b", Value: table.Null()}}),
+		table.MapVal([]table.RecordField{{Name: "x", Value: day}}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.AddRowTyped([]table.Value{table.Null(), table.MapVal(nil)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"avro", "parquet"} {
+		t.Run(format, func(t *testing.T) {
+			reloaded := writeAndReloadTable(t, tbl, format)
+			wantDays := "map<string,date>"
+			if format == "parquet" {
+				// Date map values use the optional element wrapper of list elements.
+				wantDays = "map<string,date?>"
+			}
+			for col, want := range map[string]string{"counts": "map<string,int?>?", "days": wantDays} {
+				if got := reloaded.Col(reloaded.ColIndex(col)).Schema().String(); got != want {
+					t.Errorf("%s schema: got %s, want %s", col, got, want)
+				}
+			}
+			for _, tc := range []struct {
+				col, want string
+				row       int
+			}{
+				{"counts", "{a:1, b:null}", 0},
+				{"counts", "null", 1},
+				{"days", "{x:2024-03-01}", 0},
+				{"days", "{}", 1},
+			} {
+				if got := reloaded.Get(tc.row, tc.col).AsString(); got != tc.want {
+					t.Errorf("%s row %d: got %s, want %s", tc.col, tc.row+1, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIntegrationUnionBinaryRoundTrip(t *testing.T) {
+	u, _ := table.ParseSchema("union<int,string,list<int>>?")
+	nested, _ := table.ParseSchema("record<d:union<date,decimal(5,2)>>")
+	day, _ := table.ParseDate("2024-03-01")
+	price, _ := table.ParseDecimal("1.50")
+	tbl := table.NewTableWithSchemas([]string{"u", "r"}, []*table.TypeDescriptor{u, nested})
+	for _, row := range [][]table.Value{
+		{table.IntVal(7), table.RecordVal([]table.RecordField{{Name: "d", Value: day}})},
+		{table.StrVal("seven"), table.RecordVal([]table.RecordField{{Name: "d", Value: price}})},
+		{table.ListVal(nil), table.RecordVal([]table.RecordField{{Name: "d", Value: day}})},
+		{table.Null(), table.RecordVal([]table.RecordField{{Name: "d", Value: price}})},
+	} {
+		if err := tbl.AddRowTyped(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tc := range []struct {
+		format  string
+		nested  string
+		nestedD []string
+	}{
+		{"avro", "record<d:union<date,decimal(5,2)>>", []string{"{d:2024-03-01}", "{d:1.50}", "{d:2024-03-01}", "{d:1.50}"}},
+		// Only top-level union columns are listed in the file metadata, so
+		// a nested union reads back as its member group.
+		{"parquet", "record<d:record<member0:date?, member1:decimal(5,2)?>>", []string{"{d:{member0:2024-03-01, member1:null}}", "{d:{member0:null, member1:1.50}}", "{d:{member0:2024-03-01, member1:null}}", "{d:{member0:null, member1:1.50}}"}},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			reloaded := writeAndReloadTable(t, tbl, tc.format)
+			for col, want := range map[string]string{"u": "union<int,string,list<int>>?", "r": tc.nested} {
+				if got := reloaded.Col(reloaded.ColIndex(col)).Schema().String(); got != want {
+					t.Errorf("%s schema: got %s, want %s", col, got, want)
+				}
+			}
+			for row, want := range []string{"7", "seven", "[]", "null"} {
+				v := reloaded.Get(row, "u")
+				if got := v.AsString(); got != want {
+					t.Errorf("u row %d: got %s, want %s", row+1, got, want)
+				}
+			}
+			if got := reloaded.Get(1, "u").Type; got != table.TypeString {
+				t.Errorf("u row 2 type: got %v, want string", got)
+			}
+			for row, want := range tc.nestedD {
+				if got := reloaded.Get(row, "r").AsString(); got != want {
+					t.Errorf("r row %d: got %s, want %s", row+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAvroUnionKeepsNamedRecordBranches(t *testing.T) {
+	schema, _ := table.ParseSchema("union<ev.Click:record<x:int>,ev.View:record<x:int>>")
+	tbl := table.NewTableWithSchemas([]string{"e"}, []*table.TypeDescriptor{schema})
+	for _, branch := range []string{"ev.View", "ev.Click"} {
+		v := table.RecordVal([]table.RecordField{{Name: "x", Value: table.IntVal(1)}}).WithBranch(branch)
+		if err := tbl.AddRowTyped([]table.Value{v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := writeTableBytes(t, tbl, "avro")
+	unionNames := true
+	reloaded, err := loader.Load(writeTempOutput(t, out, "out.avro"), loader.Options{Format: "avro", UnionNames: &unionNames})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reloaded.Col(0).Schema().String(), "union<ev.Click:record<x:int>,ev.View:record<x:int>>"; got != want {
+		t.Fatalf("schema: got %s, want %s", got, want)
+	}
+	for row, want := range []string{"ev.View", "ev.Click"} {
+		if got := reloaded.Get(row, "e").Branch(); got != want {
+			t.Errorf("row %d branch: got %q, want %q", row+1, got, want)
+		}
+	}
+}
+
+func TestParquetUnionKeepsBranchesThatOnlyDifferedByName(t *testing.T) {
+	var avro bytes.Buffer
+	w, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &avro, Schema: `{"type":"record","name":"Row","fields":[
+		{"name":"e","type":["null",
+			{"type":"record","name":"Click","namespace":"ev","fields":[{"name":"x","type":"long"}]},
+			{"type":"record","name":"View","namespace":"ev","fields":[{"name":"x","type":"long"}]},
+			"long"],"default":null}]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append([]map[string]any{
+		{"e": goavro.Union("ev.View", map[string]any{"x": int64(2)})},
+		{"e": goavro.Union("long", int64(5))},
+		{"e": goavro.Union("ev.Click", map[string]any{"x": int64(1)})},
+		{"e": nil},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	unionNames := true
+	tbl, err := loader.Load(writeTempOutput(t, avro.Bytes(), "in.avro"), loader.Options{Format: "avro", UnionNames: &unionNames})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tbl.Col(0).Schema().String(), "union<ev.Click:record<x:int>,ev.View:record<x:int>,int>?"; got != want {
+		t.Fatalf("avro schema: got %s, want %s", got, want)
+	}
+
+	reloaded := writeAndReloadTable(t, tbl, "parquet")
+	if got, want := reloaded.Col(0).Schema().String(), "union<record<x:int>,int>?"; got != want {
+		t.Fatalf("parquet schema: got %s, want %s", got, want)
+	}
+	for row, want := range []string{"{x:2}", "5", "{x:1}", "null"} {
+		if got := reloaded.Get(row, "e").AsString(); got != want {
+			t.Errorf("row %d: got %s, want %s", row+1, got, want)
+		}
+	}
+}
+
+func TestAvroUnionRejectsBranchesSharingAnAvroType(t *testing.T) {
+	schema, _ := table.ParseSchema("union<int,duration>")
+	tbl := table.NewTableWithSchemas([]string{"u"}, []*table.TypeDescriptor{schema})
+	var buf bytes.Buffer
+	err := Write(&buf, tbl, "avro")
+	if err == nil || !strings.Contains(err.Error(), "branches int and duration are both Avro long") {
+		t.Fatalf("expected shared Avro type error, got %v", err)
+	}
+}
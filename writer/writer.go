@@ -155,30 +155,5 @@ func writeJSONL(w io.Writer, t *table.Table) error {
 
 // valueToJSON converts a table.Value to a Go value suitable for json.Marshal.
 func valueToJSON(v table.Value) interface{} {
-	switch v.Type {
-	case table.TypeNull:
-		return nil
-	case table.TypeInt:
-		return v.Int
-	case table.TypeFloat:
-		return v.Float
-	case table.TypeString:
-		return v.Str
-	case table.TypeBool:
-		return v.Bool
-	case table.TypeList:
-		elems := make([]interface{}, len(v.List))
-		for i, e := range v.List {
-			elems[i] = valueToJSON(e)
-		}
-		return elems
-	case table.TypeRecord:
-		obj := make(map[string]interface{}, len(v.Fields))
-		for _, f := range v.Fields {
-			obj[f.Name] = valueToJSON(f.Value)
-		}
-		return obj
-	default:
-		return v.AsString()
-	}
+	return table.ValueToJSON(v)
 }